@@ -0,0 +1,5 @@
+// Package notificationv1 holds the generated gRPC client and server code of the
+// notification API. Regenerate it after editing notification.proto.
+package notificationv1
+
+//go:generate protoc -I ../.. --go_out=../.. --go_opt=paths=source_relative --go-grpc_out=../.. --go-grpc_opt=paths=source_relative notification/v1/notification.proto
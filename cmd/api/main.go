@@ -3,23 +3,50 @@ package main
 import (
 	"context"
 	"log"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
+	"github.com/semih-yildiz/notification-service/internal/api"
+	audiencemanage "github.com/semih-yildiz/notification-service/internal/application/audience/command/manage"
+	audiencelookup "github.com/semih-yildiz/notification-service/internal/application/audience/query/lookup"
+	"github.com/semih-yildiz/notification-service/internal/application/auth/command/apikey"
+	"github.com/semih-yildiz/notification-service/internal/application/auth/query/client"
+	inboxmanage "github.com/semih-yildiz/notification-service/internal/application/inbox/command/manage"
+	inboxlookup "github.com/semih-yildiz/notification-service/internal/application/inbox/query/lookup"
+	"github.com/semih-yildiz/notification-service/internal/application/notification/command/bulk"
 	"github.com/semih-yildiz/notification-service/internal/application/notification/command/cancel"
 	"github.com/semih-yildiz/notification-service/internal/application/notification/command/create"
+	"github.com/semih-yildiz/notification-service/internal/application/notification/command/engage"
+	"github.com/semih-yildiz/notification-service/internal/application/notification/command/fanout"
+	"github.com/semih-yildiz/notification-service/internal/application/notification/command/receipt"
 	"github.com/semih-yildiz/notification-service/internal/application/notification/query/get"
+	"github.com/semih-yildiz/notification-service/internal/application/notification/query/imports"
 	"github.com/semih-yildiz/notification-service/internal/application/notification/query/list"
+	profilemanage "github.com/semih-yildiz/notification-service/internal/application/profile/command/manage"
+	profilelookup "github.com/semih-yildiz/notification-service/internal/application/profile/query/lookup"
+	templatemanage "github.com/semih-yildiz/notification-service/internal/application/template/command/manage"
+	templatelookup "github.com/semih-yildiz/notification-service/internal/application/template/query/lookup"
+	"github.com/semih-yildiz/notification-service/internal/application/tenant/command/manage"
+	"github.com/semih-yildiz/notification-service/internal/application/tenant/query/lookup"
+	grpcserver "github.com/semih-yildiz/notification-service/internal/grpc"
 	httpserver "github.com/semih-yildiz/notification-service/internal/http"
 	"github.com/semih-yildiz/notification-service/internal/infrastructure/cache/redis"
 	"github.com/semih-yildiz/notification-service/internal/infrastructure/config"
 	"github.com/semih-yildiz/notification-service/internal/infrastructure/messaging/rabbitmq"
 	"github.com/semih-yildiz/notification-service/internal/infrastructure/persistence/postgres"
+	"github.com/semih-yildiz/notification-service/internal/infrastructure/storage/filesystem"
+	"github.com/semih-yildiz/notification-service/internal/infrastructure/tracking"
+	sharedctx "github.com/semih-yildiz/notification-service/internal/shared/context"
 	"github.com/semih-yildiz/notification-service/internal/shared/logger"
 )
 
+// shutdownGracePeriod bounds how long shutdown waits for open requests and streams.
+const shutdownGracePeriod = 10 * time.Second
+
 func main() {
 	cfg := config.Load()
 	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
@@ -44,7 +71,7 @@ func main() {
 	defer rdb.Close()
 
 	// RabbitMQ Publisher
-	pub, err := rabbitmq.NewPublisher(rabbitmq.Config{URL: cfg.RabbitMQ.URL})
+	pub, err := rabbitmq.NewPublisher(rabbitmq.Config{URL: cfg.RabbitMQ.URL, TenantShards: cfg.RabbitMQ.TenantShards})
 	if err != nil {
 		log.Fatalf("rabbitmq: %v", err)
 	}
@@ -55,27 +82,100 @@ func main() {
 		cfg.RabbitMQ.ManagementURL,
 		cfg.RabbitMQ.ManagementUser,
 		cfg.RabbitMQ.ManagementPass,
-	)
+	).WithTenantShards(cfg.RabbitMQ.TenantShards)
 
 	// Repositories
 	notifRepo := postgres.NewNotificationRepository(db.DB)
 	batchRepo := postgres.NewBatchRepository(db.DB)
 	metricsRepo := postgres.NewMetricsRepository(db.DB)
+	clientRepo := postgres.NewClientRepository(db.DB)
+	tenantRepo := postgres.NewTenantRepository(db.DB)
+	importRepo := postgres.NewImportJobRepository(db.DB)
+	audienceRepo := postgres.NewAudienceRepository(db.DB)
+	profileRepo := postgres.NewProfileRepository(db.DB)
+	templateRepo := postgres.NewTemplateRepository(db.DB)
+	inboxRepo := postgres.NewInboxRepository(db.DB)
 	idemStore := redis.NewIdempotencyStore(rdb)
+	dedupeStore := redis.NewDedupeStore(rdb)
+	quotaLimiter := redis.NewQuotaLimiter(rdb, tenantRepo)
+	apiRateLimiter := redis.NewAPIRateLimiter(rdb)
+	statusEvents := redis.NewStatusEvents(rdb)
+	inboxEvents := redis.NewInboxEvents(rdb)
 	appLogger := logger.New()
+	importStorage, err := filesystem.NewImportStorage(cfg.Import.Dir)
+	if err != nil {
+		log.Fatalf("imports: %v", err)
+	}
 
 	// Application layer: usecase
-	createUsecase := create.NewUseCase(notifRepo, batchRepo, pub, idemStore, appLogger)
-	cancelUsecase := cancel.NewUseCase(notifRepo)
-	getUsecase := get.NewUseCase(notifRepo, batchRepo)
+	createUsecase := create.NewUseCase(notifRepo, batchRepo, pub, idemStore, appLogger).
+		WithDedupe(dedupeStore, cfg.Dedupe.Window).
+		WithQuotas(quotaLimiter).
+		WithProfiles(profileRepo).
+		WithDeliveryWindows(tenantRepo).
+		WithFallbacks(notifRepo).
+		WithDigests(tenantRepo, notifRepo).
+		WithLocales(cfg.Locale.Fallback)
+	cancelUsecase := cancel.NewUseCase(notifRepo, batchRepo).WithStatusEvents(statusEvents)
+	getUsecase := get.NewUseCase(notifRepo, batchRepo).WithHistory(notifRepo).WithEngagement(notifRepo)
 	listUsecase := list.NewUseCase(notifRepo)
+	bulkUsecase := bulk.NewUseCase(importRepo, importStorage, createUsecase, appLogger, cfg.Import.MaxBytes)
+	importsUsecase := imports.NewUseCase(importRepo)
+	fanoutUsecase := fanout.NewUseCase(batchRepo, audienceRepo, pub, createUsecase, appLogger)
+	audienceManageUsecase := audiencemanage.NewUseCase(audienceRepo)
+	audienceLookupUsecase := audiencelookup.NewUseCase(audienceRepo)
+	profileManageUsecase := profilemanage.NewUseCase(profileRepo)
+	profileLookupUsecase := profilelookup.NewUseCase(profileRepo)
+	templateManageUsecase := templatemanage.NewUseCase(templateRepo)
+	templateLookupUsecase := templatelookup.NewUseCase(templateRepo)
+	inboxManageUsecase := inboxmanage.NewUseCase(inboxRepo, appLogger).WithEvents(inboxEvents)
+	inboxLookupUsecase := inboxlookup.NewUseCase(inboxRepo)
+	apikeyUsecase := apikey.NewUseCase(clientRepo, tenantRepo)
+	clientUsecase := client.NewUseCase(clientRepo)
+	manageUsecase := manage.NewUseCase(tenantRepo)
+	lookupUsecase := lookup.NewUseCase(tenantRepo, quotaLimiter)
+
+	// Startup tasks act for no tenant; requests are scoped by their API key.
+	sysCtx := sharedctx.Unscoped(ctx)
+	if err := manageUsecase.EnsureDefault(sysCtx); err != nil {
+		log.Fatalf("default tenant: %v", err)
+	}
+	// Renew the leases of this process's imports and fail abandoned ones.
+	go bulkUsecase.Run(sysCtx)
+	if cfg.Auth.BootstrapAdminKey != "" {
+		if err := apikeyUsecase.EnsureAdmin(sysCtx, "bootstrap-admin", cfg.Auth.BootstrapAdminKey); err != nil {
+			log.Fatalf("auth bootstrap: %v", err)
+		}
+	}
 
 	// HTTP layer: handle
-	notificationHandler := httpserver.NewNotificationHandler(createUsecase, cancelUsecase, getUsecase, listUsecase)
+	notificationHandler := httpserver.NewNotificationHandler(createUsecase, cancelUsecase, getUsecase, listUsecase).
+		WithStatusEvents(statusEvents).
+		WithImports(bulkUsecase, importsUsecase).
+		WithAudiences(audienceManageUsecase, audienceLookupUsecase, fanoutUsecase).
+		WithProfiles(profileManageUsecase, profileLookupUsecase).
+		WithTemplates(templateManageUsecase, templateLookupUsecase).
+		WithInbox(inboxManageUsecase, inboxLookupUsecase, inboxEvents)
+	if cfg.Webhook.ReceiptSecret != "" {
+		receiptUsecase := receipt.NewUseCase(notifRepo, appLogger).WithStatusEvents(statusEvents)
+		notificationHandler.WithReceipts(receiptUsecase, cfg.Webhook.ReceiptSecret)
+	}
+	if cfg.Tracking.Enabled() {
+		links := tracking.NewLinks(cfg.Tracking.BaseURL, cfg.Tracking.Secret)
+		notificationHandler.WithTracking(engage.NewUseCase(links, notifRepo, appLogger))
+	}
+	adminHandler := httpserver.NewAdminHandler(apikeyUsecase, clientUsecase, manageUsecase, lookupUsecase)
 	healthHandler := httpserver.NewHealthHandler(sqlDB, rdb, metricsRepo, mqManagement)
 
 	// Initialize Echo server
-	e := httpserver.NewEcho(notificationHandler, healthHandler, "")
+	limits := httpserver.RateLimits{
+		Store:  apiRateLimiter,
+		Create: api.RateLimitPolicy{Bucket: "create", Limit: cfg.APILimit.Create, Window: cfg.APILimit.Window},
+		Batch:  api.RateLimitPolicy{Bucket: "batch", Limit: cfg.APILimit.Batch, Window: cfg.APILimit.Window},
+		Read:   api.RateLimitPolicy{Bucket: "read", Limit: cfg.APILimit.Read, Window: cfg.APILimit.Window},
+		Public: api.RateLimitPolicy{Bucket: "public", Limit: cfg.APILimit.Public, Window: cfg.APILimit.Window},
+	}
+	e := httpserver.NewEcho(notificationHandler, adminHandler, healthHandler, clientUsecase, limits, "")
 	e.Server.Addr = ":" + cfg.App.Port
 
 	// Start server
@@ -86,10 +186,43 @@ func main() {
 		}
 	}()
 
-	// Graceful shutdown
+	// gRPC server, backed by the same use cases
+	notificationServer := grpcserver.NewNotificationServer(createUsecase, cancelUsecase, getUsecase, listUsecase).
+		WithStatusEvents(statusEvents)
+	grpcServer := grpcserver.NewServer(notificationServer, clientUsecase, grpcserver.RateLimits{
+		Store:  limits.Store,
+		Create: limits.Create,
+		Batch:  limits.Batch,
+		Read:   limits.Read,
+	})
+	lis, err := net.Listen("tcp", ":"+cfg.App.GRPCPort)
+	if err != nil {
+		log.Fatalf("grpc listen: %v", err)
+	}
+	go func() {
+		log.Printf("grpc listening on :%s", cfg.App.GRPCPort)
+		if err := grpcServer.Serve(lis); err != nil {
+			log.Printf("grpc serve: %v", err)
+		}
+	}()
+
+	// Graceful shutdown. Status streams only end with their clients, so both servers
+	// cut them off after a grace period.
 	<-ctx.Done()
-	if err := e.Shutdown(context.Background()); err != nil {
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownGracePeriod)
+	defer cancel()
+	if err := e.Shutdown(shutdownCtx); err != nil {
 		log.Printf("shutdown: %v", err)
 	}
+	stopped := make(chan struct{})
+	go func() {
+		grpcServer.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-shutdownCtx.Done():
+		grpcServer.Stop()
+	}
 	log.Println("api shutdown")
 }
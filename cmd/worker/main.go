@@ -7,13 +7,23 @@ import (
 	"os/signal"
 	"syscall"
 
+	"github.com/semih-yildiz/notification-service/internal/application/inbox/command/deliver"
+	"github.com/semih-yildiz/notification-service/internal/application/notification/command/create"
+	"github.com/semih-yildiz/notification-service/internal/application/notification/command/digest"
+	"github.com/semih-yildiz/notification-service/internal/application/notification/command/fallback"
+	"github.com/semih-yildiz/notification-service/internal/application/notification/command/fanout"
 	"github.com/semih-yildiz/notification-service/internal/application/notification/command/process"
+	"github.com/semih-yildiz/notification-service/internal/application/notification/command/release"
 	"github.com/semih-yildiz/notification-service/internal/application/notification/port"
+	"github.com/semih-yildiz/notification-service/internal/domain/notification"
 	"github.com/semih-yildiz/notification-service/internal/infrastructure/cache/redis"
 	"github.com/semih-yildiz/notification-service/internal/infrastructure/config"
 	"github.com/semih-yildiz/notification-service/internal/infrastructure/messaging/rabbitmq"
 	"github.com/semih-yildiz/notification-service/internal/infrastructure/persistence/postgres"
+	"github.com/semih-yildiz/notification-service/internal/infrastructure/provider/chat"
 	"github.com/semih-yildiz/notification-service/internal/infrastructure/provider/webhook"
+	"github.com/semih-yildiz/notification-service/internal/infrastructure/tracking"
+	sharedctx "github.com/semih-yildiz/notification-service/internal/shared/context"
 	"github.com/semih-yildiz/notification-service/internal/shared/logger"
 )
 
@@ -22,6 +32,8 @@ func main() {
 	cfg := config.Load()
 	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 	defer stop()
+	// The worker serves every tenant; use cases scope to one where they need to.
+	ctx = sharedctx.Unscoped(ctx)
 
 	// Infrastructure: DB
 	db, err := postgres.New(ctx, postgres.Config{DSN: cfg.DB.DSN})
@@ -39,26 +51,70 @@ func main() {
 	defer rdb.Close()
 
 	// RabbitMQ Consumer
-	consumer, err := rabbitmq.NewConsumer(rabbitmq.Config{URL: cfg.RabbitMQ.URL})
+	consumer, err := rabbitmq.NewConsumer(rabbitmq.Config{URL: cfg.RabbitMQ.URL, TenantShards: cfg.RabbitMQ.TenantShards})
 	if err != nil {
 		log.Fatalf("rabbitmq: %v", err)
 	}
 	defer consumer.Close()
 
+	// RabbitMQ Publisher, for the notifications of audience sends
+	pub, err := rabbitmq.NewPublisher(rabbitmq.Config{URL: cfg.RabbitMQ.URL, TenantShards: cfg.RabbitMQ.TenantShards})
+	if err != nil {
+		log.Fatalf("rabbitmq publisher: %v", err)
+	}
+	defer pub.Close()
+
 	// Repositories and services
 	notifRepo := postgres.NewNotificationRepository(db.DB)
 	attemptRepo := postgres.NewDeliveryAttemptRepository(db.DB)
+	batchRepo := postgres.NewBatchRepository(db.DB)
+	audienceRepo := postgres.NewAudienceRepository(db.DB)
+	tenantRepo := postgres.NewTenantRepository(db.DB)
+	templateRepo := postgres.NewTemplateRepository(db.DB)
+	inboxRepo := postgres.NewInboxRepository(db.DB)
 	rateLimiter := redis.NewRateLimiter(rdb)
 	deliveryClient := webhook.NewClient(cfg.Webhook.URL)
 	appLogger := logger.New()
 
-	processUseCase := process.NewUseCase(notifRepo, attemptRepo, rateLimiter, deliveryClient, appLogger)
+	statusEvents := redis.NewStatusEvents(rdb)
+	// In-app notifications are delivered to the inbox here instead of the provider.
+	inboxDelivery := deliver.NewUseCase(inboxRepo, appLogger).WithEvents(redis.NewInboxEvents(rdb))
+	processUseCase := process.NewUseCase(notifRepo, attemptRepo, rateLimiter, deliveryClient, appLogger).
+		WithStatusEvents(statusEvents).
+		WithDeliveryWindows(notifRepo).
+		WithInbox(inboxDelivery).
+		WithProvider(notification.ProviderChat, chat.NewClient(cfg.Chat.Workspaces, cfg.Chat.AllowedHosts))
+	if cfg.Tracking.Enabled() {
+		processUseCase.WithTracking(tracking.NewLinks(cfg.Tracking.BaseURL, cfg.Tracking.Secret))
+	}
+	releaseUseCase := release.NewUseCase(notifRepo, pub, appLogger).WithStatusEvents(statusEvents)
+	quotaLimiter := redis.NewQuotaLimiter(rdb, tenantRepo)
+	fallbackUseCase := fallback.NewUseCase(notifRepo, notifRepo, pub, appLogger).
+		WithStatusEvents(statusEvents).
+		WithQuotas(quotaLimiter)
+	profileRepo := postgres.NewProfileRepository(db.DB)
+	digestUseCase := digest.NewUseCase(notifRepo, tenantRepo, templateRepo, notifRepo, pub, appLogger).
+		WithStatusEvents(statusEvents).
+		WithProfiles(profileRepo).
+		WithLocales(cfg.Locale.Fallback)
+
+	// Audience sends are expanded here, through the same create path as API batches.
+	createUseCase := create.NewUseCase(notifRepo, batchRepo, pub, redis.NewIdempotencyStore(rdb), appLogger).
+		WithDedupe(redis.NewDedupeStore(rdb), cfg.Dedupe.Window).
+		WithQuotas(quotaLimiter).
+		WithProfiles(profileRepo).
+		WithLocales(cfg.Locale.Fallback)
+	fanoutUseCase := fanout.NewUseCase(batchRepo, audienceRepo, pub, createUseCase, appLogger)
 
 	processFn := func(ctx context.Context, evt *port.NotificationEvent) error {
 		return processUseCase.Execute(ctx, &process.Command{NotificationID: evt.NotificationID})
 	}
 
 	log.Printf("worker consuming (env=%s)", cfg.Env)
+	go func() { _ = consumer.RunFanout(ctx, fanoutUseCase.Expand) }()
+	go releaseUseCase.Run(ctx, cfg.Deferral.ReleaseInterval)
+	go fallbackUseCase.Run(ctx, cfg.Fallback.CheckInterval)
+	go digestUseCase.Run(ctx, cfg.Digest.FlushInterval)
 	_ = consumer.Run(ctx, processFn)
 	log.Println("worker shutdown")
 }
@@ -0,0 +1,37 @@
+// Package api holds the request types, validation and rate limits shared by the
+// REST and gRPC transports.
+package api
+
+import "fmt"
+
+// Error codes of API error responses.
+const (
+	ErrCodeBadRequest          = "bad_request"
+	ErrCodeNotFound            = "not_found"
+	ErrCodeConflict            = "conflict"
+	ErrCodeInternalServerError = "internal_server_error"
+	ErrCodeValidation          = "validation_error"
+	ErrCodeDuplicateRequest    = "duplicate_request"
+	ErrCodeIdempotencyConflict = "idempotency_key_conflict"
+	ErrCodeRateLimitExceeded   = "rate_limit_exceeded"
+	ErrCodeQuotaExceeded       = "quota_exceeded"
+	ErrCodeUnauthorized        = "unauthorized"
+	ErrCodeForbidden           = "forbidden"
+	ErrCodePayloadTooLarge     = "payload_too_large"
+	ErrCodeUserOptedOut        = "user_opted_out"
+	ErrCodeUserUnreachable     = "user_unreachable"
+)
+
+// ValidationError is one invalid field of a request.
+type ValidationError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+	Index   *int   `json:"index,omitempty"` // batch item the error belongs to
+}
+
+// ValidationErrors is a validation failure listing every invalid field.
+type ValidationErrors []ValidationError
+
+func (e ValidationErrors) Error() string {
+	return fmt.Sprintf("validation failed: %d errors", len(e))
+}
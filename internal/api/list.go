@@ -0,0 +1,226 @@
+package api
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/semih-yildiz/notification-service/internal/domain/notification"
+)
+
+// ListParams are the filters of GET /notifications and ListNotifications.
+type ListParams struct {
+	Statuses        []notification.Status
+	Channel         *notification.Channel
+	Priority        *notification.Priority
+	Recipient       *string
+	RecipientPrefix *string
+	BatchID         *string
+	ClientID        *string
+	IdempotencyKey  *string
+	FailureReason   *string
+	From            *time.Time
+	To              *time.Time
+	SentFrom        *time.Time
+	SentTo          *time.Time
+	SortBy          string // created_at or updated_at
+	SortAscending   bool
+	Limit           int
+	Offset          int
+	Cursor          string
+	// IncludeTotal asks for the exact number of matches, which is expensive.
+	IncludeTotal bool
+}
+
+// Default and maximum page size of GET /notifications.
+const (
+	DefaultListLimit = 100
+	MaxListLimit     = 1000
+)
+
+// ListSorts are the accepted values of the sort parameter; a leading "-" sorts
+// newest first.
+var ListSorts = []string{"-created_at", "created_at", "-updated_at", "updated_at"}
+
+// ParseListParams parses the list query. It returns ValidationErrors listing every
+// invalid parameter.
+func ParseListParams(q url.Values) (*ListParams, error) {
+	p := &ListParams{Limit: DefaultListLimit}
+	var validationErrors ValidationErrors
+
+	// status may be repeated and/or comma-separated: status=failed,cancelled
+	for _, v := range q["status"] {
+		for _, s := range strings.Split(v, ",") {
+			st := notification.Status(strings.TrimSpace(s))
+			if !st.Valid() {
+				validationErrors = append(validationErrors, ValidationError{
+					Field:   "status",
+					Message: "status must be one of: " + statusList(),
+				})
+				break
+			}
+			p.Statuses = append(p.Statuses, st)
+		}
+	}
+
+	if s := q.Get("channel"); s != "" {
+		ch := notification.Channel(s)
+		if ch.Valid() {
+			p.Channel = &ch
+		} else {
+			validationErrors = append(validationErrors, ValidationError{
+				Field:   "channel",
+				Message: "channel must be one of: " + notification.ChannelList(),
+			})
+		}
+	}
+
+	if s := q.Get("priority"); s != "" {
+		pr := notification.Priority(s)
+		if pr.Valid() {
+			p.Priority = &pr
+		} else {
+			validationErrors = append(validationErrors, ValidationError{
+				Field:   "priority",
+				Message: "priority must be one of: high, normal, low",
+			})
+		}
+	}
+
+	for _, sp := range []struct {
+		field string
+		dst   **string
+	}{
+		{"recipient", &p.Recipient},
+		{"recipient_prefix", &p.RecipientPrefix},
+		{"batch_id", &p.BatchID},
+		{"client_id", &p.ClientID},
+		{"idempotency_key", &p.IdempotencyKey},
+		{"failure_reason", &p.FailureReason},
+	} {
+		if s := q.Get(sp.field); s != "" {
+			*sp.dst = &s
+		}
+	}
+	if p.Recipient != nil && p.RecipientPrefix != nil {
+		validationErrors = append(validationErrors, ValidationError{
+			Field:   "recipient_prefix",
+			Message: "recipient_prefix cannot be combined with recipient",
+		})
+	}
+
+	for _, tp := range []struct {
+		field string
+		dst   **time.Time
+	}{{"from", &p.From}, {"to", &p.To}, {"sent_from", &p.SentFrom}, {"sent_to", &p.SentTo}} {
+		s := q.Get(tp.field)
+		if s == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			validationErrors = append(validationErrors, ValidationError{
+				Field:   tp.field,
+				Message: tp.field + " must be an RFC 3339 timestamp, e.g. 2024-01-31T10:00:00Z",
+			})
+			continue
+		}
+		*tp.dst = &t
+	}
+	if p.From != nil && p.To != nil && p.From.After(*p.To) {
+		validationErrors = append(validationErrors, ValidationError{
+			Field:   "from",
+			Message: "from must not be after to",
+		})
+	}
+	if p.SentFrom != nil && p.SentTo != nil && p.SentFrom.After(*p.SentTo) {
+		validationErrors = append(validationErrors, ValidationError{
+			Field:   "sent_from",
+			Message: "sent_from must not be after sent_to",
+		})
+	}
+
+	p.SortBy = "created_at"
+	if s := q.Get("sort"); s != "" {
+		valid := false
+		for _, v := range ListSorts {
+			valid = valid || s == v
+		}
+		if valid {
+			p.SortBy = strings.TrimPrefix(s, "-")
+			p.SortAscending = !strings.HasPrefix(s, "-")
+		} else {
+			validationErrors = append(validationErrors, ValidationError{
+				Field:   "sort",
+				Message: "sort must be one of: " + strings.Join(ListSorts, ", "),
+			})
+		}
+	}
+
+	if s := q.Get("limit"); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 1 || n > MaxListLimit {
+			validationErrors = append(validationErrors, ValidationError{
+				Field:   "limit",
+				Message: fmt.Sprintf("limit must be an integer between 1 and %d", MaxListLimit),
+			})
+		} else {
+			p.Limit = n
+		}
+	}
+
+	if s := q.Get("offset"); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 0 {
+			validationErrors = append(validationErrors, ValidationError{
+				Field:   "offset",
+				Message: "offset must be a non-negative integer",
+			})
+		} else {
+			p.Offset = n
+		}
+	}
+
+	if s := q.Get("cursor"); s != "" {
+		if p.Offset > 0 {
+			validationErrors = append(validationErrors, ValidationError{
+				Field:   "cursor",
+				Message: "cursor cannot be combined with offset",
+			})
+		}
+		if p.SortBy == "updated_at" {
+			validationErrors = append(validationErrors, ValidationError{
+				Field:   "cursor",
+				Message: "cursor only pages sorts by created_at; use offset with updated_at",
+			})
+		}
+		p.Cursor = s
+	}
+
+	if s := q.Get("include_total"); s != "" {
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			validationErrors = append(validationErrors, ValidationError{
+				Field:   "include_total",
+				Message: "include_total must be true or false",
+			})
+		}
+		p.IncludeTotal = b
+	}
+
+	if len(validationErrors) > 0 {
+		return nil, validationErrors
+	}
+
+	return p, nil
+}
+
+func statusList() string {
+	names := make([]string, len(notification.Statuses()))
+	for i, st := range notification.Statuses() {
+		names[i] = st.String()
+	}
+	return strings.Join(names, ", ")
+}
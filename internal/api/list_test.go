@@ -0,0 +1,122 @@
+package api
+
+import (
+	"errors"
+	"net/url"
+	"testing"
+
+	"github.com/semih-yildiz/notification-service/internal/domain/notification"
+)
+
+func TestParseListParams_Defaults(t *testing.T) {
+	p, err := ParseListParams(url.Values{})
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if p.Limit != DefaultListLimit || p.Offset != 0 || p.Statuses != nil || p.Channel != nil {
+		t.Errorf("unexpected defaults: %+v", p)
+	}
+	if p.SortBy != "created_at" || p.SortAscending {
+		t.Errorf("expected newest first, got %s ascending=%v", p.SortBy, p.SortAscending)
+	}
+}
+
+func TestParseListParams_Valid(t *testing.T) {
+	q := url.Values{
+		"status":   {"sent"},
+		"channel":  {"email"},
+		"batch_id": {"b-1"},
+		"from":     {"2024-01-01T00:00:00Z"},
+		"to":       {"2024-01-02T00:00:00Z"},
+		"limit":    {"50"},
+		"offset":   {"10"},
+	}
+
+	p, err := ParseListParams(q)
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(p.Statuses) != 1 || p.Statuses[0] != "sent" || *p.Channel != "email" || *p.BatchID != "b-1" || p.Limit != 50 || p.Offset != 10 {
+		t.Errorf("unexpected params: %+v", p)
+	}
+	if p.From == nil || p.To == nil {
+		t.Error("expected time range")
+	}
+}
+
+func TestParseListParams_RichFilters(t *testing.T) {
+	q := url.Values{
+		"status":           {"failed,cancelled", "queued"},
+		"priority":         {"high"},
+		"recipient_prefix": {"+90"},
+		"sent_from":        {"2024-01-01T00:00:00Z"},
+		"sent_to":          {"2024-01-02T00:00:00Z"},
+		"idempotency_key":  {"order-1"},
+		"failure_reason":   {"timeout"},
+		"client_id":        {"c-1"},
+		"sort":             {"updated_at"},
+	}
+
+	p, err := ParseListParams(q)
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	want := []notification.Status{notification.StatusFailed, notification.StatusCancelled, notification.StatusQueued}
+	if len(p.Statuses) != len(want) {
+		t.Fatalf("expected statuses %v, got %v", want, p.Statuses)
+	}
+	for i := range want {
+		if p.Statuses[i] != want[i] {
+			t.Errorf("expected statuses %v, got %v", want, p.Statuses)
+		}
+	}
+	if *p.Priority != "high" || *p.RecipientPrefix != "+90" || *p.IdempotencyKey != "order-1" || *p.FailureReason != "timeout" || *p.ClientID != "c-1" {
+		t.Errorf("unexpected params: %+v", p)
+	}
+	if p.SentFrom == nil || p.SentTo == nil {
+		t.Error("expected sent_at range")
+	}
+	if p.SortBy != "updated_at" || !p.SortAscending {
+		t.Errorf("expected updated_at ascending, got %s ascending=%v", p.SortBy, p.SortAscending)
+	}
+}
+
+func TestParseListParams_Invalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		query url.Values
+		field string
+	}{
+		{"unknown status", url.Values{"status": {"done"}}, "status"},
+		{"unknown channel", url.Values{"channel": {"fax"}}, "channel"},
+		{"bad from", url.Values{"from": {"yesterday"}}, "from"},
+		{"bad to", url.Values{"to": {"2024-01-01"}}, "to"},
+		{"from after to", url.Values{"from": {"2024-01-02T00:00:00Z"}, "to": {"2024-01-01T00:00:00Z"}}, "from"},
+		{"limit too large", url.Values{"limit": {"1001"}}, "limit"},
+		{"limit not a number", url.Values{"limit": {"ten"}}, "limit"},
+		{"negative offset", url.Values{"offset": {"-1"}}, "offset"},
+		{"cursor with offset", url.Values{"cursor": {"abc"}, "offset": {"10"}}, "cursor"},
+		{"cursor with updated_at sort", url.Values{"cursor": {"abc"}, "sort": {"-updated_at"}}, "cursor"},
+		{"bad include_total", url.Values{"include_total": {"maybe"}}, "include_total"},
+		{"one unknown status in a list", url.Values{"status": {"sent,done"}}, "status"},
+		{"unknown priority", url.Values{"priority": {"urgent"}}, "priority"},
+		{"recipient with prefix", url.Values{"recipient": {"a@b.c"}, "recipient_prefix": {"a"}}, "recipient_prefix"},
+		{"bad sent_from", url.Values{"sent_from": {"today"}}, "sent_from"},
+		{"sent_from after sent_to", url.Values{"sent_from": {"2024-01-02T00:00:00Z"}, "sent_to": {"2024-01-01T00:00:00Z"}}, "sent_from"},
+		{"unknown sort", url.Values{"sort": {"priority"}}, "sort"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseListParams(tt.query)
+
+			var verrs ValidationErrors
+			if !errors.As(err, &verrs) || len(verrs) != 1 || verrs[0].Field != tt.field {
+				t.Errorf("expected one %s error, got %v", tt.field, verrs)
+			}
+		})
+	}
+}
@@ -0,0 +1,297 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/semih-yildiz/notification-service/internal/domain/notification"
+	"github.com/semih-yildiz/notification-service/internal/domain/profile"
+)
+
+// NotificationItem represents a single notification (used in both single and batch requests).
+// Single creates may name a user_id and category, or a fallback chain, instead of a
+// recipient and channel.
+type NotificationItem struct {
+	Recipient      string  `json:"recipient"`
+	Channel        string  `json:"channel"`
+	UserID         string  `json:"user_id,omitempty"`
+	Category       string  `json:"category,omitempty"`
+	Content        string  `json:"content"`
+	Priority       string  `json:"priority"`
+	IdempotencyKey *string `json:"idempotency_key,omitempty"`
+	// ContentVariants holds the content in other languages, keyed by locale; the
+	// recipient's locale picks one, and content is used when none matches.
+	ContentVariants notification.Variants `json:"content_variants,omitempty"`
+	// Locale is the recipient's locale, e.g. tr-TR. Empty uses the user's profile.
+	Locale string `json:"locale,omitempty"`
+	// DeliveryWindow defers non-urgent delivery outside these hours.
+	DeliveryWindow *DeliveryWindowRequest `json:"delivery_window,omitempty"`
+	// Fallback tries each step in turn until one is delivered.
+	Fallback []FallbackStepRequest `json:"fallback,omitempty"`
+	// Track adds open and click tracking to HTML email.
+	Track bool `json:"track,omitempty"`
+}
+
+// FallbackStepRequest is one step of a fallback chain. A step not delivered within
+// timeout_seconds is given up for the next; zero waits until it fails.
+type FallbackStepRequest struct {
+	Channel        string `json:"channel"`
+	Recipient      string `json:"recipient"`
+	TimeoutSeconds int    `json:"timeout_seconds,omitempty"`
+}
+
+// Steps converts the request's fallback chain.
+func (item *NotificationItem) Steps() []notification.FallbackStep {
+	steps := make([]notification.FallbackStep, len(item.Fallback))
+	for i, s := range item.Fallback {
+		steps[i] = notification.FallbackStep{
+			Channel:   notification.Channel(s.Channel),
+			Recipient: s.Recipient,
+			Timeout:   time.Duration(s.TimeoutSeconds) * time.Second,
+		}
+	}
+	return steps
+}
+
+// DeliveryWindowRequest is a time of day range, e.g. {"start": "09:00", "end": "21:00"}.
+// An empty time_zone means the recipient's, or UTC when it is unknown.
+type DeliveryWindowRequest struct {
+	Start    string `json:"start"`
+	End      string `json:"end"`
+	TimeZone string `json:"time_zone,omitempty"`
+}
+
+// DeliveryWindowMessage describes a valid delivery window in validation errors.
+const DeliveryWindowMessage = "delivery_window needs different HH:MM start and end times and an IANA time_zone, e.g. Europe/Istanbul"
+
+// CategoryMessage describes a valid category name in validation errors.
+const CategoryMessage = "categories must be lowercase letters, digits, '.', '_' and '-', at most 64 characters"
+
+// Window parses the request; a nil request is no window.
+func (w *DeliveryWindowRequest) Window() (*notification.DeliveryWindow, error) {
+	if w == nil {
+		return nil, nil
+	}
+	return notification.ParseDeliveryWindow(w.Start, w.End, w.TimeZone)
+}
+
+// BatchWindow converts the window of a batch item. An unparsable window, which only
+// reaches the use case in partial mode, is passed on as an invalid one so the use
+// case rejects just that item.
+func (w *DeliveryWindowRequest) BatchWindow() *notification.DeliveryWindow {
+	window, err := w.Window()
+	if err != nil {
+		return &notification.DeliveryWindow{Start: -1}
+	}
+	return window
+}
+
+// Validate checks the item and defaults an empty priority to normal. It returns
+// ValidationErrors listing every invalid field.
+func (item *NotificationItem) Validate() error {
+	var validationErrors ValidationErrors
+
+	if len(item.Fallback) > 0 {
+		validationErrors = append(validationErrors, item.validateFallback()...)
+	} else if item.UserID != "" {
+		validationErrors = append(validationErrors, item.validateUser()...)
+	} else if item.Recipient == "" {
+		validationErrors = append(validationErrors, ValidationError{
+			Field:   "recipient",
+			Message: "recipient is required",
+		})
+	} else if len(item.Recipient) > notification.MaxRecipientLength {
+		validationErrors = append(validationErrors, ValidationError{
+			Field:   "recipient",
+			Message: fmt.Sprintf("recipient must be at most %d characters", notification.MaxRecipientLength),
+		})
+	}
+
+	// A user's channel is resolved from their preferences; a chain names its own.
+	ch := notification.Channel(item.Channel)
+	addressed := item.UserID == "" && len(item.Fallback) == 0
+	if addressed && item.Channel == "" {
+		validationErrors = append(validationErrors, ValidationError{
+			Field:   "channel",
+			Message: "channel is required",
+		})
+	} else if addressed && !ch.Valid() {
+		validationErrors = append(validationErrors, ValidationError{
+			Field:   "channel",
+			Message: "channel must be one of: " + notification.ChannelList(),
+		})
+	} else if addressed && item.Recipient != "" {
+		if err := notification.ValidateRecipient(ch, item.Recipient); err != nil {
+			validationErrors = append(validationErrors, ValidationError{Field: "recipient", Message: err.Error()})
+		}
+	}
+
+	if item.Content == "" && len(item.ContentVariants) == 0 {
+		validationErrors = append(validationErrors, ValidationError{
+			Field:   "content",
+			Message: "content or content_variants is required",
+		})
+	} else if ch.Valid() && len(item.Content) > notification.MaxContentLength(ch) {
+		validationErrors = append(validationErrors, ValidationError{
+			Field:   "content",
+			Message: fmt.Sprintf("content must be at most %d characters for channel %s", notification.MaxContentLength(ch), ch),
+		})
+	} else if err := notification.ValidateContent(ch, item.Content); err != nil {
+		validationErrors = append(validationErrors, ValidationError{Field: "content", Message: err.Error()})
+	}
+
+	// A user's channel is not known yet, so only the variants themselves are checked.
+	if len(item.ContentVariants) > 0 && addressed && ch.Valid() {
+		if err := notification.ValidateVariants(ch, item.ContentVariants); err != nil {
+			validationErrors = append(validationErrors, ValidationError{Field: "content_variants", Message: notification.VariantsMessage(ch, err)})
+		}
+	} else if len(item.ContentVariants) > 0 {
+		if err := item.ContentVariants.Validate(); err != nil {
+			validationErrors = append(validationErrors, ValidationError{Field: "content_variants", Message: err.Error()})
+		}
+	}
+	if item.Locale != "" && !notification.ValidLocale(item.Locale) {
+		validationErrors = append(validationErrors, ValidationError{
+			Field:   "locale",
+			Message: notification.ErrInvalidLocale.Error(),
+		})
+	}
+
+	if _, err := item.DeliveryWindow.Window(); err != nil {
+		validationErrors = append(validationErrors, ValidationError{
+			Field:   "delivery_window",
+			Message: DeliveryWindowMessage,
+		})
+	}
+
+	// Set default priority if not provided
+	if item.Priority == "" {
+		item.Priority = "normal"
+	} else if !notification.Priority(item.Priority).Valid() {
+		validationErrors = append(validationErrors, ValidationError{
+			Field:   "priority",
+			Message: "priority must be one of: high, normal, low",
+		})
+	}
+
+	if len(validationErrors) > 0 {
+		return validationErrors
+	}
+
+	return nil
+}
+
+// validateFallback checks the steps of a fallback chain and that content and every
+// content variant fit every step's channel.
+func (item *NotificationItem) validateFallback() ValidationErrors {
+	var validationErrors ValidationErrors
+	if item.Recipient != "" || item.Channel != "" || item.UserID != "" || item.DeliveryWindow != nil {
+		validationErrors = append(validationErrors, ValidationError{
+			Field:   "fallback",
+			Message: "fallback cannot be combined with recipient, channel, user_id or delivery_window",
+		})
+	}
+	if len(item.Fallback) < notification.MinFallbackSteps || len(item.Fallback) > notification.MaxFallbackSteps {
+		validationErrors = append(validationErrors, ValidationError{
+			Field:   "fallback",
+			Message: fmt.Sprintf("fallback must have %d-%d steps", notification.MinFallbackSteps, notification.MaxFallbackSteps),
+		})
+	}
+	maxTimeout := int(notification.MaxFallbackTimeout / time.Second)
+	for i, step := range item.Fallback {
+		field := fmt.Sprintf("fallback[%d]", i)
+		ch := notification.Channel(step.Channel)
+		if !ch.Valid() {
+			validationErrors = append(validationErrors, ValidationError{
+				Field:   field + ".channel",
+				Message: "channel must be one of: " + notification.ChannelList(),
+			})
+		} else if err := notification.ValidateVariants(ch, item.ContentVariants); err != nil {
+			validationErrors = append(validationErrors, ValidationError{Field: "content_variants", Message: notification.VariantsMessage(ch, err)})
+		} else if len(item.Content) > notification.MaxContentLength(ch) {
+			validationErrors = append(validationErrors, ValidationError{
+				Field:   "content",
+				Message: fmt.Sprintf("content must be at most %d characters for channel %s", notification.MaxContentLength(ch), ch),
+			})
+		} else if err := notification.ValidateContent(ch, item.Content); err != nil {
+			validationErrors = append(validationErrors, ValidationError{Field: "content", Message: err.Error()})
+		}
+		if step.Recipient == "" || len(step.Recipient) > notification.MaxRecipientLength {
+			validationErrors = append(validationErrors, ValidationError{
+				Field:   field + ".recipient",
+				Message: fmt.Sprintf("recipient is required and must be at most %d characters", notification.MaxRecipientLength),
+			})
+		} else if err := notification.ValidateRecipient(ch, step.Recipient); err != nil {
+			validationErrors = append(validationErrors, ValidationError{Field: field + ".recipient", Message: err.Error()})
+		}
+		if step.TimeoutSeconds < 0 || step.TimeoutSeconds > maxTimeout {
+			validationErrors = append(validationErrors, ValidationError{
+				Field:   field + ".timeout_seconds",
+				Message: fmt.Sprintf("timeout_seconds must be 0-%d", maxTimeout),
+			})
+		}
+	}
+	return validationErrors
+}
+
+// validateUser checks the fields of an item addressed to a user ID. Its content
+// limit is checked once the channel is resolved.
+func (item *NotificationItem) validateUser() ValidationErrors {
+	var validationErrors ValidationErrors
+	if len(item.UserID) > profile.MaxUserIDLength {
+		validationErrors = append(validationErrors, ValidationError{
+			Field:   "user_id",
+			Message: fmt.Sprintf("user_id must be at most %d characters", profile.MaxUserIDLength),
+		})
+	}
+	if item.Recipient != "" || item.Channel != "" {
+		validationErrors = append(validationErrors, ValidationError{
+			Field:   "user_id",
+			Message: "user_id cannot be combined with recipient or channel",
+		})
+	}
+	if item.Category != "" && profile.ValidateCategory(item.Category) != nil {
+		validationErrors = append(validationErrors, ValidationError{
+			Field:   "category",
+			Message: "category must be lowercase letters, digits, '.', '_' and '-', at most 64 characters",
+		})
+	}
+	return validationErrors
+}
+
+// ValidateBatch validates every item of a batch request. Item errors carry the
+// item's index.
+func ValidateBatch(items []NotificationItem) error {
+	if len(items) == 0 || len(items) > notification.MaxBatchSize {
+		return ValidationErrors{{
+			Field:   "items",
+			Message: fmt.Sprintf("batch must contain 1-%d items", notification.MaxBatchSize),
+		}}
+	}
+
+	var validationErrors ValidationErrors
+	for i := range items {
+		var itemErrors ValidationErrors
+		if items[i].UserID != "" {
+			itemErrors = ValidationErrors{{Field: "user_id", Message: "user_id is only supported when creating a single notification"}}
+		} else if len(items[i].Fallback) > 0 {
+			itemErrors = ValidationErrors{{Field: "fallback", Message: "fallback is only supported when creating a single notification"}}
+		} else {
+			errors.As(items[i].Validate(), &itemErrors)
+		}
+		if len(itemErrors) > 0 {
+			for _, ve := range itemErrors {
+				index := i
+				ve.Index = &index
+				validationErrors = append(validationErrors, ve)
+			}
+		}
+	}
+
+	if len(validationErrors) > 0 {
+		return validationErrors
+	}
+
+	return nil
+}
@@ -0,0 +1,407 @@
+package api
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/semih-yildiz/notification-service/internal/domain/notification"
+)
+
+func TestNotificationItem_Validate_Success(t *testing.T) {
+	item := &NotificationItem{
+		Recipient: "+905551234567",
+		Channel:   "sms",
+		Content:   "Test message",
+		Priority:  "high",
+	}
+
+	err := item.Validate()
+
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+}
+
+func TestNotificationItem_Validate_DefaultPriority(t *testing.T) {
+	item := &NotificationItem{
+		Recipient: "+905551234567",
+		Channel:   "email",
+		Content:   "Test message",
+		Priority:  "",
+	}
+
+	err := item.Validate()
+
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+	if item.Priority != "normal" {
+		t.Errorf("expected priority to default to 'normal', got %s", item.Priority)
+	}
+}
+
+func TestNotificationItem_Validate_MissingRecipient(t *testing.T) {
+	item := &NotificationItem{
+		Recipient: "",
+		Channel:   "sms",
+		Content:   "Test message",
+		Priority:  "high",
+	}
+
+	err := item.Validate()
+
+	if err == nil {
+		t.Error("expected validation error for missing recipient")
+	}
+}
+
+func TestNotificationItem_Validate_MissingChannel(t *testing.T) {
+	item := &NotificationItem{
+		Recipient: "+905551234567",
+		Channel:   "",
+		Content:   "Test message",
+		Priority:  "high",
+	}
+
+	err := item.Validate()
+
+	if err == nil {
+		t.Error("expected validation error for missing channel")
+	}
+}
+
+func TestNotificationItem_Validate_InvalidChannel(t *testing.T) {
+	item := &NotificationItem{
+		Recipient: "+905551234567",
+		Channel:   "invalid",
+		Content:   "Test message",
+		Priority:  "high",
+	}
+
+	err := item.Validate()
+
+	if err == nil {
+		t.Error("expected validation error for invalid channel")
+	}
+}
+
+func TestNotificationItem_Validate_MissingContent(t *testing.T) {
+	item := &NotificationItem{
+		Recipient: "+905551234567",
+		Channel:   "sms",
+		Content:   "",
+		Priority:  "high",
+	}
+
+	err := item.Validate()
+
+	if err == nil {
+		t.Error("expected validation error for missing content")
+	}
+}
+
+func TestNotificationItem_Validate_InvalidPriority(t *testing.T) {
+	item := &NotificationItem{
+		Recipient: "+905551234567",
+		Channel:   "sms",
+		Content:   "Test message",
+		Priority:  "invalid",
+	}
+
+	err := item.Validate()
+
+	if err == nil {
+		t.Error("expected validation error for invalid priority")
+	}
+}
+
+func TestNotificationItem_Validate_MultipleErrors(t *testing.T) {
+	item := &NotificationItem{
+		Recipient: "",
+		Channel:   "invalid",
+		Content:   "",
+		Priority:  "invalid",
+	}
+
+	err := item.Validate()
+
+	if err == nil {
+		t.Error("expected validation error for multiple errors")
+	}
+}
+
+func TestNotificationItem_Validate_AllChannels(t *testing.T) {
+	channels := []string{"sms", "email", "push"}
+
+	for _, channel := range channels {
+		t.Run(channel, func(t *testing.T) {
+			item := &NotificationItem{
+				Recipient: "+905551234567",
+				Channel:   channel,
+				Content:   "Test message",
+				Priority:  "high",
+			}
+
+			err := item.Validate()
+
+			if err != nil {
+				t.Errorf("expected no error for channel %s, got %v", channel, err)
+			}
+		})
+	}
+}
+
+func TestNotificationItem_Validate_Chat(t *testing.T) {
+	tests := []struct {
+		name      string
+		item      NotificationItem
+		wantField string
+	}{
+		{"Channel reference with Markdown", NotificationItem{Recipient: "ops#alerts", Channel: "chat", Content: "**Deploy** finished"}, ""},
+		{"Webhook URL with blocks", NotificationItem{Recipient: "https://hooks.slack.com/services/T0/B0/x", Channel: "chat", Content: `{"blocks":[{"type":"divider"}]}`}, ""},
+		{"Malformed recipient", NotificationItem{Recipient: "alerts", Channel: "chat", Content: "hi"}, "recipient"},
+		{"Malformed blocks", NotificationItem{Recipient: "ops#alerts", Channel: "chat", Content: `{"blocks":[]}`}, "content"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.item.Validate()
+			if tt.wantField == "" {
+				if err != nil {
+					t.Fatalf("expected no error, got %v", err)
+				}
+				return
+			}
+			var verrs ValidationErrors
+			if !errors.As(err, &verrs) || verrs[0].Field != tt.wantField {
+				t.Errorf("expected %s error, got %v", tt.wantField, err)
+			}
+		})
+	}
+}
+
+func TestNotificationItem_Validate_ContentVariants(t *testing.T) {
+	variants := notification.Variants{"tr-TR": "Merhaba", "en": "Hello"}
+	tests := []struct {
+		name      string
+		item      NotificationItem
+		wantField string
+	}{
+		{"Variants without content", NotificationItem{Recipient: "+905551234567", Channel: "sms", ContentVariants: variants, Locale: "tr-TR"}, ""},
+		{"Variants for a user", NotificationItem{UserID: "u-1", ContentVariants: variants}, ""},
+		{"Neither content nor variants", NotificationItem{Recipient: "+905551234567", Channel: "sms"}, "content"},
+		{"Variant over the channel limit", NotificationItem{Recipient: "+905551234567", Channel: "sms", Content: "Hi", ContentVariants: notification.Variants{"de": strings.Repeat("a", notification.MaxContentLengthSMS+1)}}, "content_variants"},
+		{"Malformed variant locale", NotificationItem{UserID: "u-1", ContentVariants: notification.Variants{"Turkish": "Merhaba"}}, "content_variants"},
+		{"Empty variant", NotificationItem{Recipient: "+905551234567", Channel: "sms", ContentVariants: notification.Variants{"tr": ""}}, "content_variants"},
+		{"Malformed locale", NotificationItem{Recipient: "+905551234567", Channel: "sms", Content: "Hi", Locale: "tr_TR"}, "locale"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.item.Validate()
+			if tt.wantField == "" {
+				if err != nil {
+					t.Fatalf("expected no error, got %v", err)
+				}
+				return
+			}
+			var verrs ValidationErrors
+			if !errors.As(err, &verrs) || verrs[0].Field != tt.wantField {
+				t.Errorf("expected %s error, got %v", tt.wantField, err)
+			}
+		})
+	}
+}
+
+func TestNotificationItem_Validate_AllPriorities(t *testing.T) {
+	priorities := []string{"high", "normal", "low"}
+
+	for _, priority := range priorities {
+		t.Run(priority, func(t *testing.T) {
+			item := &NotificationItem{
+				Recipient: "+905551234567",
+				Channel:   "sms",
+				Content:   "Test message",
+				Priority:  priority,
+			}
+
+			err := item.Validate()
+
+			if err != nil {
+				t.Errorf("expected no error for priority %s, got %v", priority, err)
+			}
+		})
+	}
+}
+
+func TestNotificationItem_Validate_WithIdempotencyKey(t *testing.T) {
+	key := "test-key-123"
+	item := &NotificationItem{
+		Recipient:      "+905551234567",
+		Channel:        "sms",
+		Content:        "Test message",
+		Priority:       "high",
+		IdempotencyKey: &key,
+	}
+
+	err := item.Validate()
+
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+}
+
+func TestNotificationItem_Validate_ReportsEveryField(t *testing.T) {
+	item := &NotificationItem{Recipient: "", Channel: "invalid", Content: "", Priority: "invalid"}
+
+	var verrs ValidationErrors
+	if !errors.As(item.Validate(), &verrs) {
+		t.Fatal("expected ValidationErrors")
+	}
+
+	fields := make(map[string]bool)
+	for _, ve := range verrs {
+		fields[ve.Field] = true
+	}
+	for _, f := range []string{"recipient", "channel", "content", "priority"} {
+		if !fields[f] {
+			t.Errorf("expected an error for %s, got %+v", f, verrs)
+		}
+	}
+}
+
+func TestNotificationItem_Validate_ContentTooLong(t *testing.T) {
+	item := &NotificationItem{Recipient: "+905551234567", Channel: "sms", Content: strings.Repeat("a", 1601)}
+
+	var verrs ValidationErrors
+	if !errors.As(item.Validate(), &verrs) || verrs[0].Field != "content" {
+		t.Errorf("expected content error, got %v", verrs)
+	}
+}
+
+func TestValidateBatch_ItemIndices(t *testing.T) {
+	items := []NotificationItem{
+		{Recipient: "+905551234567", Channel: "sms", Content: "ok"},
+		{Recipient: "+905551234567", Channel: "fax", Content: "ok"},
+		{Recipient: "", Channel: "sms", Content: "ok"},
+	}
+
+	var verrs ValidationErrors
+	if !errors.As(ValidateBatch(items), &verrs) {
+		t.Fatal("expected ValidationErrors")
+	}
+	if len(verrs) != 2 {
+		t.Fatalf("expected 2 errors, got %+v", verrs)
+	}
+	if *verrs[0].Index != 1 || verrs[0].Field != "channel" {
+		t.Errorf("expected channel error at index 1, got %+v", verrs[0])
+	}
+	if *verrs[1].Index != 2 || verrs[1].Field != "recipient" {
+		t.Errorf("expected recipient error at index 2, got %+v", verrs[1])
+	}
+}
+
+func TestNotificationItem_Validate_User(t *testing.T) {
+	tests := []struct {
+		name      string
+		item      NotificationItem
+		wantField string
+	}{
+		{"valid", NotificationItem{UserID: "u-1", Category: "security", Content: "ok"}, ""},
+		{"default category", NotificationItem{UserID: "u-1", Content: "ok"}, ""},
+		{"with recipient", NotificationItem{UserID: "u-1", Recipient: "+905551234567", Content: "ok"}, "user_id"},
+		{"with channel", NotificationItem{UserID: "u-1", Channel: "sms", Content: "ok"}, "user_id"},
+		{"bad category", NotificationItem{UserID: "u-1", Category: "Not Valid", Content: "ok"}, "category"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.item.Validate()
+			var verrs ValidationErrors
+			if tt.wantField == "" {
+				if err != nil {
+					t.Errorf("expected no error, got %v", err)
+				}
+				return
+			}
+			if !errors.As(err, &verrs) || verrs[0].Field != tt.wantField {
+				t.Errorf("expected %s error, got %v", tt.wantField, err)
+			}
+		})
+	}
+}
+
+func TestNotificationItem_Validate_DeliveryWindow(t *testing.T) {
+	item := &NotificationItem{Recipient: "+905551234567", Channel: "sms", Content: "ok",
+		DeliveryWindow: &DeliveryWindowRequest{Start: "09:00", End: "21:00", TimeZone: "Europe/Istanbul"}}
+	if err := item.Validate(); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if w, _ := item.DeliveryWindow.Window(); w == nil || w.Start != 9*60 || w.End != 21*60 {
+		t.Errorf("expected 09:00-21:00, got %v", w)
+	}
+
+	item.DeliveryWindow = &DeliveryWindowRequest{Start: "9am", End: "21:00"}
+	var verrs ValidationErrors
+	if !errors.As(item.Validate(), &verrs) || verrs[0].Field != "delivery_window" {
+		t.Errorf("expected delivery_window error, got %v", verrs)
+	}
+}
+
+func TestNotificationItem_Validate_Fallback(t *testing.T) {
+	steps := []FallbackStepRequest{
+		{Channel: "push", Recipient: "device-token", TimeoutSeconds: 300},
+		{Channel: "sms", Recipient: "+905551234567"},
+	}
+
+	tests := []struct {
+		name      string
+		item      NotificationItem
+		wantField string
+	}{
+		{"Valid chain", NotificationItem{Fallback: steps, Content: "ok"}, ""},
+		{"One step", NotificationItem{Fallback: steps[:1], Content: "ok"}, "fallback"},
+		{"With recipient", NotificationItem{Fallback: steps, Recipient: "a@example.com", Content: "ok"}, "fallback"},
+		{"Unknown channel", NotificationItem{Fallback: []FallbackStepRequest{steps[0], {Channel: "fax", Recipient: "1"}}, Content: "ok"}, "fallback[1].channel"},
+		{"Timeout too long", NotificationItem{Fallback: []FallbackStepRequest{{Channel: "push", Recipient: "t", TimeoutSeconds: 90000}, steps[1]}, Content: "ok"}, "fallback[0].timeout_seconds"},
+		{"Malformed chat recipient", NotificationItem{Fallback: []FallbackStepRequest{steps[0], {Channel: "chat", Recipient: "alerts"}}, Content: "ok"}, "fallback[1].recipient"},
+		{"Variants only", NotificationItem{Fallback: steps, ContentVariants: notification.Variants{"tr": "tamam", "en": "ok"}}, ""},
+		{"Variant over a step's limit", NotificationItem{Fallback: steps, Content: "ok", ContentVariants: notification.Variants{"tr": strings.Repeat("a", notification.MaxContentLengthSMS+1)}}, "content_variants"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.item.Validate()
+			if tt.wantField == "" {
+				if err != nil {
+					t.Fatalf("expected no error, got %v", err)
+				}
+				if got := tt.item.Steps(); len(got) != 2 || got[0].Timeout != 5*time.Minute {
+					t.Errorf("expected two steps with a 5m timeout, got %+v", got)
+				}
+				return
+			}
+			var verrs ValidationErrors
+			if !errors.As(err, &verrs) || verrs[0].Field != tt.wantField {
+				t.Errorf("expected %s error, got %v", tt.wantField, err)
+			}
+		})
+	}
+}
+
+func TestValidateBatch_RejectsUserItems(t *testing.T) {
+	items := []NotificationItem{{UserID: "u-1", Content: "ok"}}
+
+	var verrs ValidationErrors
+	if !errors.As(ValidateBatch(items), &verrs) || verrs[0].Field != "user_id" || *verrs[0].Index != 0 {
+		t.Errorf("expected user_id error at index 0, got %v", verrs)
+	}
+}
+
+func TestValidateBatch_Size(t *testing.T) {
+	var verrs ValidationErrors
+	if !errors.As(ValidateBatch(nil), &verrs) || verrs[0].Field != "items" {
+		t.Errorf("expected items error, got %v", verrs)
+	}
+}
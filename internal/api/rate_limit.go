@@ -0,0 +1,38 @@
+package api
+
+import (
+	"context"
+	"time"
+)
+
+// RateLimitStore counts requests in fixed windows.
+type RateLimitStore interface {
+	// Hit counts one request against key in the current window and returns the
+	// window's count so far and when it resets.
+	Hit(ctx context.Context, key string, window time.Duration) (count int, reset time.Time, err error)
+}
+
+// RateLimitPolicy allows Limit requests per Window in one bucket. A zero Limit
+// disables the bucket.
+type RateLimitPolicy struct {
+	Bucket string
+	Limit  int
+	Window time.Duration
+}
+
+// Enabled reports whether the policy limits anything.
+func (p RateLimitPolicy) Enabled() bool {
+	return p.Limit > 0 && p.Window > 0
+}
+
+// Key is the store key counting client's requests in the policy's bucket. REST and
+// gRPC calls of a client share it.
+func (p RateLimitPolicy) Key(clientID string) string {
+	return p.Bucket + ":client:" + clientID
+}
+
+// IPKey is the store key counting the requests of an IP without an API client in
+// the policy's bucket.
+func (p RateLimitPolicy) IPKey(ip string) string {
+	return p.Bucket + ":ip:" + ip
+}
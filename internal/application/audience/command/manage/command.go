@@ -0,0 +1,28 @@
+package manage
+
+// CreateCommand creates an audience.
+type CreateCommand struct {
+	Name     string
+	ClientID *string // API client making the request
+	TenantID string  // owning tenant; empty means the default tenant
+}
+
+// MemberInput is one member to add.
+type MemberInput struct {
+	Channel    string
+	Recipient  string
+	Suppressed bool
+}
+
+// AddMembersCommand adds members to an audience, or changes their suppression.
+type AddMembersCommand struct {
+	AudienceID string
+	Members    []MemberInput
+}
+
+// RemoveMemberCommand removes one member from an audience.
+type RemoveMemberCommand struct {
+	AudienceID string
+	Channel    string
+	Recipient  string
+}
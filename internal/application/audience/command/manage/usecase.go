@@ -0,0 +1,99 @@
+package manage
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/semih-yildiz/notification-service/internal/application/audience/port"
+	"github.com/semih-yildiz/notification-service/internal/domain/audience"
+	"github.com/semih-yildiz/notification-service/internal/domain/notification"
+	"github.com/semih-yildiz/notification-service/internal/domain/tenant"
+)
+
+type UseCase struct {
+	repo port.AudienceRepository
+}
+
+func NewUseCase(repo port.AudienceRepository) *UseCase {
+	return &UseCase{repo: repo}
+}
+
+// Create registers a new, empty audience.
+func (u *UseCase) Create(ctx context.Context, cmd *CreateCommand) (*audience.Audience, error) {
+	name := strings.TrimSpace(cmd.Name)
+	if err := audience.ValidateName(name); err != nil {
+		return nil, err
+	}
+	tenantID := cmd.TenantID
+	if tenantID == "" {
+		tenantID = tenant.DefaultID
+	}
+
+	a := &audience.Audience{
+		ID:        uuid.New().String(),
+		TenantID:  tenantID,
+		Name:      name,
+		ClientID:  cmd.ClientID,
+		CreatedAt: time.Now(),
+	}
+	if err := u.repo.Create(ctx, a); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// Delete removes an audience with its members. Batches already sent to it are kept.
+func (u *UseCase) Delete(ctx context.Context, id string) error {
+	if _, err := u.repo.GetByID(ctx, id); err != nil {
+		return err
+	}
+	return u.repo.Delete(ctx, id)
+}
+
+// AddMembers adds up to MaxMembersPerRequest members. Members already on the list
+// keep their place and take the given suppression.
+func (u *UseCase) AddMembers(ctx context.Context, cmd *AddMembersCommand) error {
+	if len(cmd.Members) == 0 || len(cmd.Members) > audience.MaxMembersPerRequest {
+		return audience.ErrTooManyMembers
+	}
+	now := time.Now()
+	members := make([]audience.Member, 0, len(cmd.Members))
+	seen := make(map[string]int, len(cmd.Members))
+	for _, in := range cmd.Members {
+		m := audience.Member{
+			AudienceID: cmd.AudienceID,
+			Channel:    notification.Channel(in.Channel),
+			Recipient:  in.Recipient,
+			Suppressed: in.Suppressed,
+			CreatedAt:  now,
+			UpdatedAt:  now,
+		}
+		if err := m.Validate(); err != nil {
+			return err
+		}
+		// A member listed twice takes its last suppression.
+		key := in.Channel + "\x00" + in.Recipient
+		if i, ok := seen[key]; ok {
+			members[i] = m
+			continue
+		}
+		seen[key] = len(members)
+		members = append(members, m)
+	}
+
+	if _, err := u.repo.GetByID(ctx, cmd.AudienceID); err != nil {
+		return err
+	}
+	return u.repo.AddMembers(ctx, cmd.AudienceID, members)
+}
+
+// RemoveMember removes one member. It returns ErrMemberNotFound if it is not on the list.
+func (u *UseCase) RemoveMember(ctx context.Context, cmd *RemoveMemberCommand) error {
+	if _, err := u.repo.GetByID(ctx, cmd.AudienceID); err != nil {
+		return err
+	}
+	return u.repo.RemoveMember(ctx, cmd.AudienceID, notification.Channel(cmd.Channel), cmd.Recipient)
+}
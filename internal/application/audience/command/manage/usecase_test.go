@@ -0,0 +1,154 @@
+package manage
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/semih-yildiz/notification-service/internal/application/audience/port"
+	"github.com/semih-yildiz/notification-service/internal/domain/audience"
+	"github.com/semih-yildiz/notification-service/internal/domain/notification"
+)
+
+type mockAudienceRepo struct {
+	audiences map[string]*audience.Audience
+	members   map[string][]audience.Member
+}
+
+func newMockAudienceRepo() *mockAudienceRepo {
+	return &mockAudienceRepo{
+		audiences: make(map[string]*audience.Audience),
+		members:   make(map[string][]audience.Member),
+	}
+}
+
+func (m *mockAudienceRepo) Create(ctx context.Context, a *audience.Audience) error {
+	for _, other := range m.audiences {
+		if other.TenantID == a.TenantID && other.Name == a.Name {
+			return audience.ErrExists
+		}
+	}
+	m.audiences[a.ID] = a
+	return nil
+}
+
+func (m *mockAudienceRepo) GetByID(ctx context.Context, id string) (*audience.Audience, error) {
+	if a, ok := m.audiences[id]; ok {
+		return a, nil
+	}
+	return nil, audience.ErrNotFound
+}
+
+func (m *mockAudienceRepo) List(ctx context.Context) ([]*audience.Audience, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *mockAudienceRepo) Delete(ctx context.Context, id string) error {
+	delete(m.audiences, id)
+	delete(m.members, id)
+	return nil
+}
+
+func (m *mockAudienceRepo) AddMembers(ctx context.Context, audienceID string, members []audience.Member) error {
+	m.members[audienceID] = append(m.members[audienceID], members...)
+	return nil
+}
+
+func (m *mockAudienceRepo) RemoveMember(ctx context.Context, audienceID string, ch notification.Channel, recipient string) error {
+	for i, mem := range m.members[audienceID] {
+		if mem.Channel == ch && mem.Recipient == recipient {
+			m.members[audienceID] = append(m.members[audienceID][:i], m.members[audienceID][i+1:]...)
+			return nil
+		}
+	}
+	return audience.ErrMemberNotFound
+}
+
+func (m *mockAudienceRepo) ListMembers(ctx context.Context, audienceID string, filter port.MemberFilter) ([]audience.Member, error) {
+	return nil, errors.New("not implemented")
+}
+
+func TestCreate_Success(t *testing.T) {
+	uc := NewUseCase(newMockAudienceRepo())
+
+	a, err := uc.Create(context.Background(), &CreateCommand{Name: " Newsletter "})
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if a.Name != "Newsletter" || a.TenantID != "default" {
+		t.Errorf("expected trimmed name in the default tenant, got %q in %q", a.Name, a.TenantID)
+	}
+	if _, err := uc.Create(context.Background(), &CreateCommand{Name: "Newsletter"}); !errors.Is(err, audience.ErrExists) {
+		t.Errorf("expected ErrExists, got %v", err)
+	}
+	if _, err := uc.Create(context.Background(), &CreateCommand{Name: "  "}); !errors.Is(err, audience.ErrInvalidName) {
+		t.Errorf("expected ErrInvalidName, got %v", err)
+	}
+}
+
+func TestAddMembers_DeduplicatesLastWins(t *testing.T) {
+	repo := newMockAudienceRepo()
+	uc := NewUseCase(repo)
+	a, _ := uc.Create(context.Background(), &CreateCommand{Name: "Newsletter"})
+
+	err := uc.AddMembers(context.Background(), &AddMembersCommand{AudienceID: a.ID, Members: []MemberInput{
+		{Channel: "email", Recipient: "a@example.com"},
+		{Channel: "sms", Recipient: "+905551112233"},
+		{Channel: "email", Recipient: "a@example.com", Suppressed: true},
+	}})
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	members := repo.members[a.ID]
+	if len(members) != 2 {
+		t.Fatalf("expected 2 members, got %d", len(members))
+	}
+	if !members[0].Suppressed {
+		t.Error("expected the repeated member to take its last suppression")
+	}
+}
+
+func TestAddMembers_Rejected(t *testing.T) {
+	repo := newMockAudienceRepo()
+	uc := NewUseCase(repo)
+	a, _ := uc.Create(context.Background(), &CreateCommand{Name: "Newsletter"})
+
+	tests := []struct {
+		name    string
+		cmd     *AddMembersCommand
+		wantErr error
+	}{
+		{"no members", &AddMembersCommand{AudienceID: a.ID}, audience.ErrTooManyMembers},
+		{"unknown channel", &AddMembersCommand{AudienceID: a.ID, Members: []MemberInput{{Channel: "fax", Recipient: "1"}}}, audience.ErrInvalidMember},
+		{"empty recipient", &AddMembersCommand{AudienceID: a.ID, Members: []MemberInput{{Channel: "sms"}}}, audience.ErrInvalidMember},
+		{"unknown audience", &AddMembersCommand{AudienceID: "missing", Members: []MemberInput{{Channel: "sms", Recipient: "1"}}}, audience.ErrNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := uc.AddMembers(context.Background(), tt.cmd); !errors.Is(err, tt.wantErr) {
+				t.Errorf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+	if len(repo.members[a.ID]) != 0 {
+		t.Errorf("expected no members stored, got %d", len(repo.members[a.ID]))
+	}
+}
+
+func TestRemoveMember(t *testing.T) {
+	repo := newMockAudienceRepo()
+	uc := NewUseCase(repo)
+	a, _ := uc.Create(context.Background(), &CreateCommand{Name: "Newsletter"})
+	_ = uc.AddMembers(context.Background(), &AddMembersCommand{AudienceID: a.ID, Members: []MemberInput{{Channel: "sms", Recipient: "+905551112233"}}})
+
+	cmd := &RemoveMemberCommand{AudienceID: a.ID, Channel: "sms", Recipient: "+905551112233"}
+	if err := uc.RemoveMember(context.Background(), cmd); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if err := uc.RemoveMember(context.Background(), cmd); !errors.Is(err, audience.ErrMemberNotFound) {
+		t.Errorf("expected ErrMemberNotFound, got %v", err)
+	}
+}
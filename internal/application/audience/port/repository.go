@@ -0,0 +1,36 @@
+package port
+
+import (
+	"context"
+
+	"github.com/semih-yildiz/notification-service/internal/domain/audience"
+	"github.com/semih-yildiz/notification-service/internal/domain/notification"
+)
+
+type AudienceRepository interface {
+	// Create returns audience.ErrExists if the tenant already has an audience with
+	// the same name.
+	Create(ctx context.Context, a *audience.Audience) error
+	GetByID(ctx context.Context, id string) (*audience.Audience, error)
+	List(ctx context.Context) ([]*audience.Audience, error)
+	// Delete removes the audience and its members.
+	Delete(ctx context.Context, id string) error
+	// AddMembers adds members, or updates the suppression of those already on the list.
+	AddMembers(ctx context.Context, audienceID string, members []audience.Member) error
+	RemoveMember(ctx context.Context, audienceID string, ch notification.Channel, recipient string) error
+	ListMembers(ctx context.Context, audienceID string, filter MemberFilter) ([]audience.Member, error)
+}
+
+// MemberFilter pages through members by (channel, recipient).
+type MemberFilter struct {
+	Channels       []notification.Channel // any of; empty means all
+	SkipSuppressed bool
+	After          *MemberCursor
+	Limit          int
+}
+
+// MemberCursor is the last member of the previous page.
+type MemberCursor struct {
+	Channel   notification.Channel
+	Recipient string
+}
@@ -0,0 +1,107 @@
+package lookup
+
+import (
+	"context"
+	"encoding/base64"
+	"strings"
+
+	"github.com/semih-yildiz/notification-service/internal/application/audience/port"
+	"github.com/semih-yildiz/notification-service/internal/domain/audience"
+	"github.com/semih-yildiz/notification-service/internal/domain/notification"
+)
+
+const (
+	defaultMembersLimit = 100
+	maxMembersLimit     = 1000
+)
+
+type UseCase struct {
+	repo port.AudienceRepository
+}
+
+func NewUseCase(repo port.AudienceRepository) *UseCase {
+	return &UseCase{repo: repo}
+}
+
+// MembersQuery pages through an audience's members, ordered by channel then recipient.
+type MembersQuery struct {
+	AudienceID string
+	Channel    string // optional
+	Cursor     string // next_cursor of the previous page
+	Limit      int    // default 100, at most 1000
+}
+
+// MembersPage is one page of members.
+type MembersPage struct {
+	Members    []audience.Member
+	NextCursor string // empty on the last page
+}
+
+// Audience returns one audience.
+func (u *UseCase) Audience(ctx context.Context, id string) (*audience.Audience, error) {
+	return u.repo.GetByID(ctx, id)
+}
+
+// List returns the tenant's audiences.
+func (u *UseCase) List(ctx context.Context) ([]*audience.Audience, error) {
+	return u.repo.List(ctx)
+}
+
+// Members returns a page of members, suppressed ones included.
+func (u *UseCase) Members(ctx context.Context, q *MembersQuery) (*MembersPage, error) {
+	limit := q.Limit
+	if limit <= 0 {
+		limit = defaultMembersLimit
+	}
+	if limit > maxMembersLimit {
+		limit = maxMembersLimit
+	}
+	filter := port.MemberFilter{Limit: limit + 1}
+	if q.Channel != "" {
+		ch := notification.Channel(q.Channel)
+		if !ch.Valid() {
+			return nil, notification.ErrInvalidChannel
+		}
+		filter.Channels = []notification.Channel{ch}
+	}
+	if q.Cursor != "" {
+		after, err := decodeCursor(q.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		filter.After = after
+	}
+
+	if _, err := u.repo.GetByID(ctx, q.AudienceID); err != nil {
+		return nil, err
+	}
+	members, err := u.repo.ListMembers(ctx, q.AudienceID, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &MembersPage{Members: members}
+	if len(members) > limit {
+		page.Members = members[:limit]
+		last := page.Members[limit-1]
+		page.NextCursor = encodeCursor(&port.MemberCursor{Channel: last.Channel, Recipient: last.Recipient})
+	}
+	return page, nil
+}
+
+// encodeCursor makes an opaque cursor; clients must not rely on its format.
+func encodeCursor(c *port.MemberCursor) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(c.Channel.String() + ":" + c.Recipient))
+}
+
+func decodeCursor(s string) (*port.MemberCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, notification.ErrInvalidCursor
+	}
+	ch, recipient, ok := strings.Cut(string(raw), ":")
+	if !ok || !notification.Channel(ch).Valid() || recipient == "" {
+		return nil, notification.ErrInvalidCursor
+	}
+	return &port.MemberCursor{Channel: notification.Channel(ch), Recipient: recipient}, nil
+}
@@ -0,0 +1,17 @@
+package apikey
+
+import "github.com/semih-yildiz/notification-service/internal/domain/auth"
+
+// IssueCommand creates a new API client and its first key.
+type IssueCommand struct {
+	Name     string
+	Scopes   []string
+	TenantID string       // tenant the client acts for; empty means the issuer's
+	Issuer   *auth.Client // admin issuing the key
+}
+
+// RotateCommand replaces a client's key.
+type RotateCommand struct {
+	ClientID string
+	Issuer   *auth.Client // admin rotating the key
+}
@@ -0,0 +1,128 @@
+package apikey
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/semih-yildiz/notification-service/internal/application/auth/port"
+	tenantport "github.com/semih-yildiz/notification-service/internal/application/tenant/port"
+	"github.com/semih-yildiz/notification-service/internal/domain/auth"
+	"github.com/semih-yildiz/notification-service/internal/domain/tenant"
+)
+
+const maxNameLength = 100
+
+type UseCase struct {
+	repo    port.ClientRepository
+	tenants tenantport.TenantRepository
+}
+
+func NewUseCase(repo port.ClientRepository, tenants tenantport.TenantRepository) *UseCase {
+	return &UseCase{repo: repo, tenants: tenants}
+}
+
+// Result carries a client and its plaintext key. The key is only available here.
+type Result struct {
+	Client *auth.Client
+	Key    string
+}
+
+// Issue creates a client with the given scopes and returns its key. Admins may only
+// issue keys into their own tenant, and only platform clients may grant ScopePlatform.
+func (u *UseCase) Issue(ctx context.Context, cmd *IssueCommand) (*Result, error) {
+	name := strings.TrimSpace(cmd.Name)
+	if name == "" || len(name) > maxNameLength {
+		return nil, auth.ErrInvalidName
+	}
+	scopes, err := auth.ParseScopes(cmd.Scopes)
+	if err != nil {
+		return nil, err
+	}
+	if cmd.Issuer == nil {
+		return nil, auth.ErrForbidden
+	}
+	tenantID := cmd.TenantID
+	if tenantID == "" {
+		tenantID = cmd.Issuer.TenantID
+	}
+	if !cmd.Issuer.CanManageTenant(tenantID) || (slices.Contains(scopes, auth.ScopePlatform) && !cmd.Issuer.Can(auth.ScopePlatform)) {
+		return nil, auth.ErrForbidden
+	}
+	if _, err := u.tenants.GetByID(ctx, tenantID); err != nil {
+		return nil, err
+	}
+	key, prefix, err := auth.GenerateKey()
+	if err != nil {
+		return nil, err
+	}
+
+	c := &auth.Client{
+		ID:        uuid.New().String(),
+		TenantID:  tenantID,
+		Name:      name,
+		KeyPrefix: prefix,
+		KeyHash:   auth.HashKey(key),
+		Scopes:    scopes,
+		CreatedAt: time.Now(),
+	}
+	if err := u.repo.Create(ctx, c); err != nil {
+		return nil, err
+	}
+	return &Result{Client: c, Key: key}, nil
+}
+
+// Rotate issues a new key for a client and revokes the old one. Only platform
+// clients may rotate the key of a platform client.
+func (u *UseCase) Rotate(ctx context.Context, cmd *RotateCommand) (*Result, error) {
+	c, err := u.repo.GetByID(ctx, cmd.ClientID)
+	if err != nil {
+		return nil, err
+	}
+	if cmd.Issuer == nil || !cmd.Issuer.CanManageTenant(c.TenantID) || (c.Can(auth.ScopePlatform) && !cmd.Issuer.Can(auth.ScopePlatform)) {
+		return nil, auth.ErrForbidden
+	}
+	key, prefix, err := auth.GenerateKey()
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if err := u.repo.UpdateKey(ctx, c.ID, prefix, auth.HashKey(key), now); err != nil {
+		return nil, err
+	}
+	c.KeyPrefix = prefix
+	c.KeyHash = auth.HashKey(key)
+	c.RotatedAt = &now
+	return &Result{Client: c, Key: key}, nil
+}
+
+// EnsureAdmin registers key as a platform client named name unless it already
+// exists. It bootstraps the first admin from configuration.
+func (u *UseCase) EnsureAdmin(ctx context.Context, name, key string) error {
+	hash := auth.HashKey(key)
+	c, err := u.repo.GetByKeyHash(ctx, hash)
+	if err == nil && !c.Can(auth.ScopePlatform) {
+		// Bootstrapped before the platform scope existed.
+		return u.repo.UpdateScopes(ctx, c.ID, append(c.Scopes, auth.ScopePlatform))
+	}
+	if err == nil || !errors.Is(err, auth.ErrNotFound) {
+		return err
+	}
+	prefix := key
+	if len(prefix) > auth.KeyPrefixLength {
+		prefix = prefix[:auth.KeyPrefixLength]
+	}
+	return u.repo.Create(ctx, &auth.Client{
+		ID:        uuid.New().String(),
+		TenantID:  tenant.DefaultID,
+		Name:      name,
+		KeyPrefix: prefix,
+		KeyHash:   hash,
+		Scopes:    []auth.Scope{auth.ScopePlatform},
+		CreatedAt: time.Now(),
+	})
+}
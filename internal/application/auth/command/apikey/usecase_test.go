@@ -0,0 +1,241 @@
+package apikey
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/semih-yildiz/notification-service/internal/domain/auth"
+	"github.com/semih-yildiz/notification-service/internal/domain/notification"
+	"github.com/semih-yildiz/notification-service/internal/domain/tenant"
+)
+
+type mockClientRepo struct {
+	clients map[string]*auth.Client
+}
+
+func newMockClientRepo() *mockClientRepo {
+	return &mockClientRepo{clients: make(map[string]*auth.Client)}
+}
+
+func (m *mockClientRepo) Create(ctx context.Context, c *auth.Client) error {
+	m.clients[c.ID] = c
+	return nil
+}
+
+func (m *mockClientRepo) GetByID(ctx context.Context, id string) (*auth.Client, error) {
+	if c, ok := m.clients[id]; ok {
+		cp := *c
+		return &cp, nil
+	}
+	return nil, auth.ErrNotFound
+}
+
+func (m *mockClientRepo) GetByKeyHash(ctx context.Context, hash string) (*auth.Client, error) {
+	for _, c := range m.clients {
+		if c.KeyHash == hash {
+			return c, nil
+		}
+	}
+	return nil, auth.ErrNotFound
+}
+
+func (m *mockClientRepo) List(ctx context.Context) ([]*auth.Client, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *mockClientRepo) UpdateKey(ctx context.Context, id, keyPrefix, keyHash string, rotatedAt time.Time) error {
+	c, ok := m.clients[id]
+	if !ok {
+		return auth.ErrNotFound
+	}
+	c.KeyPrefix, c.KeyHash, c.RotatedAt = keyPrefix, keyHash, &rotatedAt
+	return nil
+}
+
+func (m *mockClientRepo) UpdateScopes(ctx context.Context, id string, scopes []auth.Scope) error {
+	c, ok := m.clients[id]
+	if !ok {
+		return auth.ErrNotFound
+	}
+	c.Scopes = scopes
+	return nil
+}
+
+var (
+	defaultAdmin = &auth.Client{ID: "admin", TenantID: tenant.DefaultID, Scopes: []auth.Scope{auth.ScopeAdmin}}
+	acmeAdmin    = &auth.Client{ID: "acme-admin", TenantID: "acme", Scopes: []auth.Scope{auth.ScopeAdmin}}
+	platform     = &auth.Client{ID: "platform", TenantID: tenant.DefaultID, Scopes: []auth.Scope{auth.ScopePlatform}}
+)
+
+type mockTenantRepo struct{}
+
+func (m *mockTenantRepo) Create(ctx context.Context, t *tenant.Tenant) error { return nil }
+
+func (m *mockTenantRepo) GetByID(ctx context.Context, id string) (*tenant.Tenant, error) {
+	if id == tenant.DefaultID || id == "acme" {
+		return &tenant.Tenant{ID: id}, nil
+	}
+	return nil, tenant.ErrNotFound
+}
+
+func (m *mockTenantRepo) List(ctx context.Context) ([]*tenant.Tenant, error) { return nil, nil }
+
+func (m *mockTenantRepo) SetQuotas(ctx context.Context, id string, quotas map[notification.Channel]int) error {
+	return nil
+}
+
+func (m *mockTenantRepo) SetDeliveryWindows(ctx context.Context, id string, windows map[string]notification.DeliveryWindow) error {
+	return nil
+}
+
+func (m *mockTenantRepo) SetDigestPolicies(ctx context.Context, id string, policies map[string]notification.DigestPolicy) error {
+	return nil
+}
+
+func TestIssue_Success(t *testing.T) {
+	repo := newMockClientRepo()
+	uc := NewUseCase(repo, &mockTenantRepo{})
+
+	result, err := uc.Issue(context.Background(), &IssueCommand{Name: "billing", Scopes: []string{"send:sms", "read"}, Issuer: defaultAdmin})
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if result.Key == "" || result.Client.KeyHash != auth.HashKey(result.Key) {
+		t.Error("expected the stored hash to match the returned key")
+	}
+	if len(repo.clients) != 1 {
+		t.Errorf("expected 1 client stored, got %d", len(repo.clients))
+	}
+	if result.Client.TenantID != tenant.DefaultID {
+		t.Errorf("expected default tenant, got %s", result.Client.TenantID)
+	}
+}
+
+func TestIssue_Tenant(t *testing.T) {
+	uc := NewUseCase(newMockClientRepo(), &mockTenantRepo{})
+
+	result, err := uc.Issue(context.Background(), &IssueCommand{Name: "billing", Scopes: []string{"read"}, TenantID: "acme", Issuer: platform})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if result.Client.TenantID != "acme" {
+		t.Errorf("expected tenant acme, got %s", result.Client.TenantID)
+	}
+
+	result, err = uc.Issue(context.Background(), &IssueCommand{Name: "billing", Scopes: []string{"admin"}, Issuer: acmeAdmin})
+	if err != nil || result.Client.TenantID != "acme" {
+		t.Errorf("expected a key in the issuer's tenant, got %+v (err %v)", result, err)
+	}
+
+	if _, err := uc.Issue(context.Background(), &IssueCommand{Name: "billing", Scopes: []string{"read"}, TenantID: "missing", Issuer: platform}); !errors.Is(err, tenant.ErrNotFound) {
+		t.Errorf("expected tenant.ErrNotFound, got %v", err)
+	}
+}
+
+func TestIssue_TenantAdminLimits(t *testing.T) {
+	uc := NewUseCase(newMockClientRepo(), &mockTenantRepo{})
+
+	tests := []struct {
+		name string
+		cmd  *IssueCommand
+	}{
+		{"Another tenant", &IssueCommand{Name: "billing", Scopes: []string{"read"}, TenantID: tenant.DefaultID, Issuer: acmeAdmin}},
+		{"Platform scope", &IssueCommand{Name: "billing", Scopes: []string{"platform"}, Issuer: acmeAdmin}},
+		{"No issuer", &IssueCommand{Name: "billing", Scopes: []string{"read"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := uc.Issue(context.Background(), tt.cmd); !errors.Is(err, auth.ErrForbidden) {
+				t.Errorf("expected ErrForbidden, got %v", err)
+			}
+		})
+	}
+}
+
+func TestIssue_Validation(t *testing.T) {
+	uc := NewUseCase(newMockClientRepo(), &mockTenantRepo{})
+
+	if _, err := uc.Issue(context.Background(), &IssueCommand{Name: " ", Scopes: []string{"read"}, Issuer: defaultAdmin}); !errors.Is(err, auth.ErrInvalidName) {
+		t.Errorf("expected ErrInvalidName, got %v", err)
+	}
+	if _, err := uc.Issue(context.Background(), &IssueCommand{Name: "billing", Scopes: []string{"root"}, Issuer: defaultAdmin}); !errors.Is(err, auth.ErrInvalidScope) {
+		t.Errorf("expected ErrInvalidScope, got %v", err)
+	}
+}
+
+func TestRotate_ReplacesKey(t *testing.T) {
+	repo := newMockClientRepo()
+	uc := NewUseCase(repo, &mockTenantRepo{})
+
+	issued, _ := uc.Issue(context.Background(), &IssueCommand{Name: "billing", Scopes: []string{"read"}, Issuer: defaultAdmin})
+	rotated, err := uc.Rotate(context.Background(), &RotateCommand{ClientID: issued.Client.ID, Issuer: defaultAdmin})
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if rotated.Key == issued.Key {
+		t.Error("expected a new key")
+	}
+	if _, err := repo.GetByKeyHash(context.Background(), auth.HashKey(issued.Key)); !errors.Is(err, auth.ErrNotFound) {
+		t.Error("expected the old key to be revoked")
+	}
+	if rotated.Client.RotatedAt == nil {
+		t.Error("expected RotatedAt to be set")
+	}
+}
+
+func TestRotate_NotFound(t *testing.T) {
+	uc := NewUseCase(newMockClientRepo(), &mockTenantRepo{})
+
+	if _, err := uc.Rotate(context.Background(), &RotateCommand{ClientID: "missing", Issuer: defaultAdmin}); !errors.Is(err, auth.ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestRotate_PlatformKey(t *testing.T) {
+	repo := newMockClientRepo()
+	uc := NewUseCase(repo, &mockTenantRepo{})
+	issued, _ := uc.Issue(context.Background(), &IssueCommand{Name: "ops", Scopes: []string{"platform"}, Issuer: platform})
+
+	if _, err := uc.Rotate(context.Background(), &RotateCommand{ClientID: issued.Client.ID, Issuer: defaultAdmin}); !errors.Is(err, auth.ErrForbidden) {
+		t.Errorf("expected ErrForbidden, got %v", err)
+	}
+	if _, err := uc.Rotate(context.Background(), &RotateCommand{ClientID: issued.Client.ID, Issuer: platform}); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+}
+
+func TestEnsureAdmin_Idempotent(t *testing.T) {
+	repo := newMockClientRepo()
+	uc := NewUseCase(repo, &mockTenantRepo{})
+
+	for i := 0; i < 2; i++ {
+		if err := uc.EnsureAdmin(context.Background(), "bootstrap-admin", "secret-admin-key"); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	}
+	if len(repo.clients) != 1 {
+		t.Errorf("expected 1 admin client, got %d", len(repo.clients))
+	}
+	for _, c := range repo.clients {
+		if !c.Can(auth.ScopePlatform) {
+			t.Error("expected platform scope")
+		}
+	}
+}
+
+func TestEnsureAdmin_GrantsPlatformToExistingAdmin(t *testing.T) {
+	repo := newMockClientRepo()
+	repo.clients["old"] = &auth.Client{ID: "old", TenantID: tenant.DefaultID, KeyHash: auth.HashKey("secret-admin-key"), Scopes: []auth.Scope{auth.ScopeAdmin}}
+	uc := NewUseCase(repo, &mockTenantRepo{})
+
+	if err := uc.EnsureAdmin(context.Background(), "bootstrap-admin", "secret-admin-key"); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(repo.clients) != 1 || !repo.clients["old"].Can(auth.ScopePlatform) {
+		t.Errorf("expected the existing admin to get the platform scope, got %+v", repo.clients)
+	}
+}
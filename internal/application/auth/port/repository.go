@@ -0,0 +1,19 @@
+package port
+
+import (
+	"context"
+	"time"
+
+	"github.com/semih-yildiz/notification-service/internal/domain/auth"
+)
+
+type ClientRepository interface {
+	Create(ctx context.Context, c *auth.Client) error
+	GetByID(ctx context.Context, id string) (*auth.Client, error)
+	GetByKeyHash(ctx context.Context, hash string) (*auth.Client, error)
+	List(ctx context.Context) ([]*auth.Client, error)
+	// UpdateKey replaces the client's key; the previous key stops working immediately.
+	UpdateKey(ctx context.Context, id, keyPrefix, keyHash string, rotatedAt time.Time) error
+	// UpdateScopes replaces the client's scopes.
+	UpdateScopes(ctx context.Context, id string, scopes []auth.Scope) error
+}
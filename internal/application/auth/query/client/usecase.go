@@ -0,0 +1,34 @@
+package client
+
+import (
+	"context"
+	"errors"
+
+	"github.com/semih-yildiz/notification-service/internal/application/auth/port"
+	"github.com/semih-yildiz/notification-service/internal/domain/auth"
+)
+
+type UseCase struct {
+	repo port.ClientRepository
+}
+
+func NewUseCase(repo port.ClientRepository) *UseCase {
+	return &UseCase{repo: repo}
+}
+
+// Authenticate resolves an API key to its client.
+func (u *UseCase) Authenticate(ctx context.Context, key string) (*auth.Client, error) {
+	if key == "" {
+		return nil, auth.ErrInvalidKey
+	}
+	c, err := u.repo.GetByKeyHash(ctx, auth.HashKey(key))
+	if errors.Is(err, auth.ErrNotFound) {
+		return nil, auth.ErrInvalidKey
+	}
+	return c, err
+}
+
+// List returns all API clients.
+func (u *UseCase) List(ctx context.Context) ([]*auth.Client, error) {
+	return u.repo.List(ctx)
+}
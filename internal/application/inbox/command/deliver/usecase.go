@@ -0,0 +1,80 @@
+package deliver
+
+import (
+	"context"
+	"time"
+
+	"github.com/semih-yildiz/notification-service/internal/application/inbox/port"
+	notifport "github.com/semih-yildiz/notification-service/internal/application/notification/port"
+	"github.com/semih-yildiz/notification-service/internal/domain/inbox"
+	"github.com/semih-yildiz/notification-service/internal/domain/notification"
+	sharedctx "github.com/semih-yildiz/notification-service/internal/shared/context"
+)
+
+var _ notifport.InboxDelivery = (*UseCase)(nil)
+
+// UseCase is the worker's delivery of in-app notifications: it stores them in the
+// recipient's inbox and tells the recipient's connected clients.
+type UseCase struct {
+	repo   port.InboxRepository
+	log    notifport.Logger
+	events port.EventBroadcaster // optional; nil disables live inbox updates
+}
+
+func NewUseCase(repo port.InboxRepository, log notifport.Logger) *UseCase {
+	return &UseCase{repo: repo, log: log}
+}
+
+// WithEvents broadcasts every delivered message to the user's live subscribers.
+func (u *UseCase) WithEvents(events port.EventBroadcaster) *UseCase {
+	u.events = events
+	return u
+}
+
+// Deliver adds n to the inbox of the user it is addressed to, as unread.
+func (u *UseCase) Deliver(ctx context.Context, n *notification.Notification) (string, error) {
+	if spec, _ := notification.LookupChannel(n.Channel); spec.Provider != notification.ProviderInbox {
+		return "", notification.ErrInvalidChannel
+	}
+	// The worker is unscoped, but user IDs are only unique within a tenant.
+	ctx = sharedctx.WithTenantID(ctx, n.TenantID)
+
+	now := time.Now()
+	m := &inbox.Message{
+		ID:        n.ID,
+		TenantID:  n.TenantID,
+		UserID:    n.Recipient,
+		Content:   n.Content,
+		State:     inbox.StateUnread,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if n.Resolution != nil {
+		m.Category = n.Resolution.Category
+	}
+	added, err := u.repo.Add(ctx, m)
+	if err != nil {
+		return "", err
+	}
+	if !added {
+		u.log.Info(ctx, "notification already in the inbox", notifport.F("notification_id", n.ID))
+		return m.ID, nil
+	}
+	u.broadcast(ctx, m)
+	return m.ID, nil
+}
+
+// broadcast publishes the new message; failures only cost live clients an update.
+func (u *UseCase) broadcast(ctx context.Context, m *inbox.Message) {
+	if u.events == nil {
+		return
+	}
+	unread, err := u.repo.CountUnread(ctx, m.UserID)
+	if err != nil {
+		u.log.Warn(ctx, "failed to count unread inbox messages", notifport.F("error", err), notifport.F("user_id", m.UserID))
+		return
+	}
+	if err := u.events.Broadcast(ctx, &port.Event{Kind: port.EventMessage, Message: m, Unread: unread}); err != nil {
+		u.log.Warn(ctx, "failed to broadcast inbox event", notifport.F("error", err), notifport.F("notification_id", m.ID))
+	}
+}
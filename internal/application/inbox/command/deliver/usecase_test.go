@@ -0,0 +1,109 @@
+package deliver
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/semih-yildiz/notification-service/internal/application/inbox/port"
+	notifport "github.com/semih-yildiz/notification-service/internal/application/notification/port"
+	"github.com/semih-yildiz/notification-service/internal/domain/inbox"
+	"github.com/semih-yildiz/notification-service/internal/domain/notification"
+	sharedctx "github.com/semih-yildiz/notification-service/internal/shared/context"
+)
+
+type mockInboxRepo struct {
+	messages map[string]*inbox.Message
+	tenantID string // tenant of the last call's context
+}
+
+func (m *mockInboxRepo) Add(ctx context.Context, msg *inbox.Message) (bool, error) {
+	m.tenantID, _ = sharedctx.TenantID(ctx)
+	if _, ok := m.messages[msg.ID]; ok {
+		return false, nil
+	}
+	m.messages[msg.ID] = msg
+	return true, nil
+}
+
+func (m *mockInboxRepo) GetByID(ctx context.Context, userID, id string) (*inbox.Message, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *mockInboxRepo) Update(ctx context.Context, msg *inbox.Message) error {
+	return errors.New("not implemented")
+}
+
+func (m *mockInboxRepo) List(ctx context.Context, userID string, filter port.MessageFilter) ([]*inbox.Message, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *mockInboxRepo) CountUnread(ctx context.Context, userID string) (int64, error) {
+	var n int64
+	for _, msg := range m.messages {
+		if msg.UserID == userID && msg.State == inbox.StateUnread {
+			n++
+		}
+	}
+	return n, nil
+}
+
+type mockBroadcaster struct {
+	events []*port.Event
+}
+
+func (m *mockBroadcaster) Broadcast(ctx context.Context, evt *port.Event) error {
+	m.events = append(m.events, evt)
+	return nil
+}
+
+type mockLogger struct{}
+
+func (m *mockLogger) Info(ctx context.Context, msg string, fields ...notifport.Field)  {}
+func (m *mockLogger) Warn(ctx context.Context, msg string, fields ...notifport.Field)  {}
+func (m *mockLogger) Error(ctx context.Context, msg string, fields ...notifport.Field) {}
+
+func TestDeliver(t *testing.T) {
+	repo := &mockInboxRepo{messages: make(map[string]*inbox.Message)}
+	events := &mockBroadcaster{}
+	uc := NewUseCase(repo, &mockLogger{}).WithEvents(events)
+	n := &notification.Notification{
+		ID:         "n-1",
+		TenantID:   "acme",
+		Recipient:  "u-1",
+		Channel:    notification.ChannelInApp,
+		Content:    "Your order shipped",
+		Resolution: &notification.Resolution{UserID: "u-1", Category: "orders"},
+	}
+
+	id, err := uc.Deliver(context.Background(), n)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	m := repo.messages["n-1"]
+	if id != "n-1" || m == nil || m.UserID != "u-1" || m.State != inbox.StateUnread || m.Category != "orders" {
+		t.Fatalf("expected an unread message for u-1, got %s %+v", id, m)
+	}
+	if repo.tenantID != "acme" {
+		t.Errorf("expected the repository scoped to the notification's tenant, got %q", repo.tenantID)
+	}
+	if len(events.events) != 1 || events.events[0].Kind != port.EventMessage || events.events[0].Unread != 1 {
+		t.Fatalf("expected one message event with 1 unread, got %+v", events.events)
+	}
+
+	// A redelivered notification is not added or announced again.
+	if _, err := uc.Deliver(context.Background(), n); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(events.events) != 1 {
+		t.Errorf("expected no event for a redelivery, got %d", len(events.events))
+	}
+}
+
+func TestDeliver_OtherChannel(t *testing.T) {
+	repo := &mockInboxRepo{messages: make(map[string]*inbox.Message)}
+	_, err := NewUseCase(repo, &mockLogger{}).Deliver(context.Background(), &notification.Notification{ID: "n-1", Channel: notification.ChannelEmail})
+	if !errors.Is(err, notification.ErrInvalidChannel) {
+		t.Errorf("expected ErrInvalidChannel, got %v", err)
+	}
+}
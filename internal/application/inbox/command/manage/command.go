@@ -0,0 +1,8 @@
+package manage
+
+// MarkCommand moves a message of the user's inbox to another state.
+type MarkCommand struct {
+	UserID    string
+	MessageID string
+	State     string // unread, read or archived
+}
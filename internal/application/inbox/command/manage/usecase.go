@@ -0,0 +1,63 @@
+package manage
+
+import (
+	"context"
+	"time"
+
+	"github.com/semih-yildiz/notification-service/internal/application/inbox/port"
+	notifport "github.com/semih-yildiz/notification-service/internal/application/notification/port"
+	"github.com/semih-yildiz/notification-service/internal/domain/inbox"
+)
+
+type UseCase struct {
+	repo   port.InboxRepository
+	log    notifport.Logger
+	events port.EventBroadcaster // optional; nil disables live inbox updates
+}
+
+func NewUseCase(repo port.InboxRepository, log notifport.Logger) *UseCase {
+	return &UseCase{repo: repo, log: log}
+}
+
+// WithEvents broadcasts every state change to the user's live subscribers.
+func (u *UseCase) WithEvents(events port.EventBroadcaster) *UseCase {
+	u.events = events
+	return u
+}
+
+// Mark marks a message read, unread or archived. Marking it with its current
+// state changes nothing.
+func (u *UseCase) Mark(ctx context.Context, cmd *MarkCommand) (*inbox.Message, error) {
+	state := inbox.State(cmd.State)
+	if !state.Valid() {
+		return nil, inbox.ErrInvalidState
+	}
+	m, err := u.repo.GetByID(ctx, cmd.UserID, cmd.MessageID)
+	if err != nil {
+		return nil, err
+	}
+	changed, err := m.Mark(state, time.Now())
+	if err != nil || !changed {
+		return m, err
+	}
+	if err := u.repo.Update(ctx, m); err != nil {
+		return nil, err
+	}
+	u.broadcast(ctx, m)
+	return m, nil
+}
+
+// broadcast publishes the change; failures only cost live clients an update.
+func (u *UseCase) broadcast(ctx context.Context, m *inbox.Message) {
+	if u.events == nil {
+		return
+	}
+	unread, err := u.repo.CountUnread(ctx, m.UserID)
+	if err != nil {
+		u.log.Warn(ctx, "failed to count unread inbox messages", notifport.F("error", err), notifport.F("user_id", m.UserID))
+		return
+	}
+	if err := u.events.Broadcast(ctx, &port.Event{Kind: port.EventState, Message: m, Unread: unread}); err != nil {
+		u.log.Warn(ctx, "failed to broadcast inbox event", notifport.F("error", err), notifport.F("message_id", m.ID))
+	}
+}
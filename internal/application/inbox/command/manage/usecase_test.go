@@ -0,0 +1,116 @@
+package manage
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/semih-yildiz/notification-service/internal/application/inbox/port"
+	notifport "github.com/semih-yildiz/notification-service/internal/application/notification/port"
+	"github.com/semih-yildiz/notification-service/internal/domain/inbox"
+)
+
+type mockInboxRepo struct {
+	messages map[string]*inbox.Message
+	updates  int
+}
+
+func (m *mockInboxRepo) Add(ctx context.Context, msg *inbox.Message) (bool, error) {
+	return false, errors.New("not implemented")
+}
+
+func (m *mockInboxRepo) GetByID(ctx context.Context, userID, id string) (*inbox.Message, error) {
+	if msg, ok := m.messages[id]; ok && msg.UserID == userID {
+		copied := *msg
+		return &copied, nil
+	}
+	return nil, inbox.ErrNotFound
+}
+
+func (m *mockInboxRepo) Update(ctx context.Context, msg *inbox.Message) error {
+	m.updates++
+	m.messages[msg.ID] = msg
+	return nil
+}
+
+func (m *mockInboxRepo) List(ctx context.Context, userID string, filter port.MessageFilter) ([]*inbox.Message, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *mockInboxRepo) CountUnread(ctx context.Context, userID string) (int64, error) {
+	var n int64
+	for _, msg := range m.messages {
+		if msg.UserID == userID && msg.State == inbox.StateUnread {
+			n++
+		}
+	}
+	return n, nil
+}
+
+type mockBroadcaster struct {
+	events []*port.Event
+}
+
+func (m *mockBroadcaster) Broadcast(ctx context.Context, evt *port.Event) error {
+	m.events = append(m.events, evt)
+	return nil
+}
+
+type mockLogger struct{}
+
+func (m *mockLogger) Info(ctx context.Context, msg string, fields ...notifport.Field)  {}
+func (m *mockLogger) Warn(ctx context.Context, msg string, fields ...notifport.Field)  {}
+func (m *mockLogger) Error(ctx context.Context, msg string, fields ...notifport.Field) {}
+
+func newRepo() *mockInboxRepo {
+	return &mockInboxRepo{messages: map[string]*inbox.Message{
+		"m-1": {ID: "m-1", UserID: "u-1", State: inbox.StateUnread},
+		"m-2": {ID: "m-2", UserID: "u-1", State: inbox.StateUnread},
+	}}
+}
+
+func TestMark(t *testing.T) {
+	repo := newRepo()
+	events := &mockBroadcaster{}
+	uc := NewUseCase(repo, &mockLogger{}).WithEvents(events)
+
+	m, err := uc.Mark(context.Background(), &MarkCommand{UserID: "u-1", MessageID: "m-1", State: "read"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if m.State != inbox.StateRead || m.ReadAt == nil || repo.messages["m-1"].State != inbox.StateRead {
+		t.Fatalf("expected m-1 read, got %+v", m)
+	}
+	if len(events.events) != 1 || events.events[0].Kind != port.EventState || events.events[0].Unread != 1 {
+		t.Fatalf("expected one state event with 1 unread, got %+v", events.events)
+	}
+
+	// Marking it read again is a no-op.
+	if _, err := uc.Mark(context.Background(), &MarkCommand{UserID: "u-1", MessageID: "m-1", State: "read"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if repo.updates != 1 || len(events.events) != 1 {
+		t.Errorf("expected no update or event for an unchanged state, got %d updates and %d events", repo.updates, len(events.events))
+	}
+}
+
+func TestMark_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		cmd     MarkCommand
+		wantErr error
+	}{
+		{"Invalid state", MarkCommand{UserID: "u-1", MessageID: "m-1", State: "deleted"}, inbox.ErrInvalidState},
+		{"Unknown message", MarkCommand{UserID: "u-1", MessageID: "m-9", State: "read"}, inbox.ErrNotFound},
+		{"Another user's message", MarkCommand{UserID: "u-2", MessageID: "m-1", State: "read"}, inbox.ErrNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewUseCase(newRepo(), &mockLogger{}).Mark(context.Background(), &tt.cmd)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
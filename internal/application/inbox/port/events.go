@@ -0,0 +1,35 @@
+package port
+
+import (
+	"context"
+
+	"github.com/semih-yildiz/notification-service/internal/domain/inbox"
+)
+
+// EventKind is what changed in an inbox.
+type EventKind string
+
+const (
+	EventMessage EventKind = "message" // a message was delivered
+	EventState   EventKind = "state"   // a message was marked read, unread or archived
+)
+
+// Event is a change to one user's inbox. Unread is the user's unread count after
+// the change.
+type Event struct {
+	Kind    EventKind
+	Message *inbox.Message
+	Unread  int64
+}
+
+// EventBroadcaster fans inbox changes out to every API replica. Broadcasting is
+// best effort: a lost event only delays a client until it reloads the inbox.
+type EventBroadcaster interface {
+	Broadcast(ctx context.Context, evt *Event) error
+}
+
+// EventSubscriber delivers the changes to one user's inbox. The returned channel
+// is closed once ctx is done. Events broadcast after the call returns are not missed.
+type EventSubscriber interface {
+	Subscribe(ctx context.Context, tenantID, userID string) (<-chan *Event, error)
+}
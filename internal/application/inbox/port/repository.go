@@ -0,0 +1,34 @@
+package port
+
+import (
+	"context"
+	"time"
+
+	"github.com/semih-yildiz/notification-service/internal/domain/inbox"
+)
+
+// InboxRepository stores users' in-app messages. User IDs are only unique within a
+// tenant, so it is meant for tenant-scoped contexts.
+type InboxRepository interface {
+	// Add stores m unless its notification is already in the inbox. It reports
+	// whether m was added.
+	Add(ctx context.Context, m *inbox.Message) (bool, error)
+	GetByID(ctx context.Context, userID, id string) (*inbox.Message, error)
+	// Update saves the message's state and its read, archived and update times.
+	Update(ctx context.Context, m *inbox.Message) error
+	List(ctx context.Context, userID string, filter MessageFilter) ([]*inbox.Message, error)
+	CountUnread(ctx context.Context, userID string) (int64, error)
+}
+
+// MessageFilter pages through a user's messages, newest first.
+type MessageFilter struct {
+	States []inbox.State // any of
+	After  *MessageCursor
+	Limit  int
+}
+
+// MessageCursor is the last message of the previous page.
+type MessageCursor struct {
+	CreatedAt time.Time
+	ID        string
+}
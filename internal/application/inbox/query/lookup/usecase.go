@@ -0,0 +1,114 @@
+package lookup
+
+import (
+	"context"
+	"encoding/base64"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/semih-yildiz/notification-service/internal/application/inbox/port"
+	"github.com/semih-yildiz/notification-service/internal/domain/inbox"
+	"github.com/semih-yildiz/notification-service/internal/domain/notification"
+)
+
+const (
+	defaultMessagesLimit = 20
+	maxMessagesLimit     = 100
+)
+
+type UseCase struct {
+	repo port.InboxRepository
+}
+
+func NewUseCase(repo port.InboxRepository) *UseCase {
+	return &UseCase{repo: repo}
+}
+
+// MessagesQuery pages through a user's inbox, newest first.
+type MessagesQuery struct {
+	UserID string
+	State  string // optional; unread and read messages when empty
+	Cursor string // next_cursor of the previous page
+	Limit  int    // default 20, at most 100
+}
+
+// MessagesPage is one page of the inbox and the user's unread count.
+type MessagesPage struct {
+	Messages   []*inbox.Message
+	Unread     int64
+	NextCursor string // empty on the last page
+}
+
+// Messages returns a page of the user's inbox. Archived messages are only listed
+// when asked for.
+func (u *UseCase) Messages(ctx context.Context, q *MessagesQuery) (*MessagesPage, error) {
+	limit := q.Limit
+	if limit <= 0 {
+		limit = defaultMessagesLimit
+	}
+	if limit > maxMessagesLimit {
+		limit = maxMessagesLimit
+	}
+	filter := port.MessageFilter{
+		States: []inbox.State{inbox.StateUnread, inbox.StateRead},
+		Limit:  limit + 1,
+	}
+	if q.State != "" {
+		state := inbox.State(q.State)
+		if !state.Valid() {
+			return nil, inbox.ErrInvalidState
+		}
+		filter.States = []inbox.State{state}
+	}
+	if q.Cursor != "" {
+		after, err := decodeCursor(q.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		filter.After = after
+	}
+
+	messages, err := u.repo.List(ctx, q.UserID, filter)
+	if err != nil {
+		return nil, err
+	}
+	unread, err := u.repo.CountUnread(ctx, q.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &MessagesPage{Messages: messages, Unread: unread}
+	if len(messages) > limit {
+		page.Messages = messages[:limit]
+		last := page.Messages[limit-1]
+		page.NextCursor = encodeCursor(&port.MessageCursor{CreatedAt: last.CreatedAt, ID: last.ID})
+	}
+	return page, nil
+}
+
+// Unread returns how many unread messages the user has.
+func (u *UseCase) Unread(ctx context.Context, userID string) (int64, error) {
+	return u.repo.CountUnread(ctx, userID)
+}
+
+// encodeCursor makes an opaque cursor; clients must not rely on its format.
+func encodeCursor(c *port.MessageCursor) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatInt(c.CreatedAt.UnixNano(), 10) + ":" + c.ID))
+}
+
+func decodeCursor(s string) (*port.MessageCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, notification.ErrInvalidCursor
+	}
+	nanos, id, ok := strings.Cut(string(raw), ":")
+	if !ok || id == "" {
+		return nil, notification.ErrInvalidCursor
+	}
+	n, err := strconv.ParseInt(nanos, 10, 64)
+	if err != nil {
+		return nil, notification.ErrInvalidCursor
+	}
+	return &port.MessageCursor{CreatedAt: time.Unix(0, n).UTC(), ID: id}, nil
+}
@@ -0,0 +1,18 @@
+package bulk
+
+import (
+	"io"
+
+	"github.com/semih-yildiz/notification-service/internal/domain/notification"
+)
+
+// Command starts a bulk import of the rows in Body.
+type Command struct {
+	Format   notification.ImportFormat
+	Body     io.Reader
+	ClientID *string // API client making the request
+	TenantID string  // owning tenant; empty means the default tenant
+	// CanSend reports whether the client may send on a channel; rows on other
+	// channels are rejected.
+	CanSend func(notification.Channel) bool
+}
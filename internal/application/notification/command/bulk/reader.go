@@ -0,0 +1,136 @@
+package bulk
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/semih-yildiz/notification-service/internal/application/notification/command/create"
+	"github.com/semih-yildiz/notification-service/internal/domain/notification"
+)
+
+// row is one parsed row. Err is set instead of Item when the row cannot be read.
+type row struct {
+	Line int
+	Item create.BatchItem
+	Err  *notification.RowError
+}
+
+// rowReader reads the rows of an upload. next returns io.EOF after the last row;
+// any other error means the rest of the file cannot be read.
+type rowReader interface {
+	next() (row, error)
+}
+
+func newRowReader(format notification.ImportFormat, r io.Reader) (rowReader, error) {
+	switch format {
+	case notification.ImportFormatNDJSON:
+		return &ndjsonReader{r: bufio.NewReader(r)}, nil
+	case notification.ImportFormatCSV:
+		return newCSVReader(r)
+	default:
+		return nil, notification.ErrInvalidImportFormat
+	}
+}
+
+// ndjsonRow is one line of an NDJSON upload; the fields match a batch item.
+type ndjsonRow struct {
+	Recipient      string  `json:"recipient"`
+	Channel        string  `json:"channel"`
+	Content        string  `json:"content"`
+	Priority       string  `json:"priority"`
+	IdempotencyKey *string `json:"idempotency_key"`
+}
+
+type ndjsonReader struct {
+	r    *bufio.Reader
+	line int
+}
+
+func (n *ndjsonReader) next() (row, error) {
+	for {
+		b, err := n.r.ReadBytes('\n')
+		if len(b) == 0 && err != nil {
+			return row{}, err
+		}
+		n.line++
+		b = bytes.TrimSpace(b)
+		if len(b) == 0 {
+			continue // blank lines separate nothing
+		}
+		var v ndjsonRow
+		if jerr := json.Unmarshal(b, &v); jerr != nil {
+			return row{Line: n.line, Err: &notification.RowError{Row: n.line, Message: "row is not a JSON object"}}, nil
+		}
+		return row{Line: n.line, Item: create.BatchItem{
+			Recipient:      v.Recipient,
+			Channel:        v.Channel,
+			Content:        v.Content,
+			Priority:       v.Priority,
+			IdempotencyKey: v.IdempotencyKey,
+		}}, nil
+	}
+}
+
+// csvColumns are the columns a CSV upload may name in its header, in any order.
+var csvColumns = []string{"recipient", "channel", "content", "priority", "idempotency_key"}
+
+type csvReader struct {
+	r       *csv.Reader
+	columns map[string]int
+}
+
+func newCSVReader(r io.Reader) (*csvReader, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1 // checked per row against the header
+	header, err := cr.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, errors.New("csv file is empty")
+		}
+		return nil, fmt.Errorf("csv header: %w", err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+	for _, required := range csvColumns[:3] {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("csv header must name the columns %s", strings.Join(csvColumns[:3], ", "))
+		}
+	}
+	return &csvReader{r: cr, columns: columns}, nil
+}
+
+func (c *csvReader) next() (row, error) {
+	record, err := c.r.Read()
+	line, _ := c.r.FieldPos(0)
+	if err != nil {
+		var perr *csv.ParseError
+		if errors.As(err, &perr) {
+			return row{Line: perr.StartLine, Err: &notification.RowError{Row: perr.StartLine, Message: "row is not valid CSV"}}, nil
+		}
+		return row{}, err
+	}
+	get := func(name string) string {
+		if i, ok := c.columns[name]; ok && i < len(record) {
+			return record[i]
+		}
+		return ""
+	}
+	item := create.BatchItem{
+		Recipient: get("recipient"),
+		Channel:   get("channel"),
+		Content:   get("content"),
+		Priority:  get("priority"),
+	}
+	if key := get("idempotency_key"); key != "" {
+		item.IdempotencyKey = &key
+	}
+	return row{Line: line, Item: item}, nil
+}
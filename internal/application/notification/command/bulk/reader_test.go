@@ -0,0 +1,89 @@
+package bulk
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/semih-yildiz/notification-service/internal/domain/notification"
+)
+
+func readAll(t *testing.T, r rowReader) []row {
+	t.Helper()
+	var rows []row
+	for {
+		r, err := r.next()
+		if errors.Is(err, io.EOF) {
+			return rows
+		}
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		rows = append(rows, r)
+	}
+}
+
+func TestNDJSONReader(t *testing.T) {
+	body := `{"recipient":"+905551234567","channel":"sms","content":"hi","priority":"high"}
+
+not json
+{"recipient":"a@b.c","channel":"email","content":"hello","idempotency_key":"k-1"}`
+
+	r, err := newRowReader(notification.ImportFormatNDJSON, strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	rows := readAll(t, r)
+
+	if len(rows) != 3 {
+		t.Fatalf("expected 3 rows, got %d", len(rows))
+	}
+	if rows[0].Line != 1 || rows[0].Item.Channel != "sms" || rows[0].Item.Priority != "high" {
+		t.Errorf("unexpected first row: %+v", rows[0])
+	}
+	if rows[1].Line != 3 || rows[1].Err == nil || rows[1].Err.Row != 3 {
+		t.Errorf("expected an error on line 3, got %+v", rows[1])
+	}
+	if rows[2].Line != 4 || rows[2].Item.IdempotencyKey == nil || *rows[2].Item.IdempotencyKey != "k-1" {
+		t.Errorf("unexpected last row: %+v", rows[2])
+	}
+}
+
+func TestCSVReader(t *testing.T) {
+	body := "Channel,recipient,content,idempotency_key\n" +
+		"sms,+905551234567,hi,\n" +
+		"email,a@b.c,\"multi\nline\",k-1\n" +
+		"push,device,\"bad\"quote\n" +
+		"push,device-2,ok,\n"
+
+	r, err := newRowReader(notification.ImportFormatCSV, strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	rows := readAll(t, r)
+
+	if len(rows) != 4 {
+		t.Fatalf("expected 4 rows, got %+v", rows)
+	}
+	if rows[0].Line != 2 || rows[0].Item.Channel != "sms" || rows[0].Item.IdempotencyKey != nil {
+		t.Errorf("unexpected first row: %+v", rows[0])
+	}
+	if rows[1].Line != 3 || rows[1].Item.Content != "multi\nline" || *rows[1].Item.IdempotencyKey != "k-1" {
+		t.Errorf("unexpected second row: %+v", rows[1])
+	}
+	if rows[2].Err == nil || rows[2].Err.Row != 5 {
+		t.Errorf("expected an error on line 5, got %+v", rows[2])
+	}
+	if rows[3].Line != 6 || rows[3].Item.Recipient != "device-2" {
+		t.Errorf("unexpected last row: %+v", rows[3])
+	}
+}
+
+func TestCSVReader_MissingColumn(t *testing.T) {
+	_, err := newRowReader(notification.ImportFormatCSV, strings.NewReader("recipient,content\na,b\n"))
+
+	if err == nil {
+		t.Error("expected an error for a header without channel")
+	}
+}
@@ -0,0 +1,360 @@
+package bulk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/semih-yildiz/notification-service/internal/application/notification/command/create"
+	"github.com/semih-yildiz/notification-service/internal/application/notification/port"
+	"github.com/semih-yildiz/notification-service/internal/domain/auth"
+	"github.com/semih-yildiz/notification-service/internal/domain/notification"
+	"github.com/semih-yildiz/notification-service/internal/domain/tenant"
+)
+
+// DefaultMaxBytes is the default size limit of an uploaded file.
+const DefaultMaxBytes = 200 << 20
+
+// maxConcurrentJobs bounds how many imports are processed at once; further jobs
+// wait in pending.
+const maxConcurrentJobs = 4
+
+// A job is held by a lease that the process holding it renews every
+// heartbeatInterval. Jobs whose lease has expired were abandoned, e.g. by a process
+// that stopped, and are failed by whichever process notices first.
+const (
+	leaseTTL          = time.Minute
+	heartbeatInterval = leaseTTL / 4
+)
+
+const interruptedReason = "import was interrupted; upload the remaining rows again"
+
+// BatchCreator creates the batches of an import. It is implemented by create.UseCase.
+type BatchCreator interface {
+	CreateNotificationBatches(ctx context.Context, cmd *create.BatchCommand) (*create.BatchResult, error)
+}
+
+type UseCase struct {
+	jobs     port.ImportJobRepository
+	storage  port.ImportStorage
+	creator  BatchCreator
+	log      port.Logger
+	maxBytes int64
+	chunk    int
+	slots    chan struct{}
+	mu       sync.Mutex
+	held     map[string]struct{} // jobs this process renews the leases of
+	// spawn runs the processing of a job; tests replace it to run synchronously.
+	spawn func(func())
+}
+
+func NewUseCase(jobs port.ImportJobRepository, storage port.ImportStorage, creator BatchCreator, log port.Logger, maxBytes int64) *UseCase {
+	if maxBytes <= 0 {
+		maxBytes = DefaultMaxBytes
+	}
+	return &UseCase{
+		jobs:     jobs,
+		storage:  storage,
+		creator:  creator,
+		log:      log,
+		maxBytes: maxBytes,
+		chunk:    notification.MaxBatchSize,
+		slots:    make(chan struct{}, maxConcurrentJobs),
+		held:     make(map[string]struct{}),
+		spawn:    func(f func()) { go f() },
+	}
+}
+
+// Start stores the upload and returns its job right away, in pending. The rows are
+// created in the background in batches of at most MaxBatchSize.
+func (u *UseCase) Start(ctx context.Context, cmd *Command) (*notification.ImportJob, error) {
+	if !cmd.Format.Valid() {
+		return nil, notification.ErrInvalidImportFormat
+	}
+
+	now := time.Now()
+	lease := now.Add(leaseTTL)
+	job := &notification.ImportJob{
+		ID:             uuid.New().String(),
+		TenantID:       tenantOrDefault(cmd.TenantID),
+		ClientID:       cmd.ClientID,
+		Format:         cmd.Format,
+		Status:         notification.ImportStatusPending,
+		LeaseExpiresAt: &lease,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+
+	if err := u.storage.Save(ctx, job.ID, cmd.Body, u.maxBytes); err != nil {
+		if delErr := u.storage.Delete(ctx, job.ID); delErr != nil {
+			u.log.Warn(ctx, "failed to delete import file", port.F("error", delErr), port.F("import_id", job.ID))
+		}
+		if !errors.Is(err, notification.ErrImportTooLarge) {
+			u.log.Error(ctx, "failed to store import file", port.F("error", err), port.F("import_id", job.ID))
+		}
+		return nil, err
+	}
+	if err := u.jobs.Create(ctx, job); err != nil {
+		u.log.Error(ctx, "failed to create import job", port.F("error", err), port.F("import_id", job.ID))
+		if delErr := u.storage.Delete(ctx, job.ID); delErr != nil {
+			u.log.Warn(ctx, "failed to delete import file", port.F("error", delErr), port.F("import_id", job.ID))
+		}
+		return nil, err
+	}
+
+	u.log.Info(ctx, "import accepted", port.F("import_id", job.ID), port.F("format", job.Format.String()))
+
+	// The job outlives the request; keep its values (tenant, correlation ID) only.
+	bg := context.WithoutCancel(ctx)
+	queued := *job
+	canSend := cmd.CanSend
+	u.hold(job.ID)
+	u.spawn(func() {
+		defer u.release(queued.ID)
+		u.slots <- struct{}{}
+		defer func() { <-u.slots }()
+		u.process(bg, &queued, canSend)
+	})
+
+	return job, nil
+}
+
+// Reclaim renews the leases of the jobs this process holds, then fails the jobs
+// whose lease has expired. It returns how many it failed.
+func (u *UseCase) Reclaim(ctx context.Context) (int, error) {
+	now := time.Now()
+	if err := u.jobs.RenewLeases(ctx, u.heldIDs(), now.Add(leaseTTL)); err != nil {
+		u.log.Error(ctx, "failed to renew import leases", port.F("error", err))
+		return 0, err
+	}
+	n, err := u.jobs.FailExpired(ctx, now, interruptedReason)
+	if err != nil {
+		u.log.Error(ctx, "failed to fail expired imports", port.F("error", err))
+		return 0, err
+	}
+	if n > 0 {
+		u.log.Warn(ctx, "failed interrupted imports", port.F("count", n))
+	}
+	return n, nil
+}
+
+// Run calls Reclaim right away and then every heartbeat interval until ctx is done.
+func (u *UseCase) Run(ctx context.Context) {
+	_, _ = u.Reclaim(ctx)
+	ticker := time.NewTicker(heartbeatInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			_, _ = u.Reclaim(ctx)
+		}
+	}
+}
+
+func (u *UseCase) hold(id string) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.held[id] = struct{}{}
+}
+
+// release stops renewing the lease of a job; an unfinished job is then failed once
+// its lease expires.
+func (u *UseCase) release(id string) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	delete(u.held, id)
+}
+
+func (u *UseCase) heldIDs() []string {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	ids := make([]string, 0, len(u.held))
+	for id := range u.held {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// process reads the stored file and creates its rows chunk by chunk, saving the
+// job's progress after every chunk.
+func (u *UseCase) process(ctx context.Context, job *notification.ImportJob, canSend func(notification.Channel) bool) {
+	defer func() {
+		if err := u.storage.Delete(ctx, job.ID); err != nil {
+			u.log.Warn(ctx, "failed to delete import file", port.F("error", err), port.F("import_id", job.ID))
+		}
+	}()
+
+	job.Status = notification.ImportStatusProcessing
+	if !u.save(ctx, job) {
+		return
+	}
+
+	f, err := u.storage.Open(ctx, job.ID)
+	if err != nil {
+		u.fail(ctx, job, "import file could not be opened")
+		return
+	}
+	defer f.Close()
+
+	rows, err := newRowReader(job.Format, f)
+	if err != nil {
+		u.fail(ctx, job, err.Error())
+		return
+	}
+
+	var chunk []row
+	var rejected []notification.RowError
+	for {
+		r, err := rows.next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			u.fail(ctx, job, fmt.Sprintf("file could not be read after row %d", job.TotalRows))
+			return
+		}
+		job.TotalRows++
+		if r.Err == nil && canSend != nil {
+			if ch := notification.Channel(r.Item.Channel); ch.Valid() && !canSend(ch) {
+				r.Err = &notification.RowError{Row: r.Line, Field: "channel", Message: "api key lacks the " + auth.SendScope(ch).String() + " scope"}
+			}
+		}
+		if r.Err != nil {
+			job.RejectedRows++
+			rejected = append(rejected, *r.Err)
+			continue
+		}
+		chunk = append(chunk, r)
+		if len(chunk) == u.chunk {
+			if !u.flush(ctx, job, chunk, rejected) {
+				return
+			}
+			chunk, rejected = chunk[:0], nil
+		}
+	}
+	if !u.flush(ctx, job, chunk, rejected) {
+		return
+	}
+
+	now := time.Now()
+	job.Status = notification.ImportStatusCompleted
+	job.CompletedAt = &now
+	if u.save(ctx, job) {
+		u.log.Info(ctx, "import completed",
+			port.F("import_id", job.ID),
+			port.F("total_rows", job.TotalRows),
+			port.F("accepted_rows", job.AcceptedRows),
+			port.F("rejected_rows", job.RejectedRows),
+			port.F("duplicate_rows", job.DuplicateRows),
+		)
+	}
+}
+
+// flush creates chunk as one batch, records the rows rejected since the last flush
+// and saves the job. It returns false once the job has failed.
+func (u *UseCase) flush(ctx context.Context, job *notification.ImportJob, chunk []row, rejected []notification.RowError) bool {
+	if len(chunk) > 0 {
+		items := make([]create.BatchItem, len(chunk))
+		for i, r := range chunk {
+			items[i] = r.Item
+		}
+		result, err := u.creator.CreateNotificationBatches(ctx, &create.BatchCommand{
+			Items:         items,
+			ClientID:      job.ClientID,
+			TenantID:      job.TenantID,
+			PartialAccept: true,
+		})
+		switch {
+		case errors.Is(err, notification.ErrQuotaExceeded):
+			// Later chunks may still fit, e.g. on other channels.
+			for _, r := range chunk {
+				rejected = append(rejected, notification.RowError{Row: r.Line, Message: "daily channel quota exceeded"})
+			}
+			job.RejectedRows += len(chunk)
+		case err != nil && result == nil:
+			u.log.Error(ctx, "failed to create import batch", port.F("error", err), port.F("import_id", job.ID))
+			u.addRowErrors(ctx, job, rejected)
+			u.fail(ctx, job, fmt.Sprintf("batch could not be created after row %d", job.TotalRows-len(chunk)))
+			return false
+		default:
+			// The batch exists even when publishing it failed; its notifications
+			// are reported as accepted like those of a regular batch request.
+			if err != nil {
+				u.log.Error(ctx, "import batch created with errors", port.F("error", err), port.F("import_id", job.ID), port.F("batch_id", result.BatchID))
+			}
+			if result.BatchID != "" {
+				job.BatchIDs = append(job.BatchIDs, result.BatchID)
+			}
+			for _, it := range result.Items {
+				line := chunk[it.Index].Line
+				switch it.Status {
+				case create.ItemStatusAccepted:
+					job.AcceptedRows++
+				case create.ItemStatusDuplicate:
+					job.DuplicateRows++
+				case create.ItemStatusRejected:
+					job.RejectedRows++
+					for _, fe := range it.Errors {
+						rejected = append(rejected, notification.RowError{Row: line, Field: fe.Field, Message: fe.Message})
+					}
+				}
+			}
+		}
+	}
+
+	if !u.addRowErrors(ctx, job, rejected) {
+		return false
+	}
+	return u.save(ctx, job)
+}
+
+func (u *UseCase) addRowErrors(ctx context.Context, job *notification.ImportJob, errs []notification.RowError) bool {
+	if len(errs) == 0 {
+		return true
+	}
+	if err := u.jobs.AddRowErrors(ctx, job.ID, errs); err != nil {
+		u.log.Error(ctx, "failed to record import row errors", port.F("error", err), port.F("import_id", job.ID))
+		u.fail(ctx, job, "row errors could not be recorded")
+		return false
+	}
+	return true
+}
+
+// save stores the job's progress. It returns false if the job could not be saved,
+// in which case processing stops and the job is failed once its lease expires.
+func (u *UseCase) save(ctx context.Context, job *notification.ImportJob) bool {
+	job.UpdatedAt = time.Now()
+	err := u.jobs.Update(ctx, job)
+	switch {
+	case errors.Is(err, notification.ErrImportNotFound):
+		u.log.Warn(ctx, "import job finished while processing", port.F("import_id", job.ID))
+		return false
+	case err != nil:
+		u.log.Error(ctx, "failed to update import job", port.F("error", err), port.F("import_id", job.ID))
+		return false
+	}
+	return true
+}
+
+func (u *UseCase) fail(ctx context.Context, job *notification.ImportJob, reason string) {
+	u.log.Warn(ctx, "import failed", port.F("import_id", job.ID), port.F("reason", reason))
+	now := time.Now()
+	job.Status = notification.ImportStatusFailed
+	job.FailureReason = &reason
+	job.CompletedAt = &now
+	u.save(ctx, job)
+}
+
+func tenantOrDefault(id string) string {
+	if id == "" {
+		return tenant.DefaultID
+	}
+	return id
+}
@@ -0,0 +1,299 @@
+package bulk
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/semih-yildiz/notification-service/internal/application/notification/command/create"
+	"github.com/semih-yildiz/notification-service/internal/application/notification/port"
+	"github.com/semih-yildiz/notification-service/internal/domain/notification"
+)
+
+type mockJobRepo struct {
+	created   *notification.ImportJob
+	updates   []notification.ImportJob
+	rowErrors []notification.RowError
+	renewed   []string
+	expired   int
+}
+
+func (m *mockJobRepo) Create(ctx context.Context, job *notification.ImportJob) error {
+	m.created = job
+	return nil
+}
+
+func (m *mockJobRepo) GetByID(ctx context.Context, id string) (*notification.ImportJob, error) {
+	return nil, notification.ErrImportNotFound
+}
+
+func (m *mockJobRepo) Update(ctx context.Context, job *notification.ImportJob) error {
+	m.updates = append(m.updates, *job)
+	return nil
+}
+
+func (m *mockJobRepo) AddRowErrors(ctx context.Context, jobID string, errs []notification.RowError) error {
+	m.rowErrors = append(m.rowErrors, errs...)
+	return nil
+}
+
+func (m *mockJobRepo) ListRowErrors(ctx context.Context, jobID string, afterRow, limit int) ([]notification.RowError, error) {
+	return nil, nil
+}
+
+func (m *mockJobRepo) RenewLeases(ctx context.Context, ids []string, until time.Time) error {
+	m.renewed = ids
+	return nil
+}
+
+func (m *mockJobRepo) FailExpired(ctx context.Context, now time.Time, reason string) (int, error) {
+	return m.expired, nil
+}
+
+func (m *mockJobRepo) last() notification.ImportJob {
+	return m.updates[len(m.updates)-1]
+}
+
+type memoryStorage struct {
+	files   map[string][]byte
+	deleted []string
+}
+
+func (s *memoryStorage) Save(ctx context.Context, jobID string, r io.Reader, maxBytes int64) error {
+	b, err := io.ReadAll(io.LimitReader(r, maxBytes+1))
+	if err != nil {
+		return err
+	}
+	if int64(len(b)) > maxBytes {
+		return notification.ErrImportTooLarge
+	}
+	s.files[jobID] = b
+	return nil
+}
+
+func (s *memoryStorage) Open(ctx context.Context, jobID string) (io.ReadCloser, error) {
+	return io.NopCloser(bytes.NewReader(s.files[jobID])), nil
+}
+
+func (s *memoryStorage) Delete(ctx context.Context, jobID string) error {
+	s.deleted = append(s.deleted, jobID)
+	delete(s.files, jobID)
+	return nil
+}
+
+type mockBatchCreator struct {
+	createFn func(ctx context.Context, cmd *create.BatchCommand) (*create.BatchResult, error)
+	calls    []*create.BatchCommand
+}
+
+func (m *mockBatchCreator) CreateNotificationBatches(ctx context.Context, cmd *create.BatchCommand) (*create.BatchResult, error) {
+	m.calls = append(m.calls, cmd)
+	if m.createFn != nil {
+		return m.createFn(ctx, cmd)
+	}
+	items := make([]create.ItemResult, len(cmd.Items))
+	for i := range cmd.Items {
+		items[i] = create.ItemResult{Index: i, Status: create.ItemStatusAccepted, NotificationID: fmt.Sprintf("n-%d", i)}
+	}
+	return &create.BatchResult{BatchID: fmt.Sprintf("b-%d", len(m.calls)), Items: items}, nil
+}
+
+type mockLogger struct{}
+
+func (m *mockLogger) Info(ctx context.Context, msg string, fields ...port.Field)  {}
+func (m *mockLogger) Warn(ctx context.Context, msg string, fields ...port.Field)  {}
+func (m *mockLogger) Error(ctx context.Context, msg string, fields ...port.Field) {}
+
+func newTestUseCase(creator *mockBatchCreator, maxBytes int64) (*UseCase, *mockJobRepo, *memoryStorage) {
+	jobs := &mockJobRepo{}
+	storage := &memoryStorage{files: make(map[string][]byte)}
+	u := NewUseCase(jobs, storage, creator, &mockLogger{}, maxBytes)
+	u.spawn = func(f func()) { f() }
+	return u, jobs, storage
+}
+
+func TestStart_ProcessesRowsInChunks(t *testing.T) {
+	creator := &mockBatchCreator{}
+	u, jobs, storage := newTestUseCase(creator, 0)
+	u.chunk = 2
+
+	var body strings.Builder
+	for i := 0; i < 5; i++ {
+		fmt.Fprintf(&body, `{"recipient":"+9055500000%d","channel":"sms","content":"hi"}`+"\n", i)
+	}
+	job, err := u.Start(context.Background(), &Command{Format: notification.ImportFormatNDJSON, Body: strings.NewReader(body.String())})
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if job.Status != notification.ImportStatusPending || jobs.created == nil || job.TenantID != "default" {
+		t.Errorf("expected a pending job in the default tenant, got %+v", job)
+	}
+	if len(creator.calls) != 3 || len(creator.calls[0].Items) != 2 || len(creator.calls[2].Items) != 1 {
+		t.Fatalf("expected chunks of 2, 2 and 1, got %d calls", len(creator.calls))
+	}
+	if !creator.calls[0].PartialAccept {
+		t.Error("expected partial accept")
+	}
+	final := jobs.last()
+	if final.Status != notification.ImportStatusCompleted || final.TotalRows != 5 || final.AcceptedRows != 5 || len(final.BatchIDs) != 3 {
+		t.Errorf("unexpected final job: %+v", final)
+	}
+	if final.CompletedAt == nil {
+		t.Error("expected completed_at")
+	}
+	if len(storage.files) != 0 || len(storage.deleted) != 1 {
+		t.Error("expected the upload to be deleted")
+	}
+}
+
+func TestStart_RecordsRowErrors(t *testing.T) {
+	creator := &mockBatchCreator{createFn: func(ctx context.Context, cmd *create.BatchCommand) (*create.BatchResult, error) {
+		return &create.BatchResult{BatchID: "b-1", Items: []create.ItemResult{
+			{Index: 0, Status: create.ItemStatusAccepted, NotificationID: "n-1"},
+			{Index: 1, Status: create.ItemStatusRejected, Errors: []notification.FieldError{{Field: "content", Message: "content is required"}}},
+			{Index: 2, Status: create.ItemStatusDuplicate, NotificationID: "n-0"},
+		}}, nil
+	}}
+	u, jobs, _ := newTestUseCase(creator, 0)
+
+	body := "recipient,channel,content\n" +
+		"+905551234567,sms,hi\n" +
+		"a@b.c,email,hello\n" +
+		"+905551234567,sms,\n" +
+		"+905551234567,sms,again\n"
+	canSend := func(ch notification.Channel) bool { return ch != notification.ChannelEmail }
+
+	_, err := u.Start(context.Background(), &Command{Format: notification.ImportFormatCSV, Body: strings.NewReader(body), CanSend: canSend})
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(creator.calls) != 1 || len(creator.calls[0].Items) != 3 {
+		t.Fatalf("expected the 3 sendable rows in one batch, got %+v", creator.calls)
+	}
+	final := jobs.last()
+	if final.TotalRows != 4 || final.AcceptedRows != 1 || final.RejectedRows != 2 || final.DuplicateRows != 1 {
+		t.Errorf("unexpected counts: %+v", final)
+	}
+	if len(jobs.rowErrors) != 2 {
+		t.Fatalf("expected 2 row errors, got %+v", jobs.rowErrors)
+	}
+	if jobs.rowErrors[0].Row != 3 || jobs.rowErrors[0].Field != "channel" {
+		t.Errorf("expected a scope error on line 3, got %+v", jobs.rowErrors[0])
+	}
+	if jobs.rowErrors[1].Row != 4 || jobs.rowErrors[1].Field != "content" {
+		t.Errorf("expected a content error on line 4, got %+v", jobs.rowErrors[1])
+	}
+}
+
+func TestStart_QuotaRejectsChunk(t *testing.T) {
+	creator := &mockBatchCreator{createFn: func(ctx context.Context, cmd *create.BatchCommand) (*create.BatchResult, error) {
+		return nil, notification.ErrQuotaExceeded
+	}}
+	u, jobs, _ := newTestUseCase(creator, 0)
+
+	body := `{"recipient":"+905551234567","channel":"sms","content":"hi"}`
+
+	if _, err := u.Start(context.Background(), &Command{Format: notification.ImportFormatNDJSON, Body: strings.NewReader(body)}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	final := jobs.last()
+	if final.Status != notification.ImportStatusCompleted || final.RejectedRows != 1 || len(jobs.rowErrors) != 1 {
+		t.Errorf("expected the row rejected for quota, got %+v", final)
+	}
+}
+
+func TestStart_FailsOnCreateError(t *testing.T) {
+	creator := &mockBatchCreator{createFn: func(ctx context.Context, cmd *create.BatchCommand) (*create.BatchResult, error) {
+		return nil, errors.New("db down")
+	}}
+	u, jobs, storage := newTestUseCase(creator, 0)
+
+	body := `{"recipient":"+905551234567","channel":"sms","content":"hi"}`
+
+	if _, err := u.Start(context.Background(), &Command{Format: notification.ImportFormatNDJSON, Body: strings.NewReader(body)}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	final := jobs.last()
+	if final.Status != notification.ImportStatusFailed || final.FailureReason == nil {
+		t.Errorf("expected a failed job, got %+v", final)
+	}
+	if len(storage.files) != 0 {
+		t.Error("expected the upload to be deleted")
+	}
+}
+
+func TestStart_InvalidCSVHeaderFailsJob(t *testing.T) {
+	u, jobs, _ := newTestUseCase(&mockBatchCreator{}, 0)
+
+	if _, err := u.Start(context.Background(), &Command{Format: notification.ImportFormatCSV, Body: strings.NewReader("to,text\n")}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if final := jobs.last(); final.Status != notification.ImportStatusFailed {
+		t.Errorf("expected a failed job, got %+v", final)
+	}
+}
+
+func TestStart_Rejected(t *testing.T) {
+	tests := []struct {
+		name   string
+		format notification.ImportFormat
+		body   string
+		want   error
+	}{
+		{"unknown format", "xml", "<a/>", notification.ErrInvalidImportFormat},
+		{"too large", notification.ImportFormatNDJSON, strings.Repeat("x", 11), notification.ErrImportTooLarge},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u, jobs, _ := newTestUseCase(&mockBatchCreator{}, 10)
+
+			_, err := u.Start(context.Background(), &Command{Format: tt.format, Body: strings.NewReader(tt.body)})
+
+			if !errors.Is(err, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, err)
+			}
+			if jobs.created != nil {
+				t.Error("expected no job")
+			}
+		})
+	}
+}
+
+func TestReclaim_RenewsHeldJobs(t *testing.T) {
+	u, jobs, _ := newTestUseCase(&mockBatchCreator{}, 0)
+	var queued func()
+	u.spawn = func(f func()) { queued = f }
+
+	job, err := u.Start(context.Background(), &Command{Format: notification.ImportFormatNDJSON, Body: strings.NewReader(`{}`)})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if job.LeaseExpiresAt == nil || !job.LeaseExpiresAt.After(time.Now()) {
+		t.Errorf("expected the job to start with a lease, got %v", job.LeaseExpiresAt)
+	}
+
+	jobs.expired = 2
+	n, err := u.Reclaim(context.Background())
+	if err != nil || n != 2 {
+		t.Fatalf("expected 2 expired jobs failed, got %d, %v", n, err)
+	}
+	if len(jobs.renewed) != 1 || jobs.renewed[0] != job.ID {
+		t.Errorf("expected the waiting job's lease renewed, got %v", jobs.renewed)
+	}
+
+	queued()
+	if _, err := u.Reclaim(context.Background()); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(jobs.renewed) != 0 {
+		t.Errorf("expected no leases renewed once the job finished, got %v", jobs.renewed)
+	}
+}
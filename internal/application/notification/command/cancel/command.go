@@ -2,8 +2,12 @@ package cancel
 
 type Command struct {
 	NotificationID string
+	// ClientID restricts the cancel to notifications created by this client.
+	// Nil skips the ownership check (admin).
+	ClientID *string
 }
 
 type BatchCommand struct {
-	BatchID string
+	BatchID  string
+	ClientID *string // see Command.ClientID
 }
@@ -2,22 +2,81 @@ package cancel
 
 import (
 	"context"
+	"time"
 
 	"github.com/semih-yildiz/notification-service/internal/application/notification/port"
+	"github.com/semih-yildiz/notification-service/internal/domain/notification"
 )
 
 type UseCase struct {
-	repo port.NotificationRepository
+	repo   port.NotificationRepository
+	batch  port.BatchRepository
+	events port.StatusBroadcaster // optional; nil disables live status events
 }
 
-func NewUseCase(repo port.NotificationRepository) *UseCase {
-	return &UseCase{repo: repo}
+func NewUseCase(repo port.NotificationRepository, batch port.BatchRepository) *UseCase {
+	return &UseCase{repo: repo, batch: batch}
+}
+
+// WithStatusEvents broadcasts cancellations to live subscribers.
+func (u *UseCase) WithStatusEvents(events port.StatusBroadcaster) *UseCase {
+	u.events = events
+	return u
 }
 
 func (u *UseCase) CancelPendingNotification(ctx context.Context, cmd *Command) error {
-	return u.repo.CancelPending(ctx, cmd.NotificationID)
+	// The notification is needed to check ownership and to tell batch watchers.
+	var n *notification.Notification
+	if cmd.ClientID != nil || u.events != nil {
+		var err error
+		n, err = u.repo.GetByID(ctx, cmd.NotificationID)
+		if err != nil {
+			return err
+		}
+		if cmd.ClientID != nil && !ownedBy(n.ClientID, *cmd.ClientID) {
+			return notification.ErrNotOwner
+		}
+	}
+	if err := u.repo.CancelPending(ctx, cmd.NotificationID); err != nil {
+		return err
+	}
+	if n != nil {
+		u.broadcast(ctx, &port.StatusEvent{NotificationID: n.ID, BatchID: n.BatchID})
+	}
+	return nil
 }
 
 func (u *UseCase) CancelPendingNotificationBatch(ctx context.Context, cmd *BatchCommand) (int, error) {
-	return u.repo.CancelPendingByBatchID(ctx, cmd.BatchID)
+	if cmd.ClientID != nil {
+		b, err := u.batch.GetByID(ctx, cmd.BatchID)
+		if err != nil {
+			return 0, err
+		}
+		if !ownedBy(b.ClientID, *cmd.ClientID) {
+			return 0, notification.ErrNotOwner
+		}
+	}
+	count, err := u.repo.CancelPendingByBatchID(ctx, cmd.BatchID)
+	if err != nil {
+		return 0, err
+	}
+	if count > 0 {
+		u.broadcast(ctx, &port.StatusEvent{BatchID: &cmd.BatchID})
+	}
+	return count, nil
+}
+
+// broadcast reports a cancellation. A failed broadcast does not undo it; live views
+// catch up on their next refresh.
+func (u *UseCase) broadcast(ctx context.Context, evt *port.StatusEvent) {
+	if u.events == nil {
+		return
+	}
+	evt.Status = notification.StatusCancelled
+	evt.OccurredAt = time.Now()
+	_ = u.events.Broadcast(ctx, evt)
+}
+
+func ownedBy(owner *string, clientID string) bool {
+	return owner != nil && *owner == clientID
 }
@@ -13,6 +13,7 @@ import (
 type mockNotificationRepo struct {
 	cancelPendingFn          func(ctx context.Context, id string) error
 	cancelPendingByBatchIDFn func(ctx context.Context, batchID string) (int, error)
+	getByIDFn                func(ctx context.Context, id string) (*notification.Notification, error)
 }
 
 func (m *mockNotificationRepo) CancelPending(ctx context.Context, id string) error {
@@ -33,11 +34,14 @@ func (m *mockNotificationRepo) Create(ctx context.Context, n *notification.Notif
 	return errors.New("not implemented")
 }
 
-func (m *mockNotificationRepo) CreateBatch(ctx context.Context, notifications []*notification.Notification) error {
+func (m *mockNotificationRepo) CreateBatch(ctx context.Context, b *notification.Batch, notifications []*notification.Notification) error {
 	return errors.New("not implemented")
 }
 
 func (m *mockNotificationRepo) GetByID(ctx context.Context, id string) (*notification.Notification, error) {
+	if m.getByIDFn != nil {
+		return m.getByIDFn(ctx, id)
+	}
 	return nil, errors.New("not implemented")
 }
 
@@ -45,6 +49,10 @@ func (m *mockNotificationRepo) UpdateStatus(ctx context.Context, id string, stat
 	return errors.New("not implemented")
 }
 
+func (m *mockNotificationRepo) UpdateStatuses(ctx context.Context, ids []string, status notification.Status) error {
+	return errors.New("not implemented")
+}
+
 func (m *mockNotificationRepo) GetByBatchID(ctx context.Context, batchID string) ([]*notification.Notification, error) {
 	return nil, errors.New("not implemented")
 }
@@ -57,6 +65,37 @@ func (m *mockNotificationRepo) ExistsByIdempotencyKey(ctx context.Context, key s
 	return false, errors.New("not implemented")
 }
 
+func (m *mockNotificationRepo) GetByIdempotencyKey(ctx context.Context, key string) (*notification.Notification, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *mockNotificationRepo) IDsByIdempotencyKey(ctx context.Context, keys []string) (map[string]string, error) {
+	return nil, errors.New("not implemented")
+}
+
+type mockBatchRepo struct {
+	getByIDFn func(ctx context.Context, id string) (*notification.Batch, error)
+}
+
+func (m *mockBatchRepo) Create(ctx context.Context, b *notification.Batch) error {
+	return errors.New("not implemented")
+}
+
+func (m *mockBatchRepo) GetByID(ctx context.Context, id string) (*notification.Batch, error) {
+	if m.getByIDFn != nil {
+		return m.getByIDFn(ctx, id)
+	}
+	return nil, errors.New("not implemented")
+}
+
+func (m *mockBatchRepo) GetByIdempotencyKey(ctx context.Context, key string) (*notification.Batch, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *mockBatchRepo) FinishExpansion(ctx context.Context, id string) error {
+	return nil
+}
+
 func TestCancelPendingNotification_Success(t *testing.T) {
 	repo := &mockNotificationRepo{
 		cancelPendingFn: func(ctx context.Context, id string) error {
@@ -64,7 +103,7 @@ func TestCancelPendingNotification_Success(t *testing.T) {
 		},
 	}
 
-	uc := NewUseCase(repo)
+	uc := NewUseCase(repo, &mockBatchRepo{})
 
 	cmd := &Command{NotificationID: "test-id"}
 	err := uc.CancelPendingNotification(context.Background(), cmd)
@@ -81,7 +120,7 @@ func TestCancelPendingNotification_Error(t *testing.T) {
 		},
 	}
 
-	uc := NewUseCase(repo)
+	uc := NewUseCase(repo, &mockBatchRepo{})
 
 	cmd := &Command{NotificationID: "test-id"}
 	err := uc.CancelPendingNotification(context.Background(), cmd)
@@ -98,7 +137,7 @@ func TestCancelPendingNotificationBatch_Success(t *testing.T) {
 		},
 	}
 
-	uc := NewUseCase(repo)
+	uc := NewUseCase(repo, &mockBatchRepo{})
 
 	cmd := &BatchCommand{BatchID: "batch-id"}
 	count, err := uc.CancelPendingNotificationBatch(context.Background(), cmd)
@@ -118,7 +157,7 @@ func TestCancelPendingNotificationBatch_Error(t *testing.T) {
 		},
 	}
 
-	uc := NewUseCase(repo)
+	uc := NewUseCase(repo, &mockBatchRepo{})
 
 	cmd := &BatchCommand{BatchID: "batch-id"}
 	count, err := uc.CancelPendingNotificationBatch(context.Background(), cmd)
@@ -130,3 +169,92 @@ func TestCancelPendingNotificationBatch_Error(t *testing.T) {
 		t.Errorf("expected count 0, got %d", count)
 	}
 }
+
+func TestCancelPendingNotification_OwnerCheck(t *testing.T) {
+	owner := "client-a"
+	cancelled := false
+	repo := &mockNotificationRepo{
+		getByIDFn: func(ctx context.Context, id string) (*notification.Notification, error) {
+			return &notification.Notification{ID: id, ClientID: &owner}, nil
+		},
+		cancelPendingFn: func(ctx context.Context, id string) error {
+			cancelled = true
+			return nil
+		},
+	}
+
+	uc := NewUseCase(repo, &mockBatchRepo{})
+
+	other := "client-b"
+	err := uc.CancelPendingNotification(context.Background(), &Command{NotificationID: "test-id", ClientID: &other})
+	if !errors.Is(err, notification.ErrNotOwner) {
+		t.Errorf("expected ErrNotOwner, got %v", err)
+	}
+	if cancelled {
+		t.Error("notification of another client must not be cancelled")
+	}
+
+	if err := uc.CancelPendingNotification(context.Background(), &Command{NotificationID: "test-id", ClientID: &owner}); err != nil {
+		t.Errorf("expected owner to cancel, got %v", err)
+	}
+	if !cancelled {
+		t.Error("expected notification to be cancelled")
+	}
+}
+
+func TestCancelPendingNotificationBatch_OwnerCheck(t *testing.T) {
+	batch := &mockBatchRepo{
+		getByIDFn: func(ctx context.Context, id string) (*notification.Batch, error) {
+			return &notification.Batch{ID: id}, nil // created before clients were recorded
+		},
+	}
+
+	uc := NewUseCase(&mockNotificationRepo{}, batch)
+
+	client := "client-a"
+	_, err := uc.CancelPendingNotificationBatch(context.Background(), &BatchCommand{BatchID: "batch-id", ClientID: &client})
+	if !errors.Is(err, notification.ErrNotOwner) {
+		t.Errorf("expected ErrNotOwner, got %v", err)
+	}
+}
+
+type mockStatusBroadcaster struct {
+	events []*port.StatusEvent
+}
+
+func (m *mockStatusBroadcaster) Broadcast(ctx context.Context, evt *port.StatusEvent) error {
+	m.events = append(m.events, evt)
+	return nil
+}
+
+func TestCancel_BroadcastsStatusEvents(t *testing.T) {
+	batchID := "batch-id"
+	repo := &mockNotificationRepo{
+		getByIDFn: func(ctx context.Context, id string) (*notification.Notification, error) {
+			return &notification.Notification{ID: id, BatchID: &batchID}, nil
+		},
+		cancelPendingByBatchIDFn: func(ctx context.Context, batchID string) (int, error) {
+			return 3, nil
+		},
+	}
+	events := &mockStatusBroadcaster{}
+	uc := NewUseCase(repo, &mockBatchRepo{}).WithStatusEvents(events)
+
+	if err := uc.CancelPendingNotification(context.Background(), &Command{NotificationID: "n1"}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if _, err := uc.CancelPendingNotificationBatch(context.Background(), &BatchCommand{BatchID: batchID}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if len(events.events) != 2 {
+		t.Fatalf("expected 2 events, got %d", len(events.events))
+	}
+	single, batch := events.events[0], events.events[1]
+	if single.NotificationID != "n1" || single.BatchID == nil || *single.BatchID != batchID || single.Status != notification.StatusCancelled {
+		t.Errorf("unexpected notification event: %+v", single)
+	}
+	if batch.NotificationID != "" || batch.BatchID == nil || *batch.BatchID != batchID || batch.Status != notification.StatusCancelled {
+		t.Errorf("unexpected batch event: %+v", batch)
+	}
+}
@@ -1,24 +1,69 @@
 package create
 
-// Command for creating a single notification.
+import "github.com/semih-yildiz/notification-service/internal/domain/notification"
+
+// Command for creating a single notification. It names either a Recipient and
+// Channel, or a UserID and Category to resolve them from the user's profile.
 type Command struct {
 	Recipient      string
 	Channel        string
+	UserID         string
+	Category       string
 	Content        string
 	Priority       string
 	IdempotencyKey *string
+	ClientID       *string // API client making the request
+	TenantID       string  // owning tenant; empty means the default tenant
+	// DeliveryWindow holds back non-urgent delivery outside these hours. Without
+	// one, a user's notification gets the tenant's window for its category.
+	DeliveryWindow *notification.DeliveryWindow
+	// CanSend limits the channels a user's notification may be resolved to; nil
+	// allows every channel.
+	CanSend func(notification.Channel) bool
+	// Track adds open and click tracking to HTML email, unless the user opted out.
+	Track bool
+	// Variants are the content in several locales; the one for Locale (else the
+	// user's profile locale) along the fallback chain is sent, else Content.
+	Variants notification.Variants
+	Locale   string
 }
 
 // BatchItem for one notification in a batch.
 type BatchItem struct {
-	Recipient string
-	Channel   string
-	Content   string
-	Priority  string
+	Recipient      string
+	Channel        string
+	Content        string
+	Priority       string
+	IdempotencyKey *string // dedupes this item across batches and single creates
+	DeliveryWindow *notification.DeliveryWindow
+	Track          bool // open and click tracking of HTML email
+	Variants       notification.Variants
+	Locale         string // picks the variant sent
 }
 
 // BatchCommand for creating a batch of notifications (max 1000).
 type BatchCommand struct {
 	Items          []BatchItem
+	IdempotencyKey *string // replaying the same key returns the original batch
+	ClientID       *string // API client making the request
+	TenantID       string  // owning tenant; empty means the default tenant
+	// PartialAccept rejects invalid items individually (including invalid priorities)
+	// and reports them per index instead of silently skipping or coercing them.
+	PartialAccept bool
+	// BatchID adds the items to this existing batch instead of creating one, e.g. an
+	// audience batch being expanded. IdempotencyKey is ignored then.
+	BatchID string
+}
+
+// FallbackCommand for creating a notification delivered through a fallback chain:
+// each step is tried in turn until one is delivered.
+type FallbackCommand struct {
+	Steps          []notification.FallbackStep
+	Content        string
+	Priority       string
 	IdempotencyKey *string
+	ClientID       *string // API client making the request
+	TenantID       string  // owning tenant; empty means the default tenant
+	Variants       notification.Variants
+	Locale         string // picks the variant sent
 }
@@ -0,0 +1,163 @@
+package create
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/semih-yildiz/notification-service/internal/application/notification/port"
+	"github.com/semih-yildiz/notification-service/internal/domain/notification"
+)
+
+var errFallbackDisabled = errors.New("fallback chains are not enabled")
+
+// CreateFallback creates the parent of a fallback chain and a child notification
+// for its first step, and queues the child. The worker moves on to the next step
+// when a child fails or times out. Only the first step's quota is taken here.
+func (u *UseCase) CreateFallback(ctx context.Context, cmd *FallbackCommand) (*Result, error) {
+	if u.chains == nil {
+		return nil, errFallbackDisabled
+	}
+	pr := notification.Priority(cmd.Priority)
+	if !pr.Valid() {
+		u.log.Warn(ctx, "invalid priority", port.F("priority", cmd.Priority))
+		return nil, notification.ErrInvalidPriority
+	}
+	if len(cmd.Content) == 0 && len(cmd.Variants) == 0 {
+		u.log.Warn(ctx, "invalid content", port.F("content_len", 0))
+		return nil, notification.ErrInvalidContent
+	}
+	if len(cmd.Variants) > 0 {
+		if err := cmd.Variants.Validate(); err != nil {
+			u.log.Warn(ctx, "invalid content variants", port.F("variants", len(cmd.Variants)))
+			return nil, err
+		}
+	}
+	// Every variant, and the default content, has to fit every step's channel.
+	for _, text := range append([]string{cmd.Content}, variantContents(cmd.Variants)...) {
+		if text == "" {
+			continue
+		}
+		if err := notification.ValidateFallbackSteps(cmd.Steps, text); err != nil {
+			u.log.Warn(ctx, "invalid fallback chain", port.F("steps", len(cmd.Steps)))
+			return nil, err
+		}
+	}
+	content, locale, err := u.localize(cmd.Content, cmd.Variants, cmd.Locale)
+	if err != nil {
+		u.log.Warn(ctx, "content not localized", port.F("error", err), port.F("locale", cmd.Locale))
+		return nil, err
+	}
+	tenantID := tenantOrDefault(cmd.TenantID)
+
+	hasKey := cmd.IdempotencyKey != nil && *cmd.IdempotencyKey != ""
+	fp := fallbackFingerprint(cmd.Steps, content, pr)
+	releaseKey := func() {
+		if !hasKey {
+			return
+		}
+		if delErr := u.idem.Delete(ctx, storeKey(tenantID, *cmd.IdempotencyKey)); delErr != nil {
+			u.log.Warn(ctx, "failed to release idempotency key", port.F("error", delErr), port.F("key", *cmd.IdempotencyKey))
+		}
+	}
+	if hasKey {
+		replay, err := u.reserveKey(ctx, tenantID, *cmd.IdempotencyKey, fp)
+		if err != nil || replay != nil {
+			return replay, err
+		}
+	}
+
+	first := cmd.Steps[0]
+	consumed, ok := u.consumeQuota(ctx, tenantID, map[notification.Channel]int{first.Channel: 1})
+	if !ok {
+		releaseKey()
+		return nil, notification.ErrQuotaExceeded
+	}
+
+	now := time.Now()
+	parent := &notification.Notification{
+		ID:             uuid.New().String(),
+		TenantID:       tenantID,
+		Recipient:      first.Recipient,
+		Channel:        first.Channel,
+		Content:        content,
+		Locale:         locale,
+		Priority:       pr,
+		Status:         notification.StatusQueued,
+		IdempotencyKey: cmd.IdempotencyKey,
+		ClientID:       cmd.ClientID,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+		Fallback:       &notification.Fallback{Steps: cmd.Steps},
+	}
+	child := &notification.Notification{
+		ID:        uuid.New().String(),
+		TenantID:  tenantID,
+		Recipient: first.Recipient,
+		Channel:   first.Channel,
+		Content:   content,
+		Locale:    locale,
+		Priority:  pr,
+		Status:    notification.StatusPending,
+		ClientID:  cmd.ClientID,
+		CreatedAt: now,
+		UpdatedAt: now,
+		ParentID:  &parent.ID,
+	}
+	parent.Fallback.ChildID = child.ID
+	parent.Fallback.Deadline = parent.Fallback.DeadlineFrom(now)
+
+	if err := u.chains.CreateFallback(ctx, parent, child); err != nil {
+		u.refundQuota(ctx, tenantID, consumed)
+		if hasKey && isUniqueViolation(err) {
+			u.log.Warn(ctx, "duplicate idempotency key (db constraint)", port.F("key", *cmd.IdempotencyKey))
+			return u.replayFromDB(ctx, *cmd.IdempotencyKey, fp, notification.ErrDuplicateRequest)
+		}
+		releaseKey()
+		u.log.Error(ctx, "failed to create fallback chain in DB", port.F("error", err), port.F("notification_id", parent.ID))
+		return nil, err
+	}
+
+	if hasKey {
+		rec := &port.IdempotencyRecord{Fingerprint: fp, ResourceID: parent.ID}
+		if err := u.idem.Complete(ctx, storeKey(tenantID, *cmd.IdempotencyKey), rec, idempotencyTTLSeconds); err != nil {
+			u.log.Warn(ctx, "failed to record idempotency result", port.F("error", err), port.F("key", *cmd.IdempotencyKey))
+		}
+	}
+
+	u.log.Info(ctx, "fallback chain created", port.F("notification_id", parent.ID), port.F("steps", len(cmd.Steps)), port.F("priority", pr))
+
+	evt := &port.NotificationEvent{
+		NotificationID: child.ID,
+		TenantID:       tenantID,
+		Recipient:      child.Recipient,
+		Channel:        child.Channel,
+		Content:        child.Content,
+		Priority:       pr,
+		CreatedAt:      now.Format("2006-01-02T15:04:05Z07:00"),
+	}
+	if err := u.pub.Publish(ctx, evt); err != nil {
+		// The worker moves on to the next step once this one is marked failed.
+		u.log.Error(ctx, "failed to publish fallback step", port.F("error", err), port.F("notification_id", child.ID))
+		reason := "publish failed: " + err.Error()
+		if err := u.repo.UpdateStatus(ctx, child.ID, notification.StatusFailed, nil, &reason); err != nil {
+			u.log.Error(ctx, "failed to update status to failed", port.F("error", err), port.F("notification_id", child.ID))
+		}
+		return &Result{Notification: parent}, nil
+	}
+	if err := u.repo.UpdateStatus(ctx, child.ID, notification.StatusQueued, nil, nil); err != nil {
+		u.log.Error(ctx, "failed to update status to queued", port.F("error", err), port.F("notification_id", child.ID))
+	}
+
+	return &Result{Notification: parent}, nil
+}
+
+func variantContents(variants notification.Variants) []string {
+	contents := make([]string, 0, len(variants))
+	for _, content := range variants {
+		contents = append(contents, content)
+	}
+	return contents
+}
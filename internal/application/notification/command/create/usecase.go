@@ -2,7 +2,11 @@ package create
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
+	"fmt"
+	"sort"
 	"strings"
 	"time"
 
@@ -10,15 +14,42 @@ import (
 	"gorm.io/gorm"
 
 	"github.com/semih-yildiz/notification-service/internal/application/notification/port"
+	profileport "github.com/semih-yildiz/notification-service/internal/application/profile/port"
+	tenantport "github.com/semih-yildiz/notification-service/internal/application/tenant/port"
 	"github.com/semih-yildiz/notification-service/internal/domain/notification"
+	"github.com/semih-yildiz/notification-service/internal/domain/profile"
+	"github.com/semih-yildiz/notification-service/internal/domain/tenant"
 )
 
+const idempotencyTTLSeconds = 7 * 24 * 3600
+
+// batchIdempotencyPrefix keeps batch keys apart from notification keys in the store.
+const batchIdempotencyPrefix = "batch:"
+
+// errKeyTaken reports that an idempotency key was stored by a concurrent request
+// after this one checked it.
+var errKeyTaken = errors.New("idempotency key taken")
+
 type UseCase struct {
 	repo  port.NotificationRepository
 	batch port.BatchRepository
 	pub   port.EventPublisher
 	idem  port.IdempotencyStore
 	log   port.Logger
+
+	dedupe       port.DedupeStore
+	dedupeWindow time.Duration
+
+	quota port.QuotaLimiter
+
+	profiles profileport.ProfileRepository
+	tenants  tenantport.TenantRepository
+
+	chains port.FallbackRepository
+
+	digests port.DigestRepository
+
+	locales []string // fallback chain of content variants, e.g. ["en"]
 }
 
 func NewUseCase(
@@ -37,8 +68,68 @@ func NewUseCase(
 	}
 }
 
-// CreateNotification creates one notification.
-func (u *UseCase) CreateNotification(ctx context.Context, cmd *Command) (*notification.Notification, error) {
+// WithDedupe collapses single creates with the same channel, recipient and content
+// into the first one sent within window. A zero window disables it.
+func (u *UseCase) WithDedupe(store port.DedupeStore, window time.Duration) *UseCase {
+	u.dedupe = store
+	u.dedupeWindow = window
+	return u
+}
+
+// WithQuotas enforces each tenant's daily per-channel quotas on creates.
+func (u *UseCase) WithQuotas(q port.QuotaLimiter) *UseCase {
+	u.quota = q
+	return u
+}
+
+// WithProfiles lets single creates address a user ID and category instead of a
+// recipient and channel.
+func (u *UseCase) WithProfiles(profiles profileport.ProfileRepository) *UseCase {
+	u.profiles = profiles
+	return u
+}
+
+// WithDeliveryWindows gives users' notifications without a delivery window of their
+// own the tenant's window for their category.
+func (u *UseCase) WithDeliveryWindows(tenants tenantport.TenantRepository) *UseCase {
+	u.tenants = tenants
+	return u
+}
+
+// WithFallbacks lets single creates name a fallback chain of channels instead of
+// one recipient and channel.
+func (u *UseCase) WithFallbacks(chains port.FallbackRepository) *UseCase {
+	u.chains = chains
+	return u
+}
+
+// WithDigests buffers users' low priority notifications of categories with a
+// digest policy in the tenant instead of sending them.
+func (u *UseCase) WithDigests(tenants tenantport.TenantRepository, digests port.DigestRepository) *UseCase {
+	u.tenants = tenants
+	u.digests = digests
+	return u
+}
+
+// WithLocales sets the locales tried, in order, when no content variant matches
+// the recipient's locale or its language.
+func (u *UseCase) WithLocales(fallback []string) *UseCase {
+	u.locales = fallback
+	return u
+}
+
+// CreateNotification creates one notification. A command naming a user ID is first
+// resolved to the channel and address the user's preferences pick for its category.
+func (u *UseCase) CreateNotification(ctx context.Context, cmd *Command) (*Result, error) {
+	var resolution *notification.Resolution
+	if cmd.UserID != "" {
+		resolved, res, err := u.resolveUser(ctx, cmd)
+		if err != nil {
+			return nil, err
+		}
+		cmd, resolution = resolved, res
+	}
+
 	ch := notification.Channel(cmd.Channel)
 	if !ch.Valid() {
 		u.log.Warn(ctx, "invalid channel", port.F("channel", cmd.Channel))
@@ -49,7 +140,7 @@ func (u *UseCase) CreateNotification(ctx context.Context, cmd *Command) (*notifi
 		u.log.Warn(ctx, "invalid priority", port.F("priority", cmd.Priority))
 		return nil, notification.ErrInvalidPriority
 	}
-	if len(cmd.Content) > notification.MaxContentLength(ch) || len(cmd.Content) == 0 {
+	if len(cmd.Content) > notification.MaxContentLength(ch) || (len(cmd.Content) == 0 && len(cmd.Variants) == 0) {
 		u.log.Warn(ctx, "invalid content", port.F("content_len", len(cmd.Content)), port.F("channel", cmd.Channel))
 		return nil, notification.ErrInvalidContent
 	}
@@ -57,58 +148,142 @@ func (u *UseCase) CreateNotification(ctx context.Context, cmd *Command) (*notifi
 		u.log.Warn(ctx, "invalid recipient", port.F("recipient_len", len(cmd.Recipient)))
 		return nil, notification.ErrInvalidContent
 	}
+	if err := notification.ValidateContent(ch, cmd.Content); err != nil {
+		u.log.Warn(ctx, "invalid content format", port.F("channel", cmd.Channel))
+		return nil, err
+	}
+	if err := notification.ValidateVariants(ch, cmd.Variants); err != nil {
+		u.log.Warn(ctx, "invalid content variants", port.F("variants", len(cmd.Variants)), port.F("channel", cmd.Channel))
+		return nil, err
+	}
+	if err := notification.ValidateRecipient(ch, cmd.Recipient); err != nil {
+		u.log.Warn(ctx, "invalid recipient format", port.F("channel", cmd.Channel))
+		return nil, err
+	}
+	wantLocale := cmd.Locale
+	if wantLocale == "" && len(cmd.Variants) > 0 && resolution == nil {
+		wantLocale = u.profileLocales(ctx, ch, []string{cmd.Recipient})[addressKey(ch, cmd.Recipient)]
+	}
+	content, locale, err := u.localize(cmd.Content, cmd.Variants, wantLocale)
+	if err != nil {
+		u.log.Warn(ctx, "content not localized", port.F("error", err), port.F("locale", wantLocale))
+		return nil, err
+	}
+	if w := cmd.DeliveryWindow; w != nil {
+		if err := w.Validate(); err != nil {
+			u.log.Warn(ctx, "invalid delivery window", port.F("window", w.String()))
+			return nil, err
+		}
+	}
+	tenantID := tenantOrDefault(cmd.TenantID)
+
 	// Idempotency check: Redis first (fast), DB fallback (guarantee)
-	if cmd.IdempotencyKey != nil && *cmd.IdempotencyKey != "" {
-		set, err := u.idem.SetIfNotExists(ctx, *cmd.IdempotencyKey, 7*24*3600)
-		if err != nil {
-			u.log.Warn(ctx, "redis idempotency check failed, falling back to DB",
-				port.F("error", err), port.F("key", *cmd.IdempotencyKey))
-			exists, dbErr := u.repo.ExistsByIdempotencyKey(ctx, *cmd.IdempotencyKey)
-			if dbErr != nil {
-				u.log.Error(ctx, "db idempotency check failed", port.F("error", dbErr), port.F("key", *cmd.IdempotencyKey))
-				return nil, dbErr
-			}
-			if exists {
-				u.log.Warn(ctx, "duplicate idempotency key (db fallback)", port.F("key", *cmd.IdempotencyKey))
-				return nil, notification.ErrDuplicateRequest
-			}
-		} else if !set {
-			u.log.Warn(ctx, "duplicate idempotency key (redis)", port.F("key", *cmd.IdempotencyKey))
-			return nil, notification.ErrDuplicateRequest
+	hasKey := cmd.IdempotencyKey != nil && *cmd.IdempotencyKey != ""
+	fp := requestFingerprint(ch, cmd.Recipient, content, pr, resolution)
+	releaseKey := func() {
+		if !hasKey {
+			return
+		}
+		if delErr := u.idem.Delete(ctx, storeKey(tenantID, *cmd.IdempotencyKey)); delErr != nil {
+			u.log.Warn(ctx, "failed to release idempotency key", port.F("error", delErr), port.F("key", *cmd.IdempotencyKey))
+		}
+	}
+	if hasKey {
+		replay, err := u.reserveKey(ctx, tenantID, *cmd.IdempotencyKey, fp)
+		if err != nil || replay != nil {
+			return replay, err
 		}
 	}
 
 	now := time.Now()
 	id := uuid.New().String()
 
+	dedupeHash, earlier, err := u.claimDedupe(ctx, tenantID, ch, cmd.Recipient, content, id)
+	if err != nil {
+		releaseKey()
+		return nil, err
+	}
+	if earlier != nil {
+		if hasKey {
+			rec := &port.IdempotencyRecord{Fingerprint: fp, ResourceID: earlier.ID}
+			if err := u.idem.Complete(ctx, storeKey(tenantID, *cmd.IdempotencyKey), rec, idempotencyTTLSeconds); err != nil {
+				u.log.Warn(ctx, "failed to record idempotency result", port.F("error", err), port.F("key", *cmd.IdempotencyKey))
+			}
+		}
+		return &Result{Notification: redacted(earlier), DeduplicatedAgainst: earlier.ID}, nil
+	}
+	releaseDedupe := func() {
+		if dedupeHash == "" {
+			return
+		}
+		if relErr := u.dedupe.Release(ctx, dedupeHash); relErr != nil {
+			u.log.Warn(ctx, "failed to release dedupe claim", port.F("error", relErr), port.F("notification_id", id))
+		}
+	}
+
+	usage := map[notification.Channel]int{ch: 1}
+	consumed, ok := u.consumeQuota(ctx, tenantID, usage)
+	if !ok {
+		releaseDedupe()
+		releaseKey()
+		return nil, notification.ErrQuotaExceeded
+	}
+
 	n := &notification.Notification{
 		ID:             id,
+		TenantID:       tenantID,
 		Recipient:      cmd.Recipient,
 		Channel:        ch,
-		Content:        cmd.Content,
+		Content:        content,
+		Locale:         locale,
 		Priority:       pr,
 		Status:         notification.StatusPending,
 		IdempotencyKey: cmd.IdempotencyKey,
+		ClientID:       cmd.ClientID,
 		CreatedAt:      now,
 		UpdatedAt:      now,
+		Resolution:     resolution,
+		DeliveryWindow: cmd.DeliveryWindow,
+		Tracking:       cmd.Track && !u.trackingOptOuts(ctx, []string{cmd.Recipient}, ch)[strings.ToLower(cmd.Recipient)],
 	}
 
-	if err := u.repo.Create(ctx, n); err != nil {
-		if cmd.IdempotencyKey != nil && *cmd.IdempotencyKey != "" && isUniqueViolation(err) {
+	create := u.repo.Create
+	if due, ok := u.digestDue(ctx, n); ok {
+		n.Status = notification.StatusBuffered
+		n.DigestDue = &due
+		create = u.digests.Buffer
+	}
+	if err := create(ctx, n); err != nil {
+		releaseDedupe()
+		u.refundQuota(ctx, tenantID, consumed)
+		if hasKey && isUniqueViolation(err) {
 			u.log.Warn(ctx, "duplicate idempotency key (db constraint)", port.F("key", *cmd.IdempotencyKey))
-			return nil, notification.ErrDuplicateRequest
+			return u.replayFromDB(ctx, *cmd.IdempotencyKey, fp, notification.ErrDuplicateRequest)
 		}
+		releaseKey()
 		u.log.Error(ctx, "failed to create notification in DB", port.F("error", err), port.F("notification_id", id))
 		return nil, err
 	}
 
+	if hasKey {
+		rec := &port.IdempotencyRecord{Fingerprint: fp, ResourceID: id}
+		if err := u.idem.Complete(ctx, storeKey(tenantID, *cmd.IdempotencyKey), rec, idempotencyTTLSeconds); err != nil {
+			u.log.Warn(ctx, "failed to record idempotency result", port.F("error", err), port.F("key", *cmd.IdempotencyKey))
+		}
+	}
+
+	if n.Status == notification.StatusBuffered {
+		u.log.Info(ctx, "notification buffered for digest", port.F("notification_id", id), port.F("channel", ch), port.F("digest_due", n.DigestDue))
+		return &Result{Notification: n}, nil
+	}
 	u.log.Info(ctx, "notification created", port.F("notification_id", id), port.F("channel", ch), port.F("priority", pr))
 
 	evt := &port.NotificationEvent{
 		NotificationID: id,
+		TenantID:       tenantID,
 		Recipient:      cmd.Recipient,
 		Channel:        ch,
-		Content:        cmd.Content,
+		Content:        content,
 		Priority:       pr,
 		IdempotencyKey: cmd.IdempotencyKey,
 		CreatedAt:      now.Format("2006-01-02T15:04:05Z07:00"),
@@ -116,7 +291,7 @@ func (u *UseCase) CreateNotification(ctx context.Context, cmd *Command) (*notifi
 
 	if err := u.pub.Publish(ctx, evt); err != nil {
 		u.log.Error(ctx, "failed to publish notification event", port.F("error", err), port.F("notification_id", id))
-		return n, err
+		return &Result{Notification: n}, err
 	}
 
 	if err := u.repo.UpdateStatus(ctx, id, notification.StatusQueued, nil, nil); err != nil {
@@ -125,108 +300,816 @@ func (u *UseCase) CreateNotification(ctx context.Context, cmd *Command) (*notifi
 	n.Status = notification.StatusQueued
 	u.log.Info(ctx, "notification event published", port.F("notification_id", id))
 
-	return n, nil
+	return &Result{Notification: n}, nil
+}
+
+// Result is the outcome of CreateNotification.
+type Result struct {
+	*notification.Notification
+	Replayed bool `json:"-"` // idempotency key already used; Notification is the original
+	// DeduplicatedAgainst is the ID of the earlier notification with the same content
+	// this request was collapsed into; Notification is that notification.
+	DeduplicatedAgainst string `json:"-"`
+}
+
+// claimDedupe claims the dedupe window for id. It returns the claimed hash, or the
+// earlier notification when one with the same content is still inside the window.
+// Dedupe fails open: store errors let the request through.
+func (u *UseCase) claimDedupe(ctx context.Context, tenantID string, ch notification.Channel, recipient, content, id string) (string, *notification.Notification, error) {
+	if u.dedupe == nil || u.dedupeWindow <= 0 {
+		return "", nil, nil
+	}
+	hash := dedupeHash(tenantID, ch, recipient, content)
+	claimed, existingID, err := u.dedupe.Claim(ctx, hash, id, u.dedupeWindow)
+	if err != nil {
+		u.log.Warn(ctx, "dedupe check failed, skipping", port.F("error", err))
+		return "", nil, nil
+	}
+	if claimed {
+		return hash, nil, nil
+	}
+
+	earlier, err := u.repo.GetByID(ctx, existingID)
+	if errors.Is(err, notification.ErrNotFound) {
+		// The claiming request has not persisted its notification (or failed to).
+		u.log.Warn(ctx, "dedupe claim without notification, skipping", port.F("notification_id", existingID))
+		return "", nil, nil
+	}
+	if err != nil {
+		u.log.Error(ctx, "failed to load deduplicated notification", port.F("error", err), port.F("notification_id", existingID))
+		return "", nil, err
+	}
+	u.log.Info(ctx, "notification deduplicated", port.F("notification_id", earlier.ID), port.F("channel", ch))
+	return "", earlier, nil
+}
+
+// dedupeHash hashes the content that makes two notifications of a tenant duplicates
+// of each other.
+func dedupeHash(tenantID string, ch notification.Channel, recipient, content string) string {
+	h := sha256.New()
+	for _, part := range []string{tenantID, ch.String(), recipient, content} {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// reserveKey reserves a notification idempotency key for the request fingerprint fp.
+// When the key was already used it returns the original notification as a replay,
+// ErrIdempotencyConflict if the payload differs, or ErrDuplicateRequest while the
+// original request is still in flight.
+func (u *UseCase) reserveKey(ctx context.Context, tenantID, key, fp string) (*Result, error) {
+	reserved, existing, err := u.idem.Reserve(ctx, storeKey(tenantID, key), &port.IdempotencyRecord{Fingerprint: fp}, idempotencyTTLSeconds)
+	if err != nil {
+		u.log.Warn(ctx, "redis idempotency check failed, falling back to DB",
+			port.F("error", err), port.F("key", key))
+		return u.replayFromDB(ctx, key, fp, nil)
+	}
+	if reserved {
+		return nil, nil
+	}
+
+	if existing != nil && existing.Fingerprint != "" && existing.Fingerprint != fp {
+		u.log.Warn(ctx, "idempotency key reused with different payload (redis)", port.F("key", key))
+		return nil, notification.ErrIdempotencyConflict
+	}
+	if existing != nil && existing.ResourceID != "" {
+		n, err := u.repo.GetByID(ctx, existing.ResourceID)
+		if err == nil {
+			u.log.Info(ctx, "idempotency key replayed (redis)", port.F("key", key), port.F("notification_id", n.ID))
+			return &Result{Notification: redacted(n), Replayed: true}, nil
+		}
+		if !errors.Is(err, notification.ErrNotFound) {
+			u.log.Error(ctx, "failed to load replayed notification", port.F("error", err), port.F("key", key))
+			return nil, err
+		}
+	}
+
+	// No usable record: the original is in flight unless the DB already has it.
+	u.log.Warn(ctx, "duplicate idempotency key (redis)", port.F("key", key))
+	return u.replayFromDB(ctx, key, fp, notification.ErrDuplicateRequest)
+}
+
+// replayFromDB resolves a used key from the DB. If no notification holds the key it
+// returns notFoundErr, which is nil when the request may proceed.
+func (u *UseCase) replayFromDB(ctx context.Context, key, fp string, notFoundErr error) (*Result, error) {
+	n, err := u.repo.GetByIdempotencyKey(ctx, key)
+	if errors.Is(err, notification.ErrNotFound) {
+		return nil, notFoundErr
+	}
+	if err != nil {
+		u.log.Error(ctx, "db idempotency check failed", port.F("error", err), port.F("key", key))
+		return nil, err
+	}
+	if storedFingerprint(n) != fp {
+		u.log.Warn(ctx, "idempotency key reused with different payload (db)", port.F("key", key))
+		return nil, notification.ErrIdempotencyConflict
+	}
+	u.log.Info(ctx, "idempotency key replayed (db)", port.F("key", key), port.F("notification_id", n.ID))
+	return &Result{Notification: redacted(n), Replayed: true}, nil
+}
+
+// fingerprint hashes the fields that define a notification request.
+func fingerprint(ch notification.Channel, recipient, content string, pr notification.Priority) string {
+	h := sha256.New()
+	for _, part := range []string{ch.String(), recipient, content, pr.String()} {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// requestFingerprint is the fingerprint of a single create. For a user's notification
+// it covers the user and category instead of the resolved channel and address, so a
+// replay still matches after the user's profile changed.
+func requestFingerprint(ch notification.Channel, recipient, content string, pr notification.Priority, res *notification.Resolution) string {
+	if res != nil {
+		return fingerprint("user", res.UserID+"\x00"+res.Category, content, pr)
+	}
+	return fingerprint(ch, recipient, content, pr)
+}
+
+// fallbackFingerprint is the fingerprint of a fallback chain create.
+func fallbackFingerprint(steps []notification.FallbackStep, content string, pr notification.Priority) string {
+	parts := make([]string, len(steps))
+	for i, s := range steps {
+		parts[i] = fmt.Sprintf("%s\x00%s\x00%d", s.Channel, s.Recipient, s.Timeout)
+	}
+	return fingerprint("fallback", strings.Join(parts, "\x00"), content, pr)
+}
+
+// batchFingerprint hashes the mode and items of a batch request, so a replayed batch
+// key must come with the same items.
+func batchFingerprint(cmd *BatchCommand) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "partial=%t\n", cmd.PartialAccept)
+	for _, item := range cmd.Items {
+		key, window := "", ""
+		if item.IdempotencyKey != nil {
+			key = *item.IdempotencyKey
+		}
+		if w := item.DeliveryWindow; w != nil {
+			window = fmt.Sprintf("%d-%d %s", w.Start, w.End, w.TimeZone)
+		}
+		fmt.Fprintf(h, "%s\x00%s\x00%s\x00%s\x00%s\x00%s\x00%t\x00%s", item.Channel, item.Recipient, item.Content, item.Priority, key, window, item.Track, item.Locale)
+		locales := make([]string, 0, len(item.Variants))
+		for l := range item.Variants {
+			locales = append(locales, l)
+		}
+		sort.Strings(locales)
+		for _, l := range locales {
+			fmt.Fprintf(h, "\x00%s=%s", l, item.Variants[l])
+		}
+		h.Write([]byte{'\n'})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// storedFingerprint is the fingerprint of the request that created n.
+func storedFingerprint(n *notification.Notification) string {
+	if n.Fallback != nil {
+		return fallbackFingerprint(n.Fallback.Steps, n.Content, n.Priority)
+	}
+	return requestFingerprint(n.Channel, n.Recipient, n.Content, n.Priority, n.Resolution)
+}
+
+// localize returns the content variant for locale, tried along its fallback chain
+// (see notification.LocaleChain), and the variant's locale. Without variants, or
+// when none matches, it returns content and no locale.
+func (u *UseCase) localize(content string, variants notification.Variants, locale string) (string, string, error) {
+	if locale != "" && !notification.ValidLocale(locale) {
+		return "", "", notification.ErrInvalidLocale
+	}
+	if len(variants) == 0 {
+		return content, "", nil
+	}
+	if variant, text, ok := variants.Resolve(locale, u.locales); ok {
+		return text, variant, nil
+	}
+	if content == "" {
+		return "", "", notification.ErrNoVariant
+	}
+	return content, "", nil
+}
+
+// resolveUser returns a copy of cmd addressed to the channel and address the user's
+// profile picks for its category, and the resolution to store with the notification.
+func (u *UseCase) resolveUser(ctx context.Context, cmd *Command) (*Command, *notification.Resolution, error) {
+	if u.profiles == nil {
+		return nil, nil, profile.ErrNotFound
+	}
+	category := cmd.Category
+	if category == "" {
+		category = profile.DefaultCategory
+	}
+	p, err := u.profiles.GetByID(ctx, cmd.UserID)
+	if err != nil {
+		if !errors.Is(err, profile.ErrNotFound) {
+			u.log.Error(ctx, "failed to load user profile", port.F("error", err), port.F("user_id", cmd.UserID))
+		}
+		return nil, nil, err
+	}
+	target, err := p.Resolve(category, cmd.CanSend)
+	if err != nil {
+		u.log.Warn(ctx, "user not resolved", port.F("error", err), port.F("user_id", cmd.UserID), port.F("category", category))
+		return nil, nil, err
+	}
+
+	resolved := *cmd
+	resolved.Channel = target.Channel.String()
+	resolved.Recipient = target.Address
+	resolved.DeliveryWindow = u.userWindow(ctx, tenantOrDefault(cmd.TenantID), category, p.TimeZone, cmd.DeliveryWindow)
+	resolved.Track = cmd.Track && !p.TrackingOptOut
+	if resolved.Locale == "" {
+		resolved.Locale = p.Locale
+	}
+	return &resolved, &target.Resolution, nil
+}
+
+// trackingOptOuts returns which email recipients, lowercased, belong to a user who
+// opted out of tracking, so emails sent by address respect the opt-out too. Other
+// channels are never tracked. A failed lookup counts every recipient as opted out.
+func (u *UseCase) trackingOptOuts(ctx context.Context, recipients []string, ch notification.Channel) map[string]bool {
+	if u.profiles == nil || ch != notification.ChannelEmail || len(recipients) == 0 {
+		return nil
+	}
+	out, err := u.profiles.TrackingOptOuts(ctx, recipients)
+	if err != nil {
+		u.log.Warn(ctx, "failed to load tracking opt-outs, sending untracked", port.F("error", err))
+		out = make(map[string]bool, len(recipients))
+		for _, r := range recipients {
+			out[strings.ToLower(r)] = true
+		}
+	}
+	return out
+}
+
+// profileLocales returns the profile locale of the users reached at addresses on
+// ch, by addressKey, for content variants sent without a locale. A failed lookup
+// leaves them to the fallback chain.
+func (u *UseCase) profileLocales(ctx context.Context, ch notification.Channel, addresses []string) map[string]string {
+	if u.profiles == nil || len(addresses) == 0 {
+		return nil
+	}
+	out, err := u.profiles.Locales(ctx, ch, addresses)
+	if err != nil {
+		u.log.Warn(ctx, "failed to load profile locales, using fallback", port.F("error", err), port.F("channel", ch))
+		return nil
+	}
+	return out
+}
+
+// addressKey is how a recipient on ch is matched to a profile address: email
+// regardless of case.
+func addressKey(ch notification.Channel, recipient string) string {
+	if ch == notification.ChannelEmail {
+		return strings.ToLower(recipient)
+	}
+	return recipient
+}
+
+// userWindow returns the delivery window of a user's notification: the requested
+// one, else the tenant's for the category, in the user's time zone unless the
+// window names its own. A failed tenant lookup sends without the category window.
+func (u *UseCase) userWindow(ctx context.Context, tenantID, category, timeZone string, requested *notification.DeliveryWindow) *notification.DeliveryWindow {
+	w := requested
+	if w == nil && u.tenants != nil {
+		t, err := u.tenants.GetByID(ctx, tenantID)
+		if err != nil {
+			u.log.Warn(ctx, "failed to load delivery windows, skipping", port.F("error", err), port.F("tenant_id", tenantID))
+			return nil
+		}
+		w, _ = t.DeliveryWindow(category)
+	}
+	if w == nil || w.TimeZone != "" || timeZone == "" {
+		return w
+	}
+	local := *w
+	local.TimeZone = timeZone
+	return &local
+}
+
+// digestDue returns when n's digest is due if n is a user's low priority
+// notification whose category has a digest policy. A failed tenant lookup sends it
+// on its own.
+func (u *UseCase) digestDue(ctx context.Context, n *notification.Notification) (time.Time, bool) {
+	if u.digests == nil || n.Resolution == nil || n.Priority != notification.PriorityLow {
+		return time.Time{}, false
+	}
+	t, err := u.tenants.GetByID(ctx, n.TenantID)
+	if err != nil {
+		u.log.Warn(ctx, "failed to load digest policies, skipping", port.F("error", err), port.F("tenant_id", n.TenantID))
+		return time.Time{}, false
+	}
+	p, ok := t.DigestPolicy(n.Resolution.Category)
+	if !ok {
+		return time.Time{}, false
+	}
+	return n.CreatedAt.Add(p.Window), true
 }
 
 // CreateNotificationBatches creates a batch and its notifications.
 func (u *UseCase) CreateNotificationBatches(ctx context.Context, cmd *BatchCommand) (*BatchResult, error) {
+	result, err := u.createNotificationBatches(ctx, cmd)
+	if errors.Is(err, errKeyTaken) {
+		// The second attempt sees the keys stored meanwhile: their items are reported
+		// as duplicates, or the batch is replayed.
+		u.log.Warn(ctx, "idempotency key taken while creating batch, retrying", port.F("item_count", len(cmd.Items)))
+		result, err = u.createNotificationBatches(ctx, cmd)
+	}
+	if errors.Is(err, errKeyTaken) {
+		return nil, notification.ErrDuplicateRequest
+	}
+	return result, err
+}
+
+func (u *UseCase) createNotificationBatches(ctx context.Context, cmd *BatchCommand) (*BatchResult, error) {
 	if len(cmd.Items) == 0 || len(cmd.Items) > notification.MaxBatchSize {
 		u.log.Warn(ctx, "batch size invalid", port.F("size", len(cmd.Items)))
 		return nil, notification.ErrBatchTooLarge
 	}
 
-	batchID := uuid.New().String()
-	now := time.Now()
-
-	b := &notification.Batch{
-		ID:             batchID,
-		IdempotencyKey: cmd.IdempotencyKey,
-		CreatedAt:      now,
+	tenantID := tenantOrDefault(cmd.TenantID)
+	existingBatch := cmd.BatchID != ""
+	hasBatchKey := !existingBatch && cmd.IdempotencyKey != nil && *cmd.IdempotencyKey != ""
+	var fp string
+	if hasBatchKey {
+		fp = batchFingerprint(cmd)
+		replay, err := u.reserveBatchKey(ctx, tenantID, *cmd.IdempotencyKey, fp)
+		if err != nil || replay != nil {
+			return replay, err
+		}
 	}
 
-	if err := u.batch.Create(ctx, b); err != nil {
-		u.log.Error(ctx, "failed to create batch", port.F("error", err), port.F("batch_id", batchID))
-		return nil, err
+	batchID := cmd.BatchID
+	if !existingBatch {
+		batchID = uuid.New().String()
 	}
-
-	u.log.Info(ctx, "batch created", port.F("batch_id", batchID), port.F("item_count", len(cmd.Items)))
+	now := time.Now()
 
 	var notifications []*notification.Notification
 	var events []*port.NotificationEvent
+	var reserved []string
+	items := make([]ItemResult, len(cmd.Items))
+	seen := make(map[string]int)
 	skipped := 0
 
-	// First pass: validate and build notification entities
+	// Release every key reserved by this request if it does not complete.
+	release := func() {
+		keys := reserved
+		if hasBatchKey {
+			keys = append(keys, storeKey(tenantID, batchIdempotencyPrefix+*cmd.IdempotencyKey))
+		}
+		for _, k := range keys {
+			if err := u.idem.Delete(ctx, k); err != nil {
+				u.log.Warn(ctx, "failed to release idempotency key", port.F("error", err), port.F("key", k))
+			}
+		}
+	}
+
+	var tracked []string
+	for _, item := range cmd.Items {
+		if item.Track && notification.Channel(item.Channel) == notification.ChannelEmail {
+			tracked = append(tracked, item.Recipient)
+		}
+	}
+	optedOut := u.trackingOptOuts(ctx, tracked, notification.ChannelEmail)
+
+	var keys []string
+	unlocalized := make(map[notification.Channel][]string)
 	for _, item := range cmd.Items {
-		ch := notification.Channel(item.Channel)
-		if !ch.Valid() {
+		if item.IdempotencyKey != nil && *item.IdempotencyKey != "" {
+			keys = append(keys, *item.IdempotencyKey)
+		}
+		if len(item.Variants) > 0 && item.Locale == "" {
+			ch := notification.Channel(item.Channel)
+			unlocalized[ch] = append(unlocalized[ch], item.Recipient)
+		}
+	}
+	profileLocales := make(map[notification.Channel]map[string]string, len(unlocalized))
+	for ch, recipients := range unlocalized {
+		profileLocales[ch] = u.profileLocales(ctx, ch, recipients)
+	}
+	var stored map[string]string
+	if len(keys) > 0 {
+		var err error
+		if stored, err = u.repo.IDsByIdempotencyKey(ctx, keys); err != nil {
+			u.log.Error(ctx, "db idempotency check failed", port.F("error", err))
+			release()
+			return nil, err
+		}
+	}
+
+	// First pass: validate and build notification entities
+	for i, item := range cmd.Items {
+		items[i].Index = i
+		pr, errs := validateBatchItem(item, cmd.PartialAccept)
+		if len(errs) > 0 {
+			items[i].Status = ItemStatusRejected
+			items[i].Errors = errs
 			skipped++
 			continue
 		}
-		pr := notification.Priority(item.Priority)
-		if !pr.Valid() {
-			pr = notification.PriorityNormal
+
+		wantLocale := item.Locale
+		if wantLocale == "" {
+			ch := notification.Channel(item.Channel)
+			wantLocale = profileLocales[ch][addressKey(ch, item.Recipient)]
+		}
+		content, locale, err := u.localize(item.Content, item.Variants, wantLocale)
+		if err != nil && !cmd.PartialAccept {
+			release()
+			return nil, err
 		}
-		if len(item.Content) > notification.MaxContentLength(ch) || len(item.Content) == 0 || len(item.Recipient) == 0 {
+		if err != nil {
+			items[i].Status = ItemStatusRejected
+			items[i].Errors = []notification.FieldError{{Field: "content_variants", Message: err.Error()}}
 			skipped++
 			continue
 		}
 
 		id := uuid.New().String()
+		if key := item.IdempotencyKey; key != nil && *key != "" {
+			if first, ok := seen[*key]; ok {
+				items[i].Status = ItemStatusDuplicate
+				items[i].NotificationID = items[first].NotificationID
+				skipped++
+				continue
+			}
+			if existingID, dup := u.reserveItemKey(ctx, tenantID, *key, stored); dup {
+				items[i].Status = ItemStatusDuplicate
+				items[i].NotificationID = existingID
+				skipped++
+				continue
+			}
+			seen[*key] = i
+			reserved = append(reserved, storeKey(tenantID, *key))
+		}
+
 		n := &notification.Notification{
-			ID:        id,
-			BatchID:   &batchID,
-			Recipient: item.Recipient,
-			Channel:   ch,
-			Content:   item.Content,
-			Priority:  pr,
-			Status:    notification.StatusPending,
-			CreatedAt: now,
-			UpdatedAt: now,
+			ID:             id,
+			TenantID:       tenantID,
+			BatchID:        &batchID,
+			Recipient:      item.Recipient,
+			Channel:        notification.Channel(item.Channel),
+			Content:        content,
+			Locale:         locale,
+			Priority:       pr,
+			Status:         notification.StatusPending,
+			IdempotencyKey: item.IdempotencyKey,
+			ClientID:       cmd.ClientID,
+			CreatedAt:      now,
+			UpdatedAt:      now,
+			DeliveryWindow: item.DeliveryWindow,
+			Tracking:       item.Track && !optedOut[strings.ToLower(item.Recipient)],
 		}
 
+		items[i].Status = ItemStatusAccepted
+		items[i].NotificationID = id
 		notifications = append(notifications, n)
 		events = append(events, &port.NotificationEvent{
 			NotificationID: id,
+			TenantID:       tenantID,
 			BatchID:        &batchID,
-			Recipient:      item.Recipient,
-			Channel:        ch,
-			Content:        item.Content,
+			Recipient:      n.Recipient,
+			Channel:        n.Channel,
+			Content:        n.Content,
 			Priority:       pr,
+			IdempotencyKey: item.IdempotencyKey,
 			CreatedAt:      now.Format("2006-01-02T15:04:05Z07:00"),
 		})
 	}
 
-	// Second pass: bulk insert all valid notifications
-	if len(notifications) > 0 {
-		if err := u.repo.CreateBatch(ctx, notifications); err != nil {
-			u.log.Error(ctx, "failed to create batch notifications in DB", port.F("error", err), port.F("batch_id", batchID))
-			return nil, err
+	if skipped > 0 {
+		u.log.Warn(ctx, "some batch items rejected or duplicated", port.F("batch_id", batchID), port.F("skipped", skipped))
+	}
+	if len(notifications) == 0 {
+		u.log.Warn(ctx, "no new batch items, batch not created", port.F("item_count", len(cmd.Items)))
+		release()
+		if !cmd.PartialAccept {
+			return u.replayDuplicates(ctx, items)
 		}
+		return &BatchResult{Items: items}, nil
 	}
 
-	if skipped > 0 {
-		u.log.Warn(ctx, "some batch items skipped", port.F("batch_id", batchID), port.F("skipped", skipped))
+	usage := make(map[notification.Channel]int)
+	for _, n := range notifications {
+		usage[n.Channel]++
+	}
+	consumed, ok := u.consumeQuota(ctx, tenantID, usage)
+	if !ok {
+		release()
+		return nil, notification.ErrQuotaExceeded
 	}
 
+	var b *notification.Batch
+	if !existingBatch {
+		b = &notification.Batch{
+			ID:             batchID,
+			TenantID:       tenantID,
+			IdempotencyKey: cmd.IdempotencyKey,
+			ClientID:       cmd.ClientID,
+			Fingerprint:    fp,
+			CreatedAt:      now,
+		}
+	}
+
+	// Second pass: insert the batch and all valid notifications together
+	if err := u.repo.CreateBatch(ctx, b, notifications); err != nil {
+		release()
+		u.refundQuota(ctx, tenantID, consumed)
+		if isUniqueViolation(err) {
+			u.log.Warn(ctx, "duplicate batch or item idempotency key (db constraint)", port.F("batch_id", batchID))
+			return nil, errKeyTaken
+		}
+		u.log.Error(ctx, "failed to create batch notifications in DB", port.F("error", err), port.F("batch_id", batchID))
+		return nil, err
+	}
+	if b != nil {
+		u.log.Info(ctx, "batch created", port.F("batch_id", batchID), port.F("item_count", len(cmd.Items)))
+	}
+
+	result := &BatchResult{BatchID: batchID, Notifications: notifications, Items: items}
+
 	if err := u.pub.PublishBatch(ctx, events); err != nil {
 		u.log.Error(ctx, "failed to publish batch events", port.F("error", err), port.F("batch_id", batchID))
-		return &BatchResult{BatchID: batchID, Notifications: notifications}, err
+		return result, err
 	}
 
-	for _, n := range notifications {
-		if err := u.repo.UpdateStatus(ctx, n.ID, notification.StatusQueued, nil, nil); err != nil {
-			u.log.Error(ctx, "failed to update status to queued", port.F("error", err), port.F("notification_id", n.ID))
-		}
+	ids := make([]string, len(notifications))
+	for i, n := range notifications {
+		ids[i] = n.ID
 		n.Status = notification.StatusQueued
 	}
+	if err := u.repo.UpdateStatuses(ctx, ids, notification.StatusQueued); err != nil {
+		u.log.Error(ctx, "failed to update statuses to queued", port.F("error", err), port.F("batch_id", batchID))
+	}
 
 	u.log.Info(ctx, "batch events published", port.F("batch_id", batchID), port.F("notification_count", len(notifications)))
-	return &BatchResult{BatchID: batchID, Notifications: notifications}, nil
+	return result, nil
+}
+
+// replayDuplicates answers a batch whose items were all created before under their
+// own idempotency keys with those notifications, as a replay. It returns
+// ErrDuplicateRequest while any of them is still being created.
+func (u *UseCase) replayDuplicates(ctx context.Context, items []ItemResult) (*BatchResult, error) {
+	result := &BatchResult{Items: items, Replayed: true}
+	loaded := make(map[string]bool)
+	batchIDs := make(map[string]bool)
+	for _, it := range items {
+		if it.Status != ItemStatusDuplicate || it.NotificationID == "" {
+			return nil, notification.ErrDuplicateRequest
+		}
+		if loaded[it.NotificationID] {
+			continue
+		}
+		loaded[it.NotificationID] = true
+		n, err := u.repo.GetByID(ctx, it.NotificationID)
+		if err != nil {
+			u.log.Error(ctx, "failed to load duplicate batch item", port.F("error", err), port.F("notification_id", it.NotificationID))
+			return nil, err
+		}
+		result.Notifications = append(result.Notifications, redacted(n))
+		if n.BatchID != nil {
+			batchIDs[*n.BatchID] = true
+		}
+	}
+	// Items from one earlier batch report it; items spread over several report none.
+	if len(batchIDs) == 1 {
+		for id := range batchIDs {
+			result.BatchID = id
+		}
+	}
+	return result, nil
+}
+
+// reserveBatchKey reserves a batch idempotency key for the request fingerprint fp.
+// When the key was already used it returns the original batch as a replay,
+// ErrIdempotencyConflict if the items differ, or ErrDuplicateRequest while that batch
+// is still being created.
+func (u *UseCase) reserveBatchKey(ctx context.Context, tenantID, key, fp string) (*BatchResult, error) {
+	reserved, existing, err := u.idem.Reserve(ctx, storeKey(tenantID, batchIdempotencyPrefix+key), &port.IdempotencyRecord{Fingerprint: fp}, idempotencyTTLSeconds)
+	if err == nil && reserved {
+		return nil, nil
+	}
+	if err != nil {
+		u.log.Warn(ctx, "redis batch idempotency check failed, falling back to DB",
+			port.F("error", err), port.F("key", key))
+	}
+	if existing != nil && existing.Fingerprint != "" && existing.Fingerprint != fp {
+		u.log.Warn(ctx, "batch idempotency key reused with different items (redis)", port.F("key", key))
+		return nil, notification.ErrIdempotencyConflict
+	}
+
+	b, dbErr := u.batch.GetByIdempotencyKey(ctx, key)
+	switch {
+	case errors.Is(dbErr, notification.ErrNotFound):
+		if err != nil {
+			// Redis unavailable and the key is unknown to the DB: not a replay.
+			return nil, nil
+		}
+		u.log.Warn(ctx, "batch with idempotency key still in progress", port.F("key", key))
+		return nil, notification.ErrDuplicateRequest
+	case dbErr != nil:
+		u.log.Error(ctx, "db batch idempotency check failed", port.F("error", dbErr), port.F("key", key))
+		return nil, dbErr
+	case b.AudienceID != nil, b.Fingerprint != "" && b.Fingerprint != fp:
+		u.log.Warn(ctx, "batch idempotency key reused with different items (db)", port.F("key", key))
+		return nil, notification.ErrIdempotencyConflict
+	}
+
+	list, err := u.repo.GetByBatchID(ctx, b.ID)
+	if err != nil {
+		u.log.Error(ctx, "failed to load replayed batch", port.F("error", err), port.F("batch_id", b.ID))
+		return nil, err
+	}
+	for _, n := range list {
+		redacted(n)
+	}
+	u.log.Info(ctx, "batch idempotency key replayed", port.F("key", key), port.F("batch_id", b.ID))
+	return &BatchResult{BatchID: b.ID, Notifications: list, Replayed: true}, nil
+}
+
+// reserveItemKey reserves one item's idempotency key. dup reports that the key was
+// already used; existingID is the notification created with it, unless that one is
+// still being created. stored maps the keys already in the DB to their notifications.
+func (u *UseCase) reserveItemKey(ctx context.Context, tenantID, key string, stored map[string]string) (existingID string, dup bool) {
+	if id, ok := stored[key]; ok {
+		return id, true
+	}
+	set, err := u.idem.SetIfNotExists(ctx, storeKey(tenantID, key), idempotencyTTLSeconds)
+	if err != nil {
+		// Redis unavailable and the key is unknown to the DB: not a duplicate.
+		u.log.Warn(ctx, "redis idempotency check failed, relying on DB", port.F("error", err), port.F("key", key))
+		return "", false
+	}
+	// A key Redis already holds was reserved by a request not persisted yet.
+	return "", !set
+}
+
+// consumeQuota takes usage from the tenant's daily channel quotas. It reports false,
+// with nothing consumed, when any channel would go over its quota. Quota store errors
+// let the request through; consumed holds only what was actually taken.
+func (u *UseCase) consumeQuota(ctx context.Context, tenantID string, usage map[notification.Channel]int) (consumed map[notification.Channel]int, ok bool) {
+	if u.quota == nil {
+		return nil, true
+	}
+	channels := make([]notification.Channel, 0, len(usage))
+	for ch := range usage {
+		channels = append(channels, ch)
+	}
+	sort.Slice(channels, func(i, j int) bool { return channels[i] < channels[j] })
+
+	consumed = make(map[notification.Channel]int, len(usage))
+	for _, ch := range channels {
+		n := usage[ch]
+		allowed, err := u.quota.Consume(ctx, tenantID, ch, n)
+		if err != nil {
+			u.log.Warn(ctx, "quota check failed, skipping", port.F("error", err), port.F("tenant_id", tenantID), port.F("channel", ch))
+			continue
+		}
+		if !allowed {
+			u.log.Warn(ctx, "daily quota exceeded", port.F("tenant_id", tenantID), port.F("channel", ch), port.F("requested", n))
+			u.refundQuota(ctx, tenantID, consumed)
+			return nil, false
+		}
+		consumed[ch] = n
+	}
+	return consumed, true
+}
+
+// refundQuota gives back quota taken by a request that was not created.
+func (u *UseCase) refundQuota(ctx context.Context, tenantID string, consumed map[notification.Channel]int) {
+	for ch, n := range consumed {
+		if err := u.quota.Refund(ctx, tenantID, ch, n); err != nil {
+			u.log.Warn(ctx, "failed to refund quota", port.F("error", err), port.F("tenant_id", tenantID), port.F("channel", ch))
+		}
+	}
+}
+
+// redacted returns n, loaded again for a replay, with its recipient as reads show
+// it: a chat webhook URL is a credential.
+func redacted(n *notification.Notification) *notification.Notification {
+	n.Recipient = notification.RedactRecipient(n.Channel, n.Recipient)
+	return n
+}
+
+// tenantOrDefault returns id, or the default tenant when it is empty.
+func tenantOrDefault(id string) string {
+	if id == "" {
+		return tenant.DefaultID
+	}
+	return id
+}
+
+// storeKey namespaces an idempotency key by tenant. Default tenant keys stay
+// unprefixed so keys stored before tenants existed still match.
+func storeKey(tenantID, key string) string {
+	if tenantID == tenant.DefaultID {
+		return key
+	}
+	return tenantID + ":" + key
+}
+
+// ItemStatus is the outcome of one batch item.
+type ItemStatus string
+
+const (
+	ItemStatusAccepted  ItemStatus = "accepted"
+	ItemStatusRejected  ItemStatus = "rejected"
+	ItemStatusDuplicate ItemStatus = "duplicate" // idempotency key already used
+)
+
+// ItemResult reports the outcome of the batch item at Index.
+type ItemResult struct {
+	Index          int
+	Status         ItemStatus
+	NotificationID string
+	Errors         []notification.FieldError
 }
 
 type BatchResult struct {
 	BatchID       string
 	Notifications []*notification.Notification
+	Items         []ItemResult // not reported for batch key replays
+	// Replayed reports that nothing was created: the batch idempotency key was already
+	// used and this is the original batch, or every item's own key was and these are
+	// the notifications created with them.
+	Replayed bool
+}
+
+// Accepted returns the number of accepted items.
+func (r *BatchResult) Accepted() int {
+	if r.Replayed {
+		return len(r.Notifications)
+	}
+	n := 0
+	for _, it := range r.Items {
+		if it.Status == ItemStatusAccepted {
+			n++
+		}
+	}
+	return n
+}
+
+// validateBatchItem returns the item's priority and its field errors. An empty priority
+// defaults to normal; an unknown one is coerced to normal unless strictPriority is set.
+func validateBatchItem(item BatchItem, strictPriority bool) (notification.Priority, []notification.FieldError) {
+	var errs []notification.FieldError
+
+	if item.Recipient == "" {
+		errs = append(errs, notification.FieldError{Field: "recipient", Message: "recipient is required"})
+	} else if len(item.Recipient) > notification.MaxRecipientLength {
+		errs = append(errs, notification.FieldError{
+			Field:   "recipient",
+			Message: fmt.Sprintf("recipient must be at most %d characters", notification.MaxRecipientLength),
+		})
+	}
+
+	ch := notification.Channel(item.Channel)
+	if !ch.Valid() {
+		errs = append(errs, notification.FieldError{Field: "channel", Message: "channel must be one of: " + notification.ChannelList()})
+	} else if item.Recipient != "" {
+		if err := notification.ValidateRecipient(ch, item.Recipient); err != nil {
+			errs = append(errs, notification.FieldError{Field: "recipient", Message: err.Error()})
+		}
+	}
+
+	if item.Content == "" && len(item.Variants) == 0 {
+		errs = append(errs, notification.FieldError{Field: "content", Message: "content or content_variants is required"})
+	} else if ch.Valid() && len(item.Content) > notification.MaxContentLength(ch) {
+		errs = append(errs, notification.FieldError{
+			Field:   "content",
+			Message: fmt.Sprintf("content must be at most %d characters for channel %s", notification.MaxContentLength(ch), ch),
+		})
+	} else if err := notification.ValidateContent(ch, item.Content); err != nil {
+		errs = append(errs, notification.FieldError{Field: "content", Message: err.Error()})
+	}
+
+	if ch.Valid() {
+		if err := notification.ValidateVariants(ch, item.Variants); err != nil {
+			errs = append(errs, notification.FieldError{Field: "content_variants", Message: notification.VariantsMessage(ch, err)})
+		}
+	}
+	if item.Locale != "" && !notification.ValidLocale(item.Locale) {
+		errs = append(errs, notification.FieldError{Field: "locale", Message: notification.ErrInvalidLocale.Error()})
+	}
+
+	if w := item.DeliveryWindow; w != nil && w.Validate() != nil {
+		errs = append(errs, notification.FieldError{Field: "delivery_window", Message: "delivery_window needs different HH:MM start and end times and an IANA time_zone"})
+	}
+
+	pr := notification.Priority(item.Priority)
+	if item.Priority == "" {
+		pr = notification.PriorityNormal
+	} else if !pr.Valid() {
+		if strictPriority {
+			errs = append(errs, notification.FieldError{Field: "priority", Message: "priority must be one of: high, normal, low"})
+		}
+		pr = notification.PriorityNormal
+	}
+
+	return pr, errs
 }
 
 func isUniqueViolation(err error) bool {
@@ -3,18 +3,25 @@ package create
 import (
 	"context"
 	"errors"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/semih-yildiz/notification-service/internal/application/notification/port"
 	"github.com/semih-yildiz/notification-service/internal/domain/notification"
+	"github.com/semih-yildiz/notification-service/internal/domain/profile"
+	"github.com/semih-yildiz/notification-service/internal/domain/tenant"
 )
 
 type mockNotificationRepo struct {
 	createFn                 func(ctx context.Context, n *notification.Notification) error
-	createBatchFn            func(ctx context.Context, notifications []*notification.Notification) error
+	createBatchFn            func(ctx context.Context, b *notification.Batch, notifications []*notification.Notification) error
 	updateStatusFn           func(ctx context.Context, id string, status notification.Status, sentAt *time.Time, reason *string) error
+	updateStatusesFn         func(ctx context.Context, ids []string, status notification.Status) error
 	existsByIdempotencyKeyFn func(ctx context.Context, key string) (bool, error)
+	getByIdempotencyKeyFn    func(ctx context.Context, key string) (*notification.Notification, error)
+	getByBatchIDFn           func(ctx context.Context, batchID string) ([]*notification.Notification, error)
+	getByIDFn                func(ctx context.Context, id string) (*notification.Notification, error)
 }
 
 func (m *mockNotificationRepo) Create(ctx context.Context, n *notification.Notification) error {
@@ -24,9 +31,9 @@ func (m *mockNotificationRepo) Create(ctx context.Context, n *notification.Notif
 	return nil
 }
 
-func (m *mockNotificationRepo) CreateBatch(ctx context.Context, notifications []*notification.Notification) error {
+func (m *mockNotificationRepo) CreateBatch(ctx context.Context, b *notification.Batch, notifications []*notification.Notification) error {
 	if m.createBatchFn != nil {
-		return m.createBatchFn(ctx, notifications)
+		return m.createBatchFn(ctx, b, notifications)
 	}
 	return nil
 }
@@ -38,6 +45,13 @@ func (m *mockNotificationRepo) UpdateStatus(ctx context.Context, id string, stat
 	return nil
 }
 
+func (m *mockNotificationRepo) UpdateStatuses(ctx context.Context, ids []string, status notification.Status) error {
+	if m.updateStatusesFn != nil {
+		return m.updateStatusesFn(ctx, ids, status)
+	}
+	return nil
+}
+
 func (m *mockNotificationRepo) ExistsByIdempotencyKey(ctx context.Context, key string) (bool, error) {
 	if m.existsByIdempotencyKeyFn != nil {
 		return m.existsByIdempotencyKeyFn(ctx, key)
@@ -45,11 +59,40 @@ func (m *mockNotificationRepo) ExistsByIdempotencyKey(ctx context.Context, key s
 	return false, nil
 }
 
+func (m *mockNotificationRepo) GetByIdempotencyKey(ctx context.Context, key string) (*notification.Notification, error) {
+	if m.getByIdempotencyKeyFn != nil {
+		return m.getByIdempotencyKeyFn(ctx, key)
+	}
+	return nil, notification.ErrNotFound
+}
+
+// IDsByIdempotencyKey looks each key up through GetByIdempotencyKey.
+func (m *mockNotificationRepo) IDsByIdempotencyKey(ctx context.Context, keys []string) (map[string]string, error) {
+	ids := make(map[string]string)
+	for _, key := range keys {
+		n, err := m.GetByIdempotencyKey(ctx, key)
+		if errors.Is(err, notification.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		ids[key] = n.ID
+	}
+	return ids, nil
+}
+
 func (m *mockNotificationRepo) GetByID(ctx context.Context, id string) (*notification.Notification, error) {
+	if m.getByIDFn != nil {
+		return m.getByIDFn(ctx, id)
+	}
 	return nil, errors.New("not implemented")
 }
 
 func (m *mockNotificationRepo) GetByBatchID(ctx context.Context, batchID string) ([]*notification.Notification, error) {
+	if m.getByBatchIDFn != nil {
+		return m.getByBatchIDFn(ctx, batchID)
+	}
 	return nil, errors.New("not implemented")
 }
 
@@ -66,7 +109,8 @@ func (m *mockNotificationRepo) CancelPendingByBatchID(ctx context.Context, batch
 }
 
 type mockBatchRepo struct {
-	createFn func(ctx context.Context, b *notification.Batch) error
+	createFn              func(ctx context.Context, b *notification.Batch) error
+	getByIdempotencyKeyFn func(ctx context.Context, key string) (*notification.Batch, error)
 }
 
 func (m *mockBatchRepo) Create(ctx context.Context, b *notification.Batch) error {
@@ -80,6 +124,17 @@ func (m *mockBatchRepo) GetByID(ctx context.Context, id string) (*notification.B
 	return nil, errors.New("not implemented")
 }
 
+func (m *mockBatchRepo) GetByIdempotencyKey(ctx context.Context, key string) (*notification.Batch, error) {
+	if m.getByIdempotencyKeyFn != nil {
+		return m.getByIdempotencyKeyFn(ctx, key)
+	}
+	return nil, notification.ErrNotFound
+}
+
+func (m *mockBatchRepo) FinishExpansion(ctx context.Context, id string) error {
+	return nil
+}
+
 func (m *mockBatchRepo) GetNotificationsByBatchID(ctx context.Context, batchID string) ([]*notification.Notification, error) {
 	return nil, errors.New("not implemented")
 }
@@ -106,6 +161,9 @@ func (m *mockPublisher) PublishBatch(ctx context.Context, events []*port.Notific
 type mockIdempotencyStore struct {
 	setIfNotExistsFn func(ctx context.Context, key string, ttl int) (bool, error)
 	existsFn         func(ctx context.Context, key string) (bool, error)
+	reserveFn        func(ctx context.Context, key string, rec *port.IdempotencyRecord, ttl int) (bool, *port.IdempotencyRecord, error)
+	completeFn       func(ctx context.Context, key string, rec *port.IdempotencyRecord, ttl int) error
+	deleteFn         func(ctx context.Context, key string) error
 }
 
 func (m *mockIdempotencyStore) SetIfNotExists(ctx context.Context, key string, ttl int) (bool, error) {
@@ -122,6 +180,156 @@ func (m *mockIdempotencyStore) Exists(ctx context.Context, key string) (bool, er
 	return false, nil
 }
 
+func (m *mockIdempotencyStore) Reserve(ctx context.Context, key string, rec *port.IdempotencyRecord, ttl int) (bool, *port.IdempotencyRecord, error) {
+	if m.reserveFn != nil {
+		return m.reserveFn(ctx, key, rec, ttl)
+	}
+	return true, nil, nil
+}
+
+func (m *mockIdempotencyStore) Complete(ctx context.Context, key string, rec *port.IdempotencyRecord, ttl int) error {
+	if m.completeFn != nil {
+		return m.completeFn(ctx, key, rec, ttl)
+	}
+	return nil
+}
+
+func (m *mockIdempotencyStore) Delete(ctx context.Context, key string) error {
+	if m.deleteFn != nil {
+		return m.deleteFn(ctx, key)
+	}
+	return nil
+}
+
+type mockDedupeStore struct {
+	claims    map[string]string
+	claimErr  error
+	releaseFn func(ctx context.Context, hash string) error
+}
+
+func (m *mockDedupeStore) Claim(ctx context.Context, hash, notificationID string, window time.Duration) (bool, string, error) {
+	if m.claimErr != nil {
+		return false, "", m.claimErr
+	}
+	if m.claims == nil {
+		m.claims = make(map[string]string)
+	}
+	if id, ok := m.claims[hash]; ok {
+		return false, id, nil
+	}
+	m.claims[hash] = notificationID
+	return true, "", nil
+}
+
+func (m *mockDedupeStore) Release(ctx context.Context, hash string) error {
+	if m.releaseFn != nil {
+		return m.releaseFn(ctx, hash)
+	}
+	delete(m.claims, hash)
+	return nil
+}
+
+type mockQuotaLimiter struct {
+	limits   map[notification.Channel]int
+	used     map[notification.Channel]int
+	refunded map[notification.Channel]int
+}
+
+func (m *mockQuotaLimiter) Consume(ctx context.Context, tenantID string, ch notification.Channel, n int) (bool, error) {
+	if m.used == nil {
+		m.used = make(map[notification.Channel]int)
+	}
+	if limit, ok := m.limits[ch]; ok && m.used[ch]+n > limit {
+		return false, nil
+	}
+	m.used[ch] += n
+	return true, nil
+}
+
+func (m *mockQuotaLimiter) Refund(ctx context.Context, tenantID string, ch notification.Channel, n int) error {
+	if m.refunded == nil {
+		m.refunded = make(map[notification.Channel]int)
+	}
+	m.used[ch] -= n
+	m.refunded[ch] += n
+	return nil
+}
+
+type mockProfileRepo struct {
+	profiles map[string]*profile.Profile
+}
+
+func (m *mockProfileRepo) Save(ctx context.Context, p *profile.Profile) error {
+	return errors.New("not implemented")
+}
+
+func (m *mockProfileRepo) GetByID(ctx context.Context, userID string) (*profile.Profile, error) {
+	if p, ok := m.profiles[userID]; ok {
+		return p, nil
+	}
+	return nil, profile.ErrNotFound
+}
+
+func (m *mockProfileRepo) Delete(ctx context.Context, userID string) error {
+	return errors.New("not implemented")
+}
+
+func (m *mockProfileRepo) TrackingOptOuts(ctx context.Context, emails []string) (map[string]bool, error) {
+	out := make(map[string]bool)
+	for _, e := range emails {
+		for _, p := range m.profiles {
+			if p.TrackingOptOut && strings.EqualFold(p.Email, e) {
+				out[strings.ToLower(e)] = true
+			}
+		}
+	}
+	return out, nil
+}
+
+// Locales matches email addresses only.
+func (m *mockProfileRepo) Locales(ctx context.Context, ch notification.Channel, addresses []string) (map[string]string, error) {
+	out := make(map[string]string)
+	for _, a := range addresses {
+		for _, p := range m.profiles {
+			if ch == notification.ChannelEmail && p.Locale != "" && strings.EqualFold(p.Email, a) {
+				out[strings.ToLower(a)] = p.Locale
+			}
+		}
+	}
+	return out, nil
+}
+
+type mockTenantRepo struct {
+	tenants map[string]*tenant.Tenant
+}
+
+func (m *mockTenantRepo) Create(ctx context.Context, t *tenant.Tenant) error {
+	return errors.New("not implemented")
+}
+
+func (m *mockTenantRepo) GetByID(ctx context.Context, id string) (*tenant.Tenant, error) {
+	if t, ok := m.tenants[id]; ok {
+		return t, nil
+	}
+	return nil, tenant.ErrNotFound
+}
+
+func (m *mockTenantRepo) List(ctx context.Context) ([]*tenant.Tenant, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *mockTenantRepo) SetQuotas(ctx context.Context, id string, quotas map[notification.Channel]int) error {
+	return errors.New("not implemented")
+}
+
+func (m *mockTenantRepo) SetDeliveryWindows(ctx context.Context, id string, windows map[string]notification.DeliveryWindow) error {
+	return errors.New("not implemented")
+}
+
+func (m *mockTenantRepo) SetDigestPolicies(ctx context.Context, id string, policies map[string]notification.DigestPolicy) error {
+	return errors.New("not implemented")
+}
+
 type mockLogger struct{}
 
 func (m *mockLogger) Info(ctx context.Context, msg string, fields ...port.Field)  {}
@@ -225,10 +433,10 @@ func TestCreateNotification_EmptyRecipient(t *testing.T) {
 	}
 }
 
-func TestCreateNotification_DuplicateIdempotencyKey_Redis(t *testing.T) {
+func TestCreateNotification_DuplicateIdempotencyKey_InFlight(t *testing.T) {
 	idem := &mockIdempotencyStore{
-		setIfNotExistsFn: func(ctx context.Context, key string, ttl int) (bool, error) {
-			return false, nil
+		reserveFn: func(ctx context.Context, key string, rec *port.IdempotencyRecord, ttl int) (bool, *port.IdempotencyRecord, error) {
+			return false, &port.IdempotencyRecord{Fingerprint: rec.Fingerprint}, nil
 		},
 	}
 
@@ -250,18 +458,21 @@ func TestCreateNotification_DuplicateIdempotencyKey_Redis(t *testing.T) {
 	}
 }
 
-func TestCreateNotification_DuplicateIdempotencyKey_DBFallback(t *testing.T) {
+func TestCreateNotification_ReplayReturnsOriginal_Redis(t *testing.T) {
 	idem := &mockIdempotencyStore{
-		setIfNotExistsFn: func(ctx context.Context, key string, ttl int) (bool, error) {
-			return false, errors.New("redis error")
+		reserveFn: func(ctx context.Context, key string, rec *port.IdempotencyRecord, ttl int) (bool, *port.IdempotencyRecord, error) {
+			return false, &port.IdempotencyRecord{Fingerprint: rec.Fingerprint, ResourceID: "original-id"}, nil
 		},
 	}
-
 	repo := &mockNotificationRepo{
-		existsByIdempotencyKeyFn: func(ctx context.Context, key string) (bool, error) {
-			return true, nil
+		createFn: func(ctx context.Context, n *notification.Notification) error {
+			t.Error("replay should not create a notification")
+			return nil
 		},
 	}
+	repo.getByIDFn = func(ctx context.Context, id string) (*notification.Notification, error) {
+		return &notification.Notification{ID: id, Status: notification.StatusSent}, nil
+	}
 
 	uc := NewUseCase(repo, &mockBatchRepo{}, &mockPublisher{}, idem, &mockLogger{})
 
@@ -274,10 +485,249 @@ func TestCreateNotification_DuplicateIdempotencyKey_DBFallback(t *testing.T) {
 		IdempotencyKey: &key,
 	}
 
+	result, err := uc.CreateNotification(context.Background(), cmd)
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !result.Replayed || result.ID != "original-id" {
+		t.Errorf("expected replay of original-id, got %+v", result)
+	}
+}
+
+func TestCreateNotification_ReplayRedactsChatWebhook(t *testing.T) {
+	webhook := "https://hooks.slack.com/services/T0/B0/secret"
+	batchID := "original-batch"
+	idem := &mockIdempotencyStore{
+		reserveFn: func(ctx context.Context, key string, rec *port.IdempotencyRecord, ttl int) (bool, *port.IdempotencyRecord, error) {
+			return false, &port.IdempotencyRecord{Fingerprint: rec.Fingerprint, ResourceID: "original-id"}, nil
+		},
+	}
+	stored := func() *notification.Notification {
+		return &notification.Notification{ID: "original-id", BatchID: &batchID, Channel: notification.ChannelChat, Recipient: webhook}
+	}
+	repo := &mockNotificationRepo{
+		getByIDFn: func(ctx context.Context, id string) (*notification.Notification, error) {
+			return stored(), nil
+		},
+		getByBatchIDFn: func(ctx context.Context, id string) ([]*notification.Notification, error) {
+			return []*notification.Notification{stored()}, nil
+		},
+	}
+	batch := &mockBatchRepo{getByIdempotencyKeyFn: func(ctx context.Context, key string) (*notification.Batch, error) {
+		return &notification.Batch{ID: batchID}, nil
+	}}
+	uc := NewUseCase(repo, batch, &mockPublisher{}, idem, &mockLogger{})
+	want := "https://hooks.slack.com/[redacted]"
+
+	key := "chat-key"
+	result, err := uc.CreateNotification(context.Background(), &Command{Recipient: webhook, Channel: "chat", Content: "Deploy done", Priority: "normal", IdempotencyKey: &key})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !result.Replayed || result.Recipient != want {
+		t.Errorf("expected a replay with the webhook redacted, got %+v", result.Notification)
+	}
+
+	batchResult, err := uc.CreateNotificationBatches(context.Background(), &BatchCommand{
+		IdempotencyKey: &key,
+		Items:          []BatchItem{{Recipient: webhook, Channel: "chat", Content: "Deploy done", Priority: "normal"}},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !batchResult.Replayed || batchResult.Notifications[0].Recipient != want {
+		t.Errorf("expected a batch replay with the webhook redacted, got %+v", batchResult.Notifications[0])
+	}
+}
+
+func TestCreateNotification_IdempotencyConflict(t *testing.T) {
+	idem := &mockIdempotencyStore{
+		reserveFn: func(ctx context.Context, key string, rec *port.IdempotencyRecord, ttl int) (bool, *port.IdempotencyRecord, error) {
+			return false, &port.IdempotencyRecord{Fingerprint: "other-payload", ResourceID: "original-id"}, nil
+		},
+	}
+
+	uc := NewUseCase(&mockNotificationRepo{}, &mockBatchRepo{}, &mockPublisher{}, idem, &mockLogger{})
+
+	key := "test-key-123"
+	cmd := &Command{
+		Recipient:      "+905551234567",
+		Channel:        "sms",
+		Content:        "Test message",
+		Priority:       "high",
+		IdempotencyKey: &key,
+	}
+
 	_, err := uc.CreateNotification(context.Background(), cmd)
 
-	if !errors.Is(err, notification.ErrDuplicateRequest) {
-		t.Errorf("expected ErrDuplicateRequest, got %v", err)
+	if !errors.Is(err, notification.ErrIdempotencyConflict) {
+		t.Errorf("expected ErrIdempotencyConflict, got %v", err)
+	}
+}
+
+func TestCreateNotification_ReplayReturnsOriginal_DBFallback(t *testing.T) {
+	idem := &mockIdempotencyStore{
+		reserveFn: func(ctx context.Context, key string, rec *port.IdempotencyRecord, ttl int) (bool, *port.IdempotencyRecord, error) {
+			return false, nil, errors.New("redis error")
+		},
+	}
+
+	repo := &mockNotificationRepo{
+		getByIdempotencyKeyFn: func(ctx context.Context, key string) (*notification.Notification, error) {
+			return &notification.Notification{
+				ID:        "original-id",
+				Recipient: "+905551234567",
+				Channel:   notification.ChannelSMS,
+				Content:   "Test message",
+				Priority:  notification.PriorityHigh,
+			}, nil
+		},
+	}
+
+	uc := NewUseCase(repo, &mockBatchRepo{}, &mockPublisher{}, idem, &mockLogger{})
+
+	key := "test-key-123"
+	cmd := &Command{
+		Recipient:      "+905551234567",
+		Channel:        "sms",
+		Content:        "Test message",
+		Priority:       "high",
+		IdempotencyKey: &key,
+	}
+
+	result, err := uc.CreateNotification(context.Background(), cmd)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !result.Replayed || result.ID != "original-id" {
+		t.Errorf("expected replay of original-id, got %+v", result)
+	}
+
+	cmd.Content = "Different message"
+	_, err = uc.CreateNotification(context.Background(), cmd)
+	if !errors.Is(err, notification.ErrIdempotencyConflict) {
+		t.Errorf("expected ErrIdempotencyConflict for different payload, got %v", err)
+	}
+}
+
+func TestCreateNotification_RecordsCreatedResource(t *testing.T) {
+	var completed *port.IdempotencyRecord
+	idem := &mockIdempotencyStore{
+		completeFn: func(ctx context.Context, key string, rec *port.IdempotencyRecord, ttl int) error {
+			completed = rec
+			return nil
+		},
+	}
+
+	uc := NewUseCase(&mockNotificationRepo{}, &mockBatchRepo{}, &mockPublisher{}, idem, &mockLogger{})
+
+	key := "test-key-123"
+	cmd := &Command{
+		Recipient:      "+905551234567",
+		Channel:        "sms",
+		Content:        "Test message",
+		Priority:       "high",
+		IdempotencyKey: &key,
+	}
+
+	result, err := uc.CreateNotification(context.Background(), cmd)
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if completed == nil || completed.ResourceID != result.ID || completed.Fingerprint == "" {
+		t.Errorf("expected idempotency record for %s, got %+v", result.ID, completed)
+	}
+}
+
+func TestCreateNotification_DedupeWindow(t *testing.T) {
+	created := map[string]*notification.Notification{}
+	repo := &mockNotificationRepo{
+		createFn: func(ctx context.Context, n *notification.Notification) error {
+			created[n.ID] = n
+			return nil
+		},
+	}
+	repo.getByIDFn = func(ctx context.Context, id string) (*notification.Notification, error) {
+		if n, ok := created[id]; ok {
+			return n, nil
+		}
+		return nil, notification.ErrNotFound
+	}
+
+	uc := NewUseCase(repo, &mockBatchRepo{}, &mockPublisher{}, &mockIdempotencyStore{}, &mockLogger{}).
+		WithDedupe(&mockDedupeStore{}, time.Minute)
+
+	cmd := &Command{Recipient: "+905551234567", Channel: "sms", Content: "Disk full", Priority: "high"}
+
+	first, err := uc.CreateNotification(context.Background(), cmd)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if first.DeduplicatedAgainst != "" {
+		t.Errorf("first request should not be deduplicated, got %s", first.DeduplicatedAgainst)
+	}
+
+	second, err := uc.CreateNotification(context.Background(), cmd)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if second.DeduplicatedAgainst != first.ID || second.ID != first.ID {
+		t.Errorf("expected dedupe against %s, got %+v", first.ID, second)
+	}
+
+	cmd.Recipient = "+905559999999"
+	third, err := uc.CreateNotification(context.Background(), cmd)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if third.DeduplicatedAgainst != "" {
+		t.Errorf("different recipient should not be deduplicated")
+	}
+	if len(created) != 2 {
+		t.Errorf("expected 2 notifications created, got %d", len(created))
+	}
+}
+
+func TestCreateNotification_DedupeFailsOpen(t *testing.T) {
+	uc := NewUseCase(&mockNotificationRepo{}, &mockBatchRepo{}, &mockPublisher{}, &mockIdempotencyStore{}, &mockLogger{}).
+		WithDedupe(&mockDedupeStore{claimErr: errors.New("redis error")}, time.Minute)
+
+	cmd := &Command{Recipient: "+905551234567", Channel: "sms", Content: "Disk full", Priority: "high"}
+
+	result, err := uc.CreateNotification(context.Background(), cmd)
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if result.DeduplicatedAgainst != "" {
+		t.Errorf("expected no dedupe, got %s", result.DeduplicatedAgainst)
+	}
+}
+
+func TestCreateNotification_DedupeReleasedOnCreateError(t *testing.T) {
+	released := false
+	repo := &mockNotificationRepo{
+		createFn: func(ctx context.Context, n *notification.Notification) error {
+			return errors.New("db error")
+		},
+	}
+	store := &mockDedupeStore{releaseFn: func(ctx context.Context, hash string) error {
+		released = true
+		return nil
+	}}
+
+	uc := NewUseCase(repo, &mockBatchRepo{}, &mockPublisher{}, &mockIdempotencyStore{}, &mockLogger{}).
+		WithDedupe(store, time.Minute)
+
+	cmd := &Command{Recipient: "+905551234567", Channel: "sms", Content: "Disk full", Priority: "high"}
+
+	if _, err := uc.CreateNotification(context.Background(), cmd); err == nil {
+		t.Fatal("expected error")
+	}
+	if !released {
+		t.Error("expected dedupe claim to be released")
 	}
 }
 
@@ -308,7 +758,21 @@ func TestCreateNotification_PublishError(t *testing.T) {
 }
 
 func TestCreateNotificationBatches_Success(t *testing.T) {
-	repo := &mockNotificationRepo{}
+	var queueCalls int
+	var queued []string
+	repo := &mockNotificationRepo{
+		updateStatusFn: func(ctx context.Context, id string, status notification.Status, sentAt *time.Time, reason *string) error {
+			t.Errorf("expected batch items queued together, got a single update of %s", id)
+			return nil
+		},
+		updateStatusesFn: func(ctx context.Context, ids []string, status notification.Status) error {
+			queueCalls++
+			if status == notification.StatusQueued {
+				queued = ids
+			}
+			return nil
+		},
+	}
 	batch := &mockBatchRepo{}
 	pub := &mockPublisher{}
 
@@ -332,6 +796,33 @@ func TestCreateNotificationBatches_Success(t *testing.T) {
 	if len(result.Notifications) != 2 {
 		t.Errorf("expected 2 notifications, got %d", len(result.Notifications))
 	}
+	if queueCalls != 1 || len(queued) != 2 || queued[0] != result.Notifications[0].ID {
+		t.Errorf("expected both notifications queued in one call, got %d calls with %v", queueCalls, queued)
+	}
+}
+
+func TestCreateNotificationBatches_ExistingBatch(t *testing.T) {
+	repo := &mockNotificationRepo{createBatchFn: func(ctx context.Context, b *notification.Batch, list []*notification.Notification) error {
+		if b != nil {
+			t.Error("expected no new batch")
+		}
+		return nil
+	}}
+	uc := NewUseCase(repo, &mockBatchRepo{}, &mockPublisher{}, &mockIdempotencyStore{}, &mockLogger{})
+
+	cmd := &BatchCommand{
+		BatchID: "audience-batch",
+		Items:   []BatchItem{{Recipient: "+905551234567", Channel: "sms", Content: "Test 1"}},
+	}
+
+	result, err := uc.CreateNotificationBatches(context.Background(), cmd)
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if result.BatchID != "audience-batch" || *result.Notifications[0].BatchID != "audience-batch" {
+		t.Errorf("expected the notifications in the existing batch, got %+v", result)
+	}
 }
 
 func TestCreateNotificationBatches_EmptyBatch(t *testing.T) {
@@ -410,3 +901,1013 @@ func TestIsUniqueViolation(t *testing.T) {
 		})
 	}
 }
+
+func TestCreateNotificationBatches_PartialAccept_ReportsRejectedItems(t *testing.T) {
+	uc := NewUseCase(&mockNotificationRepo{}, &mockBatchRepo{}, &mockPublisher{}, &mockIdempotencyStore{}, &mockLogger{})
+
+	cmd := &BatchCommand{
+		PartialAccept: true,
+		Items: []BatchItem{
+			{Recipient: "+905551234567", Channel: "sms", Content: "Valid", Priority: "high"},
+			{Recipient: "+905551234568", Channel: "fax", Content: "Invalid channel", Priority: "high"},
+			{Recipient: "+905551234569", Channel: "sms", Content: "Bad priority", Priority: "urgent"},
+			{Recipient: "", Channel: "sms", Content: "", Priority: ""},
+		},
+	}
+
+	result, err := uc.CreateNotificationBatches(context.Background(), cmd)
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(result.Items) != 4 {
+		t.Fatalf("expected 4 item results, got %d", len(result.Items))
+	}
+	if result.Accepted() != 1 || len(result.Notifications) != 1 {
+		t.Errorf("expected 1 accepted item, got %d", result.Accepted())
+	}
+	if result.Items[0].Status != ItemStatusAccepted || result.Items[0].NotificationID != result.Notifications[0].ID {
+		t.Errorf("expected item 0 accepted with notification ID, got %+v", result.Items[0])
+	}
+
+	wantFields := map[int][]string{
+		1: {"channel"},
+		2: {"priority"},
+		3: {"recipient", "content"},
+	}
+	for idx, fields := range wantFields {
+		it := result.Items[idx]
+		if it.Index != idx || it.Status != ItemStatusRejected {
+			t.Errorf("item %d: expected rejected, got %+v", idx, it)
+			continue
+		}
+		if len(it.Errors) != len(fields) {
+			t.Errorf("item %d: expected %d errors, got %+v", idx, len(fields), it.Errors)
+			continue
+		}
+		for i, f := range fields {
+			if it.Errors[i].Field != f {
+				t.Errorf("item %d: expected error on %s, got %s", idx, f, it.Errors[i].Field)
+			}
+		}
+	}
+}
+
+func TestCreateNotificationBatches_DefaultModeCoercesPriority(t *testing.T) {
+	uc := NewUseCase(&mockNotificationRepo{}, &mockBatchRepo{}, &mockPublisher{}, &mockIdempotencyStore{}, &mockLogger{})
+
+	cmd := &BatchCommand{
+		Items: []BatchItem{
+			{Recipient: "+905551234567", Channel: "sms", Content: "Test", Priority: "urgent"},
+		},
+	}
+
+	result, err := uc.CreateNotificationBatches(context.Background(), cmd)
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(result.Notifications) != 1 {
+		t.Fatalf("expected 1 notification, got %d", len(result.Notifications))
+	}
+	if result.Notifications[0].Priority != notification.PriorityNormal {
+		t.Errorf("expected priority normal, got %s", result.Notifications[0].Priority)
+	}
+}
+
+func TestCreateNotificationBatches_NoValidItemsSkipsBatch(t *testing.T) {
+	repo := &mockNotificationRepo{
+		createBatchFn: func(ctx context.Context, b *notification.Batch, list []*notification.Notification) error {
+			t.Error("batch should not be created when every item is rejected")
+			return nil
+		},
+	}
+
+	uc := NewUseCase(repo, &mockBatchRepo{}, &mockPublisher{}, &mockIdempotencyStore{}, &mockLogger{})
+
+	cmd := &BatchCommand{
+		PartialAccept: true,
+		Items:         []BatchItem{{Recipient: "+905551234567", Channel: "fax", Content: "Test"}},
+	}
+
+	result, err := uc.CreateNotificationBatches(context.Background(), cmd)
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if result.BatchID != "" || result.Accepted() != 0 {
+		t.Errorf("expected no batch and no accepted items, got %+v", result)
+	}
+}
+
+func TestCreateNotificationBatches_AllItemKeysReplayed(t *testing.T) {
+	batchID := "original-batch"
+	existing := &notification.Notification{ID: "n1", BatchID: &batchID}
+	idem := &mockIdempotencyStore{
+		setIfNotExistsFn: func(ctx context.Context, key string, ttl int) (bool, error) {
+			return false, nil
+		},
+	}
+	repo := &mockNotificationRepo{
+		getByIdempotencyKeyFn: func(ctx context.Context, key string) (*notification.Notification, error) {
+			return existing, nil
+		},
+		getByIDFn: func(ctx context.Context, id string) (*notification.Notification, error) {
+			return existing, nil
+		},
+		createBatchFn: func(ctx context.Context, b *notification.Batch, list []*notification.Notification) error {
+			t.Error("batch should not be created when every item key was used")
+			return nil
+		},
+	}
+
+	uc := NewUseCase(repo, &mockBatchRepo{}, &mockPublisher{}, idem, &mockLogger{})
+
+	key := "item-key"
+	cmd := &BatchCommand{
+		Items: []BatchItem{{Recipient: "+905551234567", Channel: "sms", Content: "Test", Priority: "high", IdempotencyKey: &key}},
+	}
+
+	result, err := uc.CreateNotificationBatches(context.Background(), cmd)
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !result.Replayed || result.BatchID != batchID {
+		t.Errorf("expected replay of %s, got %+v", batchID, result)
+	}
+	if len(result.Notifications) != 1 || result.Notifications[0].ID != "n1" {
+		t.Errorf("expected the existing notification, got %+v", result.Notifications)
+	}
+}
+
+func TestCreateNotificationBatches_ReplayReturnsOriginalBatch(t *testing.T) {
+	idem := &mockIdempotencyStore{
+		reserveFn: func(ctx context.Context, key string, rec *port.IdempotencyRecord, ttl int) (bool, *port.IdempotencyRecord, error) {
+			if key != "batch:batch-key" {
+				t.Errorf("expected namespaced batch key, got %s", key)
+			}
+			return false, rec, nil
+		},
+	}
+	batchID := "original-batch"
+	batch := &mockBatchRepo{
+		getByIdempotencyKeyFn: func(ctx context.Context, key string) (*notification.Batch, error) {
+			return &notification.Batch{ID: batchID}, nil
+		},
+	}
+	repo := &mockNotificationRepo{
+		getByBatchIDFn: func(ctx context.Context, id string) ([]*notification.Notification, error) {
+			return []*notification.Notification{{ID: "n1", BatchID: &batchID}, {ID: "n2", BatchID: &batchID}}, nil
+		},
+		createBatchFn: func(ctx context.Context, b *notification.Batch, list []*notification.Notification) error {
+			t.Error("replay should not create a new batch")
+			return nil
+		},
+	}
+
+	uc := NewUseCase(repo, batch, &mockPublisher{}, idem, &mockLogger{})
+
+	key := "batch-key"
+	cmd := &BatchCommand{
+		IdempotencyKey: &key,
+		Items:          []BatchItem{{Recipient: "+905551234567", Channel: "sms", Content: "Test", Priority: "high"}},
+	}
+
+	result, err := uc.CreateNotificationBatches(context.Background(), cmd)
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !result.Replayed || result.BatchID != batchID {
+		t.Errorf("expected replay of %s, got %+v", batchID, result)
+	}
+	if result.Accepted() != 2 {
+		t.Errorf("expected 2 replayed notifications, got %d", result.Accepted())
+	}
+}
+
+func TestCreateNotificationBatches_ReplayInProgress(t *testing.T) {
+	idem := &mockIdempotencyStore{
+		reserveFn: func(ctx context.Context, key string, rec *port.IdempotencyRecord, ttl int) (bool, *port.IdempotencyRecord, error) {
+			return false, nil, nil
+		},
+	}
+
+	uc := NewUseCase(&mockNotificationRepo{}, &mockBatchRepo{}, &mockPublisher{}, idem, &mockLogger{})
+
+	key := "batch-key"
+	cmd := &BatchCommand{
+		IdempotencyKey: &key,
+		Items:          []BatchItem{{Recipient: "+905551234567", Channel: "sms", Content: "Test", Priority: "high"}},
+	}
+
+	_, err := uc.CreateNotificationBatches(context.Background(), cmd)
+
+	if !errors.Is(err, notification.ErrDuplicateRequest) {
+		t.Errorf("expected ErrDuplicateRequest, got %v", err)
+	}
+}
+
+func TestCreateNotificationBatches_ReplayWithDifferentItemsConflicts(t *testing.T) {
+	key := "batch-key"
+	items := []BatchItem{{Recipient: "+905551234567", Channel: "sms", Content: "Test", Priority: "high"}}
+	original := batchFingerprint(&BatchCommand{Items: items})
+
+	tests := []struct {
+		name  string
+		idem  *mockIdempotencyStore
+		batch *notification.Batch
+	}{
+		{
+			name: "redis record",
+			idem: &mockIdempotencyStore{reserveFn: func(ctx context.Context, key string, rec *port.IdempotencyRecord, ttl int) (bool, *port.IdempotencyRecord, error) {
+				return false, &port.IdempotencyRecord{Fingerprint: original}, nil
+			}},
+		},
+		{
+			name: "stored batch",
+			idem: &mockIdempotencyStore{reserveFn: func(ctx context.Context, key string, rec *port.IdempotencyRecord, ttl int) (bool, *port.IdempotencyRecord, error) {
+				return false, nil, errors.New("redis down")
+			}},
+			batch: &notification.Batch{ID: "original-batch", Fingerprint: original},
+		},
+		{
+			name: "audience batch",
+			idem: &mockIdempotencyStore{reserveFn: func(ctx context.Context, key string, rec *port.IdempotencyRecord, ttl int) (bool, *port.IdempotencyRecord, error) {
+				return false, nil, nil
+			}},
+			batch: &notification.Batch{ID: "audience-batch", AudienceID: &key},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			batch := &mockBatchRepo{getByIdempotencyKeyFn: func(ctx context.Context, key string) (*notification.Batch, error) {
+				if tt.batch == nil {
+					return nil, notification.ErrNotFound
+				}
+				return tt.batch, nil
+			}}
+			uc := NewUseCase(&mockNotificationRepo{}, batch, &mockPublisher{}, tt.idem, &mockLogger{})
+
+			changed := []BatchItem{{Recipient: "+905551234567", Channel: "sms", Content: "Changed", Priority: "high"}}
+			_, err := uc.CreateNotificationBatches(context.Background(), &BatchCommand{IdempotencyKey: &key, Items: changed})
+
+			if !errors.Is(err, notification.ErrIdempotencyConflict) {
+				t.Errorf("expected ErrIdempotencyConflict, got %v", err)
+			}
+		})
+	}
+}
+
+func TestCreateNotificationBatches_ItemIdempotencyKeys(t *testing.T) {
+	idem := &mockIdempotencyStore{
+		setIfNotExistsFn: func(ctx context.Context, key string, ttl int) (bool, error) {
+			return key != "used-key", nil
+		},
+	}
+	repo := &mockNotificationRepo{
+		getByIdempotencyKeyFn: func(ctx context.Context, key string) (*notification.Notification, error) {
+			if key == "used-key" {
+				return &notification.Notification{ID: "existing-id"}, nil
+			}
+			return nil, notification.ErrNotFound
+		},
+	}
+	var created []*notification.Notification
+	repo.createBatchFn = func(ctx context.Context, b *notification.Batch, list []*notification.Notification) error {
+		created = list
+		return nil
+	}
+
+	uc := NewUseCase(repo, &mockBatchRepo{}, &mockPublisher{}, idem, &mockLogger{})
+
+	used, fresh := "used-key", "fresh-key"
+	cmd := &BatchCommand{
+		Items: []BatchItem{
+			{Recipient: "+905551234567", Channel: "sms", Content: "Test 1", IdempotencyKey: &used},
+			{Recipient: "+905551234568", Channel: "sms", Content: "Test 2", IdempotencyKey: &fresh},
+			{Recipient: "+905551234568", Channel: "sms", Content: "Test 2", IdempotencyKey: &fresh},
+			{Recipient: "+905551234569", Channel: "sms", Content: "Test 3"},
+		},
+	}
+
+	result, err := uc.CreateNotificationBatches(context.Background(), cmd)
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(created) != 2 {
+		t.Fatalf("expected 2 notifications created, got %d", len(created))
+	}
+	if created[0].IdempotencyKey == nil || *created[0].IdempotencyKey != fresh {
+		t.Error("expected item idempotency key to be stored on the notification")
+	}
+	if it := result.Items[0]; it.Status != ItemStatusDuplicate || it.NotificationID != "existing-id" {
+		t.Errorf("expected item 0 duplicate of existing-id, got %+v", it)
+	}
+	if it := result.Items[2]; it.Status != ItemStatusDuplicate || it.NotificationID != result.Items[1].NotificationID {
+		t.Errorf("expected item 2 duplicate of item 1, got %+v", it)
+	}
+	if result.Items[3].Status != ItemStatusAccepted {
+		t.Errorf("expected item 3 accepted, got %+v", result.Items[3])
+	}
+}
+
+func TestCreateNotificationBatches_UniqueViolationReleasesKeys(t *testing.T) {
+	var released []string
+	idem := &mockIdempotencyStore{
+		deleteFn: func(ctx context.Context, key string) error {
+			released = append(released, key)
+			return nil
+		},
+	}
+	repo := &mockNotificationRepo{
+		createBatchFn: func(ctx context.Context, b *notification.Batch, list []*notification.Notification) error {
+			return errors.New("ERROR: duplicate key value violates unique constraint (SQLSTATE 23505)")
+		},
+	}
+
+	uc := NewUseCase(repo, &mockBatchRepo{}, &mockPublisher{}, idem, &mockLogger{})
+
+	batchKey, itemKey := "batch-key", "item-key"
+	cmd := &BatchCommand{
+		IdempotencyKey: &batchKey,
+		Items:          []BatchItem{{Recipient: "+905551234567", Channel: "sms", Content: "Test", IdempotencyKey: &itemKey}},
+	}
+
+	_, err := uc.CreateNotificationBatches(context.Background(), cmd)
+
+	if !errors.Is(err, notification.ErrDuplicateRequest) {
+		t.Errorf("expected ErrDuplicateRequest, got %v", err)
+	}
+	if len(released) != 4 {
+		t.Errorf("expected item and batch keys released after both attempts, got %v", released)
+	}
+}
+
+func TestCreateNotificationBatches_ItemKeyStoredAfterRedisExpiry(t *testing.T) {
+	repo := &mockNotificationRepo{
+		getByIdempotencyKeyFn: func(ctx context.Context, key string) (*notification.Notification, error) {
+			if key == "old-key" {
+				return &notification.Notification{ID: "existing-id"}, nil
+			}
+			return nil, notification.ErrNotFound
+		},
+	}
+	var created []*notification.Notification
+	repo.createBatchFn = func(ctx context.Context, b *notification.Batch, list []*notification.Notification) error {
+		created = list
+		return nil
+	}
+
+	uc := NewUseCase(repo, &mockBatchRepo{}, &mockPublisher{}, &mockIdempotencyStore{}, &mockLogger{})
+
+	old, fresh := "old-key", "fresh-key"
+	result, err := uc.CreateNotificationBatches(context.Background(), &BatchCommand{Items: []BatchItem{
+		{Recipient: "+905551234567", Channel: "sms", Content: "Test", IdempotencyKey: &old},
+		{Recipient: "+905551234568", Channel: "sms", Content: "Test", IdempotencyKey: &fresh},
+	}})
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if it := result.Items[0]; it.Status != ItemStatusDuplicate || it.NotificationID != "existing-id" {
+		t.Errorf("expected item 0 duplicate of existing-id, got %+v", it)
+	}
+	if len(created) != 1 || *created[0].IdempotencyKey != fresh {
+		t.Errorf("expected only the fresh item created, got %+v", created)
+	}
+}
+
+func TestCreateNotificationBatches_ItemKeyTakenConcurrentlyIsRetried(t *testing.T) {
+	stored := false
+	repo := &mockNotificationRepo{
+		getByIdempotencyKeyFn: func(ctx context.Context, key string) (*notification.Notification, error) {
+			if stored && key == "raced-key" {
+				return &notification.Notification{ID: "concurrent-id"}, nil
+			}
+			return nil, notification.ErrNotFound
+		},
+	}
+	var created []*notification.Notification
+	var batches int
+	repo.createBatchFn = func(ctx context.Context, b *notification.Batch, list []*notification.Notification) error {
+		if !stored {
+			stored = true
+			return errors.New("ERROR: duplicate key value violates unique constraint (SQLSTATE 23505)")
+		}
+		if b != nil {
+			batches++
+		}
+		created = list
+		return nil
+	}
+
+	uc := NewUseCase(repo, &mockBatchRepo{}, &mockPublisher{}, &mockIdempotencyStore{}, &mockLogger{})
+
+	raced := "raced-key"
+	result, err := uc.CreateNotificationBatches(context.Background(), &BatchCommand{
+		PartialAccept: true,
+		Items: []BatchItem{
+			{Recipient: "+905551234567", Channel: "sms", Content: "Test", IdempotencyKey: &raced},
+			{Recipient: "+905551234568", Channel: "sms", Content: "Test"},
+		},
+	})
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if it := result.Items[0]; it.Status != ItemStatusDuplicate || it.NotificationID != "concurrent-id" {
+		t.Errorf("expected item 0 duplicate of concurrent-id, got %+v", it)
+	}
+	if batches != 1 || len(created) != 1 || result.BatchID == "" {
+		t.Errorf("expected one batch with the other item, got %d batches, %d items", batches, len(created))
+	}
+}
+
+func TestCreateNotification_TenantScopesKeysAndEntity(t *testing.T) {
+	var reservedKey string
+	idem := &mockIdempotencyStore{
+		reserveFn: func(ctx context.Context, key string, rec *port.IdempotencyRecord, ttl int) (bool, *port.IdempotencyRecord, error) {
+			reservedKey = key
+			return true, nil, nil
+		},
+	}
+	var evt *port.NotificationEvent
+	pub := &mockPublisher{publishFn: func(ctx context.Context, e *port.NotificationEvent) error {
+		evt = e
+		return nil
+	}}
+
+	uc := NewUseCase(&mockNotificationRepo{}, &mockBatchRepo{}, pub, idem, &mockLogger{})
+
+	key := "order-1"
+	cmd := &Command{Recipient: "+905551234567", Channel: "sms", Content: "Test", Priority: "normal", IdempotencyKey: &key, TenantID: "acme"}
+
+	result, err := uc.CreateNotification(context.Background(), cmd)
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if reservedKey != "acme:order-1" {
+		t.Errorf("expected tenant-scoped key, got %s", reservedKey)
+	}
+	if result.TenantID != "acme" || evt.TenantID != "acme" {
+		t.Errorf("expected tenant acme on notification and event, got %s / %s", result.TenantID, evt.TenantID)
+	}
+}
+
+func TestCreateNotification_QuotaExceeded(t *testing.T) {
+	var released bool
+	idem := &mockIdempotencyStore{deleteFn: func(ctx context.Context, key string) error {
+		released = true
+		return nil
+	}}
+	repo := &mockNotificationRepo{createFn: func(ctx context.Context, n *notification.Notification) error {
+		t.Error("over-quota request should not be created")
+		return nil
+	}}
+	quota := &mockQuotaLimiter{limits: map[notification.Channel]int{notification.ChannelSMS: 0}}
+
+	uc := NewUseCase(repo, &mockBatchRepo{}, &mockPublisher{}, idem, &mockLogger{}).WithQuotas(quota)
+
+	key := "order-1"
+	cmd := &Command{Recipient: "+905551234567", Channel: "sms", Content: "Test", Priority: "normal", IdempotencyKey: &key}
+
+	_, err := uc.CreateNotification(context.Background(), cmd)
+
+	if !errors.Is(err, notification.ErrQuotaExceeded) {
+		t.Errorf("expected ErrQuotaExceeded, got %v", err)
+	}
+	if !released {
+		t.Error("expected idempotency key to be released")
+	}
+}
+
+func TestCreateNotification_QuotaRefundedOnCreateError(t *testing.T) {
+	repo := &mockNotificationRepo{createFn: func(ctx context.Context, n *notification.Notification) error {
+		return errors.New("db error")
+	}}
+	quota := &mockQuotaLimiter{}
+
+	uc := NewUseCase(repo, &mockBatchRepo{}, &mockPublisher{}, &mockIdempotencyStore{}, &mockLogger{}).WithQuotas(quota)
+
+	cmd := &Command{Recipient: "+905551234567", Channel: "email", Content: "Test", Priority: "normal"}
+
+	if _, err := uc.CreateNotification(context.Background(), cmd); err == nil {
+		t.Fatal("expected error")
+	}
+	if quota.used[notification.ChannelEmail] != 0 || quota.refunded[notification.ChannelEmail] != 1 {
+		t.Errorf("expected quota refunded, got used=%v refunded=%v", quota.used, quota.refunded)
+	}
+}
+
+func TestCreateNotificationBatches_QuotaExceededRefundsOtherChannels(t *testing.T) {
+	repo := &mockNotificationRepo{createBatchFn: func(ctx context.Context, b *notification.Batch, list []*notification.Notification) error {
+		t.Error("over-quota batch should not be created")
+		return nil
+	}}
+	quota := &mockQuotaLimiter{limits: map[notification.Channel]int{notification.ChannelSMS: 1}}
+
+	uc := NewUseCase(repo, &mockBatchRepo{}, &mockPublisher{}, &mockIdempotencyStore{}, &mockLogger{}).WithQuotas(quota)
+
+	cmd := &BatchCommand{Items: []BatchItem{
+		{Recipient: "user@example.com", Channel: "email", Content: "Test"},
+		{Recipient: "+905551234567", Channel: "sms", Content: "Test"},
+		{Recipient: "+905551234568", Channel: "sms", Content: "Test"},
+	}}
+
+	_, err := uc.CreateNotificationBatches(context.Background(), cmd)
+
+	if !errors.Is(err, notification.ErrQuotaExceeded) {
+		t.Errorf("expected ErrQuotaExceeded, got %v", err)
+	}
+	if quota.used[notification.ChannelEmail] != 0 || quota.used[notification.ChannelSMS] != 0 {
+		t.Errorf("expected no quota left consumed, got %v", quota.used)
+	}
+}
+
+func TestCreateNotification_ResolvesUser(t *testing.T) {
+	var created *notification.Notification
+	repo := &mockNotificationRepo{
+		createFn: func(ctx context.Context, n *notification.Notification) error {
+			created = n
+			return nil
+		},
+	}
+	profiles := &mockProfileRepo{profiles: map[string]*profile.Profile{
+		"u-1": {
+			UserID: "u-1",
+			Phone:  "+905551234567",
+			Email:  "u1@example.com",
+			Preferences: map[string][]notification.Channel{
+				"security":  {notification.ChannelSMS, notification.ChannelEmail},
+				"marketing": {},
+			},
+		},
+	}}
+	uc := NewUseCase(repo, &mockBatchRepo{}, &mockPublisher{}, &mockIdempotencyStore{}, &mockLogger{}).WithProfiles(profiles)
+
+	onlyEmail := func(ch notification.Channel) bool { return ch == notification.ChannelEmail }
+	result, err := uc.CreateNotification(context.Background(), &Command{UserID: "u-1", Category: "security", Content: "New login", Priority: "high", CanSend: onlyEmail})
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if result.Channel != notification.ChannelEmail || result.Recipient != "u1@example.com" {
+		t.Errorf("expected email to u1@example.com, got %s to %s", result.Channel, result.Recipient)
+	}
+	want := notification.Resolution{UserID: "u-1", Category: "security", Source: notification.ResolvedByCategory}
+	if created == nil || created.Resolution == nil || *created.Resolution != want {
+		t.Errorf("expected resolution %+v stored, got %+v", want, created)
+	}
+
+	tests := []struct {
+		name    string
+		cmd     *Command
+		wantErr error
+	}{
+		{"opted out", &Command{UserID: "u-1", Category: "marketing", Content: "Sale", Priority: "low"}, profile.ErrOptedOut},
+		{"unknown user", &Command{UserID: "u-2", Content: "Hi", Priority: "normal"}, profile.ErrNotFound},
+		{"no allowed channel", &Command{UserID: "u-1", Category: "security", Content: "Hi", Priority: "normal", CanSend: func(notification.Channel) bool { return false }}, profile.ErrUnreachable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := uc.CreateNotification(context.Background(), tt.cmd); !errors.Is(err, tt.wantErr) {
+				t.Errorf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestCreateNotification_UserReplayAfterProfileChange(t *testing.T) {
+	original := &notification.Notification{
+		ID:         "original-id",
+		Recipient:  "old@example.com",
+		Channel:    notification.ChannelEmail,
+		Content:    "New login",
+		Priority:   notification.PriorityHigh,
+		Resolution: &notification.Resolution{UserID: "u-1", Category: "security", Source: notification.ResolvedByCategory},
+	}
+	repo := &mockNotificationRepo{
+		getByIdempotencyKeyFn: func(ctx context.Context, key string) (*notification.Notification, error) {
+			return original, nil
+		},
+	}
+	idem := &mockIdempotencyStore{
+		reserveFn: func(ctx context.Context, key string, rec *port.IdempotencyRecord, ttl int) (bool, *port.IdempotencyRecord, error) {
+			return false, nil, errors.New("redis error")
+		},
+	}
+	profiles := &mockProfileRepo{profiles: map[string]*profile.Profile{
+		"u-1": {UserID: "u-1", Email: "new@example.com"},
+	}}
+	uc := NewUseCase(repo, &mockBatchRepo{}, &mockPublisher{}, idem, &mockLogger{}).WithProfiles(profiles)
+
+	key := "login-1"
+	result, err := uc.CreateNotification(context.Background(), &Command{UserID: "u-1", Category: "security", Content: "New login", Priority: "high", IdempotencyKey: &key})
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !result.Replayed || result.ID != "original-id" {
+		t.Errorf("expected replay of original-id, got %+v", result)
+	}
+}
+
+func TestCreateNotification_TrackingOptOut(t *testing.T) {
+	var created []*notification.Notification
+	repo := &mockNotificationRepo{
+		createFn: func(ctx context.Context, n *notification.Notification) error {
+			created = append(created, n)
+			return nil
+		},
+	}
+	profiles := &mockProfileRepo{profiles: map[string]*profile.Profile{
+		"u-1": {UserID: "u-1", Email: "u1@example.com"},
+		"u-2": {UserID: "u-2", Email: "u2@example.com", TrackingOptOut: true},
+	}}
+	uc := NewUseCase(repo, &mockBatchRepo{}, &mockPublisher{}, &mockIdempotencyStore{}, &mockLogger{}).WithProfiles(profiles)
+
+	for _, userID := range []string{"u-1", "u-2"} {
+		if _, err := uc.CreateNotification(context.Background(), &Command{UserID: userID, Content: "<p>Hi</p>", Priority: "normal", Track: true}); err != nil {
+			t.Fatalf("%s: expected no error, got %v", userID, err)
+		}
+	}
+	if len(created) != 2 || !created[0].Tracking || created[1].Tracking {
+		t.Errorf("expected only u-1 tracked, got %+v", created)
+	}
+
+	// Sent to the address instead of the user ID, the opt-out still applies.
+	created = nil
+	for _, recipient := range []string{"u1@example.com", "U2@Example.com"} {
+		if _, err := uc.CreateNotification(context.Background(), &Command{Recipient: recipient, Channel: "email", Content: "<p>Hi</p>", Priority: "normal", Track: true}); err != nil {
+			t.Fatalf("%s: expected no error, got %v", recipient, err)
+		}
+	}
+	if len(created) != 2 || !created[0].Tracking || created[1].Tracking {
+		t.Errorf("expected only u1@example.com tracked, got %+v", created)
+	}
+
+	var batched []*notification.Notification
+	repo.createBatchFn = func(ctx context.Context, b *notification.Batch, list []*notification.Notification) error {
+		batched = list
+		return nil
+	}
+	_, err := uc.CreateNotificationBatches(context.Background(), &BatchCommand{Items: []BatchItem{
+		{Recipient: "u1@example.com", Channel: "email", Content: "<p>Hi</p>", Priority: "normal", Track: true},
+		{Recipient: "u2@example.com", Channel: "email", Content: "<p>Hi</p>", Priority: "normal", Track: true},
+	}})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(batched) != 2 || !batched[0].Tracking || batched[1].Tracking {
+		t.Errorf("expected only the first batch item tracked, got %+v", batched)
+	}
+}
+
+func TestCreateNotificationBatches_AudienceChunkSkipsTrackingForOptedOutMember(t *testing.T) {
+	var batched []*notification.Notification
+	repo := &mockNotificationRepo{
+		createBatchFn: func(ctx context.Context, b *notification.Batch, list []*notification.Notification) error {
+			batched = list
+			return nil
+		},
+	}
+	profiles := &mockProfileRepo{profiles: map[string]*profile.Profile{
+		"u-2": {UserID: "u-2", Email: "u2@example.com", TrackingOptOut: true},
+	}}
+	uc := NewUseCase(repo, &mockBatchRepo{}, &mockPublisher{}, &mockIdempotencyStore{}, &mockLogger{}).WithProfiles(profiles)
+
+	items := make([]BatchItem, 0, 2)
+	for _, recipient := range []string{"u1@example.com", "u2@example.com"} {
+		key := "audience:batch-1:email:" + recipient
+		items = append(items, BatchItem{Recipient: recipient, Channel: "email", Content: "<p>Hi</p>", Priority: "normal", IdempotencyKey: &key, Track: true})
+	}
+	_, err := uc.CreateNotificationBatches(context.Background(), &BatchCommand{BatchID: "batch-1", PartialAccept: true, Items: items})
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(batched) != 2 || !batched[0].Tracking || batched[1].Tracking {
+		t.Errorf("expected only the member who did not opt out tracked, got %+v", batched)
+	}
+}
+
+func TestCreateNotificationBatches_ProfileLocale(t *testing.T) {
+	var batched []*notification.Notification
+	repo := &mockNotificationRepo{
+		createBatchFn: func(ctx context.Context, b *notification.Batch, list []*notification.Notification) error {
+			batched = list
+			return nil
+		},
+	}
+	profiles := &mockProfileRepo{profiles: map[string]*profile.Profile{
+		"u-1": {UserID: "u-1", Email: "u1@example.com", Locale: "tr-TR"},
+	}}
+	uc := NewUseCase(repo, &mockBatchRepo{}, &mockPublisher{}, &mockIdempotencyStore{}, &mockLogger{}).
+		WithProfiles(profiles).
+		WithLocales([]string{"en"})
+	variants := notification.Variants{"tr": "Merhaba", "en": "Hello", "de": "Hallo"}
+
+	// As an audience send expands it: one content for every member.
+	_, err := uc.CreateNotificationBatches(context.Background(), &BatchCommand{BatchID: "batch-1", PartialAccept: true, Items: []BatchItem{
+		{Recipient: "u1@example.com", Channel: "email", Variants: variants, Priority: "normal"},
+		{Recipient: "other@example.com", Channel: "email", Variants: variants, Priority: "normal"},
+		{Recipient: "u1@example.com", Channel: "email", Variants: variants, Locale: "de", Priority: "normal"},
+	}})
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(batched) != 3 {
+		t.Fatalf("expected 3 notifications, got %d", len(batched))
+	}
+	for i, want := range []string{"tr", "en", "de"} {
+		if batched[i].Locale != want {
+			t.Errorf("item %d: expected locale %q, got %q", i, want, batched[i].Locale)
+		}
+	}
+}
+
+func TestCreateNotification_LocalizedContent(t *testing.T) {
+	var created *notification.Notification
+	repo := &mockNotificationRepo{
+		createFn: func(ctx context.Context, n *notification.Notification) error {
+			created = n
+			return nil
+		},
+	}
+	profiles := &mockProfileRepo{profiles: map[string]*profile.Profile{
+		"u-1": {UserID: "u-1", Email: "u1@example.com", Locale: "tr-TR"},
+	}}
+	uc := NewUseCase(repo, &mockBatchRepo{}, &mockPublisher{}, &mockIdempotencyStore{}, &mockLogger{}).
+		WithProfiles(profiles).
+		WithLocales([]string{"en"})
+	variants := notification.Variants{"tr": "Merhaba", "en": "Hello", "de-DE": "Hallo"}
+
+	tests := []struct {
+		name        string
+		cmd         *Command
+		wantContent string
+		wantLocale  string
+	}{
+		{"explicit locale", &Command{Recipient: "+905551234567", Channel: "sms", Variants: variants, Locale: "de-DE", Priority: "normal"}, "Hallo", "de-DE"},
+		{"profile locale by prefix", &Command{UserID: "u-1", Variants: variants, Priority: "normal"}, "Merhaba", "tr"},
+		{"profile locale by address", &Command{Recipient: "U1@example.com", Channel: "email", Variants: variants, Priority: "normal"}, "Merhaba", "tr"},
+		{"explicit locale overrides profile", &Command{UserID: "u-1", Variants: variants, Locale: "en-GB", Priority: "normal"}, "Hello", "en"},
+		{"fallback locale", &Command{Recipient: "+905551234567", Channel: "sms", Variants: variants, Locale: "fr-FR", Priority: "normal"}, "Hello", "en"},
+		{"default content", &Command{Recipient: "+905551234567", Channel: "sms", Content: "Bonjour", Variants: notification.Variants{"tr": "Merhaba"}, Locale: "fr", Priority: "normal"}, "Bonjour", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			created = nil
+			if _, err := uc.CreateNotification(context.Background(), tt.cmd); err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if created == nil || created.Content != tt.wantContent || created.Locale != tt.wantLocale {
+				t.Errorf("expected %q in locale %q, got %+v", tt.wantContent, tt.wantLocale, created)
+			}
+		})
+	}
+
+	errTests := []struct {
+		name    string
+		cmd     *Command
+		wantErr error
+	}{
+		{"no matching variant", &Command{Recipient: "+905551234567", Channel: "sms", Variants: notification.Variants{"de": "Hallo"}, Locale: "fr", Priority: "normal"}, notification.ErrNoVariant},
+		{"variant over channel limit", &Command{Recipient: "+905551234567", Channel: "sms", Content: "Hi", Variants: notification.Variants{"tr": strings.Repeat("a", notification.MaxContentLengthSMS+1)}, Priority: "normal"}, notification.ErrInvalidContent},
+		{"invalid variant locale", &Command{Recipient: "+905551234567", Channel: "sms", Variants: notification.Variants{"Turkish": "Merhaba"}, Priority: "normal"}, notification.ErrInvalidVariants},
+		{"invalid locale", &Command{Recipient: "+905551234567", Channel: "sms", Variants: variants, Locale: "tr_TR", Priority: "normal"}, notification.ErrInvalidLocale},
+	}
+	for _, tt := range errTests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := uc.CreateNotification(context.Background(), tt.cmd); !errors.Is(err, tt.wantErr) {
+				t.Errorf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestCreateNotification_DeliveryWindows(t *testing.T) {
+	var created *notification.Notification
+	repo := &mockNotificationRepo{
+		createFn: func(ctx context.Context, n *notification.Notification) error {
+			created = n
+			return nil
+		},
+	}
+	profiles := &mockProfileRepo{profiles: map[string]*profile.Profile{
+		"u-1": {UserID: "u-1", Email: "u1@example.com", TimeZone: "Europe/Istanbul"},
+	}}
+	tenants := &mockTenantRepo{tenants: map[string]*tenant.Tenant{
+		tenant.DefaultID: {ID: tenant.DefaultID, DeliveryWindows: map[string]notification.DeliveryWindow{
+			"marketing": {Start: 9 * 60, End: 21 * 60},
+		}},
+	}}
+	uc := NewUseCase(repo, &mockBatchRepo{}, &mockPublisher{}, &mockIdempotencyStore{}, &mockLogger{}).
+		WithProfiles(profiles).
+		WithDeliveryWindows(tenants)
+	requested := &notification.DeliveryWindow{Start: 10 * 60, End: 18 * 60, TimeZone: "UTC"}
+
+	tests := []struct {
+		name string
+		cmd  *Command
+		want *notification.DeliveryWindow
+	}{
+		{"category window in the user's zone", &Command{UserID: "u-1", Category: "marketing", Content: "Sale", Priority: "low"}, &notification.DeliveryWindow{Start: 9 * 60, End: 21 * 60, TimeZone: "Europe/Istanbul"}},
+		{"requested window wins", &Command{UserID: "u-1", Category: "marketing", Content: "Sale", Priority: "low", DeliveryWindow: requested}, requested},
+		{"category without window", &Command{UserID: "u-1", Category: "security", Content: "Login", Priority: "high"}, nil},
+		{"address send keeps its window", &Command{Recipient: "a@example.com", Channel: "email", Content: "Sale", Priority: "low", DeliveryWindow: requested}, requested},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := uc.CreateNotification(context.Background(), tt.cmd); err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			got := created.DeliveryWindow
+			if (got == nil) != (tt.want == nil) || (got != nil && *got != *tt.want) {
+				t.Errorf("expected window %v, got %v", tt.want, got)
+			}
+		})
+	}
+
+	bad := &Command{Recipient: "a@example.com", Channel: "email", Content: "Sale", Priority: "low", DeliveryWindow: &notification.DeliveryWindow{Start: 60, End: 60}}
+	if _, err := uc.CreateNotification(context.Background(), bad); !errors.Is(err, notification.ErrInvalidWindow) {
+		t.Errorf("expected ErrInvalidWindow, got %v", err)
+	}
+}
+
+type mockDigestRepo struct {
+	buffered []*notification.Notification
+	openDue  *time.Time
+}
+
+func (m *mockDigestRepo) Buffer(ctx context.Context, n *notification.Notification) error {
+	if m.openDue != nil {
+		n.DigestDue = m.openDue
+	}
+	m.buffered = append(m.buffered, n)
+	return nil
+}
+
+func (m *mockDigestRepo) DueDigests(ctx context.Context, now time.Time, limit int) ([]*port.DigestBuffer, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *mockDigestRepo) CreateDigest(ctx context.Context, digest *notification.Notification, bufferedIDs []string) (bool, error) {
+	return false, errors.New("not implemented")
+}
+
+func TestCreateNotification_Digests(t *testing.T) {
+	var created, published int
+	repo := &mockNotificationRepo{
+		createFn: func(ctx context.Context, n *notification.Notification) error {
+			created++
+			return nil
+		},
+	}
+	pub := &mockPublisher{publishFn: func(ctx context.Context, evt *port.NotificationEvent) error {
+		published++
+		return nil
+	}}
+	profiles := &mockProfileRepo{profiles: map[string]*profile.Profile{
+		"u-1": {UserID: "u-1", Email: "u1@example.com"},
+	}}
+	tenants := &mockTenantRepo{tenants: map[string]*tenant.Tenant{
+		tenant.DefaultID: {ID: tenant.DefaultID, DigestPolicies: map[string]notification.DigestPolicy{
+			"likes": {Window: 15 * time.Minute},
+		}},
+	}}
+	digests := &mockDigestRepo{}
+	uc := NewUseCase(repo, &mockBatchRepo{}, pub, &mockIdempotencyStore{}, &mockLogger{}).
+		WithProfiles(profiles).
+		WithDigests(tenants, digests)
+
+	result, err := uc.CreateNotification(context.Background(), &Command{UserID: "u-1", Category: "likes", Content: "Ada liked your post", Priority: "low"})
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if result.Status != notification.StatusBuffered || len(digests.buffered) != 1 {
+		t.Fatalf("expected the notification to be buffered, got status %s", result.Status)
+	}
+	if result.DigestDue == nil || !result.DigestDue.Equal(result.CreatedAt.Add(15*time.Minute)) {
+		t.Errorf("expected digest due after the window, got %v", result.DigestDue)
+	}
+	if created != 0 || published != 0 {
+		t.Errorf("expected buffered notification not to be created or published, got %d created, %d published", created, published)
+	}
+
+	open := time.Now().Add(time.Minute)
+	digests.openDue = &open
+	result, err = uc.CreateNotification(context.Background(), &Command{UserID: "u-1", Category: "likes", Content: "Grace liked your post", Priority: "low"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !result.DigestDue.Equal(open) {
+		t.Errorf("expected the open buffer's due time %v, got %v", open, result.DigestDue)
+	}
+
+	sent := []*Command{
+		{UserID: "u-1", Category: "likes", Content: "Ada liked your post", Priority: "normal"},
+		{UserID: "u-1", Category: "security", Content: "New login", Priority: "low"},
+		{Recipient: "a@example.com", Channel: "email", Content: "Ada liked your post", Priority: "low"},
+	}
+	for _, cmd := range sent {
+		result, err := uc.CreateNotification(context.Background(), cmd)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if result.Status != notification.StatusQueued {
+			t.Errorf("expected %+v to be sent on its own, got status %s", cmd, result.Status)
+		}
+	}
+	if created != len(sent) || published != len(sent) {
+		t.Errorf("expected %d sent notifications, got %d created, %d published", len(sent), created, published)
+	}
+}
+
+type mockFallbackRepo struct {
+	parent, first *notification.Notification
+}
+
+func (m *mockFallbackRepo) CreateFallback(ctx context.Context, parent, first *notification.Notification) error {
+	m.parent, m.first = parent, first
+	return nil
+}
+
+func (m *mockFallbackRepo) DueFallbacks(ctx context.Context, now time.Time, limit int) ([]*port.DueFallback, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *mockFallbackRepo) AdvanceFallback(ctx context.Context, parentID string, from int, next *notification.Notification, deadline *time.Time, events []*notification.HistoryEntry) (bool, error) {
+	return false, errors.New("not implemented")
+}
+
+func (m *mockFallbackRepo) FinishFallback(ctx context.Context, parentID string, from int, result *notification.Notification, events []*notification.HistoryEntry) (bool, error) {
+	return false, errors.New("not implemented")
+}
+
+func TestCreateFallback(t *testing.T) {
+	chains := &mockFallbackRepo{}
+	var published *port.NotificationEvent
+	queued := map[string]notification.Status{}
+	repo := &mockNotificationRepo{
+		updateStatusFn: func(ctx context.Context, id string, status notification.Status, sentAt *time.Time, reason *string) error {
+			queued[id] = status
+			return nil
+		},
+	}
+	pub := &mockPublisher{publishFn: func(ctx context.Context, evt *port.NotificationEvent) error {
+		published = evt
+		return nil
+	}}
+	uc := NewUseCase(repo, &mockBatchRepo{}, pub, &mockIdempotencyStore{}, &mockLogger{}).WithFallbacks(chains)
+	steps := []notification.FallbackStep{
+		{Channel: notification.ChannelPush, Recipient: "device-token", Timeout: 5 * time.Minute},
+		{Channel: notification.ChannelSMS, Recipient: "+905551234567"},
+		{Channel: notification.ChannelEmail, Recipient: "user@example.com"},
+	}
+
+	result, err := uc.CreateFallback(context.Background(), &FallbackCommand{Steps: steps, Content: "Your account was locked", Priority: "high"})
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	parent, first := chains.parent, chains.first
+	if result.ID != parent.ID || parent.Status != notification.StatusQueued || len(parent.Fallback.Steps) != 3 {
+		t.Fatalf("expected a queued parent with 3 steps, got %+v", parent)
+	}
+	if parent.Fallback.ChildID != first.ID || parent.Fallback.Deadline == nil || first.ParentID == nil || *first.ParentID != parent.ID {
+		t.Errorf("expected the parent to run its first child with a deadline, got %+v / %+v", parent.Fallback, first)
+	}
+	if published == nil || published.NotificationID != first.ID || published.Channel != notification.ChannelPush {
+		t.Errorf("expected the push step published, got %+v", published)
+	}
+	if queued[first.ID] != notification.StatusQueued {
+		t.Errorf("expected the first child queued, got %v", queued)
+	}
+
+	_, err = uc.CreateFallback(context.Background(), &FallbackCommand{Steps: steps[:1], Content: "Hi", Priority: "high"})
+	if !errors.Is(err, notification.ErrInvalidFallback) {
+		t.Errorf("expected ErrInvalidFallback, got %v", err)
+	}
+
+	result, err = uc.CreateFallback(context.Background(), &FallbackCommand{Steps: steps, Variants: notification.Variants{"tr": "Hesabınız kilitlendi", "en": "Your account was locked"}, Locale: "tr-TR", Priority: "high"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if result.Locale != "tr" || chains.first.Locale != "tr" || chains.first.Content != "Hesabınız kilitlendi" {
+		t.Errorf("expected the tr variant on parent and child, got %+v / %+v", result.Notification, chains.first)
+	}
+
+	// The SMS step's limit applies to every variant, not only the one chosen.
+	_, err = uc.CreateFallback(context.Background(), &FallbackCommand{Steps: steps, Variants: notification.Variants{"tr": "Kilitlendi", "de": strings.Repeat("a", notification.MaxContentLengthSMS+1)}, Locale: "tr", Priority: "high"})
+	if !errors.Is(err, notification.ErrInvalidFallback) {
+		t.Errorf("expected ErrInvalidFallback, got %v", err)
+	}
+}
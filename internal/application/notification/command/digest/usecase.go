@@ -0,0 +1,272 @@
+package digest
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/semih-yildiz/notification-service/internal/application/notification/port"
+	profileport "github.com/semih-yildiz/notification-service/internal/application/profile/port"
+	templateport "github.com/semih-yildiz/notification-service/internal/application/template/port"
+	tenantport "github.com/semih-yildiz/notification-service/internal/application/tenant/port"
+	"github.com/semih-yildiz/notification-service/internal/domain/notification"
+	"github.com/semih-yildiz/notification-service/internal/domain/template"
+	sharedctx "github.com/semih-yildiz/notification-service/internal/shared/context"
+)
+
+// batchSize is how many due buffers are flushed per run.
+const batchSize = 200
+
+// UseCase flushes digest buffers: the buffered notifications of a recipient on a
+// channel are summarized by one low priority notification, rendered from the
+// template of their category's digest policy, and marked digested.
+type UseCase struct {
+	digests   port.DigestRepository
+	tenants   tenantport.TenantRepository
+	templates templateport.TemplateRepository
+	repo      port.NotificationRepository
+	pub       port.EventPublisher
+	log       port.Logger
+	events    port.StatusBroadcaster        // optional; nil disables live status events
+	profiles  profileport.ProfileRepository // optional; nil uses only the digested notifications' locale
+	locales   []string                      // fallback chain of template variants
+}
+
+// NewUseCase returns a new digest use case.
+func NewUseCase(
+	digests port.DigestRepository,
+	tenants tenantport.TenantRepository,
+	templates templateport.TemplateRepository,
+	repo port.NotificationRepository,
+	pub port.EventPublisher,
+	log port.Logger,
+) *UseCase {
+	return &UseCase{digests: digests, tenants: tenants, templates: templates, repo: repo, pub: pub, log: log}
+}
+
+// WithStatusEvents broadcasts digested notifications to live subscribers.
+func (u *UseCase) WithStatusEvents(events port.StatusBroadcaster) *UseCase {
+	u.events = events
+	return u
+}
+
+// WithLocales renders digests from the template variant for the recipient's
+// locale, trying fallback in order when neither it nor its language has one.
+func (u *UseCase) WithLocales(fallback []string) *UseCase {
+	u.locales = fallback
+	return u
+}
+
+// WithProfiles takes the recipient's locale from their profile when none of the
+// digested notifications was localized.
+func (u *UseCase) WithProfiles(profiles profileport.ProfileRepository) *UseCase {
+	u.profiles = profiles
+	return u
+}
+
+// Execute flushes up to batchSize due buffers and returns how many digests were
+// created.
+func (u *UseCase) Execute(ctx context.Context) (int, error) {
+	due, err := u.digests.DueDigests(ctx, time.Now(), batchSize)
+	if err != nil {
+		u.log.Error(ctx, "failed to load due digests", port.F("error", err))
+		return 0, err
+	}
+	flushed := 0
+	for _, buf := range due {
+		ok, err := u.flush(ctx, buf)
+		if err != nil {
+			u.log.Error(ctx, "failed to create digest", port.F("error", err), port.F("recipient", buf.Recipient), port.F("channel", buf.Channel))
+			continue
+		}
+		if ok {
+			flushed++
+		}
+	}
+	if flushed > 0 {
+		u.log.Info(ctx, "digests created", port.F("count", flushed))
+	}
+	return flushed, nil
+}
+
+// Run calls Execute every interval until ctx is done.
+func (u *UseCase) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			_, _ = u.Execute(ctx)
+		}
+	}
+}
+
+// flush replaces a buffer with its digest and queues the digest. It reports false
+// when the buffer changed meanwhile, e.g. a notification in it was cancelled; the
+// rest is flushed on the next run.
+func (u *UseCase) flush(ctx context.Context, buf *port.DigestBuffer) (bool, error) {
+	if len(buf.Notifications) == 0 {
+		return false, nil
+	}
+	first := buf.Notifications[0]
+	category := ""
+	if first.Resolution != nil {
+		category = first.Resolution.Category
+	}
+
+	data := template.DigestData{
+		Count:     len(buf.Notifications),
+		Category:  category,
+		Channel:   buf.Channel.String(),
+		Recipient: buf.Recipient,
+		Items:     make([]template.DigestItem, len(buf.Notifications)),
+	}
+	ids := make([]string, len(buf.Notifications))
+	for i, n := range buf.Notifications {
+		ids[i] = n.ID
+		data.Items[i] = template.DigestItem{Content: n.Content, CreatedAt: n.CreatedAt}
+	}
+	content, templateID, locale := u.render(ctx, buf.TenantID, category, buf.Channel, u.recipientLocale(ctx, buf), data)
+
+	now := time.Now()
+	digest := &notification.Notification{
+		ID:             uuid.New().String(),
+		TenantID:       buf.TenantID,
+		Recipient:      buf.Recipient,
+		Channel:        buf.Channel,
+		Content:        content,
+		Locale:         locale,
+		Priority:       notification.PriorityLow,
+		Status:         notification.StatusPending,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+		Resolution:     first.Resolution,
+		DeliveryWindow: first.DeliveryWindow,
+		TemplateID:     templateID,
+		Tracking:       first.Tracking,
+	}
+	ok, err := u.digests.CreateDigest(ctx, digest, ids)
+	if err != nil || !ok {
+		return false, err
+	}
+	u.queue(ctx, digest)
+	for _, n := range buf.Notifications {
+		u.broadcast(ctx, n)
+	}
+	return true, nil
+}
+
+// recipientLocale returns the locale of the newest localized notification in buf,
+// else the locale of the user's profile.
+func (u *UseCase) recipientLocale(ctx context.Context, buf *port.DigestBuffer) string {
+	for i := len(buf.Notifications) - 1; i >= 0; i-- {
+		if locale := buf.Notifications[i].Locale; locale != "" {
+			return locale
+		}
+	}
+	res := buf.Notifications[0].Resolution
+	if u.profiles == nil || res == nil {
+		return ""
+	}
+	p, err := u.profiles.GetByID(sharedctx.WithTenantID(ctx, buf.TenantID), res.UserID)
+	if err != nil {
+		u.log.Warn(ctx, "failed to load profile locale, using fallback", port.F("error", err), port.F("user_id", res.UserID))
+		return ""
+	}
+	return p.Locale
+}
+
+// render renders the digest with the template of the category's policy, in its
+// variant for locale if it has one, and returns the variant's locale. A missing
+// policy or template, or one that fails to render, uses the default summary. The
+// result is cut to the channel's content limit.
+func (u *UseCase) render(ctx context.Context, tenantID, category string, ch notification.Channel, locale string, data template.DigestData) (string, *string, string) {
+	body, templateID, variant := template.DefaultDigestBody, (*string)(nil), ""
+	if tpl := u.policyTemplate(ctx, tenantID, category); tpl != nil {
+		templateID = &tpl.ID
+		body, variant = tpl.Localized(locale, u.locales)
+	}
+	content, err := template.Render(body, data)
+	if err != nil && templateID != nil {
+		u.log.Warn(ctx, "failed to render digest template, using default", port.F("error", err), port.F("template_id", *templateID))
+		templateID, variant = nil, ""
+		content, err = template.Render(template.DefaultDigestBody, data)
+	}
+	if err != nil {
+		u.log.Error(ctx, "failed to render default digest", port.F("error", err))
+	}
+	if max := notification.MaxContentLength(ch); len(content) > max {
+		content = strings.ToValidUTF8(content[:max], "")
+	}
+	return content, templateID, variant
+}
+
+// policyTemplate returns the template of the tenant's digest policy for category,
+// or nil when there is none.
+func (u *UseCase) policyTemplate(ctx context.Context, tenantID, category string) *template.Template {
+	t, err := u.tenants.GetByID(ctx, tenantID)
+	if err != nil {
+		u.log.Warn(ctx, "failed to load digest policy, using default", port.F("error", err), port.F("tenant_id", tenantID))
+		return nil
+	}
+	p, ok := t.DigestPolicy(category)
+	if !ok || p.TemplateID == "" {
+		return nil
+	}
+	tpl, err := u.templates.GetByID(ctx, p.TemplateID)
+	if err != nil {
+		if !errors.Is(err, template.ErrNotFound) {
+			u.log.Warn(ctx, "failed to load digest template, using default", port.F("error", err), port.F("template_id", p.TemplateID))
+		}
+		return nil
+	}
+	// The worker reads every tenant; a policy may only use its own tenant's templates.
+	if tpl.TenantID != tenantID {
+		return nil
+	}
+	return tpl
+}
+
+func (u *UseCase) queue(ctx context.Context, n *notification.Notification) {
+	evt := &port.NotificationEvent{
+		NotificationID: n.ID,
+		TenantID:       n.TenantID,
+		Recipient:      n.Recipient,
+		Channel:        n.Channel,
+		Content:        n.Content,
+		Priority:       n.Priority,
+		CreatedAt:      n.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}
+	if err := u.pub.Publish(ctx, evt); err != nil {
+		u.log.Error(ctx, "failed to publish digest", port.F("error", err), port.F("notification_id", n.ID))
+		reason := "publish failed: " + err.Error()
+		if err := u.repo.UpdateStatus(ctx, n.ID, notification.StatusFailed, nil, &reason); err != nil {
+			u.log.Error(ctx, "failed to update status to failed", port.F("error", err), port.F("notification_id", n.ID))
+		}
+		return
+	}
+	if err := u.repo.UpdateStatus(ctx, n.ID, notification.StatusQueued, nil, nil); err != nil {
+		u.log.Error(ctx, "failed to update status to queued", port.F("error", err), port.F("notification_id", n.ID))
+	}
+}
+
+// broadcast publishes that n was digested; failures only cost live viewers an update.
+func (u *UseCase) broadcast(ctx context.Context, n *notification.Notification) {
+	if u.events == nil {
+		return
+	}
+	evt := &port.StatusEvent{
+		NotificationID: n.ID,
+		BatchID:        n.BatchID,
+		Status:         notification.StatusDigested,
+		OccurredAt:     time.Now(),
+	}
+	if err := u.events.Broadcast(ctx, evt); err != nil {
+		u.log.Warn(ctx, "failed to broadcast status event", port.F("error", err), port.F("notification_id", n.ID))
+	}
+}
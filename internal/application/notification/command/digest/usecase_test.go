@@ -0,0 +1,365 @@
+package digest
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/semih-yildiz/notification-service/internal/application/notification/port"
+	"github.com/semih-yildiz/notification-service/internal/domain/notification"
+	"github.com/semih-yildiz/notification-service/internal/domain/profile"
+	"github.com/semih-yildiz/notification-service/internal/domain/template"
+	"github.com/semih-yildiz/notification-service/internal/domain/tenant"
+)
+
+type mockDigestRepo struct {
+	due      []*port.DigestBuffer
+	stale    bool // CreateDigest finds the buffer changed
+	digest   *notification.Notification
+	buffered []string
+}
+
+func (m *mockDigestRepo) Buffer(ctx context.Context, n *notification.Notification) error {
+	return errors.New("not implemented")
+}
+
+func (m *mockDigestRepo) DueDigests(ctx context.Context, now time.Time, limit int) ([]*port.DigestBuffer, error) {
+	return m.due, nil
+}
+
+func (m *mockDigestRepo) CreateDigest(ctx context.Context, digest *notification.Notification, bufferedIDs []string) (bool, error) {
+	if m.stale {
+		return false, nil
+	}
+	m.digest, m.buffered = digest, bufferedIDs
+	return true, nil
+}
+
+type mockTenantRepo struct {
+	tenants map[string]*tenant.Tenant
+}
+
+func (m *mockTenantRepo) Create(ctx context.Context, t *tenant.Tenant) error {
+	return errors.New("not implemented")
+}
+
+func (m *mockTenantRepo) GetByID(ctx context.Context, id string) (*tenant.Tenant, error) {
+	if t, ok := m.tenants[id]; ok {
+		return t, nil
+	}
+	return nil, tenant.ErrNotFound
+}
+
+func (m *mockTenantRepo) List(ctx context.Context) ([]*tenant.Tenant, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *mockTenantRepo) SetQuotas(ctx context.Context, id string, quotas map[notification.Channel]int) error {
+	return errors.New("not implemented")
+}
+
+func (m *mockTenantRepo) SetDeliveryWindows(ctx context.Context, id string, windows map[string]notification.DeliveryWindow) error {
+	return errors.New("not implemented")
+}
+
+func (m *mockTenantRepo) SetDigestPolicies(ctx context.Context, id string, policies map[string]notification.DigestPolicy) error {
+	return errors.New("not implemented")
+}
+
+type mockTemplateRepo struct {
+	templates map[string]*template.Template
+}
+
+func (m *mockTemplateRepo) Create(ctx context.Context, t *template.Template) error {
+	return errors.New("not implemented")
+}
+
+func (m *mockTemplateRepo) GetByID(ctx context.Context, id string) (*template.Template, error) {
+	if t, ok := m.templates[id]; ok {
+		return t, nil
+	}
+	return nil, template.ErrNotFound
+}
+
+func (m *mockTemplateRepo) List(ctx context.Context) ([]*template.Template, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *mockTemplateRepo) Update(ctx context.Context, t *template.Template) error {
+	return errors.New("not implemented")
+}
+
+func (m *mockTemplateRepo) Delete(ctx context.Context, id string) error {
+	return errors.New("not implemented")
+}
+
+type mockNotificationRepo struct {
+	statuses map[string]notification.Status
+}
+
+func (m *mockNotificationRepo) Create(ctx context.Context, n *notification.Notification) error {
+	return errors.New("not implemented")
+}
+
+func (m *mockNotificationRepo) CreateBatch(ctx context.Context, b *notification.Batch, notifications []*notification.Notification) error {
+	return errors.New("not implemented")
+}
+
+func (m *mockNotificationRepo) GetByID(ctx context.Context, id string) (*notification.Notification, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *mockNotificationRepo) GetByBatchID(ctx context.Context, batchID string) ([]*notification.Notification, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *mockNotificationRepo) UpdateStatus(ctx context.Context, id string, status notification.Status, sentAt *time.Time, reason *string) error {
+	m.statuses[id] = status
+	return nil
+}
+
+func (m *mockNotificationRepo) UpdateStatuses(ctx context.Context, ids []string, status notification.Status) error {
+	for _, id := range ids {
+		m.statuses[id] = status
+	}
+	return nil
+}
+
+func (m *mockNotificationRepo) List(ctx context.Context, filter port.ListFilter) (*port.ListResult, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *mockNotificationRepo) CancelPending(ctx context.Context, id string) error {
+	return errors.New("not implemented")
+}
+
+func (m *mockNotificationRepo) CancelPendingByBatchID(ctx context.Context, batchID string) (int, error) {
+	return 0, errors.New("not implemented")
+}
+
+func (m *mockNotificationRepo) ExistsByIdempotencyKey(ctx context.Context, key string) (bool, error) {
+	return false, errors.New("not implemented")
+}
+
+func (m *mockNotificationRepo) GetByIdempotencyKey(ctx context.Context, key string) (*notification.Notification, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *mockNotificationRepo) IDsByIdempotencyKey(ctx context.Context, keys []string) (map[string]string, error) {
+	return nil, errors.New("not implemented")
+}
+
+type mockPublisher struct {
+	published []*port.NotificationEvent
+}
+
+func (m *mockPublisher) Publish(ctx context.Context, evt *port.NotificationEvent) error {
+	m.published = append(m.published, evt)
+	return nil
+}
+
+func (m *mockPublisher) PublishBatch(ctx context.Context, events []*port.NotificationEvent) error {
+	return errors.New("not implemented")
+}
+
+type mockProfileRepo struct {
+	profiles map[string]*profile.Profile
+}
+
+func (m *mockProfileRepo) Save(ctx context.Context, p *profile.Profile) error {
+	return errors.New("not implemented")
+}
+
+func (m *mockProfileRepo) GetByID(ctx context.Context, userID string) (*profile.Profile, error) {
+	if p, ok := m.profiles[userID]; ok {
+		return p, nil
+	}
+	return nil, profile.ErrNotFound
+}
+
+func (m *mockProfileRepo) Delete(ctx context.Context, userID string) error {
+	return errors.New("not implemented")
+}
+
+func (m *mockProfileRepo) TrackingOptOuts(ctx context.Context, emails []string) (map[string]bool, error) {
+	out := make(map[string]bool)
+	for _, e := range emails {
+		for _, p := range m.profiles {
+			if p.TrackingOptOut && strings.EqualFold(p.Email, e) {
+				out[strings.ToLower(e)] = true
+			}
+		}
+	}
+	return out, nil
+}
+
+func (m *mockProfileRepo) Locales(ctx context.Context, ch notification.Channel, addresses []string) (map[string]string, error) {
+	return nil, errors.New("not implemented")
+}
+
+type mockLogger struct{}
+
+func (m *mockLogger) Info(ctx context.Context, msg string, fields ...port.Field)  {}
+func (m *mockLogger) Warn(ctx context.Context, msg string, fields ...port.Field)  {}
+func (m *mockLogger) Error(ctx context.Context, msg string, fields ...port.Field) {}
+
+// likes returns a due buffer of two liked-post notifications to one email address.
+func likes(tenantID string) *port.DigestBuffer {
+	res := &notification.Resolution{UserID: "u-1", Category: "likes", Source: notification.ResolvedByCategory}
+	return &port.DigestBuffer{
+		TenantID:  tenantID,
+		Channel:   notification.ChannelEmail,
+		Recipient: "u1@example.com",
+		Notifications: []*notification.Notification{
+			{ID: "n-1", TenantID: tenantID, Channel: notification.ChannelEmail, Content: "Ada liked your post", Resolution: res},
+			{ID: "n-2", TenantID: tenantID, Channel: notification.ChannelEmail, Content: "Grace liked your post", Resolution: res},
+		},
+	}
+}
+
+func newUseCase(digests *mockDigestRepo, templates map[string]*template.Template) (*UseCase, *mockNotificationRepo, *mockPublisher) {
+	tenants := &mockTenantRepo{tenants: map[string]*tenant.Tenant{
+		"acme": {ID: "acme", DigestPolicies: map[string]notification.DigestPolicy{
+			"likes": {Window: 15 * time.Minute, TemplateID: "tpl-likes"},
+		}},
+	}}
+	repo := &mockNotificationRepo{statuses: map[string]notification.Status{}}
+	pub := &mockPublisher{}
+	uc := NewUseCase(digests, tenants, &mockTemplateRepo{templates: templates}, repo, pub, &mockLogger{})
+	return uc, repo, pub
+}
+
+func TestExecute_RendersPolicyTemplate(t *testing.T) {
+	digests := &mockDigestRepo{due: []*port.DigestBuffer{likes("acme")}}
+	uc, repo, pub := newUseCase(digests, map[string]*template.Template{
+		"tpl-likes": {ID: "tpl-likes", TenantID: "acme", Body: "{{.Count}} people liked your posts"},
+	})
+
+	flushed, err := uc.Execute(context.Background())
+
+	if err != nil || flushed != 1 {
+		t.Fatalf("expected 1 digest, got %d (err %v)", flushed, err)
+	}
+	d := digests.digest
+	if d.Content != "2 people liked your posts" {
+		t.Errorf("expected rendered content, got %q", d.Content)
+	}
+	if d.TemplateID == nil || *d.TemplateID != "tpl-likes" {
+		t.Errorf("expected template tpl-likes, got %v", d.TemplateID)
+	}
+	if d.Priority != notification.PriorityLow || d.Resolution == nil || d.Resolution.Category != "likes" {
+		t.Errorf("expected a low priority likes digest, got %+v", d)
+	}
+	if len(digests.buffered) != 2 || digests.buffered[0] != "n-1" || digests.buffered[1] != "n-2" {
+		t.Errorf("expected both originals linked, got %v", digests.buffered)
+	}
+	if len(pub.published) != 1 || repo.statuses[d.ID] != notification.StatusQueued {
+		t.Errorf("expected the digest to be queued, got %d published, status %s", len(pub.published), repo.statuses[d.ID])
+	}
+}
+
+func TestExecute_FallsBackToDefaultTemplate(t *testing.T) {
+	tests := []struct {
+		name      string
+		tenantID  string
+		templates map[string]*template.Template
+	}{
+		{"template deleted", "acme", nil},
+		{"template fails to render", "acme", map[string]*template.Template{
+			"tpl-likes": {ID: "tpl-likes", TenantID: "acme", Body: "{{.Missing}}"},
+		}},
+		{"template of another tenant", "acme", map[string]*template.Template{
+			"tpl-likes": {ID: "tpl-likes", TenantID: "other", Body: "leaked"},
+		}},
+		{"tenant without policy", "globex", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			digests := &mockDigestRepo{due: []*port.DigestBuffer{likes(tt.tenantID)}}
+			uc, _, _ := newUseCase(digests, tt.templates)
+
+			if _, err := uc.Execute(context.Background()); err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			d := digests.digest
+			if d == nil || !strings.HasPrefix(d.Content, "You have 2 new notifications") {
+				t.Fatalf("expected the default summary, got %+v", d)
+			}
+			if d.TemplateID != nil {
+				t.Errorf("expected no template, got %s", *d.TemplateID)
+			}
+		})
+	}
+}
+
+func TestExecute_RendersLocalizedTemplate(t *testing.T) {
+	templates := map[string]*template.Template{
+		"tpl-likes": {ID: "tpl-likes", TenantID: "acme", Body: "{{.Count}} people liked your posts", Variants: notification.Variants{
+			"tr": "{{.Count}} kişi gönderilerinizi beğendi",
+			"de": "{{.Count}} Personen gefällt Ihr Beitrag",
+		}},
+	}
+	tests := []struct {
+		name        string
+		locale      string // of the buffered notifications
+		profile     string // of the user's profile
+		wantContent string
+		wantLocale  string
+	}{
+		{"notification locale", "de", "tr-TR", "2 Personen gefällt Ihr Beitrag", "de"},
+		{"profile locale", "", "tr-TR", "2 kişi gönderilerinizi beğendi", "tr"},
+		{"no variant", "", "fr", "2 people liked your posts", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := likes("acme")
+			for _, n := range buf.Notifications {
+				n.Locale = tt.locale
+			}
+			digests := &mockDigestRepo{due: []*port.DigestBuffer{buf}}
+			uc, _, _ := newUseCase(digests, templates)
+			uc.WithProfiles(&mockProfileRepo{profiles: map[string]*profile.Profile{
+				"u-1": {UserID: "u-1", Locale: tt.profile},
+			}})
+
+			if _, err := uc.Execute(context.Background()); err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if d := digests.digest; d == nil || d.Content != tt.wantContent || d.Locale != tt.wantLocale {
+				t.Errorf("expected %q in locale %q, got %+v", tt.wantContent, tt.wantLocale, d)
+			}
+		})
+	}
+}
+
+func TestExecute_TruncatesToChannelLimit(t *testing.T) {
+	buf := likes("acme")
+	buf.Channel = notification.ChannelSMS
+	long := strings.Repeat("x", notification.MaxContentLengthSMS)
+	buf.Notifications[0].Content = long
+	digests := &mockDigestRepo{due: []*port.DigestBuffer{buf}}
+	uc, _, _ := newUseCase(digests, nil)
+
+	if _, err := uc.Execute(context.Background()); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if got := len(digests.digest.Content); got != notification.MaxContentLengthSMS {
+		t.Errorf("expected content cut to %d, got %d", notification.MaxContentLengthSMS, got)
+	}
+}
+
+func TestExecute_SkipsChangedBuffer(t *testing.T) {
+	digests := &mockDigestRepo{due: []*port.DigestBuffer{likes("acme")}, stale: true}
+	uc, repo, pub := newUseCase(digests, nil)
+
+	flushed, err := uc.Execute(context.Background())
+
+	if err != nil || flushed != 0 {
+		t.Fatalf("expected nothing flushed, got %d (err %v)", flushed, err)
+	}
+	if len(pub.published) != 0 || len(repo.statuses) != 0 {
+		t.Errorf("expected nothing queued, got %d published", len(pub.published))
+	}
+}
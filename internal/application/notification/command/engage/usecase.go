@@ -0,0 +1,69 @@
+package engage
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/semih-yildiz/notification-service/internal/application/notification/port"
+	"github.com/semih-yildiz/notification-service/internal/domain/notification"
+)
+
+// OpenCommand is a load of a tracked email's pixel.
+type OpenCommand struct {
+	NotificationID string
+	Signature      string
+}
+
+// ClickCommand is a click on a tracked email's link.
+type ClickCommand struct {
+	NotificationID string
+	URL            string
+	Signature      string
+}
+
+// UseCase records opens and clicks of tracked emails.
+type UseCase struct {
+	links port.TrackingLinks
+	repo  port.EngagementRepository
+	log   port.Logger
+}
+
+// NewUseCase returns a new engagement use case.
+func NewUseCase(links port.TrackingLinks, repo port.EngagementRepository, log port.Logger) *UseCase {
+	return &UseCase{links: links, repo: repo, log: log}
+}
+
+// Open records the open. It returns notification.ErrInvalidTrackingLink for a
+// link this service did not sign.
+func (u *UseCase) Open(ctx context.Context, cmd *OpenCommand) error {
+	if !u.links.VerifyOpen(cmd.NotificationID, cmd.Signature) {
+		return notification.ErrInvalidTrackingLink
+	}
+	u.record(ctx, cmd.NotificationID, &notification.Engagement{Event: notification.HistoryOpened, OccurredAt: time.Now()})
+	return nil
+}
+
+// Click records the click and returns the URL to redirect to. It returns
+// notification.ErrInvalidTrackingLink for a link this service did not sign, so it
+// cannot redirect anywhere else.
+func (u *UseCase) Click(ctx context.Context, cmd *ClickCommand) (string, error) {
+	if cmd.URL == "" || !u.links.VerifyClick(cmd.NotificationID, cmd.URL, cmd.Signature) {
+		return "", notification.ErrInvalidTrackingLink
+	}
+	u.record(ctx, cmd.NotificationID, &notification.Engagement{Event: notification.HistoryClicked, URL: cmd.URL, OccurredAt: time.Now()})
+	return cmd.URL, nil
+}
+
+// record stores the engagement; failures only cost the statistics an event, the
+// recipient still gets the pixel or the redirect.
+func (u *UseCase) record(ctx context.Context, id string, e *notification.Engagement) {
+	err := u.repo.RecordEngagement(ctx, id, e)
+	switch {
+	case err == nil:
+	case errors.Is(err, notification.ErrNotFound):
+		u.log.Warn(ctx, "engagement for unknown notification", port.F("notification_id", id), port.F("event", e.Event))
+	default:
+		u.log.Error(ctx, "failed to record engagement", port.F("error", err), port.F("notification_id", id), port.F("event", e.Event))
+	}
+}
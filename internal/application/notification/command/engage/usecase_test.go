@@ -0,0 +1,97 @@
+package engage
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/semih-yildiz/notification-service/internal/application/notification/port"
+	"github.com/semih-yildiz/notification-service/internal/domain/notification"
+)
+
+// mockLinks accepts the signature "ok".
+type mockLinks struct{}
+
+func (mockLinks) OpenURL(id string) string                { return "" }
+func (mockLinks) ClickURL(id, target string) string       { return "" }
+func (mockLinks) VerifyOpen(id, sig string) bool          { return sig == "ok" }
+func (mockLinks) VerifyClick(id, target, sig string) bool { return sig == "ok" }
+
+type mockEngagementRepo struct {
+	err      error
+	recorded []*notification.Engagement
+}
+
+func (m *mockEngagementRepo) RecordEngagement(ctx context.Context, id string, e *notification.Engagement) error {
+	m.recorded = append(m.recorded, e)
+	return m.err
+}
+
+func (m *mockEngagementRepo) BatchEngagement(ctx context.Context, batchID string) (*notification.EngagementStats, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *mockEngagementRepo) DigestEngagement(ctx context.Context, templateID string) (*notification.EngagementStats, error) {
+	return nil, errors.New("not implemented")
+}
+
+type mockLogger struct{}
+
+func (m *mockLogger) Info(ctx context.Context, msg string, fields ...port.Field)  {}
+func (m *mockLogger) Warn(ctx context.Context, msg string, fields ...port.Field)  {}
+func (m *mockLogger) Error(ctx context.Context, msg string, fields ...port.Field) {}
+
+func TestOpen(t *testing.T) {
+	repo := &mockEngagementRepo{}
+	uc := NewUseCase(mockLinks{}, repo, &mockLogger{})
+
+	if err := uc.Open(context.Background(), &OpenCommand{NotificationID: "n-1", Signature: "ok"}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if err := uc.Open(context.Background(), &OpenCommand{NotificationID: "n-1", Signature: "forged"}); !errors.Is(err, notification.ErrInvalidTrackingLink) {
+		t.Errorf("expected ErrInvalidTrackingLink, got %v", err)
+	}
+	if len(repo.recorded) != 1 || repo.recorded[0].Event != notification.HistoryOpened {
+		t.Errorf("expected one open recorded, got %+v", repo.recorded)
+	}
+}
+
+func TestClick(t *testing.T) {
+	repo := &mockEngagementRepo{err: errors.New("db down")}
+	uc := NewUseCase(mockLinks{}, repo, &mockLogger{})
+
+	target, err := uc.Click(context.Background(), &ClickCommand{NotificationID: "n-1", URL: "https://shop.example", Signature: "ok"})
+
+	if err != nil {
+		t.Fatalf("expected a failed record not to block the redirect, got %v", err)
+	}
+	if target != "https://shop.example" {
+		t.Errorf("expected redirect target, got %q", target)
+	}
+	if len(repo.recorded) != 1 || repo.recorded[0].Event != notification.HistoryClicked || repo.recorded[0].URL != target {
+		t.Errorf("expected the click recorded, got %+v", repo.recorded)
+	}
+}
+
+func TestClick_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		cmd  ClickCommand
+	}{
+		{"Forged signature", ClickCommand{NotificationID: "n-1", URL: "https://evil.example", Signature: "forged"}},
+		{"Missing URL", ClickCommand{NotificationID: "n-1", Signature: "ok"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &mockEngagementRepo{}
+			_, err := NewUseCase(mockLinks{}, repo, &mockLogger{}).Click(context.Background(), &tt.cmd)
+			if !errors.Is(err, notification.ErrInvalidTrackingLink) {
+				t.Errorf("expected ErrInvalidTrackingLink, got %v", err)
+			}
+			if len(repo.recorded) != 0 {
+				t.Error("expected nothing recorded")
+			}
+		})
+	}
+}
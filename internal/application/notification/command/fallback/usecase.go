@@ -0,0 +1,244 @@
+package fallback
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/semih-yildiz/notification-service/internal/application/notification/port"
+	"github.com/semih-yildiz/notification-service/internal/domain/notification"
+)
+
+// batchSize is how many due fallback chains are advanced per run.
+const batchSize = 200
+
+// UseCase advances fallback chains: when the current step's notification is
+// delivered the parent is sent through that channel; when it fails, is cancelled,
+// is reported undelivered or times out the next step is queued, and after the last
+// step the parent fails. A step that was sent keeps its deadline running, waiting
+// for a delivery receipt; one without a timeout, or the last step at its deadline,
+// counts as sent.
+type UseCase struct {
+	chains port.FallbackRepository
+	repo   port.NotificationRepository
+	pub    port.EventPublisher
+	log    port.Logger
+	events port.StatusBroadcaster // optional; nil disables live status events
+	quota  port.QuotaLimiter      // optional; nil skips quota checks on later steps
+}
+
+// NewUseCase returns a new fallback use case.
+func NewUseCase(chains port.FallbackRepository, repo port.NotificationRepository, pub port.EventPublisher, log port.Logger) *UseCase {
+	return &UseCase{chains: chains, repo: repo, pub: pub, log: log}
+}
+
+// WithStatusEvents broadcasts parents' final status to live subscribers.
+func (u *UseCase) WithStatusEvents(events port.StatusBroadcaster) *UseCase {
+	u.events = events
+	return u
+}
+
+// WithQuotas takes each later step from the tenant's daily channel quota. A step
+// over its quota fails and the chain moves on.
+func (u *UseCase) WithQuotas(q port.QuotaLimiter) *UseCase {
+	u.quota = q
+	return u
+}
+
+// Execute advances up to batchSize due chains and returns how many moved on.
+func (u *UseCase) Execute(ctx context.Context) (int, error) {
+	now := time.Now()
+	due, err := u.chains.DueFallbacks(ctx, now, batchSize)
+	if err != nil {
+		u.log.Error(ctx, "failed to load due fallback chains", port.F("error", err))
+		return 0, err
+	}
+	advanced := 0
+	for _, d := range due {
+		ok, err := u.advance(ctx, d, now)
+		if err != nil {
+			u.log.Error(ctx, "failed to advance fallback chain", port.F("error", err), port.F("notification_id", d.Parent.ID))
+			continue
+		}
+		if ok {
+			advanced++
+		}
+	}
+	return advanced, nil
+}
+
+// Run calls Execute every interval until ctx is done.
+func (u *UseCase) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			_, _ = u.Execute(ctx)
+		}
+	}
+}
+
+// advance finishes or moves on the chain of d. It reports false when another
+// worker got there first.
+func (u *UseCase) advance(ctx context.Context, d *port.DueFallback, now time.Time) (bool, error) {
+	parent, child, f := d.Parent, d.Child, d.Parent.Fallback
+
+	awaitingReceipt := child.Status == notification.StatusSent && f.Deadline != nil
+	if awaitingReceipt && now.Before(*f.Deadline) {
+		return false, nil
+	}
+	if child.Status == notification.StatusDelivered || (child.Status == notification.StatusSent && (!awaitingReceipt || !f.HasNext())) {
+		result := *parent
+		result.Status = notification.StatusSent
+		result.Channel, result.Recipient = child.Channel, child.Recipient
+		result.SentAt, result.FailureReason = child.SentAt, nil
+		events := []*notification.HistoryEntry{
+			{Event: notification.HistoryFallbackSucceeded, Detail: string(child.Status) + " by " + f.Describe(), OccurredAt: now},
+		}
+		ok, err := u.chains.FinishFallback(ctx, parent.ID, f.Current, &result, events)
+		if ok {
+			u.log.Info(ctx, "fallback chain sent", port.F("notification_id", parent.ID), port.F("channel", child.Channel), port.F("step", f.Current+1))
+			u.broadcast(ctx, parent, notification.StatusSent, nil)
+		}
+		return ok, err
+	}
+
+	ended := &notification.HistoryEntry{Event: notification.HistoryStepFailed, OccurredAt: now}
+	switch {
+	case !child.Status.Terminal():
+		ended.Event = notification.HistoryStepTimedOut
+		ended.Detail = fmt.Sprintf("%s: not sent within %s", f.Describe(), f.Step().Timeout)
+	case child.Status == notification.StatusSent:
+		ended.Event = notification.HistoryStepTimedOut
+		ended.Detail = fmt.Sprintf("%s: not delivered within %s", f.Describe(), f.Step().Timeout)
+	case child.FailureReason != nil:
+		ended.Detail = fmt.Sprintf("%s: %s", f.Describe(), *child.FailureReason)
+	default:
+		ended.Detail = fmt.Sprintf("%s: %s", f.Describe(), child.Status)
+	}
+
+	if !f.HasNext() {
+		reason := fmt.Sprintf("all %d fallback steps failed", len(f.Steps))
+		result := *parent
+		result.Status = notification.StatusFailed
+		result.FailureReason = &reason
+		events := []*notification.HistoryEntry{
+			ended,
+			{Event: notification.HistoryFallbackExhausted, Detail: reason, OccurredAt: now},
+		}
+		ok, err := u.chains.FinishFallback(ctx, parent.ID, f.Current, &result, events)
+		if ok {
+			u.log.Warn(ctx, "fallback chain failed", port.F("notification_id", parent.ID), port.F("steps", len(f.Steps)))
+			u.broadcast(ctx, parent, notification.StatusFailed, &reason)
+		}
+		return ok, err
+	}
+
+	next := *f
+	next.Current++
+	step := next.Step()
+	nextChild := &notification.Notification{
+		ID:        uuid.New().String(),
+		TenantID:  parent.TenantID,
+		Recipient: step.Recipient,
+		Channel:   step.Channel,
+		Content:   parent.Content,
+		Locale:    parent.Locale,
+		Priority:  parent.Priority,
+		Status:    notification.StatusPending,
+		ClientID:  parent.ClientID,
+		CreatedAt: now,
+		UpdatedAt: now,
+		ParentID:  &parent.ID,
+	}
+	consumed, allowed := u.consumeQuota(ctx, parent.TenantID, step.Channel)
+	if !allowed {
+		reason := notification.ErrQuotaExceeded.Error()
+		nextChild.Status, nextChild.FailureReason = notification.StatusFailed, &reason
+	}
+	events := []*notification.HistoryEntry{
+		ended,
+		{Event: notification.HistoryStepStarted, Detail: next.Describe(), OccurredAt: now},
+	}
+	ok, err := u.chains.AdvanceFallback(ctx, parent.ID, f.Current, nextChild, next.DeadlineFrom(now), events)
+	if err != nil || !ok {
+		if consumed {
+			u.refundQuota(ctx, parent.TenantID, step.Channel)
+		}
+		return ok, err
+	}
+	u.log.Info(ctx, "fallback chain advanced", port.F("notification_id", parent.ID), port.F("step", next.Current+1), port.F("channel", step.Channel))
+	if nextChild.Status == notification.StatusPending {
+		u.queue(ctx, nextChild)
+	}
+	return true, nil
+}
+
+// queue publishes a step's notification. If that fails the step is marked failed so
+// the next run moves on.
+func (u *UseCase) queue(ctx context.Context, n *notification.Notification) {
+	evt := &port.NotificationEvent{
+		NotificationID: n.ID,
+		TenantID:       n.TenantID,
+		Recipient:      n.Recipient,
+		Channel:        n.Channel,
+		Content:        n.Content,
+		Priority:       n.Priority,
+		CreatedAt:      n.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}
+	if err := u.pub.Publish(ctx, evt); err != nil {
+		u.log.Error(ctx, "failed to publish fallback step", port.F("error", err), port.F("notification_id", n.ID))
+		reason := "publish failed: " + err.Error()
+		if err := u.repo.UpdateStatus(ctx, n.ID, notification.StatusFailed, nil, &reason); err != nil {
+			u.log.Error(ctx, "failed to update status to failed", port.F("error", err), port.F("notification_id", n.ID))
+		}
+		return
+	}
+	if err := u.repo.UpdateStatus(ctx, n.ID, notification.StatusQueued, nil, nil); err != nil {
+		u.log.Error(ctx, "failed to update status to queued", port.F("error", err), port.F("notification_id", n.ID))
+	}
+}
+
+// consumeQuota takes one send on ch from the tenant's quota. It reports whether a
+// send was taken and whether the step may go ahead; quota store errors let it through.
+func (u *UseCase) consumeQuota(ctx context.Context, tenantID string, ch notification.Channel) (consumed, allowed bool) {
+	if u.quota == nil {
+		return false, true
+	}
+	allowed, err := u.quota.Consume(ctx, tenantID, ch, 1)
+	if err != nil {
+		u.log.Warn(ctx, "quota check failed, skipping", port.F("error", err), port.F("tenant_id", tenantID), port.F("channel", ch))
+		return false, true
+	}
+	if !allowed {
+		u.log.Warn(ctx, "daily quota exceeded for fallback step", port.F("tenant_id", tenantID), port.F("channel", ch))
+	}
+	return allowed, allowed
+}
+
+func (u *UseCase) refundQuota(ctx context.Context, tenantID string, ch notification.Channel) {
+	if err := u.quota.Refund(ctx, tenantID, ch, 1); err != nil {
+		u.log.Warn(ctx, "failed to refund quota", port.F("error", err), port.F("tenant_id", tenantID), port.F("channel", ch))
+	}
+}
+
+// broadcast publishes the parent's final status; failures only cost live viewers an update.
+func (u *UseCase) broadcast(ctx context.Context, n *notification.Notification, status notification.Status, reason *string) {
+	if u.events == nil {
+		return
+	}
+	evt := &port.StatusEvent{
+		NotificationID: n.ID,
+		Status:         status,
+		FailureReason:  reason,
+		OccurredAt:     time.Now(),
+	}
+	if err := u.events.Broadcast(ctx, evt); err != nil {
+		u.log.Warn(ctx, "failed to broadcast status event", port.F("error", err), port.F("notification_id", n.ID))
+	}
+}
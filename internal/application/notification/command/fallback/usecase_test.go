@@ -0,0 +1,269 @@
+package fallback
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/semih-yildiz/notification-service/internal/application/notification/port"
+	"github.com/semih-yildiz/notification-service/internal/domain/notification"
+)
+
+type mockFallbackRepo struct {
+	due      []*port.DueFallback
+	next     *notification.Notification
+	deadline *time.Time
+	result   *notification.Notification
+	events   []*notification.HistoryEntry
+}
+
+func (m *mockFallbackRepo) CreateFallback(ctx context.Context, parent, first *notification.Notification) error {
+	return errors.New("not implemented")
+}
+
+func (m *mockFallbackRepo) DueFallbacks(ctx context.Context, now time.Time, limit int) ([]*port.DueFallback, error) {
+	return m.due, nil
+}
+
+func (m *mockFallbackRepo) AdvanceFallback(ctx context.Context, parentID string, from int, next *notification.Notification, deadline *time.Time, events []*notification.HistoryEntry) (bool, error) {
+	m.next, m.deadline, m.events = next, deadline, events
+	return true, nil
+}
+
+func (m *mockFallbackRepo) FinishFallback(ctx context.Context, parentID string, from int, result *notification.Notification, events []*notification.HistoryEntry) (bool, error) {
+	m.result, m.events = result, events
+	return true, nil
+}
+
+type mockNotificationRepo struct {
+	statuses map[string]notification.Status
+}
+
+func (m *mockNotificationRepo) Create(ctx context.Context, n *notification.Notification) error {
+	return errors.New("not implemented")
+}
+
+func (m *mockNotificationRepo) CreateBatch(ctx context.Context, b *notification.Batch, notifications []*notification.Notification) error {
+	return errors.New("not implemented")
+}
+
+func (m *mockNotificationRepo) GetByID(ctx context.Context, id string) (*notification.Notification, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *mockNotificationRepo) GetByBatchID(ctx context.Context, batchID string) ([]*notification.Notification, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *mockNotificationRepo) UpdateStatus(ctx context.Context, id string, status notification.Status, sentAt *time.Time, reason *string) error {
+	m.statuses[id] = status
+	return nil
+}
+
+func (m *mockNotificationRepo) UpdateStatuses(ctx context.Context, ids []string, status notification.Status) error {
+	for _, id := range ids {
+		m.statuses[id] = status
+	}
+	return nil
+}
+
+func (m *mockNotificationRepo) List(ctx context.Context, filter port.ListFilter) (*port.ListResult, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *mockNotificationRepo) CancelPending(ctx context.Context, id string) error {
+	return errors.New("not implemented")
+}
+
+func (m *mockNotificationRepo) CancelPendingByBatchID(ctx context.Context, batchID string) (int, error) {
+	return 0, errors.New("not implemented")
+}
+
+func (m *mockNotificationRepo) ExistsByIdempotencyKey(ctx context.Context, key string) (bool, error) {
+	return false, errors.New("not implemented")
+}
+
+func (m *mockNotificationRepo) GetByIdempotencyKey(ctx context.Context, key string) (*notification.Notification, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *mockNotificationRepo) IDsByIdempotencyKey(ctx context.Context, keys []string) (map[string]string, error) {
+	return nil, errors.New("not implemented")
+}
+
+type mockPublisher struct {
+	published []*port.NotificationEvent
+}
+
+func (m *mockPublisher) Publish(ctx context.Context, evt *port.NotificationEvent) error {
+	m.published = append(m.published, evt)
+	return nil
+}
+
+func (m *mockPublisher) PublishBatch(ctx context.Context, events []*port.NotificationEvent) error {
+	return errors.New("not implemented")
+}
+
+type mockQuotaLimiter struct {
+	allowed bool
+}
+
+func (m *mockQuotaLimiter) Consume(ctx context.Context, tenantID string, ch notification.Channel, n int) (bool, error) {
+	return m.allowed, nil
+}
+
+func (m *mockQuotaLimiter) Refund(ctx context.Context, tenantID string, ch notification.Channel, n int) error {
+	return nil
+}
+
+type mockLogger struct{}
+
+func (m *mockLogger) Info(ctx context.Context, msg string, fields ...port.Field)  {}
+func (m *mockLogger) Warn(ctx context.Context, msg string, fields ...port.Field)  {}
+func (m *mockLogger) Error(ctx context.Context, msg string, fields ...port.Field) {}
+
+// chain returns a push → sms → email parent on step current and its child.
+func chain(current int, childStatus notification.Status) *port.DueFallback {
+	parent := &notification.Notification{
+		ID:       "parent",
+		TenantID: "acme",
+		Channel:  notification.ChannelPush,
+		Content:  "Your account was locked",
+		Priority: notification.PriorityHigh,
+		Status:   notification.StatusQueued,
+		Fallback: &notification.Fallback{
+			Steps: []notification.FallbackStep{
+				{Channel: notification.ChannelPush, Recipient: "device-token", Timeout: 5 * time.Minute},
+				{Channel: notification.ChannelSMS, Recipient: "+905551234567", Timeout: 10 * time.Minute},
+				{Channel: notification.ChannelEmail, Recipient: "user@example.com"},
+			},
+			Current: current,
+			ChildID: "child",
+		},
+	}
+	step := parent.Fallback.Steps[current]
+	child := &notification.Notification{ID: "child", Channel: step.Channel, Recipient: step.Recipient, Status: childStatus}
+	return &port.DueFallback{Parent: parent, Child: child}
+}
+
+func TestExecute_SentStepFinishesChain(t *testing.T) {
+	due := chain(1, notification.StatusSent)
+	sentAt := time.Now()
+	due.Child.SentAt = &sentAt
+	chains := &mockFallbackRepo{due: []*port.DueFallback{due}}
+
+	count, err := NewUseCase(chains, &mockNotificationRepo{}, &mockPublisher{}, &mockLogger{}).Execute(context.Background())
+
+	if err != nil || count != 1 {
+		t.Fatalf("expected one chain advanced, got %d, %v", count, err)
+	}
+	r := chains.result
+	if r == nil || r.Status != notification.StatusSent || r.Channel != notification.ChannelSMS || r.Recipient != "+905551234567" || r.SentAt != &sentAt {
+		t.Fatalf("expected the parent sent over sms, got %+v", r)
+	}
+	if len(chains.events) != 1 || chains.events[0].Event != notification.HistoryFallbackSucceeded {
+		t.Errorf("expected a fallback_succeeded entry, got %+v", chains.events)
+	}
+}
+
+func TestExecute_SentStepAwaitsReceipt(t *testing.T) {
+	future, past := time.Now().Add(time.Minute), time.Now().Add(-time.Minute)
+	tests := []struct {
+		name        string
+		current     int
+		childStatus notification.Status
+		deadline    *time.Time
+		wantResult  notification.Status // empty when the chain does not finish
+		wantNext    bool
+	}{
+		{"delivered", 0, notification.StatusDelivered, &future, notification.StatusSent, false},
+		{"sent before the deadline", 0, notification.StatusSent, &future, "", false},
+		{"sent past the deadline", 0, notification.StatusSent, &past, "", true},
+		{"undelivered", 0, notification.StatusUndelivered, &future, "", true},
+		{"last step sent past the deadline", 2, notification.StatusSent, &past, notification.StatusSent, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			due := chain(tt.current, tt.childStatus)
+			due.Parent.Fallback.Deadline = tt.deadline
+			chains := &mockFallbackRepo{due: []*port.DueFallback{due}}
+			repo := &mockNotificationRepo{statuses: map[string]notification.Status{}}
+
+			if _, err := NewUseCase(chains, repo, &mockPublisher{}, &mockLogger{}).Execute(context.Background()); err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if tt.wantResult == "" && chains.result != nil {
+				t.Errorf("expected the chain still running, got %+v", chains.result)
+			}
+			if tt.wantResult != "" && (chains.result == nil || chains.result.Status != tt.wantResult) {
+				t.Errorf("expected the parent %s, got %+v", tt.wantResult, chains.result)
+			}
+			if (chains.next != nil) != tt.wantNext {
+				t.Errorf("expected next step %v, got %+v", tt.wantNext, chains.next)
+			}
+		})
+	}
+}
+
+func TestExecute_AdvancesFailedOrTimedOutStep(t *testing.T) {
+	tests := []struct {
+		name        string
+		childStatus notification.Status
+		wantEvent   notification.HistoryEvent
+	}{
+		{"failed", notification.StatusFailed, notification.HistoryStepFailed},
+		{"timed out", notification.StatusQueued, notification.HistoryStepTimedOut},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chains := &mockFallbackRepo{due: []*port.DueFallback{chain(0, tt.childStatus)}}
+			repo := &mockNotificationRepo{statuses: map[string]notification.Status{}}
+			pub := &mockPublisher{}
+
+			if _, err := NewUseCase(chains, repo, pub, &mockLogger{}).Execute(context.Background()); err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			next := chains.next
+			if next == nil || next.Channel != notification.ChannelSMS || next.ParentID == nil || *next.ParentID != "parent" {
+				t.Fatalf("expected an sms step, got %+v", next)
+			}
+			if chains.deadline == nil || len(chains.events) != 2 || chains.events[0].Event != tt.wantEvent || chains.events[1].Event != notification.HistoryStepStarted {
+				t.Errorf("expected %s then step_started with a deadline, got %+v (%v)", tt.wantEvent, chains.events, chains.deadline)
+			}
+			if len(pub.published) != 1 || pub.published[0].NotificationID != next.ID || repo.statuses[next.ID] != notification.StatusQueued {
+				t.Errorf("expected the sms step queued, got %+v %v", pub.published, repo.statuses)
+			}
+		})
+	}
+}
+
+func TestExecute_LastStepFailsChain(t *testing.T) {
+	chains := &mockFallbackRepo{due: []*port.DueFallback{chain(2, notification.StatusFailed)}}
+
+	if _, err := NewUseCase(chains, &mockNotificationRepo{}, &mockPublisher{}, &mockLogger{}).Execute(context.Background()); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	r := chains.result
+	if r == nil || r.Status != notification.StatusFailed || r.FailureReason == nil || *r.FailureReason != "all 3 fallback steps failed" {
+		t.Fatalf("expected the parent failed, got %+v", r)
+	}
+	if len(chains.events) != 2 || chains.events[1].Event != notification.HistoryFallbackExhausted {
+		t.Errorf("expected step_failed then fallback_exhausted, got %+v", chains.events)
+	}
+}
+
+func TestExecute_StepOverQuotaFails(t *testing.T) {
+	chains := &mockFallbackRepo{due: []*port.DueFallback{chain(0, notification.StatusFailed)}}
+	pub := &mockPublisher{}
+
+	uc := NewUseCase(chains, &mockNotificationRepo{}, pub, &mockLogger{}).WithQuotas(&mockQuotaLimiter{allowed: false})
+	if _, err := uc.Execute(context.Background()); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if chains.next == nil || chains.next.Status != notification.StatusFailed || len(pub.published) != 0 {
+		t.Errorf("expected the sms step failed without publishing, got %+v %+v", chains.next, pub.published)
+	}
+}
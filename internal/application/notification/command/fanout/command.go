@@ -0,0 +1,18 @@
+package fanout
+
+import "github.com/semih-yildiz/notification-service/internal/domain/notification"
+
+// Command sends one message to every member of an audience on Channels.
+type Command struct {
+	AudienceID     string
+	Channels       []string
+	Content        string
+	Variants       notification.Variants // content in other languages, keyed by locale
+	Locale         string                // picks the variant sent to every member
+	Priority       string
+	DeliveryWindow *notification.DeliveryWindow // given to every member's notification
+	Track          bool                         // open and click tracking of HTML email
+	IdempotencyKey *string                      // replaying the same key returns the original batch
+	ClientID       *string                      // API client making the request
+	TenantID       string                       // owning tenant; empty means the default tenant
+}
@@ -0,0 +1,252 @@
+package fanout
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+
+	audienceport "github.com/semih-yildiz/notification-service/internal/application/audience/port"
+	"github.com/semih-yildiz/notification-service/internal/application/notification/command/create"
+	"github.com/semih-yildiz/notification-service/internal/application/notification/port"
+	"github.com/semih-yildiz/notification-service/internal/domain/notification"
+	"github.com/semih-yildiz/notification-service/internal/domain/tenant"
+	sharedctx "github.com/semih-yildiz/notification-service/internal/shared/context"
+)
+
+// chunksPerEvent bounds the members one fan-out event expands, so each stays well
+// within the consumer's message timeout; the rest goes to a follow-up event.
+const chunksPerEvent = 10
+
+// idempotencyPrefix starts the idempotency key of every notification created for an
+// audience member, which makes redelivered fan-out events skip members already done.
+const idempotencyPrefix = "audience:"
+
+// BatchCreator creates the notifications of each chunk of members. It is implemented
+// by create.UseCase.
+type BatchCreator interface {
+	CreateNotificationBatches(ctx context.Context, cmd *create.BatchCommand) (*create.BatchResult, error)
+}
+
+type UseCase struct {
+	batch     port.BatchRepository
+	audiences audienceport.AudienceRepository
+	pub       port.FanoutPublisher
+	creator   BatchCreator
+	log       port.Logger
+	chunk     int
+}
+
+func NewUseCase(
+	batch port.BatchRepository,
+	audiences audienceport.AudienceRepository,
+	pub port.FanoutPublisher,
+	creator BatchCreator,
+	log port.Logger,
+) *UseCase {
+	return &UseCase{
+		batch:     batch,
+		audiences: audiences,
+		pub:       pub,
+		creator:   creator,
+		log:       log,
+		chunk:     notification.MaxBatchSize,
+	}
+}
+
+// Result is the batch a send created, or the original one for a replayed key.
+type Result struct {
+	Batch    *notification.Batch
+	Replayed bool
+}
+
+// Send creates an empty batch for the audience and hands its expansion to a worker.
+// The batch stays in progress until every member has a notification.
+func (u *UseCase) Send(ctx context.Context, cmd *Command) (*Result, error) {
+	if len(cmd.Channels) == 0 {
+		return nil, notification.ErrInvalidChannel
+	}
+	var channels []notification.Channel
+	seen := make(map[notification.Channel]bool, len(cmd.Channels))
+	for _, c := range cmd.Channels {
+		ch := notification.Channel(c)
+		if !ch.Valid() {
+			return nil, notification.ErrInvalidChannel
+		}
+		if len(cmd.Content) > notification.MaxContentLength(ch) || (len(cmd.Content) == 0 && len(cmd.Variants) == 0) {
+			return nil, notification.ErrInvalidContent
+		}
+		if err := notification.ValidateVariants(ch, cmd.Variants); err != nil {
+			return nil, err
+		}
+		if !seen[ch] {
+			seen[ch] = true
+			channels = append(channels, ch)
+		}
+	}
+	if cmd.Locale != "" && !notification.ValidLocale(cmd.Locale) {
+		return nil, notification.ErrInvalidLocale
+	}
+	pr := notification.PriorityNormal
+	if cmd.Priority != "" {
+		pr = notification.Priority(cmd.Priority)
+		if !pr.Valid() {
+			return nil, notification.ErrInvalidPriority
+		}
+	}
+
+	if w := cmd.DeliveryWindow; w != nil {
+		if err := w.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	if _, err := u.audiences.GetByID(ctx, cmd.AudienceID); err != nil {
+		return nil, err
+	}
+
+	hasKey := cmd.IdempotencyKey != nil && *cmd.IdempotencyKey != ""
+	if hasKey {
+		if replay, err := u.replay(ctx, *cmd.IdempotencyKey, cmd.AudienceID); replay != nil || err != nil {
+			return replay, err
+		}
+	}
+
+	tenantID := cmd.TenantID
+	if tenantID == "" {
+		tenantID = tenant.DefaultID
+	}
+	audienceID := cmd.AudienceID
+	b := &notification.Batch{
+		ID:             uuid.New().String(),
+		TenantID:       tenantID,
+		IdempotencyKey: cmd.IdempotencyKey,
+		ClientID:       cmd.ClientID,
+		AudienceID:     &audienceID,
+		Expanding:      true,
+		CreatedAt:      time.Now(),
+	}
+	if err := u.batch.Create(ctx, b); err != nil {
+		// A concurrent request with the same key may have won the insert.
+		if hasKey {
+			if replay, rerr := u.replay(ctx, *cmd.IdempotencyKey, cmd.AudienceID); replay != nil {
+				return replay, rerr
+			}
+		}
+		u.log.Error(ctx, "failed to create audience batch", port.F("error", err), port.F("audience_id", cmd.AudienceID))
+		return nil, err
+	}
+
+	evt := &port.FanoutEvent{
+		BatchID:    b.ID,
+		AudienceID: cmd.AudienceID,
+		TenantID:   tenantID,
+		ClientID:   cmd.ClientID,
+		Channels:   channels,
+		Content:    cmd.Content,
+		Variants:   cmd.Variants,
+		Locale:     cmd.Locale,
+		Priority:   pr,
+		Window:     cmd.DeliveryWindow,
+		Track:      cmd.Track,
+	}
+	if err := u.pub.PublishFanout(ctx, evt); err != nil {
+		u.log.Error(ctx, "failed to publish fan-out event", port.F("error", err), port.F("batch_id", b.ID))
+		// Leave an empty, completed batch rather than one in progress forever.
+		if ferr := u.batch.FinishExpansion(ctx, b.ID); ferr != nil {
+			u.log.Error(ctx, "failed to finish audience batch", port.F("error", ferr), port.F("batch_id", b.ID))
+		}
+		return nil, err
+	}
+
+	u.log.Info(ctx, "audience send accepted", port.F("batch_id", b.ID), port.F("audience_id", cmd.AudienceID))
+	return &Result{Batch: b}, nil
+}
+
+// replay returns the batch created earlier with key, or nil if there is none. A key
+// used for a different audience or a regular batch is a conflict.
+func (u *UseCase) replay(ctx context.Context, key, audienceID string) (*Result, error) {
+	b, err := u.batch.GetByIdempotencyKey(ctx, key)
+	if errors.Is(err, notification.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if b.AudienceID == nil || *b.AudienceID != audienceID {
+		return nil, notification.ErrIdempotencyConflict
+	}
+	return &Result{Batch: b, Replayed: true}, nil
+}
+
+// Expand creates the notifications of up to chunksPerEvent chunks of members, then
+// publishes a follow-up event for the rest or marks the batch fully expanded.
+// Suppressed members are skipped. Members over the tenant's quota are skipped and
+// logged.
+func (u *UseCase) Expand(ctx context.Context, evt *port.FanoutEvent) error {
+	var after *audienceport.MemberCursor
+	if evt.AfterRecipient != "" {
+		after = &audienceport.MemberCursor{Channel: evt.AfterChannel, Recipient: evt.AfterRecipient}
+	}
+
+	for i := 0; i < chunksPerEvent; i++ {
+		members, err := u.audiences.ListMembers(ctx, evt.AudienceID, audienceport.MemberFilter{
+			Channels:       evt.Channels,
+			SkipSuppressed: true,
+			After:          after,
+			Limit:          u.chunk,
+		})
+		if err != nil {
+			return err
+		}
+		if len(members) > 0 {
+			items := make([]create.BatchItem, len(members))
+			for j, m := range members {
+				key := idempotencyPrefix + evt.BatchID + ":" + m.Channel.String() + ":" + m.Recipient
+				items[j] = create.BatchItem{
+					Recipient:      m.Recipient,
+					Channel:        m.Channel.String(),
+					Content:        evt.Content,
+					Priority:       evt.Priority.String(),
+					IdempotencyKey: &key,
+					DeliveryWindow: evt.Window,
+					Track:          evt.Track,
+					Variants:       evt.Variants,
+					Locale:         evt.Locale,
+				}
+			}
+			// Scoped to the batch's tenant, member profiles are looked up within it.
+			result, err := u.creator.CreateNotificationBatches(sharedctx.WithTenantID(ctx, evt.TenantID), &create.BatchCommand{
+				Items:         items,
+				ClientID:      evt.ClientID,
+				TenantID:      evt.TenantID,
+				PartialAccept: true,
+				BatchID:       evt.BatchID,
+			})
+			switch {
+			case errors.Is(err, notification.ErrQuotaExceeded):
+				u.log.Warn(ctx, "audience members skipped: quota exceeded", port.F("batch_id", evt.BatchID), port.F("skipped", len(members)))
+			case err != nil && result == nil:
+				return err
+			case err != nil:
+				// The notifications exist; like a regular batch, their events are not retried.
+				u.log.Error(ctx, "audience chunk created with errors", port.F("error", err), port.F("batch_id", evt.BatchID))
+			}
+			last := members[len(members)-1]
+			after = &audienceport.MemberCursor{Channel: last.Channel, Recipient: last.Recipient}
+		}
+		if len(members) < u.chunk {
+			if err := u.batch.FinishExpansion(ctx, evt.BatchID); err != nil {
+				return err
+			}
+			u.log.Info(ctx, "audience batch expanded", port.F("batch_id", evt.BatchID), port.F("audience_id", evt.AudienceID))
+			return nil
+		}
+	}
+
+	next := *evt
+	next.AfterChannel = after.Channel
+	next.AfterRecipient = after.Recipient
+	return u.pub.PublishFanout(ctx, &next)
+}
@@ -0,0 +1,338 @@
+package fanout
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	audienceport "github.com/semih-yildiz/notification-service/internal/application/audience/port"
+	"github.com/semih-yildiz/notification-service/internal/application/notification/command/create"
+	"github.com/semih-yildiz/notification-service/internal/application/notification/port"
+	"github.com/semih-yildiz/notification-service/internal/domain/audience"
+	"github.com/semih-yildiz/notification-service/internal/domain/notification"
+	sharedctx "github.com/semih-yildiz/notification-service/internal/shared/context"
+)
+
+// mockAudienceRepo holds one audience whose members are sorted by channel, recipient.
+type mockAudienceRepo struct {
+	audience *audience.Audience
+	members  []audience.Member
+}
+
+func (m *mockAudienceRepo) Create(ctx context.Context, a *audience.Audience) error { return nil }
+
+func (m *mockAudienceRepo) GetByID(ctx context.Context, id string) (*audience.Audience, error) {
+	if m.audience == nil || m.audience.ID != id {
+		return nil, audience.ErrNotFound
+	}
+	return m.audience, nil
+}
+
+func (m *mockAudienceRepo) List(ctx context.Context) ([]*audience.Audience, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *mockAudienceRepo) Delete(ctx context.Context, id string) error { return nil }
+
+func (m *mockAudienceRepo) AddMembers(ctx context.Context, audienceID string, members []audience.Member) error {
+	return nil
+}
+
+func (m *mockAudienceRepo) RemoveMember(ctx context.Context, audienceID string, ch notification.Channel, recipient string) error {
+	return nil
+}
+
+func (m *mockAudienceRepo) ListMembers(ctx context.Context, audienceID string, filter audienceport.MemberFilter) ([]audience.Member, error) {
+	var out []audience.Member
+	for _, mem := range m.members {
+		if filter.SkipSuppressed && mem.Suppressed {
+			continue
+		}
+		if len(filter.Channels) > 0 && !containsChannel(filter.Channels, mem.Channel) {
+			continue
+		}
+		if a := filter.After; a != nil && (mem.Channel < a.Channel || mem.Channel == a.Channel && mem.Recipient <= a.Recipient) {
+			continue
+		}
+		if len(out) == filter.Limit {
+			break
+		}
+		out = append(out, mem)
+	}
+	return out, nil
+}
+
+func containsChannel(channels []notification.Channel, ch notification.Channel) bool {
+	for _, c := range channels {
+		if c == ch {
+			return true
+		}
+	}
+	return false
+}
+
+type mockBatchRepo struct {
+	created  []*notification.Batch
+	finished []string
+}
+
+func (m *mockBatchRepo) Create(ctx context.Context, b *notification.Batch) error {
+	m.created = append(m.created, b)
+	return nil
+}
+
+func (m *mockBatchRepo) GetByID(ctx context.Context, id string) (*notification.Batch, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *mockBatchRepo) GetByIdempotencyKey(ctx context.Context, key string) (*notification.Batch, error) {
+	for _, b := range m.created {
+		if b.IdempotencyKey != nil && *b.IdempotencyKey == key {
+			return b, nil
+		}
+	}
+	return nil, notification.ErrNotFound
+}
+
+func (m *mockBatchRepo) FinishExpansion(ctx context.Context, id string) error {
+	m.finished = append(m.finished, id)
+	return nil
+}
+
+type mockFanoutPublisher struct {
+	events []*port.FanoutEvent
+	err    error
+}
+
+func (m *mockFanoutPublisher) PublishFanout(ctx context.Context, evt *port.FanoutEvent) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.events = append(m.events, evt)
+	return nil
+}
+
+type mockBatchCreator struct {
+	cmds    []*create.BatchCommand
+	tenants []string // tenant each command's context was scoped to
+	err     error
+}
+
+func (m *mockBatchCreator) CreateNotificationBatches(ctx context.Context, cmd *create.BatchCommand) (*create.BatchResult, error) {
+	m.cmds = append(m.cmds, cmd)
+	tenantID, _ := sharedctx.TenantID(ctx)
+	m.tenants = append(m.tenants, tenantID)
+	if m.err != nil {
+		return nil, m.err
+	}
+	return &create.BatchResult{BatchID: cmd.BatchID}, nil
+}
+
+type mockLogger struct{}
+
+func (m *mockLogger) Info(ctx context.Context, msg string, fields ...port.Field)  {}
+func (m *mockLogger) Warn(ctx context.Context, msg string, fields ...port.Field)  {}
+func (m *mockLogger) Error(ctx context.Context, msg string, fields ...port.Field) {}
+
+func newTestUseCase(members []audience.Member) (*UseCase, *mockBatchRepo, *mockFanoutPublisher, *mockBatchCreator) {
+	audiences := &mockAudienceRepo{audience: &audience.Audience{ID: "aud-1", TenantID: "default", Name: "Newsletter"}, members: members}
+	batch := &mockBatchRepo{}
+	pub := &mockFanoutPublisher{}
+	creator := &mockBatchCreator{}
+	return NewUseCase(batch, audiences, pub, creator, &mockLogger{}), batch, pub, creator
+}
+
+func TestSend_CreatesExpandingBatch(t *testing.T) {
+	uc, batch, pub, _ := newTestUseCase(nil)
+	key := "send-1"
+
+	result, err := uc.Send(context.Background(), &Command{AudienceID: "aud-1", Channels: []string{"email", "sms", "email"}, Content: "Hello", IdempotencyKey: &key})
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !result.Batch.Expanding || result.Batch.Status() != notification.BatchStatusInProgress {
+		t.Errorf("expected an expanding batch in progress, got %+v", result.Batch)
+	}
+	if len(pub.events) != 1 || len(pub.events[0].Channels) != 2 || pub.events[0].Priority != notification.PriorityNormal {
+		t.Fatalf("expected one event for 2 channels at normal priority, got %+v", pub.events)
+	}
+
+	again, err := uc.Send(context.Background(), &Command{AudienceID: "aud-1", Channels: []string{"email"}, Content: "Hello", IdempotencyKey: &key})
+	if err != nil || !again.Replayed || again.Batch.ID != result.Batch.ID {
+		t.Errorf("expected the original batch replayed, got %+v (%v)", again, err)
+	}
+	if len(batch.created) != 1 || len(pub.events) != 1 {
+		t.Errorf("expected no second batch or event, got %d batches, %d events", len(batch.created), len(pub.events))
+	}
+}
+
+func TestSend_Rejected(t *testing.T) {
+	uc, batch, _, _ := newTestUseCase(nil)
+
+	tests := []struct {
+		name    string
+		cmd     *Command
+		wantErr error
+	}{
+		{"no channels", &Command{AudienceID: "aud-1", Content: "Hello"}, notification.ErrInvalidChannel},
+		{"unknown channel", &Command{AudienceID: "aud-1", Channels: []string{"fax"}, Content: "Hello"}, notification.ErrInvalidChannel},
+		{"empty content", &Command{AudienceID: "aud-1", Channels: []string{"sms"}}, notification.ErrInvalidContent},
+		{"bad priority", &Command{AudienceID: "aud-1", Channels: []string{"sms"}, Content: "Hello", Priority: "urgent"}, notification.ErrInvalidPriority},
+		{"bad variants", &Command{AudienceID: "aud-1", Channels: []string{"sms"}, Variants: notification.Variants{"not a locale": "Merhaba"}}, notification.ErrInvalidVariants},
+		{"bad locale", &Command{AudienceID: "aud-1", Channels: []string{"sms"}, Content: "Hello", Locale: "??"}, notification.ErrInvalidLocale},
+		{"unknown audience", &Command{AudienceID: "missing", Channels: []string{"sms"}, Content: "Hello"}, audience.ErrNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := uc.Send(context.Background(), tt.cmd); !errors.Is(err, tt.wantErr) {
+				t.Errorf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+	if len(batch.created) != 0 {
+		t.Errorf("expected no batch created, got %d", len(batch.created))
+	}
+}
+
+func TestSend_PublishFailureFinishesBatch(t *testing.T) {
+	uc, batch, pub, _ := newTestUseCase(nil)
+	pub.err = errors.New("broker down")
+
+	if _, err := uc.Send(context.Background(), &Command{AudienceID: "aud-1", Channels: []string{"sms"}, Content: "Hello"}); err == nil {
+		t.Fatal("expected an error")
+	}
+	if len(batch.finished) != 1 {
+		t.Errorf("expected the batch to be finished, got %v", batch.finished)
+	}
+}
+
+func TestExpand_SkipsSuppressedAndOtherChannels(t *testing.T) {
+	uc, batch, pub, creator := newTestUseCase([]audience.Member{
+		{Channel: notification.ChannelEmail, Recipient: "a@example.com"},
+		{Channel: notification.ChannelEmail, Recipient: "b@example.com", Suppressed: true},
+		{Channel: notification.ChannelEmail, Recipient: "c@example.com"},
+		{Channel: notification.ChannelPush, Recipient: "token-1"},
+		{Channel: notification.ChannelSMS, Recipient: "+905551112233"},
+	})
+	uc.chunk = 2
+
+	err := uc.Expand(context.Background(), &port.FanoutEvent{
+		BatchID:    "batch-1",
+		AudienceID: "aud-1",
+		TenantID:   "default",
+		Channels:   []notification.Channel{notification.ChannelEmail, notification.ChannelSMS},
+		Content:    "Hello",
+		Priority:   notification.PriorityLow,
+	})
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	var recipients []string
+	for _, cmd := range creator.cmds {
+		if cmd.BatchID != "batch-1" || !cmd.PartialAccept {
+			t.Errorf("expected partial chunks of batch-1, got %+v", cmd)
+		}
+		for _, it := range cmd.Items {
+			recipients = append(recipients, it.Recipient)
+		}
+	}
+	if len(recipients) != 3 || recipients[1] != "c@example.com" {
+		t.Errorf("expected the 3 unsuppressed email and sms members, got %v", recipients)
+	}
+	if key := creator.cmds[0].Items[0].IdempotencyKey; key == nil || *key != "audience:batch-1:email:a@example.com" {
+		t.Errorf("expected a per-member idempotency key, got %v", key)
+	}
+	if len(batch.finished) != 1 || len(pub.events) != 0 {
+		t.Errorf("expected the batch finished without a follow-up, got %v and %d events", batch.finished, len(pub.events))
+	}
+}
+
+func TestExpand_PassesVariants(t *testing.T) {
+	uc, _, pub, creator := newTestUseCase([]audience.Member{{Channel: notification.ChannelSMS, Recipient: "+905551112233"}})
+	variants := notification.Variants{"tr": "Merhaba", "de": "Hallo"}
+
+	if _, err := uc.Send(context.Background(), &Command{AudienceID: "aud-1", Channels: []string{"sms"}, Variants: variants, Locale: "tr-TR"}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(pub.events) != 1 {
+		t.Fatalf("expected one event, got %d", len(pub.events))
+	}
+	if err := uc.Expand(context.Background(), pub.events[0]); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(creator.cmds) != 1 || len(creator.cmds[0].Items) != 1 {
+		t.Fatalf("expected one chunk of one item, got %+v", creator.cmds)
+	}
+	if item := creator.cmds[0].Items[0]; item.Locale != "tr-TR" || item.Variants["tr"] != "Merhaba" || len(item.Variants) != 2 {
+		t.Errorf("expected the variants and locale on the item, got %+v", item)
+	}
+}
+
+func TestExpand_TrackedChunkScopedToTenant(t *testing.T) {
+	uc, _, _, creator := newTestUseCase([]audience.Member{{Channel: notification.ChannelEmail, Recipient: "opted-out@example.com"}})
+
+	// The worker's context sees every tenant; tracking opt-outs must not.
+	err := uc.Expand(sharedctx.Unscoped(context.Background()), &port.FanoutEvent{
+		BatchID:    "batch-1",
+		AudienceID: "aud-1",
+		TenantID:   "acme",
+		Channels:   []notification.Channel{notification.ChannelEmail},
+		Content:    "<p>Hello</p>",
+		Priority:   notification.PriorityNormal,
+		Track:      true,
+	})
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(creator.cmds) != 1 || !creator.cmds[0].Items[0].Track {
+		t.Fatalf("expected one tracked chunk, got %+v", creator.cmds)
+	}
+	if creator.tenants[0] != "acme" {
+		t.Errorf("expected the chunk created within tenant acme, got %q", creator.tenants[0])
+	}
+}
+
+func TestExpand_ContinuesInFollowUpEvent(t *testing.T) {
+	var members []audience.Member
+	for _, r := range []string{"01", "02", "03", "04", "05", "06", "07", "08", "09", "10", "11", "12"} {
+		members = append(members, audience.Member{Channel: notification.ChannelSMS, Recipient: r})
+	}
+	uc, batch, pub, creator := newTestUseCase(members)
+	uc.chunk = 1
+	evt := &port.FanoutEvent{BatchID: "batch-1", AudienceID: "aud-1", Channels: []notification.Channel{notification.ChannelSMS}, Content: "Hello"}
+
+	if err := uc.Expand(context.Background(), evt); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(creator.cmds) != chunksPerEvent || len(batch.finished) != 0 {
+		t.Fatalf("expected %d chunks and an unfinished batch, got %d and %v", chunksPerEvent, len(creator.cmds), batch.finished)
+	}
+	if len(pub.events) != 1 || pub.events[0].AfterRecipient != "10" {
+		t.Fatalf("expected a follow-up after member 10, got %+v", pub.events)
+	}
+
+	if err := uc.Expand(context.Background(), pub.events[0]); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(creator.cmds) != 12 || len(batch.finished) != 1 {
+		t.Errorf("expected all 12 members and a finished batch, got %d and %v", len(creator.cmds), batch.finished)
+	}
+}
+
+func TestExpand_QuotaExceededSkipsChunk(t *testing.T) {
+	uc, batch, _, creator := newTestUseCase([]audience.Member{{Channel: notification.ChannelSMS, Recipient: "+905551112233"}})
+	creator.err = notification.ErrQuotaExceeded
+
+	err := uc.Expand(context.Background(), &port.FanoutEvent{BatchID: "batch-1", AudienceID: "aud-1", Channels: []notification.Channel{notification.ChannelSMS}, Content: "Hello"})
+
+	if err != nil {
+		t.Fatalf("expected the chunk skipped without an error, got %v", err)
+	}
+	if len(batch.finished) != 1 {
+		t.Errorf("expected the batch finished, got %v", batch.finished)
+	}
+}
@@ -2,6 +2,7 @@ package process
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -14,6 +15,8 @@ import (
 const maxDeliveryAttempts = 5
 const initialBackoff = time.Second
 
+var errInboxDisabled = errors.New("in-app delivery is not configured")
+
 // UseCase processes a notification: rate limit, deliver with retry, update status.
 type UseCase struct {
 	notifRepo   port.NotificationRepository
@@ -21,6 +24,11 @@ type UseCase struct {
 	rateLimit   port.RateLimiter
 	delivery    port.DeliveryClient
 	log         port.Logger
+	events      port.StatusBroadcaster         // optional; nil disables live status events
+	deferrals   port.DeferralRepository        // optional; nil ignores delivery windows
+	tracking    port.TrackingLinks             // optional; nil sends email untracked
+	inbox       port.InboxDelivery             // optional; nil fails in-app notifications
+	providers   map[string]port.DeliveryClient // by notification.Provider*; others use delivery
 }
 
 // NewUseCase returns a new process use case.
@@ -40,6 +48,42 @@ func NewUseCase(
 	}
 }
 
+// WithStatusEvents broadcasts every status change the worker makes to live subscribers.
+func (u *UseCase) WithStatusEvents(events port.StatusBroadcaster) *UseCase {
+	u.events = events
+	return u
+}
+
+// WithDeliveryWindows defers non-urgent notifications that arrive outside their
+// delivery window until it opens.
+func (u *UseCase) WithDeliveryWindows(deferrals port.DeferralRepository) *UseCase {
+	u.deferrals = deferrals
+	return u
+}
+
+// WithTracking adds an open pixel and click tracking links to the HTML email of
+// notifications created with tracking.
+func (u *UseCase) WithTracking(links port.TrackingLinks) *UseCase {
+	u.tracking = links
+	return u
+}
+
+// WithInbox delivers in-app notifications to the recipient's inbox.
+func (u *UseCase) WithInbox(inbox port.InboxDelivery) *UseCase {
+	u.inbox = inbox
+	return u
+}
+
+// WithProvider sends notifications of the channels bound to provider (see
+// notification.ChannelSpec) through client instead of the default one.
+func (u *UseCase) WithProvider(provider string, client port.DeliveryClient) *UseCase {
+	if u.providers == nil {
+		u.providers = make(map[string]port.DeliveryClient)
+	}
+	u.providers[provider] = client
+	return u
+}
+
 // Execute processes one notification.
 func (u *UseCase) Execute(ctx context.Context, cmd *Command) error {
 	u.log.Info(ctx, "processing notification", port.F("notification_id", cmd.NotificationID))
@@ -55,10 +99,14 @@ func (u *UseCase) Execute(ctx context.Context, cmd *Command) error {
 		return nil
 	}
 
-	allowed, err := u.rateLimit.Allow(ctx, n.Channel)
+	if deferred, err := u.deferOutsideWindow(ctx, n); deferred || err != nil {
+		return err
+	}
+
+	allowed, err := u.rateLimit.Allow(ctx, n.TenantID, n.Channel)
 	if err != nil || !allowed {
 		if !allowed {
-			u.log.Warn(ctx, "rate limit exceeded", port.F("notification_id", cmd.NotificationID), port.F("channel", n.Channel))
+			u.log.Warn(ctx, "rate limit exceeded", port.F("notification_id", cmd.NotificationID), port.F("channel", n.Channel), port.F("tenant_id", n.TenantID))
 			return fmt.Errorf("rate limit exceeded for channel %s", n.Channel)
 		}
 		u.log.Error(ctx, "rate limiter error", port.F("error", err), port.F("notification_id", cmd.NotificationID))
@@ -68,17 +116,19 @@ func (u *UseCase) Execute(ctx context.Context, cmd *Command) error {
 	req := &port.DeliveryRequest{
 		To:      n.Recipient,
 		Channel: n.Channel.String(),
-		Content: n.Content,
+		Content: u.trackedContent(n),
 	}
 
 	var lastErr error
 	var lastCode int
+	attempts := 0
 	backoff := initialBackoff
 
 	for attempt := 1; attempt <= maxDeliveryAttempts; attempt++ {
+		attempts = attempt
 		u.log.Info(ctx, "delivery attempt", port.F("notification_id", cmd.NotificationID), port.F("attempt", attempt), port.F("channel", n.Channel))
 
-		resp, code, err := u.delivery.Deliver(ctx, req)
+		resp, code, err := u.deliver(ctx, n, req)
 
 		da := &notification.DeliveryAttempt{
 			ID:             uuid.New().String(),
@@ -99,6 +149,9 @@ func (u *UseCase) Execute(ctx context.Context, cmd *Command) error {
 			}
 			u.log.Warn(ctx, "delivery attempt failed", port.F("notification_id", cmd.NotificationID), port.F("attempt", attempt), port.F("status_code", code), port.F("error", err))
 
+			if errors.Is(err, port.ErrDeliveryRejected) {
+				break
+			}
 			if attempt < maxDeliveryAttempts {
 				u.log.Info(ctx, "retrying after backoff", port.F("notification_id", cmd.NotificationID), port.F("backoff_ms", backoff.Milliseconds()))
 				time.Sleep(backoff)
@@ -119,20 +172,94 @@ func (u *UseCase) Execute(ctx context.Context, cmd *Command) error {
 		if err := u.notifRepo.UpdateStatus(ctx, cmd.NotificationID, notification.StatusSent, &now, nil); err != nil {
 			u.log.Error(ctx, "failed to update status to sent", port.F("error", err), port.F("notification_id", cmd.NotificationID))
 			// Don't fail the delivery since it was successful
+		} else {
+			u.broadcast(ctx, n, notification.StatusSent, nil)
 		}
 		u.log.Info(ctx, "notification delivered successfully", port.F("notification_id", cmd.NotificationID), port.F("attempt", attempt), port.F("message_id", da.ResponseBody))
 		return nil
 	}
 
-	reason := fmt.Sprintf("failed after %d attempts: %v", maxDeliveryAttempts, lastErr)
+	reason := fmt.Sprintf("failed after %d attempts: %v", attempts, lastErr)
 	if lastCode > 0 {
 		reason = fmt.Sprintf("status %d: %s", lastCode, reason)
 	}
 	if err := u.notifRepo.UpdateStatus(ctx, cmd.NotificationID, notification.StatusFailed, nil, &reason); err != nil {
 		u.log.Error(ctx, "failed to update status to failed", port.F("error", err), port.F("notification_id", cmd.NotificationID))
 		// Continue anyway since we want to return the delivery error
+	} else {
+		u.broadcast(ctx, n, notification.StatusFailed, &reason)
 	}
-	u.log.Error(ctx, "notification delivery failed permanently", port.F("notification_id", cmd.NotificationID), port.F("attempts", maxDeliveryAttempts), port.F("last_error", lastErr), port.F("last_code", lastCode))
+	u.log.Error(ctx, "notification delivery failed permanently", port.F("notification_id", cmd.NotificationID), port.F("attempts", attempts), port.F("last_error", lastErr), port.F("last_code", lastCode))
 
 	return lastErr
 }
+
+// deferOutsideWindow parks n until its delivery window opens if the window is
+// closed now. High priority notifications are delivered at any time.
+func (u *UseCase) deferOutsideWindow(ctx context.Context, n *notification.Notification) (bool, error) {
+	w := n.DeliveryWindow
+	if u.deferrals == nil || w == nil || n.Priority == notification.PriorityHigh {
+		return false, nil
+	}
+	now := time.Now()
+	if w.Open(now) {
+		return false, nil
+	}
+	until := w.NextOpen(now)
+	detail := fmt.Sprintf("outside delivery window %s; deferred until %s", w, until.UTC().Format(time.RFC3339))
+	if err := u.deferrals.Defer(ctx, n.ID, until, detail); err != nil {
+		u.log.Error(ctx, "failed to defer notification", port.F("error", err), port.F("notification_id", n.ID))
+		return false, err
+	}
+	u.broadcast(ctx, n, notification.StatusDeferred, nil)
+	u.log.Info(ctx, "notification deferred to its delivery window", port.F("notification_id", n.ID), port.F("until", until))
+	return true, nil
+}
+
+// deliver sends req through the provider the channel is bound to, or stores
+// the notification in the recipient's inbox.
+func (u *UseCase) deliver(ctx context.Context, n *notification.Notification, req *port.DeliveryRequest) (*port.DeliveryResponse, int, error) {
+	spec, _ := notification.LookupChannel(n.Channel)
+	if spec.Provider != notification.ProviderInbox {
+		if client, ok := u.providers[spec.Provider]; ok {
+			return client.Deliver(ctx, req)
+		}
+		return u.delivery.Deliver(ctx, req)
+	}
+	if u.inbox == nil {
+		return nil, 0, errInboxDisabled
+	}
+	id, err := u.inbox.Deliver(ctx, n)
+	if err != nil {
+		return nil, 0, err
+	}
+	return &port.DeliveryResponse{MessageID: id, Status: "stored", Timestamp: time.Now().Format(time.RFC3339)}, 0, nil
+}
+
+// trackedContent returns the content to deliver: HTML email of a tracked
+// notification gets the tracking pixel and links, anything else is sent as is.
+func (u *UseCase) trackedContent(n *notification.Notification) string {
+	if u.tracking == nil || !n.Tracking || n.Channel != notification.ChannelEmail || !notification.IsHTML(n.Content) {
+		return n.Content
+	}
+	return notification.InjectTracking(n.Content, u.tracking.OpenURL(n.ID), func(target string) string {
+		return u.tracking.ClickURL(n.ID, target)
+	})
+}
+
+// broadcast publishes a status change; failures only cost live viewers an update.
+func (u *UseCase) broadcast(ctx context.Context, n *notification.Notification, status notification.Status, reason *string) {
+	if u.events == nil {
+		return
+	}
+	evt := &port.StatusEvent{
+		NotificationID: n.ID,
+		BatchID:        n.BatchID,
+		Status:         status,
+		FailureReason:  reason,
+		OccurredAt:     time.Now(),
+	}
+	if err := u.events.Broadcast(ctx, evt); err != nil {
+		u.log.Warn(ctx, "failed to broadcast status event", port.F("error", err), port.F("notification_id", n.ID))
+	}
+}
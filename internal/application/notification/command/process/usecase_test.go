@@ -3,6 +3,8 @@ package process
 import (
 	"context"
 	"errors"
+	"fmt"
+	"strings"
 	"testing"
 	"time"
 
@@ -29,11 +31,15 @@ func (m *mockNotificationRepo) UpdateStatus(ctx context.Context, id string, stat
 	return nil
 }
 
+func (m *mockNotificationRepo) UpdateStatuses(ctx context.Context, ids []string, status notification.Status) error {
+	return nil
+}
+
 func (m *mockNotificationRepo) Create(ctx context.Context, n *notification.Notification) error {
 	return errors.New("not implemented")
 }
 
-func (m *mockNotificationRepo) CreateBatch(ctx context.Context, notifications []*notification.Notification) error {
+func (m *mockNotificationRepo) CreateBatch(ctx context.Context, b *notification.Batch, notifications []*notification.Notification) error {
 	return errors.New("not implemented")
 }
 
@@ -57,6 +63,14 @@ func (m *mockNotificationRepo) ExistsByIdempotencyKey(ctx context.Context, key s
 	return false, errors.New("not implemented")
 }
 
+func (m *mockNotificationRepo) GetByIdempotencyKey(ctx context.Context, key string) (*notification.Notification, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *mockNotificationRepo) IDsByIdempotencyKey(ctx context.Context, keys []string) (map[string]string, error) {
+	return nil, errors.New("not implemented")
+}
+
 type mockDeliveryAttemptRepo struct {
 	createFn func(ctx context.Context, da *notification.DeliveryAttempt) error
 }
@@ -73,12 +87,12 @@ func (m *mockDeliveryAttemptRepo) GetByNotificationID(ctx context.Context, notif
 }
 
 type mockRateLimiter struct {
-	allowFn func(ctx context.Context, channel notification.Channel) (bool, error)
+	allowFn func(ctx context.Context, tenantID string, channel notification.Channel) (bool, error)
 }
 
-func (m *mockRateLimiter) Allow(ctx context.Context, channel notification.Channel) (bool, error) {
+func (m *mockRateLimiter) Allow(ctx context.Context, tenantID string, channel notification.Channel) (bool, error) {
 	if m.allowFn != nil {
-		return m.allowFn(ctx, channel)
+		return m.allowFn(ctx, tenantID, channel)
 	}
 	return true, nil
 }
@@ -100,6 +114,15 @@ func (m *mockLogger) Info(ctx context.Context, msg string, fields ...port.Field)
 func (m *mockLogger) Warn(ctx context.Context, msg string, fields ...port.Field)  {}
 func (m *mockLogger) Error(ctx context.Context, msg string, fields ...port.Field) {}
 
+type mockStatusBroadcaster struct {
+	events []*port.StatusEvent
+}
+
+func (m *mockStatusBroadcaster) Broadcast(ctx context.Context, evt *port.StatusEvent) error {
+	m.events = append(m.events, evt)
+	return nil
+}
+
 func TestExecute_Success(t *testing.T) {
 	notifRepo := &mockNotificationRepo{
 		getByIDFn: func(ctx context.Context, id string) (*notification.Notification, error) {
@@ -129,6 +152,36 @@ func TestExecute_Success(t *testing.T) {
 	}
 }
 
+func TestExecute_BroadcastsStatusChange(t *testing.T) {
+	batchID := "batch-1"
+	notifRepo := &mockNotificationRepo{
+		getByIDFn: func(ctx context.Context, id string) (*notification.Notification, error) {
+			return &notification.Notification{
+				ID:       id,
+				BatchID:  &batchID,
+				Channel:  notification.ChannelSMS,
+				Priority: notification.PriorityNormal,
+				Status:   notification.StatusQueued,
+			}, nil
+		},
+	}
+	events := &mockStatusBroadcaster{}
+
+	uc := NewUseCase(notifRepo, &mockDeliveryAttemptRepo{}, &mockRateLimiter{}, &mockDeliveryClient{}, &mockLogger{}).
+		WithStatusEvents(events)
+
+	if err := uc.Execute(context.Background(), &Command{NotificationID: "test-id"}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(events.events) != 1 {
+		t.Fatalf("expected 1 event, got %d", len(events.events))
+	}
+	evt := events.events[0]
+	if evt.NotificationID != "test-id" || evt.BatchID == nil || *evt.BatchID != batchID || evt.Status != notification.StatusSent {
+		t.Errorf("unexpected event: %+v", evt)
+	}
+}
+
 func TestExecute_NotificationNotFound(t *testing.T) {
 	notifRepo := &mockNotificationRepo{
 		getByIDFn: func(ctx context.Context, id string) (*notification.Notification, error) {
@@ -183,7 +236,7 @@ func TestExecute_RateLimitExceeded(t *testing.T) {
 	}
 
 	rateLimiter := &mockRateLimiter{
-		allowFn: func(ctx context.Context, channel notification.Channel) (bool, error) {
+		allowFn: func(ctx context.Context, tenantID string, channel notification.Channel) (bool, error) {
 			return false, nil
 		},
 	}
@@ -212,7 +265,7 @@ func TestExecute_RateLimiterError(t *testing.T) {
 	}
 
 	rateLimiter := &mockRateLimiter{
-		allowFn: func(ctx context.Context, channel notification.Channel) (bool, error) {
+		allowFn: func(ctx context.Context, tenantID string, channel notification.Channel) (bool, error) {
 			return false, errors.New("redis error")
 		},
 	}
@@ -309,6 +362,46 @@ func TestExecute_AllDeliveryAttemptsFail(t *testing.T) {
 	}
 }
 
+func TestExecute_DeliveryRejected(t *testing.T) {
+	notifRepo := &mockNotificationRepo{
+		getByIDFn: func(ctx context.Context, id string) (*notification.Notification, error) {
+			return &notification.Notification{
+				ID:        id,
+				Recipient: "billing#alerts",
+				Channel:   notification.ChannelChat,
+				Content:   "Test message",
+				Status:    notification.StatusQueued,
+			}, nil
+		},
+	}
+	var reason string
+	notifRepo.updateStatusFn = func(ctx context.Context, id string, s notification.Status, sentAt *time.Time, r *string) error {
+		if s == notification.StatusFailed && r != nil {
+			reason = *r
+		}
+		return nil
+	}
+
+	attemptCount := 0
+	deliveryClient := &mockDeliveryClient{
+		deliverFn: func(ctx context.Context, req *port.DeliveryRequest) (*port.DeliveryResponse, int, error) {
+			attemptCount++
+			return nil, 404, fmt.Errorf("%w: status 404", port.ErrDeliveryRejected)
+		},
+	}
+
+	uc := NewUseCase(notifRepo, &mockDeliveryAttemptRepo{}, &mockRateLimiter{}, deliveryClient, &mockLogger{})
+	if err := uc.Execute(context.Background(), &Command{NotificationID: "test-id"}); !errors.Is(err, port.ErrDeliveryRejected) {
+		t.Errorf("expected ErrDeliveryRejected, got %v", err)
+	}
+	if attemptCount != 1 {
+		t.Errorf("expected a single attempt, got %d", attemptCount)
+	}
+	if !strings.Contains(reason, "failed after 1 attempts") {
+		t.Errorf("expected the failure reason to count one attempt, got %q", reason)
+	}
+}
+
 func TestExecute_DeliveryAttemptSaveError(t *testing.T) {
 	notifRepo := &mockNotificationRepo{
 		getByIDFn: func(ctx context.Context, id string) (*notification.Notification, error) {
@@ -368,3 +461,228 @@ func TestExecute_UpdateStatusError(t *testing.T) {
 		t.Error("expected status update to be called")
 	}
 }
+
+type mockDeferralRepo struct {
+	deferred map[string]time.Time
+}
+
+func (m *mockDeferralRepo) Defer(ctx context.Context, id string, until time.Time, detail string) error {
+	if m.deferred == nil {
+		m.deferred = make(map[string]time.Time)
+	}
+	m.deferred[id] = until
+	return nil
+}
+
+func (m *mockDeferralRepo) ReleaseDue(ctx context.Context, now time.Time, limit int) ([]*notification.Notification, error) {
+	return nil, errors.New("not implemented")
+}
+
+// closedWindow returns a UTC window that opens two hours from now.
+func closedWindow() *notification.DeliveryWindow {
+	now := time.Now().UTC()
+	start := (now.Hour()+2)%24*60 + now.Minute()
+	return &notification.DeliveryWindow{Start: start, End: (start + 60) % (24 * 60)}
+}
+
+func TestExecute_DefersOutsideDeliveryWindow(t *testing.T) {
+	window := closedWindow()
+	tests := []struct {
+		name         string
+		priority     notification.Priority
+		wantDeferred bool
+	}{
+		{"normal is deferred", notification.PriorityNormal, true},
+		{"low is deferred", notification.PriorityLow, true},
+		{"high bypasses", notification.PriorityHigh, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			notifRepo := &mockNotificationRepo{
+				getByIDFn: func(ctx context.Context, id string) (*notification.Notification, error) {
+					return &notification.Notification{ID: id, Channel: notification.ChannelPush, Priority: tt.priority, Status: notification.StatusQueued, DeliveryWindow: window}, nil
+				},
+			}
+			delivered := false
+			delivery := &mockDeliveryClient{deliverFn: func(ctx context.Context, req *port.DeliveryRequest) (*port.DeliveryResponse, int, error) {
+				delivered = true
+				return &port.DeliveryResponse{MessageID: "m-1"}, 202, nil
+			}}
+			deferrals := &mockDeferralRepo{}
+			events := &mockStatusBroadcaster{}
+			uc := NewUseCase(notifRepo, &mockDeliveryAttemptRepo{}, &mockRateLimiter{}, delivery, &mockLogger{}).
+				WithStatusEvents(events).
+				WithDeliveryWindows(deferrals)
+
+			if err := uc.Execute(context.Background(), &Command{NotificationID: "n-1"}); err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			until, deferred := deferrals.deferred["n-1"]
+			if deferred != tt.wantDeferred || delivered == tt.wantDeferred {
+				t.Fatalf("expected deferred=%v, got deferred=%v delivered=%v", tt.wantDeferred, deferred, delivered)
+			}
+			if !deferred {
+				return
+			}
+			if !window.Open(until) || until.Before(time.Now()) {
+				t.Errorf("expected deferral to the next window opening, got %v", until)
+			}
+			if len(events.events) != 1 || events.events[0].Status != notification.StatusDeferred {
+				t.Errorf("expected a deferred event, got %+v", events.events)
+			}
+		})
+	}
+}
+
+type stubTrackingLinks struct{}
+
+func (stubTrackingLinks) OpenURL(id string) string { return "https://t.example/open/" + id }
+func (stubTrackingLinks) ClickURL(id, target string) string {
+	return "https://t.example/click/" + id + "?url=" + target
+}
+func (stubTrackingLinks) VerifyOpen(id, sig string) bool          { return false }
+func (stubTrackingLinks) VerifyClick(id, target, sig string) bool { return false }
+
+func TestExecute_Tracking(t *testing.T) {
+	html := `<p>Hi <a href="https://shop.example">shop</a></p>`
+	tests := []struct {
+		name        string
+		channel     notification.Channel
+		content     string
+		tracking    bool
+		wantContent string
+	}{
+		{
+			name:        "Tracked HTML email",
+			channel:     notification.ChannelEmail,
+			content:     html,
+			tracking:    true,
+			wantContent: `<p>Hi <a href="https://t.example/click/n-1?url=https://shop.example">shop</a></p><img src="https://t.example/open/n-1" width="1" height="1" alt="" style="display:none">`,
+		},
+		{"Untracked HTML email", notification.ChannelEmail, html, false, html},
+		{"Tracked plain text email", notification.ChannelEmail, "Hi, see https://shop.example", true, "Hi, see https://shop.example"},
+		{"Tracked SMS", notification.ChannelSMS, html, true, html},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			notifRepo := &mockNotificationRepo{
+				getByIDFn: func(ctx context.Context, id string) (*notification.Notification, error) {
+					return &notification.Notification{
+						ID: id, Recipient: "user@example.com", Channel: tt.channel, Content: tt.content,
+						Priority: notification.PriorityNormal, Status: notification.StatusQueued, Tracking: tt.tracking,
+					}, nil
+				},
+			}
+			var sent string
+			deliveryClient := &mockDeliveryClient{
+				deliverFn: func(ctx context.Context, req *port.DeliveryRequest) (*port.DeliveryResponse, int, error) {
+					sent = req.Content
+					return &port.DeliveryResponse{MessageID: "msg-1"}, 202, nil
+				},
+			}
+
+			uc := NewUseCase(notifRepo, &mockDeliveryAttemptRepo{}, &mockRateLimiter{}, deliveryClient, &mockLogger{}).
+				WithTracking(stubTrackingLinks{})
+			if err := uc.Execute(context.Background(), &Command{NotificationID: "n-1"}); err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if sent != tt.wantContent {
+				t.Errorf("delivered content = %q, want %q", sent, tt.wantContent)
+			}
+		})
+	}
+}
+
+type mockInbox struct {
+	delivered []string
+}
+
+func (m *mockInbox) Deliver(ctx context.Context, n *notification.Notification) (string, error) {
+	m.delivered = append(m.delivered, n.ID)
+	return n.ID, nil
+}
+
+func TestExecute_InApp(t *testing.T) {
+	notifRepo := &mockNotificationRepo{
+		getByIDFn: func(ctx context.Context, id string) (*notification.Notification, error) {
+			return &notification.Notification{
+				ID: id, Recipient: "u-1", Channel: notification.ChannelInApp, Content: "Your order shipped",
+				Priority: notification.PriorityNormal, Status: notification.StatusQueued,
+			}, nil
+		},
+	}
+	var status notification.Status
+	notifRepo.updateStatusFn = func(ctx context.Context, id string, s notification.Status, sentAt *time.Time, reason *string) error {
+		status = s
+		return nil
+	}
+	var attempt *notification.DeliveryAttempt
+	attemptRepo := &mockDeliveryAttemptRepo{
+		createFn: func(ctx context.Context, da *notification.DeliveryAttempt) error {
+			attempt = da
+			return nil
+		},
+	}
+	deliveryClient := &mockDeliveryClient{
+		deliverFn: func(ctx context.Context, req *port.DeliveryRequest) (*port.DeliveryResponse, int, error) {
+			t.Error("expected no provider call for an in-app notification")
+			return nil, 0, errors.New("unexpected")
+		},
+	}
+	inbox := &mockInbox{}
+
+	uc := NewUseCase(notifRepo, attemptRepo, &mockRateLimiter{}, deliveryClient, &mockLogger{}).WithInbox(inbox)
+	if err := uc.Execute(context.Background(), &Command{NotificationID: "n-1"}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(inbox.delivered) != 1 || inbox.delivered[0] != "n-1" {
+		t.Errorf("expected n-1 in the inbox, got %v", inbox.delivered)
+	}
+	if status != notification.StatusSent || attempt == nil || !attempt.Success || attempt.ResponseBody != "n-1" {
+		t.Errorf("expected a successful attempt and sent status, got %s and %+v", status, attempt)
+	}
+}
+
+func TestExecute_Provider(t *testing.T) {
+	notifRepo := &mockNotificationRepo{
+		getByIDFn: func(ctx context.Context, id string) (*notification.Notification, error) {
+			return &notification.Notification{
+				ID: id, Recipient: "ops#alerts", Channel: notification.ChannelChat, Content: "*Deploy* finished",
+				Priority: notification.PriorityNormal, Status: notification.StatusQueued,
+			}, nil
+		},
+	}
+	var status notification.Status
+	notifRepo.updateStatusFn = func(ctx context.Context, id string, s notification.Status, sentAt *time.Time, reason *string) error {
+		status = s
+		return nil
+	}
+	attemptRepo := &mockDeliveryAttemptRepo{}
+	deliveryClient := &mockDeliveryClient{
+		deliverFn: func(ctx context.Context, req *port.DeliveryRequest) (*port.DeliveryResponse, int, error) {
+			t.Error("expected no default provider call for a chat notification")
+			return nil, 0, errors.New("unexpected")
+		},
+	}
+	var sent *port.DeliveryRequest
+	chatClient := &mockDeliveryClient{
+		deliverFn: func(ctx context.Context, req *port.DeliveryRequest) (*port.DeliveryResponse, int, error) {
+			sent = req
+			return &port.DeliveryResponse{Status: "accepted"}, 200, nil
+		},
+	}
+
+	uc := NewUseCase(notifRepo, attemptRepo, &mockRateLimiter{}, deliveryClient, &mockLogger{}).
+		WithProvider(notification.ProviderChat, chatClient)
+	if err := uc.Execute(context.Background(), &Command{NotificationID: "n-1"}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if sent == nil || sent.To != "ops#alerts" || sent.Channel != "chat" {
+		t.Errorf("expected the chat provider to get the notification, got %+v", sent)
+	}
+	if status != notification.StatusSent {
+		t.Errorf("expected sent status, got %s", status)
+	}
+}
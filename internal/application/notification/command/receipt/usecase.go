@@ -0,0 +1,103 @@
+package receipt
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/semih-yildiz/notification-service/internal/application/notification/port"
+	"github.com/semih-yildiz/notification-service/internal/domain/notification"
+)
+
+// Command is a delivery receipt posted by a provider.
+type Command struct {
+	MessageID  string
+	Status     string // "delivered" or "undelivered"
+	Reason     string
+	OccurredAt time.Time // zero uses the time the receipt is applied
+}
+
+// Result is the notification the receipt belongs to and its status afterwards.
+type Result struct {
+	NotificationID string
+	Status         notification.Status
+	// Applied is false for a duplicate receipt or one for a notification that had
+	// already left sent.
+	Applied bool
+}
+
+// UseCase moves sent notifications to delivered or undelivered on provider receipts.
+type UseCase struct {
+	receipts port.ReceiptRepository
+	log      port.Logger
+	events   port.StatusBroadcaster // optional; nil disables live status events
+}
+
+// NewUseCase returns a new receipt use case.
+func NewUseCase(receipts port.ReceiptRepository, log port.Logger) *UseCase {
+	return &UseCase{receipts: receipts, log: log}
+}
+
+// WithStatusEvents broadcasts every applied receipt to live subscribers.
+func (u *UseCase) WithStatusEvents(events port.StatusBroadcaster) *UseCase {
+	u.events = events
+	return u
+}
+
+// Execute applies the receipt. It returns notification.ErrNotFound for an unknown
+// message ID and notification.ErrReceiptTooEarly while the notification has not
+// been marked sent yet, so the provider retries it.
+func (u *UseCase) Execute(ctx context.Context, cmd *Command) (*Result, error) {
+	r := &notification.DeliveryReceipt{
+		MessageID:  cmd.MessageID,
+		Status:     notification.Status(cmd.Status),
+		Reason:     cmd.Reason,
+		OccurredAt: cmd.OccurredAt,
+	}
+	if err := r.Validate(); err != nil {
+		return nil, err
+	}
+	if r.OccurredAt.IsZero() {
+		r.OccurredAt = time.Now()
+	}
+
+	n, applied, err := u.receipts.ApplyReceipt(ctx, r)
+	if err != nil {
+		if !errors.Is(err, notification.ErrNotFound) {
+			u.log.Error(ctx, "failed to apply delivery receipt", port.F("error", err), port.F("message_id", r.MessageID))
+		}
+		return nil, err
+	}
+	if !applied {
+		if !n.Status.Terminal() {
+			return nil, notification.ErrReceiptTooEarly
+		}
+		u.log.Info(ctx, "delivery receipt ignored", port.F("notification_id", n.ID), port.F("status", n.Status), port.F("receipt", r.Status))
+		return &Result{NotificationID: n.ID, Status: n.Status}, nil
+	}
+
+	u.log.Info(ctx, "delivery receipt applied", port.F("notification_id", n.ID), port.F("status", r.Status), port.F("message_id", r.MessageID))
+	var reason *string
+	if r.Status == notification.StatusUndelivered && r.Reason != "" {
+		reason = &r.Reason
+	}
+	u.broadcast(ctx, n, r.Status, reason, r.OccurredAt)
+	return &Result{NotificationID: n.ID, Status: r.Status, Applied: true}, nil
+}
+
+// broadcast publishes the receipt; failures only cost live viewers an update.
+func (u *UseCase) broadcast(ctx context.Context, n *notification.Notification, status notification.Status, reason *string, at time.Time) {
+	if u.events == nil {
+		return
+	}
+	evt := &port.StatusEvent{
+		NotificationID: n.ID,
+		BatchID:        n.BatchID,
+		Status:         status,
+		FailureReason:  reason,
+		OccurredAt:     at,
+	}
+	if err := u.events.Broadcast(ctx, evt); err != nil {
+		u.log.Warn(ctx, "failed to broadcast status event", port.F("error", err), port.F("notification_id", n.ID))
+	}
+}
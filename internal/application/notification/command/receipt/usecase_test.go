@@ -0,0 +1,134 @@
+package receipt
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/semih-yildiz/notification-service/internal/application/notification/port"
+	"github.com/semih-yildiz/notification-service/internal/domain/notification"
+)
+
+type mockReceiptRepo struct {
+	applyFn func(ctx context.Context, r *notification.DeliveryReceipt) (*notification.Notification, bool, error)
+	applied []*notification.DeliveryReceipt
+}
+
+func (m *mockReceiptRepo) ApplyReceipt(ctx context.Context, r *notification.DeliveryReceipt) (*notification.Notification, bool, error) {
+	m.applied = append(m.applied, r)
+	return m.applyFn(ctx, r)
+}
+
+type mockBroadcaster struct {
+	events []*port.StatusEvent
+}
+
+func (m *mockBroadcaster) Broadcast(ctx context.Context, evt *port.StatusEvent) error {
+	m.events = append(m.events, evt)
+	return nil
+}
+
+type mockLogger struct{}
+
+func (m *mockLogger) Info(ctx context.Context, msg string, fields ...port.Field)  {}
+func (m *mockLogger) Warn(ctx context.Context, msg string, fields ...port.Field)  {}
+func (m *mockLogger) Error(ctx context.Context, msg string, fields ...port.Field) {}
+
+func TestExecute_AppliesReceipt(t *testing.T) {
+	batchID := "b-1"
+	repo := &mockReceiptRepo{applyFn: func(ctx context.Context, r *notification.DeliveryReceipt) (*notification.Notification, bool, error) {
+		return &notification.Notification{ID: "n-1", BatchID: &batchID, Status: notification.StatusSent}, true, nil
+	}}
+	events := &mockBroadcaster{}
+	at := time.Date(2026, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	res, err := NewUseCase(repo, &mockLogger{}).WithStatusEvents(events).Execute(context.Background(), &Command{
+		MessageID: " msg-1 ", Status: "undelivered", Reason: "absent subscriber", OccurredAt: at,
+	})
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !res.Applied || res.NotificationID != "n-1" || res.Status != notification.StatusUndelivered {
+		t.Errorf("unexpected result %+v", res)
+	}
+	if len(repo.applied) != 1 || repo.applied[0].MessageID != "msg-1" {
+		t.Errorf("expected trimmed message id, got %+v", repo.applied)
+	}
+	if len(events.events) != 1 {
+		t.Fatalf("expected one status event, got %d", len(events.events))
+	}
+	evt := events.events[0]
+	if evt.Status != notification.StatusUndelivered || evt.FailureReason == nil || *evt.FailureReason != "absent subscriber" || !evt.OccurredAt.Equal(at) || evt.BatchID != &batchID {
+		t.Errorf("unexpected status event %+v", evt)
+	}
+}
+
+func TestExecute_Duplicate(t *testing.T) {
+	repo := &mockReceiptRepo{applyFn: func(ctx context.Context, r *notification.DeliveryReceipt) (*notification.Notification, bool, error) {
+		return &notification.Notification{ID: "n-1", Status: notification.StatusDelivered}, false, nil
+	}}
+	events := &mockBroadcaster{}
+
+	res, err := NewUseCase(repo, &mockLogger{}).WithStatusEvents(events).Execute(context.Background(), &Command{MessageID: "msg-1", Status: "delivered"})
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if res.Applied || res.Status != notification.StatusDelivered {
+		t.Errorf("expected unchanged delivered notification, got %+v", res)
+	}
+	if len(events.events) != 0 {
+		t.Errorf("expected no status event, got %d", len(events.events))
+	}
+}
+
+func TestExecute_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		cmd     Command
+		applyFn func(ctx context.Context, r *notification.DeliveryReceipt) (*notification.Notification, bool, error)
+		wantErr error
+	}{
+		{
+			name:    "Invalid status",
+			cmd:     Command{MessageID: "msg-1", Status: "sent"},
+			wantErr: notification.ErrInvalidReceipt,
+		},
+		{
+			name:    "Missing message id",
+			cmd:     Command{Status: "delivered"},
+			wantErr: notification.ErrInvalidReceipt,
+		},
+		{
+			name: "Unknown message id",
+			cmd:  Command{MessageID: "msg-1", Status: "delivered"},
+			applyFn: func(ctx context.Context, r *notification.DeliveryReceipt) (*notification.Notification, bool, error) {
+				return nil, false, notification.ErrNotFound
+			},
+			wantErr: notification.ErrNotFound,
+		},
+		{
+			name: "Not sent yet",
+			cmd:  Command{MessageID: "msg-1", Status: "delivered"},
+			applyFn: func(ctx context.Context, r *notification.DeliveryReceipt) (*notification.Notification, bool, error) {
+				return &notification.Notification{ID: "n-1", Status: notification.StatusQueued}, false, nil
+			},
+			wantErr: notification.ErrReceiptTooEarly,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &mockReceiptRepo{applyFn: tt.applyFn}
+			_, err := NewUseCase(repo, &mockLogger{}).Execute(context.Background(), &tt.cmd)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("expected %v, got %v", tt.wantErr, err)
+			}
+			if tt.applyFn == nil && len(repo.applied) != 0 {
+				t.Errorf("expected invalid receipt not to reach the repository")
+			}
+		})
+	}
+}
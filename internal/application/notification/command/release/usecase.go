@@ -0,0 +1,113 @@
+package release
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/semih-yildiz/notification-service/internal/application/notification/port"
+	"github.com/semih-yildiz/notification-service/internal/domain/notification"
+)
+
+// batchSize is how many deferred notifications are released per query.
+const batchSize = 500
+
+// retryDelay is how long a notification that could not be requeued stays parked.
+const retryDelay = time.Minute
+
+// UseCase requeues deferred notifications once their delivery window opens.
+type UseCase struct {
+	deferrals port.DeferralRepository
+	pub       port.EventPublisher
+	log       port.Logger
+	events    port.StatusBroadcaster // optional; nil disables live status events
+}
+
+// NewUseCase returns a new release use case.
+func NewUseCase(deferrals port.DeferralRepository, pub port.EventPublisher, log port.Logger) *UseCase {
+	return &UseCase{deferrals: deferrals, pub: pub, log: log}
+}
+
+// WithStatusEvents broadcasts every release to live subscribers.
+func (u *UseCase) WithStatusEvents(events port.StatusBroadcaster) *UseCase {
+	u.events = events
+	return u
+}
+
+// Execute requeues every deferred notification that is due and returns how many
+// were requeued. Notifications that cannot be published are deferred again for
+// retryDelay.
+func (u *UseCase) Execute(ctx context.Context) (int, error) {
+	requeued := 0
+	for {
+		now := time.Now()
+		due, err := u.deferrals.ReleaseDue(ctx, now, batchSize)
+		if err != nil {
+			u.log.Error(ctx, "failed to release deferred notifications", port.F("error", err))
+			return requeued, err
+		}
+		for _, n := range due {
+			if err := u.pub.Publish(ctx, toEvent(n)); err != nil {
+				u.log.Error(ctx, "failed to requeue deferred notification", port.F("error", err), port.F("notification_id", n.ID))
+				detail := fmt.Sprintf("requeue failed: %v", err)
+				if err := u.deferrals.Defer(ctx, n.ID, now.Add(retryDelay), detail); err != nil {
+					u.log.Error(ctx, "failed to park notification again", port.F("error", err), port.F("notification_id", n.ID))
+				}
+				continue
+			}
+			requeued++
+			u.broadcast(ctx, n)
+		}
+		if len(due) < batchSize {
+			break
+		}
+	}
+	if requeued > 0 {
+		u.log.Info(ctx, "deferred notifications requeued", port.F("count", requeued))
+	}
+	return requeued, nil
+}
+
+// Run calls Execute every interval until ctx is done.
+func (u *UseCase) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			_, _ = u.Execute(ctx)
+		}
+	}
+}
+
+func toEvent(n *notification.Notification) *port.NotificationEvent {
+	return &port.NotificationEvent{
+		NotificationID: n.ID,
+		TenantID:       n.TenantID,
+		BatchID:        n.BatchID,
+		Recipient:      n.Recipient,
+		Channel:        n.Channel,
+		Content:        n.Content,
+		Priority:       n.Priority,
+		IdempotencyKey: n.IdempotencyKey,
+		CreatedAt:      n.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}
+}
+
+// broadcast publishes the release; failures only cost live viewers an update.
+func (u *UseCase) broadcast(ctx context.Context, n *notification.Notification) {
+	if u.events == nil {
+		return
+	}
+	evt := &port.StatusEvent{
+		NotificationID: n.ID,
+		BatchID:        n.BatchID,
+		Status:         notification.StatusQueued,
+		OccurredAt:     time.Now(),
+	}
+	if err := u.events.Broadcast(ctx, evt); err != nil {
+		u.log.Warn(ctx, "failed to broadcast status event", port.F("error", err), port.F("notification_id", n.ID))
+	}
+}
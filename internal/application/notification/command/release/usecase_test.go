@@ -0,0 +1,85 @@
+package release
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/semih-yildiz/notification-service/internal/application/notification/port"
+	"github.com/semih-yildiz/notification-service/internal/domain/notification"
+)
+
+type mockDeferralRepo struct {
+	due      [][]*notification.Notification // returned by successive ReleaseDue calls
+	deferred map[string]time.Time
+}
+
+func (m *mockDeferralRepo) Defer(ctx context.Context, id string, until time.Time, detail string) error {
+	if m.deferred == nil {
+		m.deferred = make(map[string]time.Time)
+	}
+	m.deferred[id] = until
+	return nil
+}
+
+func (m *mockDeferralRepo) ReleaseDue(ctx context.Context, now time.Time, limit int) ([]*notification.Notification, error) {
+	if len(m.due) == 0 {
+		return nil, nil
+	}
+	next := m.due[0]
+	m.due = m.due[1:]
+	return next, nil
+}
+
+type mockPublisher struct {
+	failFor   string
+	published []*port.NotificationEvent
+}
+
+func (m *mockPublisher) Publish(ctx context.Context, evt *port.NotificationEvent) error {
+	if evt.NotificationID == m.failFor {
+		return errors.New("broker down")
+	}
+	m.published = append(m.published, evt)
+	return nil
+}
+
+func (m *mockPublisher) PublishBatch(ctx context.Context, events []*port.NotificationEvent) error {
+	return errors.New("not implemented")
+}
+
+type mockLogger struct{}
+
+func (m *mockLogger) Info(ctx context.Context, msg string, fields ...port.Field)  {}
+func (m *mockLogger) Warn(ctx context.Context, msg string, fields ...port.Field)  {}
+func (m *mockLogger) Error(ctx context.Context, msg string, fields ...port.Field) {}
+
+func TestExecute_RequeuesDue(t *testing.T) {
+	batchID := "b-1"
+	repo := &mockDeferralRepo{due: [][]*notification.Notification{{
+		{ID: "n-1", TenantID: "acme", BatchID: &batchID, Channel: notification.ChannelPush, Priority: notification.PriorityLow},
+		{ID: "n-2", TenantID: "acme", Channel: notification.ChannelEmail, Priority: notification.PriorityNormal},
+	}}}
+	pub := &mockPublisher{failFor: "n-2"}
+
+	count, err := NewUseCase(repo, pub, &mockLogger{}).Execute(context.Background())
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if count != 1 || len(pub.published) != 1 || pub.published[0].NotificationID != "n-1" || pub.published[0].TenantID != "acme" {
+		t.Errorf("expected n-1 requeued, got %d %+v", count, pub.published)
+	}
+	if until, ok := repo.deferred["n-2"]; !ok || until.Before(time.Now()) {
+		t.Errorf("expected n-2 parked again, got %v", repo.deferred)
+	}
+}
+
+func TestExecute_Empty(t *testing.T) {
+	count, err := NewUseCase(&mockDeferralRepo{}, &mockPublisher{}, &mockLogger{}).Execute(context.Background())
+
+	if err != nil || count != 0 {
+		t.Errorf("expected nothing released, got %d, %v", count, err)
+	}
+}
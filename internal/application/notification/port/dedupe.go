@@ -0,0 +1,15 @@
+package port
+
+import (
+	"context"
+	"time"
+)
+
+// DedupeStore tracks recently sent content per recipient for the dedupe window.
+type DedupeStore interface {
+	// Claim records notificationID under hash for window if the hash is unclaimed.
+	// Otherwise it returns the ID of the notification that claimed it.
+	Claim(ctx context.Context, hash, notificationID string, window time.Duration) (claimed bool, existingID string, err error)
+	// Release drops a claim made by a request that did not complete.
+	Release(ctx context.Context, hash string) error
+}
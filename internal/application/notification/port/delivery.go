@@ -1,6 +1,14 @@
 package port
 
-import "context"
+import (
+	"context"
+	"errors"
+)
+
+// ErrDeliveryRejected marks a delivery failure retrying cannot fix, such as an
+// unknown recipient or a request the provider refused. Clients wrap it; the
+// notification is then failed without further attempts.
+var ErrDeliveryRejected = errors.New("delivery rejected")
 
 type DeliveryRequest struct {
 	To      string `json:"to"`
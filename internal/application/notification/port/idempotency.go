@@ -2,7 +2,20 @@ package port
 
 import "context"
 
+// IdempotencyRecord is what an idempotency key resolves to.
+type IdempotencyRecord struct {
+	Fingerprint string `json:"fingerprint"`           // hash of the request payload
+	ResourceID  string `json:"resource_id,omitempty"` // empty while the request is in flight
+}
+
 type IdempotencyStore interface {
 	SetIfNotExists(ctx context.Context, key string, ttlSeconds int) (set bool, err error)
 	Exists(ctx context.Context, key string) (bool, error)
+	// Reserve stores rec under key if the key is unused. Otherwise it returns the record
+	// already stored, or nil when the key holds no record.
+	Reserve(ctx context.Context, key string, rec *IdempotencyRecord, ttlSeconds int) (reserved bool, existing *IdempotencyRecord, err error)
+	// Complete records the created resource against a reserved key.
+	Complete(ctx context.Context, key string, rec *IdempotencyRecord, ttlSeconds int) error
+	// Delete releases a key reserved by a request that did not complete.
+	Delete(ctx context.Context, key string) error
 }
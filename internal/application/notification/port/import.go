@@ -0,0 +1,36 @@
+package port
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/semih-yildiz/notification-service/internal/domain/notification"
+)
+
+// ImportJobRepository stores bulk import jobs and their rejected rows.
+type ImportJobRepository interface {
+	Create(ctx context.Context, job *notification.ImportJob) error
+	GetByID(ctx context.Context, id string) (*notification.ImportJob, error)
+	// Update saves the job's status, counters and batches. It returns
+	// notification.ErrImportNotFound once the job has finished, e.g. because it was
+	// failed when its lease expired.
+	Update(ctx context.Context, job *notification.ImportJob) error
+	AddRowErrors(ctx context.Context, jobID string, errs []notification.RowError) error
+	// ListRowErrors returns the errors of up to limit rows after afterRow, by row.
+	ListRowErrors(ctx context.Context, jobID string, afterRow, limit int) ([]notification.RowError, error)
+	// RenewLeases extends the leases of the unfinished jobs in ids until until.
+	RenewLeases(ctx context.Context, ids []string, until time.Time) error
+	// FailExpired fails unfinished jobs whose lease expired before now, e.g. because
+	// the process holding them stopped. It returns how many it failed.
+	FailExpired(ctx context.Context, now time.Time, reason string) (int, error)
+}
+
+// ImportStorage keeps uploaded files until their job is processed.
+type ImportStorage interface {
+	// Save stores r under jobID. It returns notification.ErrImportTooLarge when r
+	// holds more than maxBytes.
+	Save(ctx context.Context, jobID string, r io.Reader, maxBytes int64) error
+	Open(ctx context.Context, jobID string) (io.ReadCloser, error)
+	Delete(ctx context.Context, jobID string) error
+}
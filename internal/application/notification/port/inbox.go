@@ -0,0 +1,15 @@
+package port
+
+import (
+	"context"
+
+	"github.com/semih-yildiz/notification-service/internal/domain/notification"
+)
+
+// InboxDelivery delivers in-app notifications by storing them in the recipient's
+// inbox instead of calling a provider.
+type InboxDelivery interface {
+	// Deliver returns the ID of the inbox message. Delivering a notification again
+	// does not add a second message.
+	Deliver(ctx context.Context, n *notification.Notification) (string, error)
+}
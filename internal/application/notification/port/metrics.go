@@ -6,12 +6,19 @@ import "context"
 type MetricsProvider interface {
 	GetQueueDepth(ctx context.Context, queueName string) (int, error)
 	GetNotificationStats(ctx context.Context) (*NotificationStats, error)
+	// GetTenantStats returns notification stats keyed by tenant ID.
+	GetTenantStats(ctx context.Context) (map[string]*NotificationStats, error)
 }
 
 type NotificationStats struct {
-	Pending int64
-	Queued  int64
-	Sent    int64
-	Failed  int64
-	Total   int64
+	Pending     int64
+	Queued      int64
+	Deferred    int64
+	Buffered    int64
+	Digested    int64
+	Sent        int64
+	Delivered   int64
+	Undelivered int64
+	Failed      int64
+	Total       int64
 }
@@ -9,6 +9,7 @@ import (
 // NotificationEvent is the event payload published to the broker.
 type NotificationEvent struct {
 	NotificationID string
+	TenantID       string
 	BatchID        *string
 	Recipient      string
 	Channel        notification.Channel
@@ -23,3 +24,28 @@ type EventPublisher interface {
 	Publish(ctx context.Context, evt *NotificationEvent) error
 	PublishBatch(ctx context.Context, events []*NotificationEvent) error
 }
+
+// FanoutEvent asks a worker to expand an audience batch into one notification per
+// member on Channels.
+type FanoutEvent struct {
+	BatchID    string
+	AudienceID string
+	TenantID   string
+	ClientID   *string
+	Channels   []notification.Channel
+	Content    string
+	Variants   notification.Variants
+	Locale     string
+	Priority   notification.Priority
+	Window     *notification.DeliveryWindow
+	Track      bool
+	// AfterChannel and AfterRecipient continue the expansion after that member;
+	// empty starts from the first one.
+	AfterChannel   notification.Channel
+	AfterRecipient string
+}
+
+// FanoutPublisher publishes audience fan-out events.
+type FanoutPublisher interface {
+	PublishFanout(ctx context.Context, evt *FanoutEvent) error
+}
@@ -0,0 +1,16 @@
+package port
+
+import (
+	"context"
+
+	"github.com/semih-yildiz/notification-service/internal/domain/notification"
+)
+
+// QuotaLimiter enforces each tenant's daily per-channel send quota.
+type QuotaLimiter interface {
+	// Consume takes n sends from today's quota. It takes nothing and returns false
+	// if fewer than n remain.
+	Consume(ctx context.Context, tenantID string, ch notification.Channel, n int) (allowed bool, err error)
+	// Refund returns sends consumed by a request that did not complete.
+	Refund(ctx context.Context, tenantID string, ch notification.Channel, n int) error
+}
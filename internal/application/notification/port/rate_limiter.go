@@ -6,7 +6,7 @@ import (
 	"github.com/semih-yildiz/notification-service/internal/domain/notification"
 )
 
-// RateLimiter limits send rate per channel.
+// RateLimiter limits send rate per channel, overall and per tenant.
 type RateLimiter interface {
-	Allow(ctx context.Context, channel notification.Channel) (allowed bool, err error)
+	Allow(ctx context.Context, tenantID string, channel notification.Channel) (allowed bool, err error)
 }
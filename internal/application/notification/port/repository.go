@@ -9,36 +9,170 @@ import (
 
 type NotificationRepository interface {
 	Create(ctx context.Context, n *notification.Notification) error
-	CreateBatch(ctx context.Context, notifications []*notification.Notification) error
+	// CreateBatch stores b, unless it is nil, and notifications in one transaction.
+	CreateBatch(ctx context.Context, b *notification.Batch, notifications []*notification.Notification) error
 	GetByID(ctx context.Context, id string) (*notification.Notification, error)
 	GetByBatchID(ctx context.Context, batchID string) ([]*notification.Notification, error)
 	UpdateStatus(ctx context.Context, id string, status notification.Status, sentAt *time.Time, failureReason *string) error
+	// UpdateStatuses moves the notifications with ids to status in one transaction,
+	// adjusting the counters of each of their batches once.
+	UpdateStatuses(ctx context.Context, ids []string, status notification.Status) error
 	List(ctx context.Context, filter ListFilter) (*ListResult, error)
 	CancelPending(ctx context.Context, id string) error
 	CancelPendingByBatchID(ctx context.Context, batchID string) (int, error)
 	ExistsByIdempotencyKey(ctx context.Context, key string) (bool, error)
+	GetByIdempotencyKey(ctx context.Context, key string) (*notification.Notification, error)
+	// IDsByIdempotencyKey returns the IDs of the notifications created with any of
+	// keys, by key.
+	IDsByIdempotencyKey(ctx context.Context, keys []string) (map[string]string, error)
 }
 
 type BatchRepository interface {
 	Create(ctx context.Context, b *notification.Batch) error
 	GetByID(ctx context.Context, id string) (*notification.Batch, error)
+	GetByIdempotencyKey(ctx context.Context, key string) (*notification.Batch, error)
+	// FinishExpansion marks an audience batch as fully expanded, completing it if
+	// every notification is already terminal.
+	FinishExpansion(ctx context.Context, id string) error
 }
 
 type DeliveryAttemptRepository interface {
 	Create(ctx context.Context, a *notification.DeliveryAttempt) error
 }
 
+// ReceiptRepository applies provider delivery receipts.
+type ReceiptRepository interface {
+	// ApplyReceipt finds the notification of any tenant whose successful delivery
+	// attempt returned r.MessageID and, if it is sent, moves it to r.Status and
+	// records the receipt in its history. It returns the notification and whether
+	// it changed, or notification.ErrNotFound when no attempt has the message ID.
+	ApplyReceipt(ctx context.Context, r *notification.DeliveryReceipt) (*notification.Notification, bool, error)
+}
+
+// DeferralRepository parks notifications outside their delivery window and releases
+// them once it opens. Both record the change in the notification's history.
+type DeferralRepository interface {
+	// Defer moves a queued notification to deferred until the given time. It is a
+	// no-op if the notification was cancelled meanwhile.
+	Defer(ctx context.Context, id string, until time.Time, detail string) error
+	// ReleaseDue requeues up to limit deferred notifications of any tenant whose
+	// time has come and returns them.
+	ReleaseDue(ctx context.Context, now time.Time, limit int) ([]*notification.Notification, error)
+}
+
+// FallbackRepository stores fallback chains. Every change to a chain is recorded
+// in the parent's history.
+type FallbackRepository interface {
+	// CreateFallback stores a queued parent with its steps and the pending child
+	// for its first step.
+	CreateFallback(ctx context.Context, parent, first *notification.Notification) error
+	// DueFallbacks returns up to limit running chains of any tenant whose current
+	// step has been delivered, has failed or timed out, or was sent without a
+	// deadline to wait for its receipt.
+	DueFallbacks(ctx context.Context, now time.Time, limit int) ([]*DueFallback, error)
+	// AdvanceFallback moves the parent from step from to the next one: the current
+	// child is cancelled if it has not finished, next is stored as the new child and
+	// deadline as the step's timeout. It returns false, changing nothing, when the
+	// parent is no longer running step from.
+	AdvanceFallback(ctx context.Context, parentID string, from int, next *notification.Notification, deadline *time.Time, events []*notification.HistoryEntry) (bool, error)
+	// FinishFallback ends the chain on step from: the current child is cancelled if
+	// it has not finished and the parent takes the status, channel, recipient and
+	// sent time or failure reason of result. It returns false like AdvanceFallback.
+	FinishFallback(ctx context.Context, parentID string, from int, result *notification.Notification, events []*notification.HistoryEntry) (bool, error)
+}
+
+// DueFallback is a running fallback chain and the child of its current step.
+type DueFallback struct {
+	Parent *notification.Notification
+	Child  *notification.Notification
+}
+
+// DigestRepository holds low priority notifications back and replaces them with one
+// digest per recipient and channel. Both changes are recorded in the history of the
+// buffered notifications.
+type DigestRepository interface {
+	// Buffer stores a buffered notification. When the recipient already has
+	// notifications buffered on the channel, n.DigestDue is moved to their due time.
+	Buffer(ctx context.Context, n *notification.Notification) error
+	// DueDigests returns up to limit buffers of any tenant whose oldest
+	// notification is due.
+	DueDigests(ctx context.Context, now time.Time, limit int) ([]*DigestBuffer, error)
+	// CreateDigest stores the pending digest and marks the buffered notifications
+	// with bufferedIDs digested and linked to it. It returns false, changing
+	// nothing, when any of them is no longer buffered.
+	CreateDigest(ctx context.Context, digest *notification.Notification, bufferedIDs []string) (bool, error)
+}
+
+// DigestBuffer is the buffered notifications of a recipient on a channel, oldest first.
+type DigestBuffer struct {
+	TenantID      string
+	Channel       notification.Channel
+	Recipient     string
+	Notifications []*notification.Notification
+}
+
+// HistoryRepository reads the history of a notification, oldest first.
+type HistoryRepository interface {
+	ListHistory(ctx context.Context, notificationID string) ([]*notification.HistoryEntry, error)
+}
+
 type ListFilter struct {
-	Status   *notification.Status
-	Channel  *notification.Channel
-	FromTime *time.Time
-	ToTime   *time.Time
-	BatchID  *string
-	Limit    int
-	Offset   int
+	Statuses        []notification.Status // any of
+	Channel         *notification.Channel
+	Priority        *notification.Priority
+	Recipient       *string // exact match
+	RecipientPrefix *string
+	FromTime        *time.Time // created_at range
+	ToTime          *time.Time
+	SentFrom        *time.Time // sent_at range
+	SentTo          *time.Time
+	BatchID         *string
+	ClientID        *string
+	IdempotencyKey  *string
+	FailureReason   *string // case-insensitive substring
+	Sort            ListSort
+	Limit           int
+	Offset          int
+	// After continues a listing ordered by created_at after this position; it
+	// replaces Offset.
+	After *Cursor
+	// WithTotal counts every match, which is expensive on large tables.
+	WithTotal bool
+}
+
+// SortField is a column listings can be ordered by. Ties are broken by id.
+type SortField string
+
+const (
+	SortByCreatedAt SortField = "created_at"
+	SortByUpdatedAt SortField = "updated_at"
+)
+
+func (f SortField) Valid() bool {
+	return f == SortByCreatedAt || f == SortByUpdatedAt
+}
+
+// ListSort orders a listing. The zero value is newest created first.
+type ListSort struct {
+	Field     SortField
+	Ascending bool
+}
+
+// Keyset reports whether the listing can be paged with a Cursor. Only created_at
+// qualifies: updated_at changes while a client pages, moving rows between pages.
+func (s ListSort) Keyset() bool {
+	return !s.Field.Valid() || s.Field == SortByCreatedAt
+}
+
+// Cursor is a position in a listing ordered by created_at: the created_at and id
+// of the last row of the previous page.
+type Cursor struct {
+	At time.Time
+	ID string
 }
 
 type ListResult struct {
 	Notifications []*notification.Notification
-	Total         int
+	Total         *int    // set only when ListFilter.WithTotal
+	NextCursor    *Cursor // nil on the last page, and for sorts without a keyset
 }
@@ -0,0 +1,33 @@
+package port
+
+import (
+	"context"
+	"time"
+
+	"github.com/semih-yildiz/notification-service/internal/domain/notification"
+)
+
+// StatusEvent reports a status change to live subscribers. An event without a
+// NotificationID covers every notification of BatchID that moved to Status, e.g.
+// when a batch is cancelled.
+type StatusEvent struct {
+	NotificationID string
+	BatchID        *string
+	Status         notification.Status
+	FailureReason  *string
+	OccurredAt     time.Time
+}
+
+// StatusBroadcaster fans status changes out to every API replica. Broadcasting is
+// best effort: a lost event only delays a live view until its next refresh.
+type StatusBroadcaster interface {
+	Broadcast(ctx context.Context, evt *StatusEvent) error
+}
+
+// StatusSubscriber delivers the status changes of one notification or one batch.
+// The returned channel is closed once ctx is done. Events broadcast after the call
+// returns are not missed.
+type StatusSubscriber interface {
+	SubscribeNotification(ctx context.Context, id string) (<-chan *StatusEvent, error)
+	SubscribeBatch(ctx context.Context, batchID string) (<-chan *StatusEvent, error)
+}
@@ -0,0 +1,31 @@
+package port
+
+import (
+	"context"
+
+	"github.com/semih-yildiz/notification-service/internal/domain/notification"
+)
+
+// TrackingLinks builds the signed open and click URLs put into tracked emails and
+// verifies them when they come back, so the redirect cannot be used for other URLs.
+type TrackingLinks interface {
+	OpenURL(notificationID string) string
+	ClickURL(notificationID, target string) string
+	VerifyOpen(notificationID, sig string) bool
+	VerifyClick(notificationID, target, sig string) bool
+}
+
+// EngagementRepository records opens and clicks of tracked emails and sums them up.
+type EngagementRepository interface {
+	// RecordEngagement sets the first opened or clicked time of the notification of
+	// any tenant with id, a click also counting as an open, and records every click
+	// and the first open in its history. It returns notification.ErrNotFound for an
+	// unknown notification.
+	RecordEngagement(ctx context.Context, id string, e *notification.Engagement) error
+	// BatchEngagement counts the batch's tracked emails that were sent and how many
+	// of them were opened and clicked.
+	BatchEngagement(ctx context.Context, batchID string) (*notification.EngagementStats, error)
+	// DigestEngagement counts the same for digest emails rendered from the template,
+	// the only notifications that carry a template.
+	DigestEngagement(ctx context.Context, templateID string) (*notification.EngagementStats, error)
+}
@@ -10,14 +10,72 @@ import (
 type UseCase struct {
 	notifRepo port.NotificationRepository
 	batchRepo port.BatchRepository
+	history   port.HistoryRepository    // optional; nil reports an empty history
+	engaged   port.EngagementRepository // optional; nil reports no tracked emails
 }
 
 func NewUseCase(notifRepo port.NotificationRepository, batchRepo port.BatchRepository) *UseCase {
 	return &UseCase{notifRepo: notifRepo, batchRepo: batchRepo}
 }
 
+// WithHistory enables History.
+func (u *UseCase) WithHistory(history port.HistoryRepository) *UseCase {
+	u.history = history
+	return u
+}
+
 func (u *UseCase) Notification(ctx context.Context, q *ByID) (*notification.Notification, error) {
-	return u.notifRepo.GetByID(ctx, q.ID)
+	n, err := u.notifRepo.GetByID(ctx, q.ID)
+	if err != nil {
+		return nil, err
+	}
+	n.Recipient = notification.RedactRecipient(n.Channel, n.Recipient)
+	return n, nil
+}
+
+// History returns the notification's history events, oldest first. The notification
+// is loaded first so other tenants' notifications are not found.
+func (u *UseCase) History(ctx context.Context, q *ByID) ([]*notification.HistoryEntry, error) {
+	if _, err := u.notifRepo.GetByID(ctx, q.ID); err != nil {
+		return nil, err
+	}
+	if u.history == nil {
+		return []*notification.HistoryEntry{}, nil
+	}
+	return u.history.ListHistory(ctx, q.ID)
+}
+
+// WithEngagement enables engagement stats.
+func (u *UseCase) WithEngagement(engaged port.EngagementRepository) *UseCase {
+	u.engaged = engaged
+	return u
+}
+
+// BatchEngagement returns the opens and clicks of the batch's tracked emails. The
+// batch is loaded first so other tenants' batches are not found.
+func (u *UseCase) BatchEngagement(ctx context.Context, q *BatchByID) (*notification.EngagementStats, error) {
+	if _, err := u.batchRepo.GetByID(ctx, q.BatchID); err != nil {
+		return nil, err
+	}
+	if u.engaged == nil {
+		return &notification.EngagementStats{}, nil
+	}
+	return u.engaged.BatchEngagement(ctx, q.BatchID)
+}
+
+// DigestEngagement returns the opens and clicks of tracked digest emails rendered
+// from the template; templates are only rendered by digests. The caller checks the
+// template is visible.
+func (u *UseCase) DigestEngagement(ctx context.Context, q *ByID) (*notification.EngagementStats, error) {
+	if u.engaged == nil {
+		return &notification.EngagementStats{}, nil
+	}
+	return u.engaged.DigestEngagement(ctx, q.ID)
+}
+
+// BatchSummary returns the batch with its aggregate counts, without loading notifications.
+func (u *UseCase) BatchSummary(ctx context.Context, q *BatchByID) (*notification.Batch, error) {
+	return u.batchRepo.GetByID(ctx, q.BatchID)
 }
 
 func (u *UseCase) Batch(ctx context.Context, q *BatchByID) (*notification.Batch, []*notification.Notification, error) {
@@ -30,6 +88,9 @@ func (u *UseCase) Batch(ctx context.Context, q *BatchByID) (*notification.Batch,
 	if err != nil {
 		return batch, nil, err
 	}
+	for _, n := range list {
+		n.Recipient = notification.RedactRecipient(n.Channel, n.Recipient)
+	}
 
 	return batch, list, nil
 }
@@ -33,7 +33,7 @@ func (m *mockNotificationRepo) Create(ctx context.Context, n *notification.Notif
 	return errors.New("not implemented")
 }
 
-func (m *mockNotificationRepo) CreateBatch(ctx context.Context, notifications []*notification.Notification) error {
+func (m *mockNotificationRepo) CreateBatch(ctx context.Context, b *notification.Batch, notifications []*notification.Notification) error {
 	return errors.New("not implemented")
 }
 
@@ -41,6 +41,10 @@ func (m *mockNotificationRepo) UpdateStatus(ctx context.Context, id string, stat
 	return errors.New("not implemented")
 }
 
+func (m *mockNotificationRepo) UpdateStatuses(ctx context.Context, ids []string, status notification.Status) error {
+	return errors.New("not implemented")
+}
+
 func (m *mockNotificationRepo) List(ctx context.Context, filter port.ListFilter) (*port.ListResult, error) {
 	return nil, errors.New("not implemented")
 }
@@ -57,6 +61,14 @@ func (m *mockNotificationRepo) ExistsByIdempotencyKey(ctx context.Context, key s
 	return false, errors.New("not implemented")
 }
 
+func (m *mockNotificationRepo) GetByIdempotencyKey(ctx context.Context, key string) (*notification.Notification, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *mockNotificationRepo) IDsByIdempotencyKey(ctx context.Context, keys []string) (map[string]string, error) {
+	return nil, errors.New("not implemented")
+}
+
 type mockBatchRepo struct {
 	getByIDFn func(ctx context.Context, id string) (*notification.Batch, error)
 }
@@ -68,6 +80,14 @@ func (m *mockBatchRepo) GetByID(ctx context.Context, id string) (*notification.B
 	return nil, errors.New("not found")
 }
 
+func (m *mockBatchRepo) GetByIdempotencyKey(ctx context.Context, key string) (*notification.Batch, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *mockBatchRepo) FinishExpansion(ctx context.Context, id string) error {
+	return nil
+}
+
 func (m *mockBatchRepo) Create(ctx context.Context, b *notification.Batch) error {
 	return errors.New("not implemented")
 }
@@ -211,3 +231,109 @@ func TestBatch_NotificationsError(t *testing.T) {
 		t.Error("expected nil notifications on error")
 	}
 }
+
+func TestBatchSummary_DoesNotLoadNotifications(t *testing.T) {
+	batchRepo := &mockBatchRepo{
+		getByIDFn: func(ctx context.Context, id string) (*notification.Batch, error) {
+			return &notification.Batch{
+				ID:     id,
+				Counts: notification.BatchCounts{notification.StatusSent: 2, notification.StatusQueued: 1},
+			}, nil
+		},
+	}
+
+	notifRepo := &mockNotificationRepo{
+		getByBatchIDFn: func(ctx context.Context, batchID string) ([]*notification.Notification, error) {
+			t.Error("BatchSummary should not load notifications")
+			return nil, nil
+		},
+	}
+
+	uc := NewUseCase(notifRepo, batchRepo)
+
+	batch, err := uc.BatchSummary(context.Background(), &BatchByID{BatchID: "batch-id"})
+
+	if err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+	if batch == nil {
+		t.Fatal("expected batch, got nil")
+	}
+	if batch.Counts.Total() != 3 {
+		t.Errorf("expected total 3, got %d", batch.Counts.Total())
+	}
+}
+
+type mockHistoryRepo struct {
+	entries map[string][]*notification.HistoryEntry
+}
+
+func (m *mockHistoryRepo) ListHistory(ctx context.Context, notificationID string) ([]*notification.HistoryEntry, error) {
+	return m.entries[notificationID], nil
+}
+
+func TestHistory(t *testing.T) {
+	repo := &mockNotificationRepo{
+		getByIDFn: func(ctx context.Context, id string) (*notification.Notification, error) {
+			if id != "n-1" {
+				return nil, notification.ErrNotFound
+			}
+			return &notification.Notification{ID: id}, nil
+		},
+	}
+	history := &mockHistoryRepo{entries: map[string][]*notification.HistoryEntry{
+		"n-1":   {{ID: "h-1", NotificationID: "n-1", Event: notification.HistoryDeferred}},
+		"other": {{ID: "h-2", NotificationID: "other", Event: notification.HistoryDeferred}},
+	}}
+	uc := NewUseCase(repo, &mockBatchRepo{}).WithHistory(history)
+
+	entries, err := uc.History(context.Background(), &ByID{ID: "n-1"})
+	if err != nil || len(entries) != 1 || entries[0].ID != "h-1" {
+		t.Errorf("expected h-1, got %+v, %v", entries, err)
+	}
+	// Notifications the caller cannot see have no visible history either.
+	if _, err := uc.History(context.Background(), &ByID{ID: "other"}); !errors.Is(err, notification.ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
+
+type mockEngagementRepo struct {
+	batches map[string]*notification.EngagementStats
+}
+
+func (m *mockEngagementRepo) RecordEngagement(ctx context.Context, id string, e *notification.Engagement) error {
+	return errors.New("not implemented")
+}
+
+func (m *mockEngagementRepo) BatchEngagement(ctx context.Context, batchID string) (*notification.EngagementStats, error) {
+	return m.batches[batchID], nil
+}
+
+func (m *mockEngagementRepo) DigestEngagement(ctx context.Context, templateID string) (*notification.EngagementStats, error) {
+	return nil, errors.New("not implemented")
+}
+
+func TestBatchEngagement(t *testing.T) {
+	batchRepo := &mockBatchRepo{
+		getByIDFn: func(ctx context.Context, id string) (*notification.Batch, error) {
+			if id != "b-1" {
+				return nil, notification.ErrNotFound
+			}
+			return &notification.Batch{ID: id}, nil
+		},
+	}
+	engaged := &mockEngagementRepo{batches: map[string]*notification.EngagementStats{
+		"b-1":   {Tracked: 4, Opened: 2, Clicked: 1},
+		"other": {Tracked: 9},
+	}}
+	uc := NewUseCase(&mockNotificationRepo{}, batchRepo).WithEngagement(engaged)
+
+	stats, err := uc.BatchEngagement(context.Background(), &BatchByID{BatchID: "b-1"})
+	if err != nil || stats.Tracked != 4 || stats.OpenRate() != 50 {
+		t.Errorf("expected b-1 stats, got %+v, %v", stats, err)
+	}
+	// Batches the caller cannot see have no visible engagement either.
+	if _, err := uc.BatchEngagement(context.Background(), &BatchByID{BatchID: "other"}); !errors.Is(err, notification.ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,12 @@
+package imports
+
+type ByID struct {
+	ID string
+}
+
+// RowErrors pages through the rejected rows of an import, in row order.
+type RowErrors struct {
+	ImportID string
+	AfterRow int // rows up to and including this one are skipped
+	Limit    int
+}
@@ -0,0 +1,29 @@
+package imports
+
+import (
+	"context"
+
+	"github.com/semih-yildiz/notification-service/internal/application/notification/port"
+	"github.com/semih-yildiz/notification-service/internal/domain/notification"
+)
+
+type UseCase struct {
+	jobs port.ImportJobRepository
+}
+
+func NewUseCase(jobs port.ImportJobRepository) *UseCase {
+	return &UseCase{jobs: jobs}
+}
+
+func (u *UseCase) Job(ctx context.Context, q *ByID) (*notification.ImportJob, error) {
+	return u.jobs.GetByID(ctx, q.ID)
+}
+
+// RowErrors returns a page of the import's row errors. It returns ErrImportNotFound
+// for imports outside the caller's tenant.
+func (u *UseCase) RowErrors(ctx context.Context, q *RowErrors) ([]notification.RowError, error) {
+	if _, err := u.jobs.GetByID(ctx, q.ImportID); err != nil {
+		return nil, err
+	}
+	return u.jobs.ListRowErrors(ctx, q.ImportID, q.AfterRow, q.Limit)
+}
@@ -3,15 +3,27 @@ package list
 import (
 	"time"
 
+	"github.com/semih-yildiz/notification-service/internal/application/notification/port"
 	"github.com/semih-yildiz/notification-service/internal/domain/notification"
 )
 
 type Query struct {
-	Status   *notification.Status
-	Channel  *notification.Channel
-	FromTime *time.Time
-	ToTime   *time.Time
-	BatchID  *string
-	Limit    int
-	Offset   int
+	Statuses        []notification.Status // any of; empty means all
+	Channel         *notification.Channel
+	Priority        *notification.Priority
+	Recipient       *string // exact match
+	RecipientPrefix *string
+	FromTime        *time.Time // created_at range
+	ToTime          *time.Time
+	SentFrom        *time.Time // sent_at range
+	SentTo          *time.Time
+	BatchID         *string
+	ClientID        *string
+	IdempotencyKey  *string
+	FailureReason   *string // case-insensitive substring
+	Sort            port.ListSort
+	Limit           int
+	Offset          int
+	Cursor          string // next_cursor of the previous page; replaces Offset
+	WithTotal       bool   // count every match
 }
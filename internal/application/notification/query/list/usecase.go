@@ -2,8 +2,13 @@ package list
 
 import (
 	"context"
+	"encoding/base64"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/semih-yildiz/notification-service/internal/application/notification/port"
+	"github.com/semih-yildiz/notification-service/internal/domain/notification"
 )
 
 type UseCase struct {
@@ -14,16 +19,91 @@ func NewUseCase(repo port.NotificationRepository) *UseCase {
 	return &UseCase{repo: repo}
 }
 
-func (u *UseCase) ListByQuery(ctx context.Context, q *Query) (*port.ListResult, error) {
+// Result is one page of notifications.
+type Result struct {
+	Notifications []*notification.Notification
+	Total         *int   // set only when the query asked for it
+	NextCursor    string // empty on the last page
+}
+
+func (u *UseCase) ListByQuery(ctx context.Context, q *Query) (*Result, error) {
 	filter := port.ListFilter{
-		Status:   q.Status,
-		Channel:  q.Channel,
-		FromTime: q.FromTime,
-		ToTime:   q.ToTime,
-		BatchID:  q.BatchID,
-		Limit:    q.Limit,
-		Offset:   q.Offset,
+		Statuses:        q.Statuses,
+		Channel:         q.Channel,
+		Priority:        q.Priority,
+		Recipient:       q.Recipient,
+		RecipientPrefix: q.RecipientPrefix,
+		FromTime:        q.FromTime,
+		ToTime:          q.ToTime,
+		SentFrom:        q.SentFrom,
+		SentTo:          q.SentTo,
+		BatchID:         q.BatchID,
+		ClientID:        q.ClientID,
+		IdempotencyKey:  q.IdempotencyKey,
+		FailureReason:   q.FailureReason,
+		Sort:            q.Sort,
+		Limit:           q.Limit,
+		Offset:          q.Offset,
+		WithTotal:       q.WithTotal,
+	}
+	if q.Cursor != "" {
+		if !q.Sort.Keyset() {
+			return nil, notification.ErrInvalidCursor
+		}
+		after, err := decodeCursor(q.Cursor, q.Sort)
+		if err != nil {
+			return nil, err
+		}
+		filter.After = after
+	}
+
+	res, err := u.repo.List(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, n := range res.Notifications {
+		n.Recipient = notification.RedactRecipient(n.Channel, n.Recipient)
+	}
+	out := &Result{Notifications: res.Notifications, Total: res.Total}
+	if res.NextCursor != nil && q.Sort.Keyset() {
+		out.NextCursor = encodeCursor(res.NextCursor, q.Sort)
+	}
+	return out, nil
+}
+
+// encodeCursor makes an opaque cursor over (created_at, id); clients must not rely
+// on its format. The sort is part of it so a cursor cannot be replayed under a
+// different direction.
+func encodeCursor(c *port.Cursor, sort port.ListSort) string {
+	raw := sortKey(sort) + ":" + strconv.FormatInt(c.At.UnixNano(), 10) + ":" + c.ID
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeCursor(s string, sort port.ListSort) (*port.Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, notification.ErrInvalidCursor
+	}
+	parts := strings.SplitN(string(raw), ":", 3)
+	if len(parts) != 3 || parts[0] != sortKey(sort) || parts[2] == "" {
+		return nil, notification.ErrInvalidCursor
 	}
+	n, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return nil, notification.ErrInvalidCursor
+	}
+	return &port.Cursor{At: time.Unix(0, n).UTC(), ID: parts[2]}, nil
+}
 
-	return u.repo.List(ctx, filter)
+// sortKey names a sort the way the API spells it, e.g. "-created_at".
+func sortKey(sort port.ListSort) string {
+	field := sort.Field
+	if !field.Valid() {
+		field = port.SortByCreatedAt
+	}
+	if sort.Ascending {
+		return string(field)
+	}
+	return "-" + string(field)
 }
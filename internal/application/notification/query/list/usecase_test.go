@@ -20,7 +20,6 @@ func (m *mockNotificationRepo) List(ctx context.Context, filter port.ListFilter)
 	}
 	return &port.ListResult{
 		Notifications: []*notification.Notification{},
-		Total:         0,
 	}, nil
 }
 
@@ -28,7 +27,7 @@ func (m *mockNotificationRepo) Create(ctx context.Context, n *notification.Notif
 	return errors.New("not implemented")
 }
 
-func (m *mockNotificationRepo) CreateBatch(ctx context.Context, notifications []*notification.Notification) error {
+func (m *mockNotificationRepo) CreateBatch(ctx context.Context, b *notification.Batch, notifications []*notification.Notification) error {
 	return errors.New("not implemented")
 }
 
@@ -40,6 +39,10 @@ func (m *mockNotificationRepo) UpdateStatus(ctx context.Context, id string, stat
 	return errors.New("not implemented")
 }
 
+func (m *mockNotificationRepo) UpdateStatuses(ctx context.Context, ids []string, status notification.Status) error {
+	return errors.New("not implemented")
+}
+
 func (m *mockNotificationRepo) GetByBatchID(ctx context.Context, batchID string) ([]*notification.Notification, error) {
 	return nil, errors.New("not implemented")
 }
@@ -56,6 +59,16 @@ func (m *mockNotificationRepo) ExistsByIdempotencyKey(ctx context.Context, key s
 	return false, errors.New("not implemented")
 }
 
+func (m *mockNotificationRepo) GetByIdempotencyKey(ctx context.Context, key string) (*notification.Notification, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *mockNotificationRepo) IDsByIdempotencyKey(ctx context.Context, keys []string) (map[string]string, error) {
+	return nil, errors.New("not implemented")
+}
+
+func intPtr(n int) *int { return &n }
+
 func TestListByQuery_Success(t *testing.T) {
 	repo := &mockNotificationRepo{
 		listFn: func(ctx context.Context, filter port.ListFilter) (*port.ListResult, error) {
@@ -64,7 +77,7 @@ func TestListByQuery_Success(t *testing.T) {
 					{ID: "n1", Status: notification.StatusQueued},
 					{ID: "n2", Status: notification.StatusQueued},
 				},
-				Total: 2,
+				Total: intPtr(2),
 			}, nil
 		},
 	}
@@ -73,9 +86,9 @@ func TestListByQuery_Success(t *testing.T) {
 
 	status := notification.StatusQueued
 	query := &Query{
-		Status: &status,
-		Limit:  10,
-		Offset: 0,
+		Statuses: []notification.Status{status},
+		Limit:    10,
+		Offset:   0,
 	}
 
 	result, err := uc.ListByQuery(context.Background(), query)
@@ -89,21 +102,21 @@ func TestListByQuery_Success(t *testing.T) {
 	if len(result.Notifications) != 2 {
 		t.Errorf("expected 2 items, got %d", len(result.Notifications))
 	}
-	if result.Total != 2 {
-		t.Errorf("expected total 2, got %d", result.Total)
+	if result.Total == nil || *result.Total != 2 {
+		t.Errorf("expected total 2, got %v", result.Total)
 	}
 }
 
 func TestListByQuery_WithFilters(t *testing.T) {
 	repo := &mockNotificationRepo{
 		listFn: func(ctx context.Context, filter port.ListFilter) (*port.ListResult, error) {
-			if filter.Status == nil {
+			if len(filter.Statuses) != 1 {
 				t.Error("expected status filter")
 			}
 			if filter.Channel == nil {
 				t.Error("expected channel filter")
 			}
-			return &port.ListResult{Notifications: []*notification.Notification{}, Total: 0}, nil
+			return &port.ListResult{Notifications: []*notification.Notification{}}, nil
 		},
 	}
 
@@ -112,10 +125,10 @@ func TestListByQuery_WithFilters(t *testing.T) {
 	status := notification.StatusQueued
 	channel := notification.ChannelSMS
 	query := &Query{
-		Status:  &status,
-		Channel: &channel,
-		Limit:   50,
-		Offset:  0,
+		Statuses: []notification.Status{status},
+		Channel:  &channel,
+		Limit:    50,
+		Offset:   0,
 	}
 
 	_, err := uc.ListByQuery(context.Background(), query)
@@ -137,7 +150,7 @@ func TestListByQuery_WithTimeRange(t *testing.T) {
 			if filter.ToTime == nil {
 				t.Error("expected toTime filter")
 			}
-			return &port.ListResult{Notifications: []*notification.Notification{}, Total: 0}, nil
+			return &port.ListResult{Notifications: []*notification.Notification{}}, nil
 		},
 	}
 
@@ -168,7 +181,7 @@ func TestListByQuery_WithBatchID(t *testing.T) {
 			if *filter.BatchID != batchID {
 				t.Errorf("expected batchID %s, got %s", batchID, *filter.BatchID)
 			}
-			return &port.ListResult{Notifications: []*notification.Notification{}, Total: 0}, nil
+			return &port.ListResult{Notifications: []*notification.Notification{}}, nil
 		},
 	}
 
@@ -233,7 +246,7 @@ func TestListByQuery_Pagination(t *testing.T) {
 					if filter.Offset != tt.offset {
 						t.Errorf("expected offset %d, got %d", tt.offset, filter.Offset)
 					}
-					return &port.ListResult{Notifications: []*notification.Notification{}, Total: 0}, nil
+					return &port.ListResult{Notifications: []*notification.Notification{}}, nil
 				},
 			}
 
@@ -252,3 +265,103 @@ func TestListByQuery_Pagination(t *testing.T) {
 		})
 	}
 }
+
+func TestListByQuery_CursorRoundTrip(t *testing.T) {
+	last := &port.Cursor{At: time.Date(2024, 1, 31, 10, 0, 0, 123456000, time.UTC), ID: "n2"}
+	var got *port.Cursor
+	repo := &mockNotificationRepo{
+		listFn: func(ctx context.Context, filter port.ListFilter) (*port.ListResult, error) {
+			got = filter.After
+			return &port.ListResult{Notifications: []*notification.Notification{{ID: "n2"}}, NextCursor: last}, nil
+		},
+	}
+
+	uc := NewUseCase(repo)
+
+	first, err := uc.ListByQuery(context.Background(), &Query{Limit: 1})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if first.NextCursor == "" {
+		t.Fatal("expected a next cursor")
+	}
+	if first.Total != nil {
+		t.Error("expected no total unless requested")
+	}
+
+	if _, err := uc.ListByQuery(context.Background(), &Query{Limit: 1, Cursor: first.NextCursor}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if got == nil || !got.At.Equal(last.At) || got.ID != last.ID {
+		t.Errorf("expected cursor %+v, got %+v", last, got)
+	}
+}
+
+func TestListByQuery_InvalidCursor(t *testing.T) {
+	uc := NewUseCase(&mockNotificationRepo{})
+
+	for _, cursor := range []string{"not base64!", "bm8tc2VwYXJhdG9y", "eDpuMQ"} {
+		if _, err := uc.ListByQuery(context.Background(), &Query{Cursor: cursor}); !errors.Is(err, notification.ErrInvalidCursor) {
+			t.Errorf("cursor %q: expected ErrInvalidCursor, got %v", cursor, err)
+		}
+	}
+}
+
+func TestListByQuery_CursorBoundToSort(t *testing.T) {
+	last := &port.Cursor{At: time.Date(2024, 1, 31, 10, 0, 0, 0, time.UTC), ID: "n2"}
+	var got port.ListFilter
+	repo := &mockNotificationRepo{
+		listFn: func(ctx context.Context, filter port.ListFilter) (*port.ListResult, error) {
+			got = filter
+			return &port.ListResult{Notifications: []*notification.Notification{{ID: "n2"}}, NextCursor: last}, nil
+		},
+	}
+
+	uc := NewUseCase(repo)
+	sort := port.ListSort{Field: port.SortByCreatedAt, Ascending: true}
+
+	first, err := uc.ListByQuery(context.Background(), &Query{Limit: 1, Sort: sort})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if got.Sort != sort {
+		t.Errorf("expected sort %+v, got %+v", sort, got.Sort)
+	}
+
+	if _, err := uc.ListByQuery(context.Background(), &Query{Limit: 1, Sort: sort, Cursor: first.NextCursor}); err != nil {
+		t.Fatalf("expected no error with the same sort, got %v", err)
+	}
+	if _, err := uc.ListByQuery(context.Background(), &Query{Limit: 1, Cursor: first.NextCursor}); !errors.Is(err, notification.ErrInvalidCursor) {
+		t.Errorf("expected ErrInvalidCursor under a different sort, got %v", err)
+	}
+}
+
+func TestListByQuery_UpdatedAtSortHasNoCursor(t *testing.T) {
+	repo := &mockNotificationRepo{
+		listFn: func(ctx context.Context, filter port.ListFilter) (*port.ListResult, error) {
+			return &port.ListResult{
+				Notifications: []*notification.Notification{{ID: "n2"}},
+				NextCursor:    &port.Cursor{At: time.Date(2024, 1, 31, 10, 0, 0, 0, time.UTC), ID: "n2"},
+			}, nil
+		},
+	}
+
+	uc := NewUseCase(repo)
+	sort := port.ListSort{Field: port.SortByUpdatedAt}
+
+	first, err := uc.ListByQuery(context.Background(), &Query{Limit: 1, Sort: sort})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if first.NextCursor != "" {
+		t.Errorf("expected no cursor for an updated_at sort, got %q", first.NextCursor)
+	}
+
+	created, err := uc.ListByQuery(context.Background(), &Query{Limit: 1})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if _, err := uc.ListByQuery(context.Background(), &Query{Limit: 1, Sort: sort, Cursor: created.NextCursor}); !errors.Is(err, notification.ErrInvalidCursor) {
+		t.Errorf("expected ErrInvalidCursor for an updated_at sort, got %v", err)
+	}
+}
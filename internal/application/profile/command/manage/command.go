@@ -0,0 +1,16 @@
+package manage
+
+// SaveCommand creates or replaces a user profile.
+type SaveCommand struct {
+	UserID      string
+	TenantID    string // owning tenant; empty means the default tenant
+	Phone       string
+	Email       string
+	PushTokens  []string
+	Locale      string
+	TimeZone    string
+	Preferences map[string][]string // category -> channels, most preferred first
+	OptOuts     []string
+	// TrackingOptOut turns off open and click tracking of the user's emails.
+	TrackingOptOut bool
+}
@@ -0,0 +1,76 @@
+package manage
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/semih-yildiz/notification-service/internal/application/profile/port"
+	"github.com/semih-yildiz/notification-service/internal/domain/notification"
+	"github.com/semih-yildiz/notification-service/internal/domain/profile"
+	"github.com/semih-yildiz/notification-service/internal/domain/tenant"
+)
+
+type UseCase struct {
+	repo port.ProfileRepository
+}
+
+func NewUseCase(repo port.ProfileRepository) *UseCase {
+	return &UseCase{repo: repo}
+}
+
+// Save creates the profile or replaces every field of an existing one. It reports
+// whether the profile was created.
+func (u *UseCase) Save(ctx context.Context, cmd *SaveCommand) (*profile.Profile, bool, error) {
+	tenantID := cmd.TenantID
+	if tenantID == "" {
+		tenantID = tenant.DefaultID
+	}
+	now := time.Now()
+	p := &profile.Profile{
+		UserID:         cmd.UserID,
+		TenantID:       tenantID,
+		Phone:          strings.TrimSpace(cmd.Phone),
+		Email:          strings.TrimSpace(cmd.Email),
+		PushTokens:     cmd.PushTokens,
+		Locale:         cmd.Locale,
+		TimeZone:       cmd.TimeZone,
+		Preferences:    make(map[string][]notification.Channel, len(cmd.Preferences)),
+		OptOuts:        toChannels(cmd.OptOuts),
+		TrackingOptOut: cmd.TrackingOptOut,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+	for category, channels := range cmd.Preferences {
+		p.Preferences[category] = toChannels(channels)
+	}
+	if err := p.Validate(); err != nil {
+		return nil, false, err
+	}
+
+	existing, err := u.repo.GetByID(ctx, cmd.UserID)
+	switch {
+	case err == nil:
+		p.CreatedAt = existing.CreatedAt
+	case !errors.Is(err, profile.ErrNotFound):
+		return nil, false, err
+	}
+	if err := u.repo.Save(ctx, p); err != nil {
+		return nil, false, err
+	}
+	return p, existing == nil, nil
+}
+
+// Delete removes a profile. Notifications already sent to the user keep their resolution.
+func (u *UseCase) Delete(ctx context.Context, userID string) error {
+	return u.repo.Delete(ctx, userID)
+}
+
+func toChannels(names []string) []notification.Channel {
+	channels := make([]notification.Channel, len(names))
+	for i, name := range names {
+		channels[i] = notification.Channel(name)
+	}
+	return channels
+}
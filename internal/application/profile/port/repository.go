@@ -0,0 +1,21 @@
+package port
+
+import (
+	"context"
+
+	"github.com/semih-yildiz/notification-service/internal/domain/notification"
+	"github.com/semih-yildiz/notification-service/internal/domain/profile"
+)
+
+type ProfileRepository interface {
+	// Save creates the profile or replaces it, addresses and preferences included.
+	Save(ctx context.Context, p *profile.Profile) error
+	GetByID(ctx context.Context, userID string) (*profile.Profile, error)
+	Delete(ctx context.Context, userID string) error
+	// TrackingOptOuts returns which of emails, lowercased, belong to a user who
+	// opted out of tracking.
+	TrackingOptOuts(ctx context.Context, emails []string) (map[string]bool, error)
+	// Locales returns the locale of the users reached at any of addresses on ch, by
+	// address, with email addresses lowercased. Users without a locale are left out.
+	Locales(ctx context.Context, ch notification.Channel, addresses []string) (map[string]string, error)
+}
@@ -0,0 +1,21 @@
+package lookup
+
+import (
+	"context"
+
+	"github.com/semih-yildiz/notification-service/internal/application/profile/port"
+	"github.com/semih-yildiz/notification-service/internal/domain/profile"
+)
+
+type UseCase struct {
+	repo port.ProfileRepository
+}
+
+func NewUseCase(repo port.ProfileRepository) *UseCase {
+	return &UseCase{repo: repo}
+}
+
+// Profile returns one user's profile.
+func (u *UseCase) Profile(ctx context.Context, userID string) (*profile.Profile, error) {
+	return u.repo.GetByID(ctx, userID)
+}
@@ -0,0 +1,20 @@
+package manage
+
+import "github.com/semih-yildiz/notification-service/internal/domain/notification"
+
+// CreateCommand creates a template.
+type CreateCommand struct {
+	Name     string
+	Body     string
+	Variants notification.Variants // Body translated, keyed by locale
+	ClientID *string               // API client making the request
+	TenantID string                // owning tenant; empty means the default tenant
+}
+
+// UpdateCommand replaces a template's name, body and variants.
+type UpdateCommand struct {
+	ID       string
+	Name     string
+	Body     string
+	Variants notification.Variants
+}
@@ -0,0 +1,87 @@
+package manage
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/semih-yildiz/notification-service/internal/application/template/port"
+	"github.com/semih-yildiz/notification-service/internal/domain/template"
+	"github.com/semih-yildiz/notification-service/internal/domain/tenant"
+)
+
+type UseCase struct {
+	repo port.TemplateRepository
+}
+
+func NewUseCase(repo port.TemplateRepository) *UseCase {
+	return &UseCase{repo: repo}
+}
+
+// Create registers a new template.
+func (u *UseCase) Create(ctx context.Context, cmd *CreateCommand) (*template.Template, error) {
+	name := strings.TrimSpace(cmd.Name)
+	if err := template.ValidateName(name); err != nil {
+		return nil, err
+	}
+	if err := template.ValidateBody(cmd.Body); err != nil {
+		return nil, err
+	}
+	if err := template.ValidateVariants(cmd.Variants); err != nil {
+		return nil, err
+	}
+	tenantID := cmd.TenantID
+	if tenantID == "" {
+		tenantID = tenant.DefaultID
+	}
+
+	now := time.Now()
+	t := &template.Template{
+		ID:        uuid.New().String(),
+		TenantID:  tenantID,
+		Name:      name,
+		Body:      cmd.Body,
+		Variants:  cmd.Variants,
+		ClientID:  cmd.ClientID,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := u.repo.Create(ctx, t); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// Update replaces a template's name, body and variants. Notifications already rendered from
+// it keep their content.
+func (u *UseCase) Update(ctx context.Context, cmd *UpdateCommand) (*template.Template, error) {
+	name := strings.TrimSpace(cmd.Name)
+	if err := template.ValidateName(name); err != nil {
+		return nil, err
+	}
+	if err := template.ValidateBody(cmd.Body); err != nil {
+		return nil, err
+	}
+	if err := template.ValidateVariants(cmd.Variants); err != nil {
+		return nil, err
+	}
+	t, err := u.repo.GetByID(ctx, cmd.ID)
+	if err != nil {
+		return nil, err
+	}
+	t.Name, t.Body, t.Variants, t.UpdatedAt = name, cmd.Body, cmd.Variants, time.Now()
+	if err := u.repo.Update(ctx, t); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// Delete removes a template. Digests naming it fall back to the default summary.
+func (u *UseCase) Delete(ctx context.Context, id string) error {
+	if _, err := u.repo.GetByID(ctx, id); err != nil {
+		return err
+	}
+	return u.repo.Delete(ctx, id)
+}
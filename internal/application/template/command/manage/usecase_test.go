@@ -0,0 +1,99 @@
+package manage
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/semih-yildiz/notification-service/internal/domain/template"
+)
+
+type mockTemplateRepo struct {
+	templates map[string]*template.Template
+}
+
+func newMockTemplateRepo() *mockTemplateRepo {
+	return &mockTemplateRepo{templates: make(map[string]*template.Template)}
+}
+
+func (m *mockTemplateRepo) Create(ctx context.Context, t *template.Template) error {
+	for _, other := range m.templates {
+		if other.TenantID == t.TenantID && other.Name == t.Name {
+			return template.ErrExists
+		}
+	}
+	m.templates[t.ID] = t
+	return nil
+}
+
+func (m *mockTemplateRepo) GetByID(ctx context.Context, id string) (*template.Template, error) {
+	if t, ok := m.templates[id]; ok {
+		cp := *t
+		return &cp, nil
+	}
+	return nil, template.ErrNotFound
+}
+
+func (m *mockTemplateRepo) List(ctx context.Context) ([]*template.Template, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *mockTemplateRepo) Update(ctx context.Context, t *template.Template) error {
+	m.templates[t.ID] = t
+	return nil
+}
+
+func (m *mockTemplateRepo) Delete(ctx context.Context, id string) error {
+	delete(m.templates, id)
+	return nil
+}
+
+func TestCreate_Validation(t *testing.T) {
+	uc := NewUseCase(newMockTemplateRepo())
+
+	tests := []struct {
+		name    string
+		cmd     *CreateCommand
+		wantErr error
+	}{
+		{"valid", &CreateCommand{Name: " likes-digest ", Body: "{{.Count}} new likes"}, nil},
+		{"duplicate name", &CreateCommand{Name: "likes-digest", Body: "Hi"}, template.ErrExists},
+		{"empty name", &CreateCommand{Name: " ", Body: "Hi"}, template.ErrInvalidName},
+		{"bad body", &CreateCommand{Name: "broken", Body: "{{.Count"}, template.ErrInvalidBody},
+		{"bad variant", &CreateCommand{Name: "broken", Body: "Hi", Variants: map[string]string{"tr": "{{.Count"}}, template.ErrInvalidVariants},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			created, err := uc.Create(context.Background(), tt.cmd)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+			if err == nil && (created.Name != "likes-digest" || created.TenantID != "default") {
+				t.Errorf("expected a trimmed name in the default tenant, got %+v", created)
+			}
+		})
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	repo := newMockTemplateRepo()
+	uc := NewUseCase(repo)
+	created, _ := uc.Create(context.Background(), &CreateCommand{Name: "likes-digest", Body: "Hi"})
+
+	updated, err := uc.Update(context.Background(), &UpdateCommand{
+		ID: created.ID, Name: "likes-digest", Body: "{{.Count}} new likes",
+		Variants: map[string]string{"tr": "{{.Count}} yeni beğeni"},
+	})
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	stored := repo.templates[created.ID]
+	if stored.Body != "{{.Count}} new likes" || stored.Variants["tr"] == "" || updated.UpdatedAt.Before(created.UpdatedAt) {
+		t.Errorf("expected the body and variants replaced, got %+v", stored)
+	}
+	if _, err := uc.Update(context.Background(), &UpdateCommand{ID: "missing", Name: "x", Body: "y"}); !errors.Is(err, template.ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
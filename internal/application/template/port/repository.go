@@ -0,0 +1,17 @@
+package port
+
+import (
+	"context"
+
+	"github.com/semih-yildiz/notification-service/internal/domain/template"
+)
+
+type TemplateRepository interface {
+	// Create and Update return template.ErrExists if the tenant already has
+	// another template with the same name.
+	Create(ctx context.Context, t *template.Template) error
+	GetByID(ctx context.Context, id string) (*template.Template, error)
+	List(ctx context.Context) ([]*template.Template, error)
+	Update(ctx context.Context, t *template.Template) error
+	Delete(ctx context.Context, id string) error
+}
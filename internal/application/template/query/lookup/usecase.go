@@ -0,0 +1,26 @@
+package lookup
+
+import (
+	"context"
+
+	"github.com/semih-yildiz/notification-service/internal/application/template/port"
+	"github.com/semih-yildiz/notification-service/internal/domain/template"
+)
+
+type UseCase struct {
+	repo port.TemplateRepository
+}
+
+func NewUseCase(repo port.TemplateRepository) *UseCase {
+	return &UseCase{repo: repo}
+}
+
+// Template returns one template.
+func (u *UseCase) Template(ctx context.Context, id string) (*template.Template, error) {
+	return u.repo.GetByID(ctx, id)
+}
+
+// List returns the tenant's templates.
+func (u *UseCase) List(ctx context.Context) ([]*template.Template, error) {
+	return u.repo.List(ctx)
+}
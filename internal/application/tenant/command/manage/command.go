@@ -0,0 +1,28 @@
+package manage
+
+import "github.com/semih-yildiz/notification-service/internal/domain/notification"
+
+// CreateCommand creates a tenant.
+type CreateCommand struct {
+	ID          string // slug used in keys and queues, e.g. "billing"
+	Name        string
+	DailyQuotas map[string]int // channel -> sends per day
+}
+
+// SetDeliveryWindowsCommand replaces a tenant's per-category delivery windows.
+type SetDeliveryWindowsCommand struct {
+	TenantID string
+	Windows  map[string]notification.DeliveryWindow // category -> window
+}
+
+// SetDigestPoliciesCommand replaces a tenant's per-category digest policies.
+type SetDigestPoliciesCommand struct {
+	TenantID string
+	Policies map[string]notification.DigestPolicy // category -> policy
+}
+
+// SetQuotasCommand replaces a tenant's daily quotas.
+type SetQuotasCommand struct {
+	TenantID    string
+	DailyQuotas map[string]int
+}
@@ -0,0 +1,123 @@
+package manage
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/semih-yildiz/notification-service/internal/application/tenant/port"
+	"github.com/semih-yildiz/notification-service/internal/domain/notification"
+	"github.com/semih-yildiz/notification-service/internal/domain/tenant"
+)
+
+const maxNameLength = 100
+
+var idPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{0,62}$`)
+
+type UseCase struct {
+	repo port.TenantRepository
+}
+
+func NewUseCase(repo port.TenantRepository) *UseCase {
+	return &UseCase{repo: repo}
+}
+
+// Create registers a new tenant.
+func (u *UseCase) Create(ctx context.Context, cmd *CreateCommand) (*tenant.Tenant, error) {
+	if !idPattern.MatchString(cmd.ID) {
+		return nil, tenant.ErrInvalidID
+	}
+	name := strings.TrimSpace(cmd.Name)
+	if name == "" || len(name) > maxNameLength {
+		return nil, tenant.ErrInvalidName
+	}
+	quotas := toQuotas(cmd.DailyQuotas)
+	if err := tenant.ValidateQuotas(quotas); err != nil {
+		return nil, err
+	}
+
+	if _, err := u.repo.GetByID(ctx, cmd.ID); err == nil {
+		return nil, tenant.ErrExists
+	} else if !errors.Is(err, tenant.ErrNotFound) {
+		return nil, err
+	}
+
+	t := &tenant.Tenant{
+		ID:          cmd.ID,
+		Name:        name,
+		DailyQuotas: quotas,
+		CreatedAt:   time.Now(),
+	}
+	if err := u.repo.Create(ctx, t); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// SetQuotas replaces a tenant's daily quotas.
+func (u *UseCase) SetQuotas(ctx context.Context, cmd *SetQuotasCommand) (*tenant.Tenant, error) {
+	quotas := toQuotas(cmd.DailyQuotas)
+	if err := tenant.ValidateQuotas(quotas); err != nil {
+		return nil, err
+	}
+	t, err := u.repo.GetByID(ctx, cmd.TenantID)
+	if err != nil {
+		return nil, err
+	}
+	if err := u.repo.SetQuotas(ctx, t.ID, quotas); err != nil {
+		return nil, err
+	}
+	t.DailyQuotas = quotas
+	return t, nil
+}
+
+// SetDeliveryWindows replaces a tenant's per-category delivery windows.
+func (u *UseCase) SetDeliveryWindows(ctx context.Context, cmd *SetDeliveryWindowsCommand) (*tenant.Tenant, error) {
+	if err := tenant.ValidateDeliveryWindows(cmd.Windows); err != nil {
+		return nil, err
+	}
+	t, err := u.repo.GetByID(ctx, cmd.TenantID)
+	if err != nil {
+		return nil, err
+	}
+	if err := u.repo.SetDeliveryWindows(ctx, t.ID, cmd.Windows); err != nil {
+		return nil, err
+	}
+	t.DeliveryWindows = cmd.Windows
+	return t, nil
+}
+
+// SetDigestPolicies replaces a tenant's per-category digest policies.
+func (u *UseCase) SetDigestPolicies(ctx context.Context, cmd *SetDigestPoliciesCommand) (*tenant.Tenant, error) {
+	if err := tenant.ValidateDigestPolicies(cmd.Policies); err != nil {
+		return nil, err
+	}
+	t, err := u.repo.GetByID(ctx, cmd.TenantID)
+	if err != nil {
+		return nil, err
+	}
+	if err := u.repo.SetDigestPolicies(ctx, t.ID, cmd.Policies); err != nil {
+		return nil, err
+	}
+	t.DigestPolicies = cmd.Policies
+	return t, nil
+}
+
+// EnsureDefault creates the default tenant if it does not exist.
+func (u *UseCase) EnsureDefault(ctx context.Context) error {
+	_, err := u.repo.GetByID(ctx, tenant.DefaultID)
+	if err == nil || !errors.Is(err, tenant.ErrNotFound) {
+		return err
+	}
+	return u.repo.Create(ctx, &tenant.Tenant{ID: tenant.DefaultID, Name: "Default", CreatedAt: time.Now()})
+}
+
+func toQuotas(in map[string]int) map[notification.Channel]int {
+	out := make(map[notification.Channel]int, len(in))
+	for ch, limit := range in {
+		out[notification.Channel(ch)] = limit
+	}
+	return out
+}
@@ -0,0 +1,151 @@
+package manage
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/semih-yildiz/notification-service/internal/domain/notification"
+	"github.com/semih-yildiz/notification-service/internal/domain/tenant"
+)
+
+type mockTenantRepo struct {
+	tenants map[string]*tenant.Tenant
+}
+
+func newMockTenantRepo() *mockTenantRepo {
+	return &mockTenantRepo{tenants: make(map[string]*tenant.Tenant)}
+}
+
+func (m *mockTenantRepo) Create(ctx context.Context, t *tenant.Tenant) error {
+	m.tenants[t.ID] = t
+	return nil
+}
+
+func (m *mockTenantRepo) GetByID(ctx context.Context, id string) (*tenant.Tenant, error) {
+	if t, ok := m.tenants[id]; ok {
+		cp := *t
+		return &cp, nil
+	}
+	return nil, tenant.ErrNotFound
+}
+
+func (m *mockTenantRepo) List(ctx context.Context) ([]*tenant.Tenant, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *mockTenantRepo) SetQuotas(ctx context.Context, id string, quotas map[notification.Channel]int) error {
+	m.tenants[id].DailyQuotas = quotas
+	return nil
+}
+
+func (m *mockTenantRepo) SetDeliveryWindows(ctx context.Context, id string, windows map[string]notification.DeliveryWindow) error {
+	m.tenants[id].DeliveryWindows = windows
+	return nil
+}
+
+func (m *mockTenantRepo) SetDigestPolicies(ctx context.Context, id string, policies map[string]notification.DigestPolicy) error {
+	m.tenants[id].DigestPolicies = policies
+	return nil
+}
+
+func TestCreate_Success(t *testing.T) {
+	repo := newMockTenantRepo()
+	uc := NewUseCase(repo)
+
+	created, err := uc.Create(context.Background(), &CreateCommand{ID: "billing", Name: "Billing", DailyQuotas: map[string]int{"sms": 100}})
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if limit, ok := created.DailyQuota(notification.ChannelSMS); !ok || limit != 100 {
+		t.Errorf("expected sms quota 100, got %d (%v)", limit, ok)
+	}
+	if _, err := uc.Create(context.Background(), &CreateCommand{ID: "billing", Name: "Billing"}); !errors.Is(err, tenant.ErrExists) {
+		t.Errorf("expected ErrExists, got %v", err)
+	}
+}
+
+func TestCreate_Validation(t *testing.T) {
+	uc := NewUseCase(newMockTenantRepo())
+
+	tests := []struct {
+		name    string
+		cmd     *CreateCommand
+		wantErr error
+	}{
+		{"uppercase id", &CreateCommand{ID: "Billing", Name: "Billing"}, tenant.ErrInvalidID},
+		{"empty name", &CreateCommand{ID: "billing", Name: " "}, tenant.ErrInvalidName},
+		{"unknown channel", &CreateCommand{ID: "billing", Name: "Billing", DailyQuotas: map[string]int{"fax": 1}}, tenant.ErrInvalidQuota},
+		{"negative quota", &CreateCommand{ID: "billing", Name: "Billing", DailyQuotas: map[string]int{"sms": -1}}, tenant.ErrInvalidQuota},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := uc.Create(context.Background(), tt.cmd); !errors.Is(err, tt.wantErr) {
+				t.Errorf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestEnsureDefault_Idempotent(t *testing.T) {
+	repo := newMockTenantRepo()
+	uc := NewUseCase(repo)
+
+	for i := 0; i < 2; i++ {
+		if err := uc.EnsureDefault(context.Background()); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	}
+	if _, ok := repo.tenants[tenant.DefaultID]; !ok || len(repo.tenants) != 1 {
+		t.Errorf("expected only the default tenant, got %v", repo.tenants)
+	}
+}
+
+func TestSetDeliveryWindows(t *testing.T) {
+	repo := newMockTenantRepo()
+	repo.tenants["billing"] = &tenant.Tenant{ID: "billing"}
+	uc := NewUseCase(repo)
+	windows := map[string]notification.DeliveryWindow{"marketing": {Start: 9 * 60, End: 21 * 60}}
+
+	updated, err := uc.SetDeliveryWindows(context.Background(), &SetDeliveryWindowsCommand{TenantID: "billing", Windows: windows})
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if w, ok := updated.DeliveryWindow("marketing"); !ok || w.Start != 9*60 {
+		t.Errorf("expected marketing window, got %+v", updated.DeliveryWindows)
+	}
+	bad := map[string]notification.DeliveryWindow{"marketing": {Start: 60, End: 60}}
+	if _, err := uc.SetDeliveryWindows(context.Background(), &SetDeliveryWindowsCommand{TenantID: "billing", Windows: bad}); !errors.Is(err, notification.ErrInvalidWindow) {
+		t.Errorf("expected ErrInvalidWindow, got %v", err)
+	}
+	if _, err := uc.SetDeliveryWindows(context.Background(), &SetDeliveryWindowsCommand{TenantID: "missing"}); !errors.Is(err, tenant.ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestSetDigestPolicies(t *testing.T) {
+	repo := newMockTenantRepo()
+	repo.tenants["social"] = &tenant.Tenant{ID: "social"}
+	uc := NewUseCase(repo)
+	policies := map[string]notification.DigestPolicy{"likes": {Window: 15 * time.Minute, TemplateID: "tpl-1"}}
+
+	updated, err := uc.SetDigestPolicies(context.Background(), &SetDigestPoliciesCommand{TenantID: "social", Policies: policies})
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if p, ok := updated.DigestPolicy("likes"); !ok || p.Window != 15*time.Minute {
+		t.Errorf("expected likes policy, got %+v", updated.DigestPolicies)
+	}
+	bad := map[string]notification.DigestPolicy{"likes": {Window: time.Second}}
+	if _, err := uc.SetDigestPolicies(context.Background(), &SetDigestPoliciesCommand{TenantID: "social", Policies: bad}); !errors.Is(err, notification.ErrInvalidDigestPolicy) {
+		t.Errorf("expected ErrInvalidDigestPolicy, got %v", err)
+	}
+	if _, err := uc.SetDigestPolicies(context.Background(), &SetDigestPoliciesCommand{TenantID: "missing"}); !errors.Is(err, tenant.ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
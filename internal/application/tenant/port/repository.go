@@ -0,0 +1,25 @@
+package port
+
+import (
+	"context"
+
+	"github.com/semih-yildiz/notification-service/internal/domain/notification"
+	"github.com/semih-yildiz/notification-service/internal/domain/tenant"
+)
+
+type TenantRepository interface {
+	Create(ctx context.Context, t *tenant.Tenant) error
+	GetByID(ctx context.Context, id string) (*tenant.Tenant, error)
+	List(ctx context.Context) ([]*tenant.Tenant, error)
+	// SetQuotas replaces all daily quotas of a tenant.
+	SetQuotas(ctx context.Context, id string, quotas map[notification.Channel]int) error
+	// SetDeliveryWindows replaces all per-category delivery windows of a tenant.
+	SetDeliveryWindows(ctx context.Context, id string, windows map[string]notification.DeliveryWindow) error
+	// SetDigestPolicies replaces all per-category digest policies of a tenant.
+	SetDigestPolicies(ctx context.Context, id string, policies map[string]notification.DigestPolicy) error
+}
+
+// QuotaUsage reports how much of today's quota a tenant has used.
+type QuotaUsage interface {
+	UsedToday(ctx context.Context, tenantID string, ch notification.Channel) (int, error)
+}
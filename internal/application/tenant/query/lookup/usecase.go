@@ -0,0 +1,46 @@
+package lookup
+
+import (
+	"context"
+
+	"github.com/semih-yildiz/notification-service/internal/application/tenant/port"
+	"github.com/semih-yildiz/notification-service/internal/domain/notification"
+	"github.com/semih-yildiz/notification-service/internal/domain/tenant"
+)
+
+type UseCase struct {
+	repo  port.TenantRepository
+	usage port.QuotaUsage
+}
+
+func NewUseCase(repo port.TenantRepository, usage port.QuotaUsage) *UseCase {
+	return &UseCase{repo: repo, usage: usage}
+}
+
+// Usage is a tenant with today's sends per quota-limited channel.
+type Usage struct {
+	*tenant.Tenant
+	UsedToday map[notification.Channel]int
+}
+
+// Tenant returns a tenant and its quota usage for today.
+func (u *UseCase) Tenant(ctx context.Context, id string) (*Usage, error) {
+	t, err := u.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	used := make(map[notification.Channel]int, len(t.DailyQuotas))
+	for ch := range t.DailyQuotas {
+		n, err := u.usage.UsedToday(ctx, t.ID, ch)
+		if err != nil {
+			return nil, err
+		}
+		used[ch] = n
+	}
+	return &Usage{Tenant: t, UsedToday: used}, nil
+}
+
+// List returns all tenants.
+func (u *UseCase) List(ctx context.Context) ([]*tenant.Tenant, error) {
+	return u.repo.List(ctx)
+}
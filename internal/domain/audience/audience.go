@@ -0,0 +1,55 @@
+package audience
+
+import (
+	"time"
+
+	"github.com/semih-yildiz/notification-service/internal/domain/notification"
+)
+
+const (
+	MaxNameLength = 100
+	// MaxMembersPerRequest caps how many members one request adds.
+	MaxMembersPerRequest = notification.MaxBatchSize
+)
+
+// Audience is a named recipient list. Sending to it creates one notification per
+// member, in one batch.
+type Audience struct {
+	ID        string
+	TenantID  string
+	Name      string // unique per tenant
+	ClientID  *string
+	CreatedAt time.Time
+}
+
+// Member is one recipient of an audience on one channel. The same person is a
+// separate member on each channel they are reached on.
+type Member struct {
+	AudienceID string
+	Channel    notification.Channel
+	Recipient  string
+	// Suppressed members stay on the list but are skipped when it is sent to,
+	// e.g. after an unsubscribe or a hard bounce.
+	Suppressed bool
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+// ValidateName checks that name is set and at most MaxNameLength characters.
+func ValidateName(name string) error {
+	if name == "" || len(name) > MaxNameLength {
+		return ErrInvalidName
+	}
+	return nil
+}
+
+// Validate checks the member's channel and recipient.
+func (m *Member) Validate() error {
+	if !m.Channel.Valid() || m.Recipient == "" || len(m.Recipient) > notification.MaxRecipientLength {
+		return ErrInvalidMember
+	}
+	if notification.ValidateRecipient(m.Channel, m.Recipient) != nil {
+		return ErrInvalidMember
+	}
+	return nil
+}
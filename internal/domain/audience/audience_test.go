@@ -0,0 +1,43 @@
+package audience
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/semih-yildiz/notification-service/internal/domain/notification"
+)
+
+func TestValidateName(t *testing.T) {
+	if err := ValidateName("newsletter"); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+	if err := ValidateName(""); err != ErrInvalidName {
+		t.Errorf("expected ErrInvalidName for an empty name, got %v", err)
+	}
+	if err := ValidateName(strings.Repeat("a", MaxNameLength+1)); err != ErrInvalidName {
+		t.Errorf("expected ErrInvalidName for a long name, got %v", err)
+	}
+}
+
+func TestMember_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		member  Member
+		wantErr bool
+	}{
+		{"Valid", Member{Channel: notification.ChannelSMS, Recipient: "+905551234567"}, false},
+		{"Unknown channel", Member{Channel: "fax", Recipient: "+905551234567"}, true},
+		{"No recipient", Member{Channel: notification.ChannelEmail}, true},
+		{"Long recipient", Member{Channel: notification.ChannelEmail, Recipient: strings.Repeat("a", notification.MaxRecipientLength+1)}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.member.Validate()
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("expected error=%v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,12 @@
+package audience
+
+import "errors"
+
+var (
+	ErrNotFound       = errors.New("audience not found")
+	ErrInvalidName    = errors.New("invalid audience name")
+	ErrExists         = errors.New("audience already exists")
+	ErrInvalidMember  = errors.New("invalid audience member")
+	ErrMemberNotFound = errors.New("audience member not found")
+	ErrTooManyMembers = errors.New("too many members in one request")
+)
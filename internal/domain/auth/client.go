@@ -0,0 +1,41 @@
+package auth
+
+import (
+	"time"
+
+	"github.com/semih-yildiz/notification-service/internal/domain/notification"
+)
+
+// Client is an API consumer authenticated by an API key.
+type Client struct {
+	ID        string
+	TenantID  string
+	Name      string
+	KeyPrefix string // first characters of the key, safe to display
+	KeyHash   string // SHA-256 of the full key; the key itself is never stored
+	Scopes    []Scope
+	CreatedAt time.Time
+	RotatedAt *time.Time
+}
+
+// Can reports whether the client holds scope. Platform clients hold every scope,
+// admin clients every scope but ScopePlatform.
+func (c *Client) Can(scope Scope) bool {
+	for _, s := range c.Scopes {
+		if s == scope || s == ScopePlatform || (s == ScopeAdmin && scope != ScopePlatform) {
+			return true
+		}
+	}
+	return false
+}
+
+// CanManageTenant reports whether the client may administer tenant id: its own,
+// or any tenant for platform clients.
+func (c *Client) CanManageTenant(id string) bool {
+	return c.TenantID == id || c.Can(ScopePlatform)
+}
+
+// CanSend reports whether the client may send on channel.
+func (c *Client) CanSend(ch notification.Channel) bool {
+	return c.Can(SendScope(ch))
+}
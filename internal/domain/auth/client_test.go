@@ -0,0 +1,121 @@
+package auth
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/semih-yildiz/notification-service/internal/domain/notification"
+)
+
+func TestClient_Can(t *testing.T) {
+	tests := []struct {
+		name   string
+		scopes []Scope
+		scope  Scope
+		want   bool
+	}{
+		{"Granted", []Scope{ScopeRead}, ScopeRead, true},
+		{"Missing", []Scope{ScopeRead}, ScopeCancel, false},
+		{"Admin implies all", []Scope{ScopeAdmin}, ScopeCancel, true},
+		{"Admin lacks platform", []Scope{ScopeAdmin}, ScopePlatform, false},
+		{"Platform implies all", []Scope{ScopePlatform}, ScopeAdmin, true},
+		{"No scopes", nil, ScopeRead, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Client{Scopes: tt.scopes}
+			if got := c.Can(tt.scope); got != tt.want {
+				t.Errorf("Can(%s) = %v, want %v", tt.scope, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClient_CanSend(t *testing.T) {
+	c := &Client{Scopes: []Scope{ScopeSendSMS}}
+
+	if !c.CanSend(notification.ChannelSMS) {
+		t.Error("expected sms to be allowed")
+	}
+	if c.CanSend(notification.ChannelEmail) {
+		t.Error("expected email to be denied")
+	}
+}
+
+func TestClient_CanManageTenant(t *testing.T) {
+	admin := &Client{TenantID: "acme", Scopes: []Scope{ScopeAdmin}}
+	platform := &Client{TenantID: "default", Scopes: []Scope{ScopePlatform}}
+
+	if !admin.CanManageTenant("acme") || admin.CanManageTenant("globex") {
+		t.Error("expected an admin to manage only its own tenant")
+	}
+	if !platform.CanManageTenant("globex") {
+		t.Error("expected a platform client to manage every tenant")
+	}
+}
+
+func TestParseScopes(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   []string
+		want    int
+		wantErr bool
+	}{
+		{"Valid", []string{"send:sms", "read"}, 2, false},
+		{"Audiences", []string{"audiences"}, 1, false},
+		{"Inbox", []string{"inbox", "send:inapp"}, 2, false},
+		{"Deduplicates", []string{"read", "read"}, 1, false},
+		{"Unknown scope", []string{"write"}, 0, true},
+		{"Unknown channel", []string{"send:fax"}, 0, true},
+		{"Empty", nil, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseScopes(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseScopes(%v) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if len(got) != tt.want {
+				t.Errorf("ParseScopes(%v) = %v, want %d scopes", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScopes(t *testing.T) {
+	scopes := Scopes()
+	for _, spec := range notification.Channels() {
+		if !slices.Contains(scopes, SendScope(spec.Name)) {
+			t.Errorf("expected the send scope of %s, got %v", spec.Name, scopes)
+		}
+	}
+	for _, s := range scopes {
+		if !s.Valid() {
+			t.Errorf("expected %s to be valid", s)
+		}
+	}
+	if !slices.Contains(scopes, ScopePlatform) || !strings.Contains(ScopeList(), "send:chat, read") {
+		t.Errorf("unexpected scope list %q", ScopeList())
+	}
+}
+
+func TestGenerateKey(t *testing.T) {
+	key, prefix, err := GenerateKey()
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+	if !strings.HasPrefix(key, prefix) || len(prefix) != KeyPrefixLength {
+		t.Errorf("prefix %q does not match key", prefix)
+	}
+
+	other, _, _ := GenerateKey()
+	if key == other {
+		t.Error("expected distinct keys")
+	}
+	if HashKey(key) == HashKey(other) || HashKey(key) != HashKey(key) {
+		t.Error("expected stable, distinct hashes")
+	}
+}
@@ -0,0 +1,11 @@
+package auth
+
+import "errors"
+
+var (
+	ErrInvalidKey   = errors.New("invalid or missing api key")
+	ErrForbidden    = errors.New("api key lacks the required scope")
+	ErrNotFound     = errors.New("api client not found")
+	ErrInvalidScope = errors.New("invalid scope")
+	ErrInvalidName  = errors.New("invalid client name")
+)
@@ -0,0 +1,32 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+)
+
+const (
+	keyPrefix      = "nsk_"
+	keySecretBytes = 32
+	// KeyPrefixLength is how much of a key is kept for display.
+	KeyPrefixLength = len(keyPrefix) + 8
+)
+
+// GenerateKey returns a new random API key and its display prefix.
+func GenerateKey() (key, prefix string, err error) {
+	b := make([]byte, keySecretBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", err
+	}
+	key = keyPrefix + base64.RawURLEncoding.EncodeToString(b)
+	return key, key[:KeyPrefixLength], nil
+}
+
+// HashKey returns the stored form of an API key. Keys are random and long, so a
+// plain SHA-256 is enough to make a leaked table useless.
+func HashKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,95 @@
+package auth
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/semih-yildiz/notification-service/internal/domain/notification"
+)
+
+// Scope is a permission granted to an API client.
+type Scope string
+
+const (
+	ScopeSendSMS   Scope = "send:sms"
+	ScopeSendEmail Scope = "send:email"
+	ScopeSendPush  Scope = "send:push"
+	ScopeSendInApp Scope = "send:inapp"
+	ScopeSendChat  Scope = "send:chat"
+	ScopeRead      Scope = "read"
+	ScopeCancel    Scope = "cancel"
+	// ScopeAdmin holds every scope within the client's own tenant.
+	ScopeAdmin Scope = "admin"
+	// ScopePlatform holds every scope in every tenant: creating tenants, setting
+	// their quotas and issuing keys into them. Only platform clients may grant it.
+	ScopePlatform Scope = "platform"
+	// ScopeAudiences allows creating, changing and deleting audiences.
+	ScopeAudiences Scope = "audiences"
+	// ScopeUsers allows creating, replacing and deleting user profiles.
+	ScopeUsers Scope = "users"
+	// ScopeTemplates allows creating, changing and deleting templates.
+	ScopeTemplates Scope = "templates"
+	// ScopeInbox allows marking in-app messages read, unread or archived.
+	ScopeInbox Scope = "inbox"
+)
+
+// fixedScopes are the scopes besides the send scope of each registered channel.
+var fixedScopes = []Scope{
+	ScopeRead, ScopeCancel, ScopeAudiences, ScopeUsers, ScopeTemplates, ScopeInbox, ScopeAdmin, ScopePlatform,
+}
+
+// SendScope returns the scope required to send on channel.
+func SendScope(ch notification.Channel) Scope {
+	return Scope("send:" + ch.String())
+}
+
+// Scopes returns every valid scope: the send scope of each registered channel,
+// then the fixed scopes.
+func Scopes() []Scope {
+	channels := notification.Channels()
+	scopes := make([]Scope, 0, len(channels)+len(fixedScopes))
+	for _, spec := range channels {
+		scopes = append(scopes, SendScope(spec.Name))
+	}
+	return append(scopes, fixedScopes...)
+}
+
+// ScopeList returns the valid scope names for messages, e.g. "send:sms, read".
+func ScopeList() string {
+	scopes := Scopes()
+	names := make([]string, len(scopes))
+	for i, s := range scopes {
+		names[i] = s.String()
+	}
+	return strings.Join(names, ", ")
+}
+
+func (s Scope) Valid() bool {
+	if slices.Contains(fixedScopes, s) {
+		return true
+	}
+	ch, ok := strings.CutPrefix(string(s), "send:")
+	return ok && notification.Channel(ch).Valid()
+}
+
+func (s Scope) String() string { return string(s) }
+
+// ParseScopes converts and validates scope names.
+func ParseScopes(names []string) ([]Scope, error) {
+	if len(names) == 0 {
+		return nil, ErrInvalidScope
+	}
+	scopes := make([]Scope, 0, len(names))
+	seen := make(map[Scope]bool, len(names))
+	for _, n := range names {
+		s := Scope(n)
+		if !s.Valid() {
+			return nil, ErrInvalidScope
+		}
+		if !seen[s] {
+			seen[s] = true
+			scopes = append(scopes, s)
+		}
+	}
+	return scopes, nil
+}
@@ -0,0 +1,8 @@
+package inbox
+
+import "errors"
+
+var (
+	ErrNotFound     = errors.New("inbox message not found")
+	ErrInvalidState = errors.New("invalid inbox message state")
+)
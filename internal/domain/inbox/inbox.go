@@ -0,0 +1,65 @@
+package inbox
+
+import "time"
+
+// State is where a message stands in the user's inbox.
+type State string
+
+const (
+	StateUnread   State = "unread"
+	StateRead     State = "read"
+	StateArchived State = "archived"
+)
+
+func (s State) Valid() bool {
+	switch s {
+	case StateUnread, StateRead, StateArchived:
+		return true
+	}
+	return false
+}
+
+func (s State) String() string { return string(s) }
+
+// Message is an in-app notification in one user's inbox. It shares its ID with the
+// notification it was delivered from, so a notification lands in the inbox once.
+type Message struct {
+	ID       string
+	TenantID string
+	UserID   string
+	Category string // set when the notification was sent to a user ID
+	Content  string
+	State    State
+	// ReadAt is when the message was first read; marking it unread clears it.
+	ReadAt     *time.Time
+	ArchivedAt *time.Time
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+// Mark moves the message to state at the given time. It reports false, changing
+// nothing, if the message is already in state. Archiving keeps the read time;
+// reading or unreading an archived message moves it back to the inbox.
+func (m *Message) Mark(state State, at time.Time) (bool, error) {
+	if !state.Valid() {
+		return false, ErrInvalidState
+	}
+	if m.State == state {
+		return false, nil
+	}
+	switch state {
+	case StateUnread:
+		m.ReadAt = nil
+		m.ArchivedAt = nil
+	case StateRead:
+		if m.ReadAt == nil {
+			m.ReadAt = &at
+		}
+		m.ArchivedAt = nil
+	case StateArchived:
+		m.ArchivedAt = &at
+	}
+	m.State = state
+	m.UpdatedAt = at
+	return true, nil
+}
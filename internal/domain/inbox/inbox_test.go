@@ -0,0 +1,56 @@
+package inbox
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestMessage_Mark(t *testing.T) {
+	created := time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)
+	earlier := created.Add(time.Minute)
+	at := created.Add(time.Hour)
+
+	tests := []struct {
+		name         string
+		from         Message
+		state        State
+		wantChanged  bool
+		wantRead     *time.Time
+		wantArchived bool
+		wantErr      error
+	}{
+		{"Read", Message{State: StateUnread}, StateRead, true, &at, false, nil},
+		{"Unread clears the read time", Message{State: StateRead, ReadAt: &earlier}, StateUnread, true, nil, false, nil},
+		{"Archive keeps the read time", Message{State: StateRead, ReadAt: &earlier}, StateArchived, true, &earlier, true, nil},
+		{"Read unarchives", Message{State: StateArchived, ReadAt: &earlier, ArchivedAt: &earlier}, StateRead, true, &earlier, false, nil},
+		{"Same state", Message{State: StateRead, ReadAt: &earlier}, StateRead, false, &earlier, false, nil},
+		{"Invalid state", Message{State: StateUnread}, State("deleted"), false, nil, false, ErrInvalidState},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := tt.from
+			m.CreatedAt, m.UpdatedAt = created, created
+			changed, err := m.Mark(tt.state, at)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if changed != tt.wantChanged {
+				t.Fatalf("expected changed %v, got %v", tt.wantChanged, changed)
+			}
+			if err != nil {
+				return
+			}
+			if (m.ReadAt == nil) != (tt.wantRead == nil) || (m.ReadAt != nil && !m.ReadAt.Equal(*tt.wantRead)) {
+				t.Errorf("expected read at %v, got %v", tt.wantRead, m.ReadAt)
+			}
+			if (m.ArchivedAt != nil) != tt.wantArchived {
+				t.Errorf("expected archived %v, got %v", tt.wantArchived, m.ArchivedAt)
+			}
+			if changed && (m.State != tt.state || !m.UpdatedAt.Equal(at)) {
+				t.Errorf("expected state %s updated at %v, got %s at %v", tt.state, at, m.State, m.UpdatedAt)
+			}
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package notification
+
+// BatchStatus is the aggregate state of a batch, derived from its notification counts.
+type BatchStatus string
+
+const (
+	BatchStatusInProgress      BatchStatus = "in_progress"      // at least one notification not terminal
+	BatchStatusCompleted       BatchStatus = "completed"        // all terminal, none failed
+	BatchStatusPartiallyFailed BatchStatus = "partially_failed" // all terminal, at least one failed or undelivered
+)
+
+func (s BatchStatus) String() string { return string(s) }
+
+// BatchCounts holds the number of notifications in a batch per status.
+type BatchCounts map[Status]int
+
+// Total returns the number of notifications in the batch.
+func (c BatchCounts) Total() int {
+	total := 0
+	for _, n := range c {
+		total += n
+	}
+	return total
+}
+
+// Terminal returns the number of notifications that reached a terminal status.
+func (c BatchCounts) Terminal() int {
+	terminal := 0
+	for s, n := range c {
+		if s.Terminal() {
+			terminal += n
+		}
+	}
+	return terminal
+}
+
+// Status derives the batch status from its counts. An audience batch stays in
+// progress until it is fully expanded; one that ended up empty is completed.
+func (b *Batch) Status() BatchStatus {
+	total := b.Counts.Total()
+	if b.Expanding || (total == 0 && b.CompletedAt == nil) || b.Counts.Terminal() < total {
+		return BatchStatusInProgress
+	}
+	if b.Counts[StatusFailed] > 0 || b.Counts[StatusUndelivered] > 0 {
+		return BatchStatusPartiallyFailed
+	}
+	return BatchStatusCompleted
+}
+
+// CompletionPercentage returns the share of terminal notifications (0-100).
+func (b *Batch) CompletionPercentage() float64 {
+	total := b.Counts.Total()
+	if total == 0 {
+		return 0
+	}
+	return float64(b.Counts.Terminal()) / float64(total) * 100.0
+}
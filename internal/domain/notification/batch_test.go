@@ -0,0 +1,85 @@
+package notification
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBatchCounts_TotalAndTerminal(t *testing.T) {
+	counts := BatchCounts{
+		StatusPending:   1,
+		StatusQueued:    2,
+		StatusSent:      3,
+		StatusFailed:    4,
+		StatusCancelled: 5,
+	}
+
+	if got := counts.Total(); got != 15 {
+		t.Errorf("BatchCounts.Total() = %d, want 15", got)
+	}
+	if got := counts.Terminal(); got != 12 {
+		t.Errorf("BatchCounts.Terminal() = %d, want 12", got)
+	}
+}
+
+func TestBatch_Status(t *testing.T) {
+	tests := []struct {
+		name   string
+		counts BatchCounts
+		want   BatchStatus
+	}{
+		{"Empty batch", BatchCounts{}, BatchStatusInProgress},
+		{"Pending items", BatchCounts{StatusPending: 1, StatusSent: 2}, BatchStatusInProgress},
+		{"Queued items", BatchCounts{StatusQueued: 1, StatusFailed: 2}, BatchStatusInProgress},
+		{"All sent", BatchCounts{StatusSent: 3}, BatchStatusCompleted},
+		{"Sent and cancelled", BatchCounts{StatusSent: 2, StatusCancelled: 1}, BatchStatusCompleted},
+		{"Some failed", BatchCounts{StatusSent: 2, StatusFailed: 1}, BatchStatusPartiallyFailed},
+		{"All failed", BatchCounts{StatusFailed: 3}, BatchStatusPartiallyFailed},
+		{"Delivered and sent", BatchCounts{StatusDelivered: 2, StatusSent: 1}, BatchStatusCompleted},
+		{"Some undelivered", BatchCounts{StatusDelivered: 2, StatusUndelivered: 1}, BatchStatusPartiallyFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := &Batch{Counts: tt.counts}
+			if got := b.Status(); got != tt.want {
+				t.Errorf("Batch.Status() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBatch_Status_Expansion(t *testing.T) {
+	now := time.Now()
+
+	expanding := &Batch{Expanding: true, Counts: BatchCounts{StatusSent: 3}}
+	if got := expanding.Status(); got != BatchStatusInProgress {
+		t.Errorf("expected an expanding batch to be in progress, got %v", got)
+	}
+	empty := &Batch{Counts: BatchCounts{}, CompletedAt: &now}
+	if got := empty.Status(); got != BatchStatusCompleted {
+		t.Errorf("expected an expanded empty batch to be completed, got %v", got)
+	}
+}
+
+func TestBatch_CompletionPercentage(t *testing.T) {
+	tests := []struct {
+		name   string
+		counts BatchCounts
+		want   float64
+	}{
+		{"Empty batch", BatchCounts{}, 0},
+		{"Nothing terminal", BatchCounts{StatusPending: 2, StatusQueued: 2}, 0},
+		{"Half terminal", BatchCounts{StatusQueued: 2, StatusSent: 1, StatusFailed: 1}, 50},
+		{"All terminal", BatchCounts{StatusSent: 1, StatusCancelled: 3}, 100},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := &Batch{Counts: tt.counts}
+			if got := b.CompletionPercentage(); got != tt.want {
+				t.Errorf("Batch.CompletionPercentage() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,21 +1,176 @@
 package notification
 
-// Channel is the delivery channel (SMS, Email, Push).
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Channel is the delivery channel (SMS, Email, Push, in-app, chat).
 type Channel string
 
 const (
 	ChannelSMS   Channel = "sms"
 	ChannelEmail Channel = "email"
 	ChannelPush  Channel = "push"
+	// ChannelInApp is delivered to the user's inbox in this service instead of a
+	// provider; the recipient is the user ID.
+	ChannelInApp Channel = "inapp"
+	// ChannelChat posts to a chat workspace through an incoming webhook; the
+	// recipient is a webhook URL or a workspace#channel reference (see ParseChatRecipient).
+	ChannelChat Channel = "chat"
 )
 
-func (c Channel) Valid() bool {
-	switch c {
-	case ChannelSMS, ChannelEmail, ChannelPush:
-		return true
-	default:
-		return false
+// Providers a channel can be bound to. The worker maps each to a delivery client;
+// ProviderInbox stores the notification in the recipient's inbox instead.
+const (
+	ProviderDefault = "default"
+	ProviderInbox   = "inbox"
+	ProviderChat    = "chat"
+)
+
+// ChannelSpec declares a channel: its name, limits, validation, queue and the
+// provider that delivers it. Everything that differs between channels derives from it.
+type ChannelSpec struct {
+	Name             Channel
+	MaxContentLength int
+	// ValidateRecipient and ValidateContent check channel-specific formats beyond
+	// the length limits; nil accepts anything.
+	ValidateRecipient func(recipient string) error
+	ValidateContent   func(content string) error
+	// Queue is the base name of the channel's queues; the routing key is Name and
+	// the dead letter queue Queue + ".dlq".
+	Queue    string
+	Provider string
+	// ProfileAddress returns a user's address on the channel from their profile's
+	// contact details, or "" if they have none; nil means user_id sends never
+	// resolve to the channel.
+	ProfileAddress func(c Contact) string
+	// ProfileRank places the channel among those tried, lowest first, for users
+	// without a preference; 0 uses it only when a preference names it.
+	ProfileRank int
+}
+
+// Contact is the contact details of a user profile that channels address.
+type Contact struct {
+	UserID     string
+	Phone      string
+	Email      string
+	PushTokens []string // most recently registered device last
+}
+
+var (
+	channelSpecs = make(map[Channel]ChannelSpec)
+	channelOrder []Channel
+)
+
+func init() {
+	RegisterChannel(ChannelSpec{
+		Name:             ChannelSMS,
+		MaxContentLength: MaxContentLengthSMS,
+		Queue:            "notifications.sms",
+		Provider:         ProviderDefault,
+		ProfileAddress:   func(c Contact) string { return c.Phone },
+		ProfileRank:      3,
+	})
+	RegisterChannel(ChannelSpec{
+		Name:             ChannelEmail,
+		MaxContentLength: MaxContentLengthEmail,
+		Queue:            "notifications.email",
+		Provider:         ProviderDefault,
+		ProfileAddress:   func(c Contact) string { return c.Email },
+		ProfileRank:      2,
+	})
+	RegisterChannel(ChannelSpec{
+		Name:             ChannelPush,
+		MaxContentLength: MaxContentLengthPush,
+		Queue:            "notifications.push",
+		Provider:         ProviderDefault,
+		ProfileAddress:   latestPushToken,
+		ProfileRank:      1,
+	})
+	RegisterChannel(ChannelSpec{
+		Name:             ChannelInApp,
+		MaxContentLength: MaxContentLengthInApp,
+		Queue:            "notifications.inapp",
+		Provider:         ProviderInbox,
+		ProfileAddress:   func(c Contact) string { return c.UserID },
+	})
+	RegisterChannel(ChannelSpec{
+		Name:              ChannelChat,
+		MaxContentLength:  MaxContentLengthChat,
+		ValidateRecipient: validateChatRecipient,
+		ValidateContent:   validateChatContent,
+		Queue:             "notifications.chat",
+		Provider:          ProviderChat,
+	})
+}
+
+// RegisterChannel adds a channel. It must be called from an init function, before
+// the registry is read; it panics on an incomplete or duplicate spec.
+func RegisterChannel(spec ChannelSpec) {
+	if spec.Name == "" || spec.MaxContentLength <= 0 || spec.Queue == "" || spec.Provider == "" {
+		panic(fmt.Sprintf("notification: incomplete spec for channel %q", spec.Name))
+	}
+	if _, dup := channelSpecs[spec.Name]; dup {
+		panic(fmt.Sprintf("notification: channel %q registered twice", spec.Name))
 	}
+	channelSpecs[spec.Name] = spec
+	channelOrder = append(channelOrder, spec.Name)
+}
+
+// latestPushToken sends push to the most recently registered device.
+func latestPushToken(c Contact) string {
+	if len(c.PushTokens) == 0 {
+		return ""
+	}
+	return c.PushTokens[len(c.PushTokens)-1]
+}
+
+// LookupChannel returns the spec of a registered channel.
+func LookupChannel(c Channel) (ChannelSpec, bool) {
+	spec, ok := channelSpecs[c]
+	return spec, ok
+}
+
+// Channels returns every registered channel's spec in registration order.
+func Channels() []ChannelSpec {
+	specs := make([]ChannelSpec, len(channelOrder))
+	for i, name := range channelOrder {
+		specs[i] = channelSpecs[name]
+	}
+	return specs
+}
+
+// ProfileChannels returns the channels tried for users without a preference, in
+// ProfileRank order.
+func ProfileChannels() []Channel {
+	var ranked []ChannelSpec
+	for _, spec := range Channels() {
+		if spec.ProfileRank > 0 && spec.ProfileAddress != nil {
+			ranked = append(ranked, spec)
+		}
+	}
+	sort.SliceStable(ranked, func(i, j int) bool { return ranked[i].ProfileRank < ranked[j].ProfileRank })
+	out := make([]Channel, len(ranked))
+	for i, spec := range ranked {
+		out[i] = spec.Name
+	}
+	return out
+}
+
+// ChannelList returns the registered channel names for messages, e.g. "sms, email, push".
+func ChannelList() string {
+	names := make([]string, len(channelOrder))
+	for i, name := range channelOrder {
+		names[i] = string(name)
+	}
+	return strings.Join(names, ", ")
+}
+
+func (c Channel) Valid() bool {
+	_, ok := channelSpecs[c]
+	return ok
 }
 
 func (c Channel) String() string { return string(c) }
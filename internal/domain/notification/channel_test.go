@@ -11,6 +11,8 @@ func TestChannel_Valid(t *testing.T) {
 		{"SMS channel", ChannelSMS, true},
 		{"Email channel", ChannelEmail, true},
 		{"Push channel", ChannelPush, true},
+		{"In-app channel", ChannelInApp, true},
+		{"Chat channel", ChannelChat, true},
 		{"Invalid channel", Channel("invalid"), false},
 		{"Empty channel", Channel(""), false},
 		{"Uppercase SMS", Channel("SMS"), false},
@@ -44,3 +46,55 @@ func TestChannel_String(t *testing.T) {
 		})
 	}
 }
+
+func TestChannels_Registry(t *testing.T) {
+	specs := Channels()
+	if len(specs) != 5 || specs[0].Name != ChannelSMS || specs[4].Name != ChannelChat {
+		t.Fatalf("expected the built-in channels in order, got %+v", specs)
+	}
+	if ChannelList() != "sms, email, push, inapp, chat" {
+		t.Errorf("unexpected channel list %q", ChannelList())
+	}
+	spec, ok := LookupChannel(ChannelInApp)
+	if !ok || spec.Provider != ProviderInbox || spec.Queue != "notifications.inapp" {
+		t.Errorf("unexpected in-app spec %+v", spec)
+	}
+	if _, ok := LookupChannel("fax"); ok {
+		t.Error("expected unknown channels to be missing")
+	}
+}
+
+func TestRegisterChannel_Rejects(t *testing.T) {
+	tests := []struct {
+		name string
+		spec ChannelSpec
+	}{
+		{"Duplicate", ChannelSpec{Name: ChannelSMS, MaxContentLength: 10, Queue: "q", Provider: ProviderDefault}},
+		{"No queue", ChannelSpec{Name: "fax", MaxContentLength: 10, Provider: ProviderDefault}},
+		{"No limit", ChannelSpec{Name: "fax", Queue: "q", Provider: ProviderDefault}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Error("expected a panic")
+				}
+			}()
+			RegisterChannel(tt.spec)
+		})
+	}
+}
+
+func TestProfileChannels(t *testing.T) {
+	got := ProfileChannels()
+	want := []Channel{ChannelPush, ChannelEmail, ChannelSMS}
+	if len(got) != len(want) {
+		t.Fatalf("ProfileChannels() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("ProfileChannels() = %v, want %v", got, want)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package notification
+
+import (
+	"encoding/json"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// MaxChatBlocks is the most blocks a chat message may carry.
+const MaxChatBlocks = 50
+
+var (
+	chatWorkspacePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,63}$`)
+	chatChannelPattern   = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]{0,79}$`)
+)
+
+// ChatRecipient is where a chat message is posted: either a webhook URL given
+// directly, or a channel of a workspace whose webhook the service is configured with.
+type ChatRecipient struct {
+	WebhookURL string // set for a direct webhook
+	Workspace  string // set for a channel reference; "default" when omitted
+	Channel    string // channel name without the leading #
+}
+
+// ParseChatRecipient accepts an https webhook URL, or a channel reference
+// "workspace#channel" ("#channel" uses the default workspace).
+func ParseChatRecipient(s string) (ChatRecipient, error) {
+	s = strings.TrimSpace(s)
+	if strings.HasPrefix(s, "https://") {
+		u, err := url.Parse(s)
+		if err != nil || u.Host == "" || u.User != nil {
+			return ChatRecipient{}, ErrInvalidChatRecipient
+		}
+		return ChatRecipient{WebhookURL: s}, nil
+	}
+	workspace, channel, ok := strings.Cut(s, "#")
+	if !ok {
+		return ChatRecipient{}, ErrInvalidChatRecipient
+	}
+	if workspace == "" {
+		workspace = "default"
+	}
+	workspace, channel = strings.ToLower(workspace), strings.ToLower(channel)
+	if !chatWorkspacePattern.MatchString(workspace) || !chatChannelPattern.MatchString(channel) {
+		return ChatRecipient{}, ErrInvalidChatRecipient
+	}
+	return ChatRecipient{Workspace: workspace, Channel: channel}, nil
+}
+
+// ChatBlocks is chat content written as a Block Kit document instead of Markdown.
+// Blocks are passed to the provider as given; Text is the notification fallback.
+type ChatBlocks struct {
+	Text   string            `json:"text,omitempty"`
+	Blocks []json.RawMessage `json:"blocks"`
+}
+
+// IsChatBlocks reports whether chat content is a Block Kit document rather than Markdown.
+func IsChatBlocks(content string) bool {
+	return strings.HasPrefix(strings.TrimSpace(content), "{")
+}
+
+// ParseChatBlocks parses a Block Kit document: an object with 1-MaxChatBlocks
+// blocks, each an object with a type.
+func ParseChatBlocks(content string) (*ChatBlocks, error) {
+	var doc ChatBlocks
+	if err := json.Unmarshal([]byte(content), &doc); err != nil {
+		return nil, ErrInvalidChatBlocks
+	}
+	if len(doc.Blocks) == 0 || len(doc.Blocks) > MaxChatBlocks {
+		return nil, ErrInvalidChatBlocks
+	}
+	for _, raw := range doc.Blocks {
+		var block struct {
+			Type string `json:"type"`
+		}
+		if err := json.Unmarshal(raw, &block); err != nil || block.Type == "" {
+			return nil, ErrInvalidChatBlocks
+		}
+	}
+	return &doc, nil
+}
+
+// RedactRecipient returns the recipient as it may be shown back to API clients. A
+// chat webhook URL is a credential, so only its host is kept.
+func RedactRecipient(ch Channel, recipient string) string {
+	if ch != ChannelChat {
+		return recipient
+	}
+	r, err := ParseChatRecipient(recipient)
+	if err != nil || r.WebhookURL == "" {
+		return recipient
+	}
+	u, _ := url.Parse(r.WebhookURL)
+	return "https://" + u.Host + "/[redacted]"
+}
+
+func validateChatRecipient(recipient string) error {
+	_, err := ParseChatRecipient(recipient)
+	return err
+}
+
+// validateChatContent accepts Markdown, or a well-formed Block Kit document.
+func validateChatContent(content string) error {
+	if !IsChatBlocks(content) {
+		return nil
+	}
+	_, err := ParseChatBlocks(content)
+	return err
+}
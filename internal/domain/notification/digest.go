@@ -0,0 +1,25 @@
+package notification
+
+import "time"
+
+const (
+	MinDigestWindow = time.Minute
+	MaxDigestWindow = 24 * time.Hour
+)
+
+// DigestPolicy collects a category's low priority notifications to the same
+// recipient and channel into one summary, sent Window after the first of them.
+type DigestPolicy struct {
+	Window time.Duration
+	// TemplateID renders the summary; empty, or a template deleted since, uses the
+	// default one.
+	TemplateID string
+}
+
+// Validate checks the window.
+func (p DigestPolicy) Validate() error {
+	if p.Window < MinDigestWindow || p.Window > MaxDigestWindow {
+		return ErrInvalidDigestPolicy
+	}
+	return nil
+}
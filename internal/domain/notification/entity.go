@@ -5,6 +5,7 @@ import "time"
 // Notification is the core aggregate: one notification request.
 type Notification struct {
 	ID             string
+	TenantID       string
 	BatchID        *string
 	Recipient      string
 	Channel        Channel
@@ -12,17 +13,86 @@ type Notification struct {
 	Priority       Priority
 	Status         Status
 	IdempotencyKey *string
+	ClientID       *string // API client that created the notification
 	CreatedAt      time.Time
 	UpdatedAt      time.Time
 	SentAt         *time.Time
 	FailureReason  *string
+	Resolution     *Resolution // set when sent to a user ID instead of an address
+	// DeliveryWindow holds back non-urgent deliveries outside these hours; nil
+	// delivers at any time.
+	DeliveryWindow *DeliveryWindow
+	DeferredUntil  *time.Time // set while deferred
+	// Fallback is set on the parent of a fallback chain; ParentID on its children.
+	Fallback *Fallback
+	ParentID *string
+	// DigestDue is when a buffered notification's digest is sent; DigestID links
+	// a digested one to the digest that summarized it.
+	DigestDue *time.Time
+	DigestID  *string
+	// TemplateID is the template the content was rendered from, if any.
+	TemplateID *string
+	// Locale is the locale of the content variant sent; empty when the content was
+	// not localized or no variant matched and the default content was sent.
+	Locale string
+	// Tracking adds an open pixel and click tracking to HTML email at send time;
+	// OpenedAt and ClickedAt are the first open and click.
+	Tracking  bool
+	OpenedAt  *time.Time
+	ClickedAt *time.Time
 }
 
 // Batch represents a batch of notifications (up to MaxBatchSize).
 type Batch struct {
 	ID             string
+	TenantID       string
 	IdempotencyKey *string
-	CreatedAt      time.Time
+	ClientID       *string // API client that created the batch
+	AudienceID     *string // set for batches sent to an audience
+	// Fingerprint hashes the items of the request that created the batch under
+	// IdempotencyKey; empty for batches created without one or before it was kept.
+	Fingerprint string
+	// Expanding is true while an audience batch is still being filled with a
+	// notification per member.
+	Expanding   bool
+	Counts      BatchCounts
+	CreatedAt   time.Time
+	CompletedAt *time.Time // set once every notification in the batch is terminal
+}
+
+// HistoryEvent is something that happened to a notification besides a delivery attempt.
+type HistoryEvent string
+
+const (
+	HistoryDeferred HistoryEvent = "deferred" // parked outside its delivery window
+	HistoryReleased HistoryEvent = "released" // requeued once the window opened
+
+	// Fallback chain events, recorded on the parent.
+	HistoryStepStarted       HistoryEvent = "step_started"
+	HistoryStepFailed        HistoryEvent = "step_failed"
+	HistoryStepTimedOut      HistoryEvent = "step_timed_out"
+	HistoryFallbackSucceeded HistoryEvent = "fallback_succeeded"
+	HistoryFallbackExhausted HistoryEvent = "fallback_exhausted"
+
+	HistoryBuffered HistoryEvent = "buffered" // held for its category's digest
+	HistoryDigested HistoryEvent = "digested" // summarized by a digest, named in the detail
+
+	HistoryReceipt HistoryEvent = "receipt" // provider delivery receipt, outcome in the detail
+
+	// Engagement with tracked emails.
+	HistoryOpened  HistoryEvent = "opened"  // first open of the email
+	HistoryClicked HistoryEvent = "clicked" // a link clicked, the URL in the detail
+)
+
+func (e HistoryEvent) String() string { return string(e) }
+
+// HistoryEntry is one event in a notification's history.
+type HistoryEntry struct {
+	ID             string
+	NotificationID string
+	Event          HistoryEvent
+	Detail         string
+	OccurredAt     time.Time
 }
 
 // DeliveryAttempt records one delivery attempt (retry and observability).
@@ -36,3 +106,22 @@ type DeliveryAttempt struct {
 	ErrorMessage   *string
 	CreatedAt      time.Time
 }
+
+// ResolutionSource is the preference a user-addressed notification's channel came from.
+type ResolutionSource string
+
+const (
+	ResolvedByCategory ResolutionSource = "category" // the category's own preference
+	ResolvedByDefault  ResolutionSource = "default"  // the user's default preference
+	ResolvedByProfile  ResolutionSource = "profile"  // no preference; first channel with an address
+)
+
+func (s ResolutionSource) String() string { return string(s) }
+
+// Resolution records how a notification sent to a user ID got its channel and
+// recipient, for auditing. The recipient is the address that was used.
+type Resolution struct {
+	UserID   string
+	Category string
+	Source   ResolutionSource
+}
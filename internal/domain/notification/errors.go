@@ -3,11 +3,29 @@ package notification
 import "errors"
 
 var (
-	ErrNotFound         = errors.New("notification or batch not found")
-	ErrInvalidChannel   = errors.New("invalid channel")
-	ErrInvalidPriority  = errors.New("invalid priority")
-	ErrInvalidContent   = errors.New("invalid content: character limits or required fields")
-	ErrDuplicateRequest = errors.New("duplicate request: idempotency key already used")
-	ErrBatchTooLarge    = errors.New("batch size exceeds maximum (1000)")
-	ErrAlreadyTerminal  = errors.New("notification already in terminal state")
+	ErrNotFound             = errors.New("notification or batch not found")
+	ErrInvalidChannel       = errors.New("invalid channel")
+	ErrInvalidPriority      = errors.New("invalid priority")
+	ErrInvalidContent       = errors.New("invalid content: character limits or required fields")
+	ErrDuplicateRequest     = errors.New("duplicate request: idempotency key already used")
+	ErrIdempotencyConflict  = errors.New("idempotency key already used with a different payload")
+	ErrBatchTooLarge        = errors.New("batch size exceeds maximum (1000)")
+	ErrAlreadyTerminal      = errors.New("notification already in terminal state")
+	ErrNotOwner             = errors.New("notification or batch belongs to another client")
+	ErrQuotaExceeded        = errors.New("daily channel quota exceeded")
+	ErrInvalidCursor        = errors.New("invalid pagination cursor")
+	ErrImportNotFound       = errors.New("import job not found")
+	ErrInvalidImportFormat  = errors.New("invalid import format")
+	ErrImportTooLarge       = errors.New("import file too large")
+	ErrInvalidWindow        = errors.New("invalid delivery window: start and end must be different HH:MM times and the time zone known")
+	ErrInvalidDigestPolicy  = errors.New("invalid digest policy: window must be between 1m and 24h")
+	ErrInvalidReceipt       = errors.New("invalid delivery receipt: message id and a delivered or undelivered status are required")
+	ErrReceiptTooEarly      = errors.New("delivery receipt arrived before the notification was marked sent")
+	ErrInvalidTrackingLink  = errors.New("invalid or tampered tracking link")
+	ErrInvalidChatRecipient = errors.New("chat recipient must be an https incoming webhook URL or a workspace#channel reference such as ops#alerts")
+	ErrInvalidChatBlocks    = errors.New("chat content starting with { must be a Block Kit message: an object with 1-50 blocks, each with a type")
+	ErrInvalidVariants      = errors.New("invalid content variants: 1-20 non-empty variants keyed by BCP 47 locale tags such as tr-TR")
+	ErrInvalidLocale        = errors.New("invalid locale: use a BCP 47 tag such as tr-TR")
+	ErrNoVariant            = errors.New("no content variant for the recipient's locale or the fallback locales, and no default content")
+	ErrInvalidFallback      = errors.New("invalid fallback chain: 2-5 steps with a channel, a recipient, a timeout of at most 24h and content within each channel's limit")
 )
@@ -0,0 +1,76 @@
+package notification
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	MinFallbackSteps   = 2
+	MaxFallbackSteps   = 5
+	MaxFallbackTimeout = 24 * time.Hour
+)
+
+// FallbackStep is one channel of a fallback chain.
+type FallbackStep struct {
+	Channel   Channel
+	Recipient string
+	// Timeout moves on to the next step when this one has not been delivered within
+	// it; zero counts the step as delivered once sent and waits until it fails.
+	Timeout time.Duration
+}
+
+func (s FallbackStep) String() string {
+	return fmt.Sprintf("%s to %s", s.Channel, s.Recipient)
+}
+
+// Fallback is the chain of a parent notification: its steps are delivered one at a
+// time as child notifications until one is delivered. The parent stays queued while
+// the chain runs, then takes the channel and recipient of the step that got through.
+type Fallback struct {
+	Steps    []FallbackStep
+	Current  int        // index of the step being tried
+	ChildID  string     // child notification delivering the current step
+	Deadline *time.Time // when the current step times out; nil waits for it to fail
+}
+
+// ValidateFallbackSteps checks that steps form a chain that can deliver content.
+func ValidateFallbackSteps(steps []FallbackStep, content string) error {
+	if len(steps) < MinFallbackSteps || len(steps) > MaxFallbackSteps {
+		return ErrInvalidFallback
+	}
+	for _, s := range steps {
+		if !s.Channel.Valid() || len(content) > MaxContentLength(s.Channel) || ValidateContent(s.Channel, content) != nil {
+			return ErrInvalidFallback
+		}
+		if s.Recipient == "" || len(s.Recipient) > MaxRecipientLength || ValidateRecipient(s.Channel, s.Recipient) != nil {
+			return ErrInvalidFallback
+		}
+		if s.Timeout < 0 || s.Timeout > MaxFallbackTimeout {
+			return ErrInvalidFallback
+		}
+	}
+	return nil
+}
+
+// Step returns the step being tried.
+func (f *Fallback) Step() FallbackStep { return f.Steps[f.Current] }
+
+// HasNext reports whether another step follows the current one.
+func (f *Fallback) HasNext() bool { return f.Current+1 < len(f.Steps) }
+
+// DeadlineFrom returns when the current step times out if it starts at t, or nil
+// when it has no timeout.
+func (f *Fallback) DeadlineFrom(t time.Time) *time.Time {
+	timeout := f.Step().Timeout
+	if timeout == 0 {
+		return nil
+	}
+	deadline := t.Add(timeout)
+	return &deadline
+}
+
+// Describe names the current step for history entries, e.g. "step 2/3 (sms to +90...)".
+func (f *Fallback) Describe() string {
+	return fmt.Sprintf("step %d/%d (%s)", f.Current+1, len(f.Steps), f.Step())
+}
@@ -0,0 +1,66 @@
+package notification
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestValidateFallbackSteps(t *testing.T) {
+	push := FallbackStep{Channel: ChannelPush, Recipient: "device-token", Timeout: 5 * time.Minute}
+	sms := FallbackStep{Channel: ChannelSMS, Recipient: "+905551234567"}
+	email := FallbackStep{Channel: ChannelEmail, Recipient: "user@example.com"}
+
+	tests := []struct {
+		name    string
+		steps   []FallbackStep
+		content string
+		wantErr bool
+	}{
+		{"Push, SMS, email", []FallbackStep{push, sms, email}, "Your account was locked", false},
+		{"Single step", []FallbackStep{push}, "Hi", true},
+		{"Too many steps", []FallbackStep{push, sms, email, push, sms, email}, "Hi", true},
+		{"Unknown channel", []FallbackStep{push, {Channel: "fax", Recipient: "123"}}, "Hi", true},
+		{"Missing recipient", []FallbackStep{push, {Channel: ChannelSMS}}, "Hi", true},
+		{"Negative timeout", []FallbackStep{{Channel: ChannelPush, Recipient: "t", Timeout: -time.Second}, sms}, "Hi", true},
+		{"Timeout too long", []FallbackStep{{Channel: ChannelPush, Recipient: "t", Timeout: 25 * time.Hour}, sms}, "Hi", true},
+		{"Chat channel reference", []FallbackStep{push, {Channel: ChannelChat, Recipient: "ops#alerts"}}, "Hi", false},
+		{"Malformed chat recipient", []FallbackStep{push, {Channel: ChannelChat, Recipient: "alerts"}}, "Hi", true},
+		{"Content too long for SMS", []FallbackStep{push, sms}, strings.Repeat("a", MaxContentLengthSMS+1), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateFallbackSteps(tt.steps, tt.content)
+			if tt.wantErr != (err != nil) {
+				t.Errorf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidFallback) {
+				t.Errorf("expected ErrInvalidFallback, got %v", err)
+			}
+		})
+	}
+}
+
+func TestFallback_Steps(t *testing.T) {
+	f := &Fallback{Steps: []FallbackStep{
+		{Channel: ChannelPush, Recipient: "device-token", Timeout: 5 * time.Minute},
+		{Channel: ChannelSMS, Recipient: "+905551234567"},
+	}}
+	start := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	if d := f.DeadlineFrom(start); d == nil || !d.Equal(start.Add(5*time.Minute)) {
+		t.Errorf("expected push to time out at 12:05, got %v", d)
+	}
+	if !f.HasNext() || f.Describe() != "step 1/2 (push to device-token)" {
+		t.Errorf("unexpected first step: %s", f.Describe())
+	}
+	f.Current++
+	if d := f.DeadlineFrom(start); d != nil {
+		t.Errorf("expected no timeout on sms, got %v", d)
+	}
+	if f.HasNext() {
+		t.Error("expected sms to be the last step")
+	}
+}
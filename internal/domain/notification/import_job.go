@@ -0,0 +1,64 @@
+package notification
+
+import "time"
+
+// ImportFormat is the file format of a bulk import.
+type ImportFormat string
+
+const (
+	ImportFormatNDJSON ImportFormat = "ndjson" // one JSON object per line
+	ImportFormatCSV    ImportFormat = "csv"    // header row naming the columns
+)
+
+func (f ImportFormat) Valid() bool {
+	return f == ImportFormatNDJSON || f == ImportFormatCSV
+}
+
+func (f ImportFormat) String() string { return string(f) }
+
+// ImportStatus is the state of a bulk import job.
+type ImportStatus string
+
+const (
+	ImportStatusPending    ImportStatus = "pending"    // uploaded, waiting to be processed
+	ImportStatusProcessing ImportStatus = "processing" // rows are being turned into batches
+	ImportStatusCompleted  ImportStatus = "completed"  // every row was accepted or rejected
+	ImportStatusFailed     ImportStatus = "failed"     // stopped early; see FailureReason
+)
+
+func (s ImportStatus) String() string { return string(s) }
+
+// Terminal returns true once the job will not change any more.
+func (s ImportStatus) Terminal() bool {
+	return s == ImportStatusCompleted || s == ImportStatusFailed
+}
+
+// ImportJob is a bulk upload of notifications, created in batches of at most
+// MaxBatchSize.
+type ImportJob struct {
+	ID            string
+	TenantID      string
+	ClientID      *string // API client that uploaded the file
+	Format        ImportFormat
+	Status        ImportStatus
+	TotalRows     int // rows read so far
+	AcceptedRows  int
+	RejectedRows  int
+	DuplicateRows int // idempotency key already used
+	BatchIDs      []string
+	FailureReason *string
+	// LeaseExpiresAt is when the job is failed unless the process holding it renews
+	// the lease first.
+	LeaseExpiresAt *time.Time
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	CompletedAt    *time.Time
+}
+
+// RowError describes why one row of an import was rejected. Row is the row's line
+// number in the uploaded file.
+type RowError struct {
+	Row     int
+	Field   string // empty when the whole row is at fault
+	Message string
+}
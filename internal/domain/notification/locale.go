@@ -0,0 +1,76 @@
+package notification
+
+import (
+	"regexp"
+	"strings"
+)
+
+// MaxContentVariants is the most locale variants a notification or template may carry.
+const MaxContentVariants = 20
+
+var localePattern = regexp.MustCompile(`^[a-z]{2,3}(-[A-Za-z0-9]{2,8})*$`)
+
+// ValidLocale reports whether tag is a BCP 47 language tag such as "tr" or "tr-TR".
+func ValidLocale(tag string) bool {
+	return localePattern.MatchString(tag)
+}
+
+// LocaleChain returns the locales tried for locale, most specific first: the tag,
+// its shorter prefixes ("tr-TR" then "tr"), then fallback. Repeats are dropped.
+func LocaleChain(locale string, fallback []string) []string {
+	var chain []string
+	seen := make(map[string]bool)
+	add := func(tag string) {
+		key := strings.ToLower(tag)
+		if tag != "" && !seen[key] {
+			seen[key] = true
+			chain = append(chain, tag)
+		}
+	}
+	for tag := locale; tag != ""; {
+		add(tag)
+		i := strings.LastIndex(tag, "-")
+		if i < 0 {
+			break
+		}
+		tag = tag[:i]
+	}
+	for _, tag := range fallback {
+		add(tag)
+	}
+	return chain
+}
+
+// Variants holds content in several languages, keyed by BCP 47 locale.
+type Variants map[string]string
+
+// Validate checks that there are 1-MaxContentVariants variants, each keyed by a
+// different locale tag and not empty. Channel limits are checked by the caller.
+func (v Variants) Validate() error {
+	if len(v) == 0 || len(v) > MaxContentVariants {
+		return ErrInvalidVariants
+	}
+	seen := make(map[string]bool, len(v))
+	for locale, content := range v {
+		key := strings.ToLower(locale)
+		if !ValidLocale(locale) || content == "" || seen[key] {
+			return ErrInvalidVariants
+		}
+		seen[key] = true
+	}
+	return nil
+}
+
+// Resolve returns the locale and content of the first variant in locale's chain
+// (see LocaleChain). Locales match regardless of case. ok is false when no variant
+// is in the chain.
+func (v Variants) Resolve(locale string, fallback []string) (string, string, bool) {
+	for _, tag := range LocaleChain(locale, fallback) {
+		for key, content := range v {
+			if strings.EqualFold(key, tag) {
+				return key, content, true
+			}
+		}
+	}
+	return "", "", false
+}
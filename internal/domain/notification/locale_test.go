@@ -0,0 +1,85 @@
+package notification
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestLocaleChain(t *testing.T) {
+	tests := []struct {
+		name     string
+		locale   string
+		fallback []string
+		want     []string
+	}{
+		{"Region then language then fallback", "tr-TR", []string{"en"}, []string{"tr-TR", "tr", "en"}},
+		{"Script and region", "zh-Hant-TW", nil, []string{"zh-Hant-TW", "zh-Hant", "zh"}},
+		{"No locale", "", []string{"en-GB", "en"}, []string{"en-GB", "en"}},
+		{"Fallback repeats the locale", "en-US", []string{"EN"}, []string{"en-US", "en"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := LocaleChain(tt.locale, tt.fallback); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("LocaleChain(%q) = %v, want %v", tt.locale, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVariants_Resolve(t *testing.T) {
+	v := Variants{"tr": "Siparişiniz yolda", "en": "Your order is on its way", "de-DE": "Ihre Bestellung ist unterwegs"}
+	fallback := []string{"en"}
+
+	tests := []struct {
+		name       string
+		locale     string
+		wantLocale string
+		wantOK     bool
+	}{
+		{"Language of a regional locale", "tr-TR", "tr", true},
+		{"Exact match ignoring case", "de-de", "de-DE", true},
+		{"Unknown locale falls back", "fr-FR", "en", true},
+		{"No locale falls back", "", "en", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			locale, content, ok := v.Resolve(tt.locale, fallback)
+			if ok != tt.wantOK || locale != tt.wantLocale || content != v[tt.wantLocale] {
+				t.Errorf("Resolve(%q) = %q, %q, %v; want %q", tt.locale, locale, content, ok, tt.wantLocale)
+			}
+		})
+	}
+
+	if _, _, ok := (Variants{"de": "Hallo"}).Resolve("tr-TR", fallback); ok {
+		t.Error("expected no variant outside the chain")
+	}
+}
+
+func TestVariants_Validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		variants Variants
+		wantErr  bool
+	}{
+		{"Valid", Variants{"tr-TR": "Merhaba", "en": "Hello"}, false},
+		{"Empty", Variants{}, true},
+		{"Bad locale", Variants{"Turkish": "Merhaba"}, true},
+		{"Empty content", Variants{"en": ""}, true},
+		{"Same locale twice", Variants{"de-DE": "Hallo", "de-de": "Hallo"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.variants.Validate()
+			if tt.wantErr != (err != nil) {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidVariants) {
+				t.Errorf("expected ErrInvalidVariants, got %v", err)
+			}
+		})
+	}
+}
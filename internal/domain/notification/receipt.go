@@ -0,0 +1,42 @@
+package notification
+
+import (
+	"strings"
+	"time"
+)
+
+// MaxReceiptReasonLength caps the provider's reason kept with an undelivered receipt.
+const MaxReceiptReasonLength = 500
+
+// DeliveryReceipt is a provider's report of what happened to a sent message,
+// matched to the notification by the message ID the provider returned on send.
+type DeliveryReceipt struct {
+	MessageID  string
+	Status     Status // StatusDelivered or StatusUndelivered
+	Reason     string // provider error code or text, if undelivered
+	OccurredAt time.Time
+}
+
+// Validate checks the message ID and status and trims the reason to its limit.
+func (r *DeliveryReceipt) Validate() error {
+	r.MessageID = strings.TrimSpace(r.MessageID)
+	if r.MessageID == "" {
+		return ErrInvalidReceipt
+	}
+	if r.Status != StatusDelivered && r.Status != StatusUndelivered {
+		return ErrInvalidReceipt
+	}
+	r.Reason = strings.TrimSpace(r.Reason)
+	if len(r.Reason) > MaxReceiptReasonLength {
+		r.Reason = strings.ToValidUTF8(r.Reason[:MaxReceiptReasonLength], "")
+	}
+	return nil
+}
+
+// Detail describes the receipt for the notification's history.
+func (r *DeliveryReceipt) Detail() string {
+	if r.Reason == "" {
+		return r.Status.String()
+	}
+	return r.Status.String() + ": " + r.Reason
+}
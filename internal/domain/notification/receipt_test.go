@@ -0,0 +1,49 @@
+package notification
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestDeliveryReceipt_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		receipt DeliveryReceipt
+		wantErr bool
+	}{
+		{"Delivered", DeliveryReceipt{MessageID: "msg-1", Status: StatusDelivered}, false},
+		{"Undelivered with reason", DeliveryReceipt{MessageID: "msg-1", Status: StatusUndelivered, Reason: "absent subscriber"}, false},
+		{"Missing message id", DeliveryReceipt{MessageID: "  ", Status: StatusDelivered}, true},
+		{"Sent is not a receipt status", DeliveryReceipt{MessageID: "msg-1", Status: StatusSent}, true},
+		{"Unknown status", DeliveryReceipt{MessageID: "msg-1", Status: Status("read")}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.receipt.Validate()
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidReceipt) {
+					t.Errorf("expected ErrInvalidReceipt, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestDeliveryReceipt_ValidateTruncatesReason(t *testing.T) {
+	r := DeliveryReceipt{MessageID: "msg-1", Status: StatusUndelivered, Reason: strings.Repeat("é", MaxReceiptReasonLength)}
+	if err := r.Validate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(r.Reason) > MaxReceiptReasonLength {
+		t.Errorf("reason length = %d, want at most %d", len(r.Reason), MaxReceiptReasonLength)
+	}
+	if r.Detail() != "undelivered: "+r.Reason {
+		t.Errorf("Detail() = %q", r.Detail())
+	}
+}
@@ -1,24 +1,72 @@
 package notification
 
+import (
+	"errors"
+	"fmt"
+)
+
 // Content and batch limits (assessment: character limits, required fields).
 const (
 	MaxContentLengthSMS   = 1600
 	MaxContentLengthEmail = 100_000
 	MaxContentLengthPush  = 4_096
+	MaxContentLengthInApp = 10_000
+	MaxContentLengthChat  = 12_000
 	MaxRecipientLength    = 512
 	MaxBatchSize          = 1000
 )
 
-// MaxContentLength returns the max content length for the channel.
+// MaxContentLength returns the max content length for the channel; unknown
+// channels get the email limit.
 func MaxContentLength(c Channel) int {
-	switch c {
-	case ChannelSMS:
-		return MaxContentLengthSMS
-	case ChannelEmail:
-		return MaxContentLengthEmail
-	case ChannelPush:
-		return MaxContentLengthPush
-	default:
-		return MaxContentLengthEmail
+	if spec, ok := LookupChannel(c); ok {
+		return spec.MaxContentLength
+	}
+	return MaxContentLengthEmail
+}
+
+// ValidateRecipient checks the channel-specific form of a recipient, beyond
+// the MaxRecipientLength every channel shares.
+func ValidateRecipient(c Channel, recipient string) error {
+	if spec, ok := LookupChannel(c); ok && spec.ValidateRecipient != nil {
+		return spec.ValidateRecipient(recipient)
+	}
+	return nil
+}
+
+// ValidateContent checks the channel-specific format of content, beyond
+// MaxContentLength.
+func ValidateContent(c Channel, content string) error {
+	if spec, ok := LookupChannel(c); ok && spec.ValidateContent != nil {
+		return spec.ValidateContent(content)
+	}
+	return nil
+}
+
+// ValidateVariants checks content variants, and every variant against the
+// channel's content limit and format. No variants are valid.
+func ValidateVariants(c Channel, variants Variants) error {
+	if len(variants) == 0 {
+		return nil
+	}
+	if err := variants.Validate(); err != nil {
+		return err
+	}
+	for _, content := range variants {
+		if len(content) > MaxContentLength(c) {
+			return ErrInvalidContent
+		}
+		if err := ValidateContent(c, content); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// VariantsMessage describes why ValidateVariants rejected variants for channel c.
+func VariantsMessage(c Channel, err error) string {
+	if errors.Is(err, ErrInvalidContent) {
+		return fmt.Sprintf("every content variant must be at most %d characters for channel %s", MaxContentLength(c), c)
 	}
+	return err.Error()
 }
@@ -1,6 +1,10 @@
 package notification
 
-import "testing"
+import (
+	"errors"
+	"strings"
+	"testing"
+)
 
 func TestMaxContentLength(t *testing.T) {
 	tests := []struct {
@@ -11,6 +15,8 @@ func TestMaxContentLength(t *testing.T) {
 		{"SMS channel", ChannelSMS, MaxContentLengthSMS},
 		{"Email channel", ChannelEmail, MaxContentLengthEmail},
 		{"Push channel", ChannelPush, MaxContentLengthPush},
+		{"In-app channel", ChannelInApp, MaxContentLengthInApp},
+		{"Chat channel", ChannelChat, MaxContentLengthChat},
 		{"Invalid channel defaults to Email", Channel("invalid"), MaxContentLengthEmail},
 		{"Empty channel defaults to Email", Channel(""), MaxContentLengthEmail},
 	}
@@ -41,3 +47,35 @@ func TestMaxContentLength_Values(t *testing.T) {
 		t.Errorf("MaxBatchSize = %d, want 1000", MaxBatchSize)
 	}
 }
+
+func TestValidateVariants(t *testing.T) {
+	tests := []struct {
+		name     string
+		channel  Channel
+		variants Variants
+		wantErr  error
+	}{
+		{"No variants", ChannelSMS, nil, nil},
+		{"Within limits", ChannelSMS, Variants{"tr": "Merhaba", "en": "Hello"}, nil},
+		{"One variant too long", ChannelSMS, Variants{"tr": "Merhaba", "de": strings.Repeat("a", MaxContentLengthSMS+1)}, ErrInvalidContent},
+		{"Malformed chat blocks", ChannelChat, Variants{"en": `{"blocks":[]}`}, ErrInvalidChatBlocks},
+		{"Bad locale", ChannelSMS, Variants{"english": "Hello"}, ErrInvalidVariants},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateVariants(tt.channel, tt.variants); !errors.Is(err, tt.wantErr) {
+				t.Errorf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestVariantsMessage(t *testing.T) {
+	if got := VariantsMessage(ChannelSMS, ErrInvalidContent); got != "every content variant must be at most 1600 characters for channel sms" {
+		t.Errorf("unexpected message for a too long variant: %q", got)
+	}
+	if got := VariantsMessage(ChannelSMS, ErrInvalidVariants); got != ErrInvalidVariants.Error() {
+		t.Errorf("expected the error text for other errors, got %q", got)
+	}
+}
@@ -4,16 +4,21 @@ package notification
 type Status string
 
 const (
-	StatusPending   Status = "pending"   // created, not yet queued
-	StatusQueued    Status = "queued"    // published to queue
-	StatusSent      Status = "sent"      // delivered successfully
-	StatusFailed    Status = "failed"    // delivery failed after retries
-	StatusCancelled Status = "cancelled" // cancelled before/during processing
+	StatusPending     Status = "pending"     // created, not yet queued
+	StatusQueued      Status = "queued"      // published to queue
+	StatusDeferred    Status = "deferred"    // parked by the worker until its delivery window opens
+	StatusBuffered    Status = "buffered"    // waiting for its recipient's digest
+	StatusDigested    Status = "digested"    // summarized by a digest instead of being sent
+	StatusSent        Status = "sent"        // accepted by the provider
+	StatusDelivered   Status = "delivered"   // provider receipt confirmed delivery to the device
+	StatusUndelivered Status = "undelivered" // provider receipt reported the delivery failed
+	StatusFailed      Status = "failed"      // delivery failed after retries
+	StatusCancelled   Status = "cancelled"   // cancelled before/during processing
 )
 
 func (s Status) Valid() bool {
 	switch s {
-	case StatusPending, StatusQueued, StatusSent, StatusFailed, StatusCancelled:
+	case StatusPending, StatusQueued, StatusDeferred, StatusBuffered, StatusDigested, StatusSent, StatusDelivered, StatusUndelivered, StatusFailed, StatusCancelled:
 		return true
 	default:
 		return false
@@ -22,7 +27,18 @@ func (s Status) Valid() bool {
 
 func (s Status) String() string { return string(s) }
 
-// Terminal returns true if no further processing should occur.
+// Statuses returns every known status in lifecycle order.
+func Statuses() []Status {
+	return []Status{StatusPending, StatusQueued, StatusDeferred, StatusBuffered, StatusDigested, StatusSent, StatusDelivered, StatusUndelivered, StatusFailed, StatusCancelled}
+}
+
+// Terminal returns true if no further processing should occur. A sent
+// notification may still move to delivered or undelivered on a provider receipt.
 func (s Status) Terminal() bool {
-	return s == StatusSent || s == StatusFailed || s == StatusCancelled
+	switch s {
+	case StatusSent, StatusDelivered, StatusUndelivered, StatusFailed, StatusCancelled, StatusDigested:
+		return true
+	default:
+		return false
+	}
 }
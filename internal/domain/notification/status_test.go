@@ -10,7 +10,12 @@ func TestStatus_Valid(t *testing.T) {
 	}{
 		{"Pending status", StatusPending, true},
 		{"Queued status", StatusQueued, true},
+		{"Deferred status", StatusDeferred, true},
+		{"Buffered status", StatusBuffered, true},
+		{"Digested status", StatusDigested, true},
 		{"Sent status", StatusSent, true},
+		{"Delivered status", StatusDelivered, true},
+		{"Undelivered status", StatusUndelivered, true},
 		{"Failed status", StatusFailed, true},
 		{"Cancelled status", StatusCancelled, true},
 		{"Invalid status", Status("invalid"), false},
@@ -57,7 +62,12 @@ func TestStatus_Terminal(t *testing.T) {
 	}{
 		{"Pending is not terminal", StatusPending, false},
 		{"Queued is not terminal", StatusQueued, false},
+		{"Deferred is not terminal", StatusDeferred, false},
+		{"Buffered is not terminal", StatusBuffered, false},
+		{"Digested is terminal", StatusDigested, true},
 		{"Sent is terminal", StatusSent, true},
+		{"Delivered is terminal", StatusDelivered, true},
+		{"Undelivered is terminal", StatusUndelivered, true},
 		{"Failed is terminal", StatusFailed, true},
 		{"Cancelled is terminal", StatusCancelled, true},
 	}
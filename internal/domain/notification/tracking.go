@@ -0,0 +1,71 @@
+package notification
+
+import (
+	"html"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var (
+	htmlPattern = regexp.MustCompile(`(?i)<(html|body|a|p|div|table|br|img|span)\b`)
+	// linkPattern matches the absolute http(s) href of an anchor, quoted either way.
+	linkPattern = regexp.MustCompile(`(?i)(<a\b[^>]*?\bhref\s*=\s*)(?:"(https?://[^"]*)"|'(https?://[^']*)')`)
+	bodyEnd     = regexp.MustCompile(`(?i)</body\s*>`)
+)
+
+// Engagement is an open or click of a tracked email.
+type Engagement struct {
+	Event      HistoryEvent // HistoryOpened or HistoryClicked
+	URL        string       // the link clicked
+	OccurredAt time.Time
+}
+
+// EngagementStats counts the tracked emails of a batch or template that were sent,
+// and how many of them were opened or clicked.
+type EngagementStats struct {
+	Tracked int64
+	Opened  int64
+	Clicked int64
+}
+
+// OpenRate returns the share of tracked emails opened (0-100).
+func (s EngagementStats) OpenRate() float64 { return rate(s.Opened, s.Tracked) }
+
+// ClickRate returns the share of tracked emails with a clicked link (0-100).
+func (s EngagementStats) ClickRate() float64 { return rate(s.Clicked, s.Tracked) }
+
+func rate(part, total int64) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(part) / float64(total) * 100.0
+}
+
+// IsHTML reports whether email content is HTML rather than plain text.
+func IsHTML(content string) bool {
+	return htmlPattern.MatchString(content)
+}
+
+// InjectTracking rewrites every absolute link of HTML content to clickURL of its
+// target and adds an invisible pixel loading pixelURL before </body>, or at the end.
+func InjectTracking(content, pixelURL string, clickURL func(target string) string) string {
+	out := linkPattern.ReplaceAllStringFunc(content, func(m string) string {
+		parts := linkPattern.FindStringSubmatch(m)
+		target := parts[2] + parts[3]
+		return parts[1] + `"` + html.EscapeString(clickURL(html.UnescapeString(target))) + `"`
+	})
+	pixel := `<img src="` + html.EscapeString(pixelURL) + `" width="1" height="1" alt="" style="display:none">`
+	if loc := lastMatch(bodyEnd, out); loc != nil {
+		return out[:loc[0]] + pixel + out[loc[0]:]
+	}
+	return strings.TrimRight(out, "\n") + pixel
+}
+
+func lastMatch(re *regexp.Regexp, s string) []int {
+	all := re.FindAllStringIndex(s, -1)
+	if len(all) == 0 {
+		return nil
+	}
+	return all[len(all)-1]
+}
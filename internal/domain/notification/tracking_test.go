@@ -0,0 +1,68 @@
+package notification
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestIsHTML(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    bool
+	}{
+		{"Document", "<html><body>Hi</body></html>", true},
+		{"Fragment with link", `Hi, <a href="https://example.com">see</a>`, true},
+		{"Plain text", "Your code is 1234", false},
+		{"Comparison", "1 < 2 and 3 > 2", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsHTML(tt.content); got != tt.want {
+				t.Errorf("IsHTML() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInjectTracking(t *testing.T) {
+	click := func(target string) string {
+		return "https://t.example/c?url=" + url.QueryEscape(target)
+	}
+	content := `<html><body><a class="btn" href="https://shop.example/p?a=1&amp;b=2">Buy</a> <a href='http://news.example'>News</a> <a href="mailto:help@example.com">Help</a> <a href="#top">Top</a></body></html>`
+
+	got := InjectTracking(content, "https://t.example/o?sig=x&y", click)
+
+	for _, want := range []string{
+		`<a class="btn" href="https://t.example/c?url=https%3A%2F%2Fshop.example%2Fp%3Fa%3D1%26b%3D2">Buy</a>`,
+		`<a href="https://t.example/c?url=http%3A%2F%2Fnews.example">News</a>`,
+		`<a href="mailto:help@example.com">Help</a>`,
+		`<a href="#top">Top</a>`,
+		`<img src="https://t.example/o?sig=x&amp;y" width="1" height="1" alt="" style="display:none"></body></html>`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected %q in\n%s", want, got)
+		}
+	}
+}
+
+func TestInjectTracking_Fragment(t *testing.T) {
+	got := InjectTracking("<p>Hello</p>\n", "https://t.example/o", func(target string) string { return target })
+
+	want := `<p>Hello</p><img src="https://t.example/o" width="1" height="1" alt="" style="display:none">`
+	if got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestEngagementStats_Rates(t *testing.T) {
+	s := EngagementStats{Tracked: 8, Opened: 4, Clicked: 2}
+	if s.OpenRate() != 50 || s.ClickRate() != 25 {
+		t.Errorf("unexpected rates %v %v", s.OpenRate(), s.ClickRate())
+	}
+	if (EngagementStats{}).OpenRate() != 0 {
+		t.Error("expected zero rate without tracked emails")
+	}
+}
@@ -0,0 +1,7 @@
+package notification
+
+// FieldError describes why one field of a notification request is invalid.
+type FieldError struct {
+	Field   string
+	Message string
+}
@@ -0,0 +1,104 @@
+package notification
+
+import (
+	"fmt"
+	"time"
+	_ "time/tzdata" // the runtime images ship without a zoneinfo database
+)
+
+// DeliveryWindow is the time of day a non-urgent notification may be delivered,
+// e.g. 09:00–21:00. A window whose end is before its start spans midnight.
+type DeliveryWindow struct {
+	Start int // minutes after midnight, inclusive
+	End   int // minutes after midnight, exclusive
+	// TimeZone is the IANA zone the times are in. Empty means the recipient's
+	// zone when it is known, otherwise UTC.
+	TimeZone string
+}
+
+// ParseDeliveryWindow parses a window from HH:MM start and end times.
+func ParseDeliveryWindow(start, end, timeZone string) (*DeliveryWindow, error) {
+	s, err := parseClock(start)
+	if err != nil {
+		return nil, err
+	}
+	e, err := parseClock(end)
+	if err != nil {
+		return nil, err
+	}
+	w := &DeliveryWindow{Start: s, End: e, TimeZone: timeZone}
+	if err := w.Validate(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+func parseClock(s string) (int, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, ErrInvalidWindow
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// Validate checks the bounds and the time zone.
+func (w *DeliveryWindow) Validate() error {
+	const day = 24 * 60
+	if w.Start < 0 || w.Start >= day || w.End < 0 || w.End >= day || w.Start == w.End {
+		return ErrInvalidWindow
+	}
+	if _, err := time.LoadLocation(w.TimeZone); err != nil || w.TimeZone == "Local" {
+		return ErrInvalidWindow
+	}
+	return nil
+}
+
+// StartClock returns the start as HH:MM.
+func (w *DeliveryWindow) StartClock() string { return clock(w.Start) }
+
+// EndClock returns the end as HH:MM.
+func (w *DeliveryWindow) EndClock() string { return clock(w.End) }
+
+func clock(minutes int) string { return fmt.Sprintf("%02d:%02d", minutes/60, minutes%60) }
+
+func (w *DeliveryWindow) String() string {
+	zone := w.TimeZone
+	if zone == "" {
+		zone = "UTC"
+	}
+	return fmt.Sprintf("%s-%s %s", w.StartClock(), w.EndClock(), zone)
+}
+
+// location returns the window's zone; windows are validated, so UTC is only a guard.
+func (w *DeliveryWindow) location() *time.Location {
+	loc, err := time.LoadLocation(w.TimeZone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// Open reports whether t falls inside the window.
+func (w *DeliveryWindow) Open(t time.Time) bool {
+	local := t.In(w.location())
+	m := local.Hour()*60 + local.Minute()
+	if w.Start < w.End {
+		return m >= w.Start && m < w.End
+	}
+	return m >= w.Start || m < w.End
+}
+
+// NextOpen returns the first time at or after t that the window is open.
+func (w *DeliveryWindow) NextOpen(t time.Time) time.Time {
+	if w.Open(t) {
+		return t
+	}
+	local := t.In(w.location())
+	y, mo, d := local.Date()
+	// time.Date normalises a start that falls into a DST gap to a valid instant.
+	open := time.Date(y, mo, d, w.Start/60, w.Start%60, 0, 0, local.Location())
+	if !open.After(t) {
+		open = time.Date(y, mo, d+1, w.Start/60, w.Start%60, 0, 0, local.Location())
+	}
+	return open
+}
@@ -0,0 +1,69 @@
+package notification
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseDeliveryWindow(t *testing.T) {
+	tests := []struct {
+		name             string
+		start, end, zone string
+		wantErr          bool
+	}{
+		{"Daytime", "09:00", "21:00", "Europe/Istanbul", false},
+		{"Overnight", "22:00", "06:30", "", false},
+		{"Same start and end", "09:00", "09:00", "", true},
+		{"Bad clock", "9am", "21:00", "", true},
+		{"Out of range", "24:00", "06:00", "", true},
+		{"Unknown zone", "09:00", "21:00", "Mars/Olympus", true},
+		{"Local zone", "09:00", "21:00", "Local", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w, err := ParseDeliveryWindow(tt.start, tt.end, tt.zone)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidWindow) {
+					t.Errorf("expected ErrInvalidWindow, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if w.StartClock() != tt.start || w.EndClock() != tt.end {
+				t.Errorf("expected %s-%s, got %s", tt.start, tt.end, w)
+			}
+		})
+	}
+}
+
+func TestDeliveryWindow_NextOpen(t *testing.T) {
+	istanbul, _ := time.LoadLocation("Europe/Istanbul")
+	day, _ := ParseDeliveryWindow("09:00", "21:00", "Europe/Istanbul")
+	night, _ := ParseDeliveryWindow("22:00", "06:00", "")
+
+	tests := []struct {
+		name   string
+		window *DeliveryWindow
+		at     time.Time
+		want   time.Time
+	}{
+		{"Inside", day, time.Date(2024, 3, 1, 12, 0, 0, 0, istanbul), time.Date(2024, 3, 1, 12, 0, 0, 0, istanbul)},
+		{"Before start", day, time.Date(2024, 3, 1, 3, 0, 0, 0, istanbul), time.Date(2024, 3, 1, 9, 0, 0, 0, istanbul)},
+		{"At end", day, time.Date(2024, 3, 1, 21, 0, 0, 0, istanbul), time.Date(2024, 3, 2, 9, 0, 0, 0, istanbul)},
+		{"Other zone", day, time.Date(2024, 3, 1, 5, 30, 0, 0, time.UTC), time.Date(2024, 3, 1, 6, 0, 0, 0, time.UTC)},
+		{"Overnight after midnight", night, time.Date(2024, 3, 1, 2, 0, 0, 0, time.UTC), time.Date(2024, 3, 1, 2, 0, 0, 0, time.UTC)},
+		{"Overnight daytime", night, time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC), time.Date(2024, 3, 1, 22, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.window.NextOpen(tt.at); !got.Equal(tt.want) {
+				t.Errorf("NextOpen(%v) = %v, want %v", tt.at, got, tt.want)
+			}
+		})
+	}
+}
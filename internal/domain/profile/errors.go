@@ -0,0 +1,12 @@
+package profile
+
+import "errors"
+
+var (
+	ErrNotFound        = errors.New("user profile not found")
+	ErrInvalidUserID   = errors.New("invalid user id")
+	ErrInvalidProfile  = errors.New("invalid user profile")
+	ErrInvalidCategory = errors.New("invalid category")
+	ErrOptedOut        = errors.New("user opted out of this category")
+	ErrUnreachable     = errors.New("user has no address on any allowed channel")
+)
@@ -0,0 +1,160 @@
+package profile
+
+import (
+	"regexp"
+	"time"
+
+	"github.com/semih-yildiz/notification-service/internal/domain/notification"
+)
+
+const (
+	MaxUserIDLength = 128
+	MaxPushTokens   = 10
+	// DefaultCategory holds the channel preference of categories without their own.
+	DefaultCategory = "default"
+)
+
+var categoryPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_.-]{0,63}$`)
+
+// Profile is a user of the calling system with their addresses and channel
+// preferences, so notifications can be sent by user ID.
+type Profile struct {
+	UserID     string // the caller's own ID, unique per tenant
+	TenantID   string
+	Phone      string
+	Email      string
+	PushTokens []string // most recently registered device last
+	Locale     string   // BCP 47, e.g. "tr-TR"; empty if unknown
+	TimeZone   string   // IANA, e.g. "Europe/Istanbul"; empty if unknown
+	// Preferences maps a category to the channels to use for it, most preferred
+	// first. An empty list opts the user out of the category.
+	Preferences map[string][]notification.Channel
+	// OptOuts are channels never used for the user, whatever the category.
+	OptOuts []notification.Channel
+	// TrackingOptOut sends the user's emails without open and click tracking.
+	TrackingOptOut bool
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+// Validate checks the profile's ID, addresses, locale, time zone and preferences.
+func (p *Profile) Validate() error {
+	if p.UserID == "" || len(p.UserID) > MaxUserIDLength {
+		return ErrInvalidUserID
+	}
+	for _, addr := range []string{p.Phone, p.Email} {
+		if len(addr) > notification.MaxRecipientLength {
+			return ErrInvalidProfile
+		}
+	}
+	if len(p.PushTokens) > MaxPushTokens {
+		return ErrInvalidProfile
+	}
+	for _, token := range p.PushTokens {
+		if token == "" || len(token) > notification.MaxRecipientLength {
+			return ErrInvalidProfile
+		}
+	}
+	if p.Locale != "" && !notification.ValidLocale(p.Locale) {
+		return ErrInvalidProfile
+	}
+	if _, err := time.LoadLocation(p.TimeZone); err != nil || p.TimeZone == "Local" {
+		return ErrInvalidProfile
+	}
+	for category, channels := range p.Preferences {
+		if err := ValidateCategory(category); err != nil {
+			return err
+		}
+		for _, ch := range channels {
+			if !ch.Valid() {
+				return ErrInvalidProfile
+			}
+		}
+	}
+	for _, ch := range p.OptOuts {
+		if !ch.Valid() {
+			return ErrInvalidProfile
+		}
+	}
+	return nil
+}
+
+// ValidateCategory checks that category is lowercase letters, digits, ".", "_" and
+// "-", at most 64 characters.
+func ValidateCategory(category string) error {
+	if !categoryPattern.MatchString(category) {
+		return ErrInvalidCategory
+	}
+	return nil
+}
+
+// Address returns the user's address on ch, or "" if they have none or the channel
+// has no ChannelSpec.ProfileAddress.
+func (p *Profile) Address(ch notification.Channel) string {
+	spec, ok := notification.LookupChannel(ch)
+	if !ok || spec.ProfileAddress == nil {
+		return ""
+	}
+	return spec.ProfileAddress(notification.Contact{UserID: p.UserID, Phone: p.Phone, Email: p.Email, PushTokens: p.PushTokens})
+}
+
+// Target is where a notification to the user goes.
+type Target struct {
+	Channel    notification.Channel
+	Address    string
+	Resolution notification.Resolution
+}
+
+// Resolve picks the channel and address for a notification in category: the first
+// channel of the category's preference, else of the default preference, else of
+// notification.ProfileChannels (push, email, sms), that the user has an address on, has not opted out of and
+// allowed accepts. A nil allowed accepts every channel.
+func (p *Profile) Resolve(category string, allowed func(notification.Channel) bool) (*Target, error) {
+	if err := ValidateCategory(category); err != nil {
+		return nil, err
+	}
+	source := notification.ResolvedByCategory
+	channels, ok := p.Preferences[category]
+	if !ok {
+		source = notification.ResolvedByDefault
+		channels, ok = p.Preferences[DefaultCategory]
+	}
+	if !ok {
+		source = notification.ResolvedByProfile
+		channels = notification.ProfileChannels()
+	}
+
+	optedOut := true
+	for _, ch := range channels {
+		if p.optedOut(ch) {
+			continue
+		}
+		optedOut = false
+		addr := p.Address(ch)
+		if addr == "" || (allowed != nil && !allowed(ch)) {
+			continue
+		}
+		return &Target{
+			Channel: ch,
+			Address: addr,
+			Resolution: notification.Resolution{
+				UserID:   p.UserID,
+				Category: category,
+				Source:   source,
+			},
+		}, nil
+	}
+	if optedOut {
+		return nil, ErrOptedOut
+	}
+	return nil, ErrUnreachable
+}
+
+func (p *Profile) optedOut(ch notification.Channel) bool {
+	for _, o := range p.OptOuts {
+		if o == ch {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,96 @@
+package profile
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/semih-yildiz/notification-service/internal/domain/notification"
+)
+
+func testProfile() *Profile {
+	return &Profile{
+		UserID:     "u-1",
+		Phone:      "+905551112233",
+		Email:      "u1@example.com",
+		PushTokens: []string{"old-device", "new-device"},
+		Preferences: map[string][]notification.Channel{
+			"marketing":     {notification.ChannelEmail},
+			"security":      {notification.ChannelSMS, notification.ChannelEmail},
+			"newsletter":    {},
+			DefaultCategory: {notification.ChannelPush, notification.ChannelSMS},
+		},
+	}
+}
+
+func TestResolve(t *testing.T) {
+	tests := []struct {
+		name       string
+		category   string
+		mutate     func(p *Profile)
+		allowed    func(notification.Channel) bool
+		wantCh     notification.Channel
+		wantAddr   string
+		wantSource notification.ResolutionSource
+		wantErr    error
+	}{
+		{"Category preference", "marketing", nil, nil, notification.ChannelEmail, "u1@example.com", notification.ResolvedByCategory, nil},
+		{"Default preference", "billing", nil, nil, notification.ChannelPush, "new-device", notification.ResolvedByDefault, nil},
+		{"No preferences", "billing", func(p *Profile) { p.Preferences = nil }, nil, notification.ChannelPush, "new-device", notification.ResolvedByProfile, nil},
+		{"In-app preference", "security", func(p *Profile) { p.Preferences["security"] = []notification.Channel{notification.ChannelInApp} }, nil, notification.ChannelInApp, "u-1", notification.ResolvedByCategory, nil},
+		{"Skips channel without address", "security", func(p *Profile) { p.Phone = "" }, nil, notification.ChannelEmail, "u1@example.com", notification.ResolvedByCategory, nil},
+		{"Skips opted-out channel", "security", func(p *Profile) { p.OptOuts = []notification.Channel{notification.ChannelSMS} }, nil, notification.ChannelEmail, "u1@example.com", notification.ResolvedByCategory, nil},
+		{"Skips disallowed channel", "security", nil, func(ch notification.Channel) bool { return ch != notification.ChannelSMS }, notification.ChannelEmail, "u1@example.com", notification.ResolvedByCategory, nil},
+		{"Category opt-out", "newsletter", nil, nil, "", "", "", ErrOptedOut},
+		{"Every channel opted out", "marketing", func(p *Profile) { p.OptOuts = []notification.Channel{notification.ChannelEmail} }, nil, "", "", "", ErrOptedOut},
+		{"No address", "marketing", func(p *Profile) { p.Email = "" }, nil, "", "", "", ErrUnreachable},
+		{"Invalid category", "Marketing!", nil, nil, "", "", "", ErrInvalidCategory},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := testProfile()
+			if tt.mutate != nil {
+				tt.mutate(p)
+			}
+			target, err := p.Resolve(tt.category, tt.allowed)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if err != nil {
+				return
+			}
+			if target.Channel != tt.wantCh || target.Address != tt.wantAddr || target.Resolution.Source != tt.wantSource {
+				t.Errorf("expected %s to %s by %s, got %+v", tt.wantCh, tt.wantAddr, tt.wantSource, target)
+			}
+			if target.Resolution.UserID != "u-1" || target.Resolution.Category != tt.category {
+				t.Errorf("expected the resolution to name the user and category, got %+v", target.Resolution)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		mutate  func(p *Profile)
+		wantErr error
+	}{
+		{"Valid", func(p *Profile) { p.Locale = "tr-TR" }, nil},
+		{"Missing user ID", func(p *Profile) { p.UserID = "" }, ErrInvalidUserID},
+		{"Bad locale", func(p *Profile) { p.Locale = "turkish!" }, ErrInvalidProfile},
+		{"Bad time zone", func(p *Profile) { p.TimeZone = "Mars/Olympus" }, ErrInvalidProfile},
+		{"Unknown channel", func(p *Profile) { p.Preferences["marketing"] = []notification.Channel{"fax"} }, ErrInvalidProfile},
+		{"Bad category", func(p *Profile) { p.Preferences["Bad Category"] = nil }, ErrInvalidCategory},
+		{"Empty push token", func(p *Profile) { p.PushTokens = []string{""} }, ErrInvalidProfile},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := testProfile()
+			tt.mutate(p)
+			if err := p.Validate(); !errors.Is(err, tt.wantErr) {
+				t.Errorf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
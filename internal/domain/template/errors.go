@@ -0,0 +1,12 @@
+package template
+
+import "errors"
+
+var (
+	ErrNotFound    = errors.New("template not found")
+	ErrInvalidName = errors.New("invalid template name")
+	ErrInvalidBody = errors.New("invalid template body")
+	// ErrInvalidVariants wraps why a template's locale variants are invalid.
+	ErrInvalidVariants = errors.New("invalid template variants")
+	ErrExists          = errors.New("template already exists")
+)
@@ -0,0 +1,117 @@
+package template
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/semih-yildiz/notification-service/internal/domain/notification"
+)
+
+const (
+	MaxNameLength = 100
+	MaxBodyLength = 10000
+)
+
+// DefaultDigestBody renders digests whose policy names no template.
+const DefaultDigestBody = `You have {{.Count}} new notifications{{range .Items}}
+- {{.Content}}{{end}}`
+
+// Template is a tenant's reusable content in Go text/template syntax, e.g. the
+// summary of a digest.
+type Template struct {
+	ID       string
+	TenantID string
+	Name     string // unique per tenant
+	Body     string
+	// Variants are translations of Body keyed by locale; Body is used when none
+	// matches the recipient's locale.
+	Variants  notification.Variants
+	ClientID  *string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// DigestData is what a digest template is rendered with.
+type DigestData struct {
+	Count     int
+	Category  string
+	Channel   string
+	Recipient string
+	Items     []DigestItem // oldest first
+}
+
+// DigestItem is one notification summarized by a digest.
+type DigestItem struct {
+	Content   string
+	CreatedAt time.Time
+}
+
+// ValidateName checks that name is set and at most MaxNameLength characters.
+func ValidateName(name string) error {
+	if name == "" || len(name) > MaxNameLength {
+		return ErrInvalidName
+	}
+	return nil
+}
+
+// ValidateBody checks that body is set, at most MaxBodyLength characters and
+// parses as a template.
+func ValidateBody(body string) error {
+	if strings.TrimSpace(body) == "" || len(body) > MaxBodyLength {
+		return ErrInvalidBody
+	}
+	if _, err := parse(body); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidBody, err)
+	}
+	return nil
+}
+
+// ValidateVariants checks each variant's locale and body; nil or empty variants are valid.
+func ValidateVariants(variants notification.Variants) error {
+	if len(variants) == 0 {
+		return nil
+	}
+	if err := variants.Validate(); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidVariants, err)
+	}
+	for locale, body := range variants {
+		if err := ValidateBody(body); err != nil {
+			return fmt.Errorf("%w: %s: %v", ErrInvalidVariants, locale, err)
+		}
+	}
+	return nil
+}
+
+// Localized returns the body for locale, tried along its fallback chain (see
+// notification.LocaleChain), and the variant's locale. Without a match it returns
+// Body and an empty locale.
+func (t *Template) Localized(locale string, fallback []string) (string, string) {
+	if variant, body, ok := t.Variants.Resolve(locale, fallback); ok {
+		return body, variant
+	}
+	return t.Body, ""
+}
+
+// Render executes the template with data.
+func (t *Template) Render(data any) (string, error) {
+	return Render(t.Body, data)
+}
+
+// Render executes body with data.
+func Render(body string, data any) (string, error) {
+	tmpl, err := parse(body)
+	if err != nil {
+		return "", err
+	}
+	var out strings.Builder
+	if err := tmpl.Execute(&out, data); err != nil {
+		return "", err
+	}
+	return out.String(), nil
+}
+
+func parse(body string) (*template.Template, error) {
+	return template.New("").Option("missingkey=error").Parse(body)
+}
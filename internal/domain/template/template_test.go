@@ -0,0 +1,90 @@
+package template
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestValidateBody(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		wantErr bool
+	}{
+		{"Plain text", "Hello", false},
+		{"Fields", "{{.Count}} new likes", false},
+		{"Blank", "  ", true},
+		{"Too long", strings.Repeat("a", MaxBodyLength+1), true},
+		{"Unclosed action", "{{.Count", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateBody(tt.body)
+			if tt.wantErr != (err != nil) {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidBody) {
+				t.Errorf("expected ErrInvalidBody, got %v", err)
+			}
+		})
+	}
+}
+
+func TestRender_DefaultDigest(t *testing.T) {
+	data := DigestData{Count: 2, Items: []DigestItem{{Content: "Ayse liked your post"}, {Content: "Can liked your post"}}}
+
+	got, err := Render(DefaultDigestBody, data)
+
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	want := "You have 2 new notifications\n- Ayse liked your post\n- Can liked your post"
+	if got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+	if _, err := Render("{{.Missing}}", data); err == nil {
+		t.Error("expected an error for an unknown field")
+	}
+}
+
+func TestTemplate_Localized(t *testing.T) {
+	tpl := &Template{Body: "You have {{.Count}} new notifications", Variants: map[string]string{
+		"tr": "{{.Count}} yeni bildiriminiz var",
+		"de": "Sie haben {{.Count}} neue Benachrichtigungen",
+	}}
+
+	tests := []struct {
+		name       string
+		locale     string
+		fallback   []string
+		wantLocale string
+		wantBody   string
+	}{
+		{"Language of a regional locale", "tr-TR", nil, "tr", tpl.Variants["tr"]},
+		{"Fallback locale", "fr", []string{"de"}, "de", tpl.Variants["de"]},
+		{"No match uses the body", "fr", []string{"en"}, "", tpl.Body},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, locale := tpl.Localized(tt.locale, tt.fallback)
+			if body != tt.wantBody || locale != tt.wantLocale {
+				t.Errorf("Localized(%q) = %q, %q; want %q, %q", tt.locale, body, locale, tt.wantBody, tt.wantLocale)
+			}
+		})
+	}
+}
+
+func TestValidateVariants(t *testing.T) {
+	if err := ValidateVariants(nil); err != nil {
+		t.Errorf("expected no variants to be valid, got %v", err)
+	}
+	if err := ValidateVariants(map[string]string{"tr": "{{.Count"}); !errors.Is(err, ErrInvalidVariants) {
+		t.Errorf("expected ErrInvalidVariants for a broken variant, got %v", err)
+	}
+	if err := ValidateVariants(map[string]string{"Turkish": "Merhaba"}); !errors.Is(err, ErrInvalidVariants) {
+		t.Errorf("expected ErrInvalidVariants for a bad locale, got %v", err)
+	}
+}
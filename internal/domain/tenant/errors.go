@@ -0,0 +1,11 @@
+package tenant
+
+import "errors"
+
+var (
+	ErrNotFound     = errors.New("tenant not found")
+	ErrInvalidID    = errors.New("invalid tenant id")
+	ErrInvalidName  = errors.New("invalid tenant name")
+	ErrInvalidQuota = errors.New("invalid quota: channel must be known and limit not negative")
+	ErrExists       = errors.New("tenant already exists")
+)
@@ -0,0 +1,88 @@
+package tenant
+
+import (
+	"time"
+
+	"github.com/semih-yildiz/notification-service/internal/domain/notification"
+	"github.com/semih-yildiz/notification-service/internal/domain/profile"
+)
+
+// DefaultID is the tenant that owns data created before tenants existed and the
+// bootstrap admin.
+const DefaultID = "default"
+
+// Tenant is a team sharing the deployment. Its data is isolated from other tenants.
+type Tenant struct {
+	ID   string
+	Name string
+	// DailyQuotas caps sends per channel per UTC day. Channels without an entry
+	// are unlimited.
+	DailyQuotas map[notification.Channel]int
+	// DeliveryWindows holds back non-urgent notifications of a category outside
+	// these hours, in the recipient's time zone unless the window names one.
+	DeliveryWindows map[string]notification.DeliveryWindow
+	// DigestPolicies batches low priority notifications of a category into one
+	// summary per recipient and channel.
+	DigestPolicies map[string]notification.DigestPolicy
+	CreatedAt      time.Time
+}
+
+// DailyQuota returns the daily limit for channel and whether one is set.
+func (t *Tenant) DailyQuota(ch notification.Channel) (limit int, limited bool) {
+	limit, limited = t.DailyQuotas[ch]
+	return limit, limited
+}
+
+// DeliveryWindow returns the delivery window of a category, if one is set.
+func (t *Tenant) DeliveryWindow(category string) (*notification.DeliveryWindow, bool) {
+	w, ok := t.DeliveryWindows[category]
+	if !ok {
+		return nil, false
+	}
+	return &w, true
+}
+
+// DigestPolicy returns the digest policy of a category, if one is set.
+func (t *Tenant) DigestPolicy(category string) (*notification.DigestPolicy, bool) {
+	p, ok := t.DigestPolicies[category]
+	if !ok {
+		return nil, false
+	}
+	return &p, true
+}
+
+// ValidateDeliveryWindows checks every category name and window.
+func ValidateDeliveryWindows(windows map[string]notification.DeliveryWindow) error {
+	for category, w := range windows {
+		if err := profile.ValidateCategory(category); err != nil {
+			return err
+		}
+		if err := w.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ValidateDigestPolicies checks every category name and policy.
+func ValidateDigestPolicies(policies map[string]notification.DigestPolicy) error {
+	for category, p := range policies {
+		if err := profile.ValidateCategory(category); err != nil {
+			return err
+		}
+		if err := p.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ValidateQuotas checks that every quota names a known channel and is not negative.
+func ValidateQuotas(quotas map[notification.Channel]int) error {
+	for ch, limit := range quotas {
+		if !ch.Valid() || limit < 0 {
+			return ErrInvalidQuota
+		}
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package tenant
+
+import (
+	"testing"
+	"time"
+
+	"github.com/semih-yildiz/notification-service/internal/domain/notification"
+)
+
+func TestTenant_DailyQuota(t *testing.T) {
+	tn := &Tenant{DailyQuotas: map[notification.Channel]int{notification.ChannelSMS: 1000}}
+
+	if limit, ok := tn.DailyQuota(notification.ChannelSMS); !ok || limit != 1000 {
+		t.Errorf("expected sms quota 1000, got %d (limited=%v)", limit, ok)
+	}
+	if _, ok := tn.DailyQuota(notification.ChannelEmail); ok {
+		t.Error("expected email to be unlimited")
+	}
+}
+
+func TestValidateQuotas(t *testing.T) {
+	tests := []struct {
+		name    string
+		quotas  map[notification.Channel]int
+		wantErr bool
+	}{
+		{"Valid", map[notification.Channel]int{notification.ChannelSMS: 10, notification.ChannelPush: 0}, false},
+		{"Empty", nil, false},
+		{"Unknown channel", map[notification.Channel]int{"fax": 10}, true},
+		{"Negative", map[notification.Channel]int{notification.ChannelEmail: -1}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateQuotas(tt.quotas); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateQuotas() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateDeliveryWindows(t *testing.T) {
+	tests := []struct {
+		name    string
+		windows map[string]notification.DeliveryWindow
+		wantErr bool
+	}{
+		{"Valid", map[string]notification.DeliveryWindow{"marketing": {Start: 9 * 60, End: 21 * 60}}, false},
+		{"Empty", nil, false},
+		{"Bad category", map[string]notification.DeliveryWindow{"Marketing!": {Start: 9 * 60, End: 21 * 60}}, true},
+		{"Bad window", map[string]notification.DeliveryWindow{"marketing": {Start: 9 * 60, End: 9 * 60}}, true},
+		{"Bad zone", map[string]notification.DeliveryWindow{"marketing": {Start: 9 * 60, End: 21 * 60, TimeZone: "Nowhere/City"}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateDeliveryWindows(tt.windows); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateDeliveryWindows() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateDigestPolicies(t *testing.T) {
+	tests := []struct {
+		name     string
+		policies map[string]notification.DigestPolicy
+		wantErr  bool
+	}{
+		{"Valid", map[string]notification.DigestPolicy{"likes": {Window: 15 * time.Minute}}, false},
+		{"Empty", nil, false},
+		{"Bad category", map[string]notification.DigestPolicy{"Likes!": {Window: 15 * time.Minute}}, true},
+		{"Window too short", map[string]notification.DigestPolicy{"likes": {Window: time.Second}}, true},
+		{"Window too long", map[string]notification.DigestPolicy{"likes": {Window: 48 * time.Hour}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateDigestPolicies(tt.policies); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateDigestPolicies() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
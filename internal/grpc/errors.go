@@ -0,0 +1,113 @@
+package grpc
+
+import (
+	"errors"
+	"fmt"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/semih-yildiz/notification-service/internal/api"
+	"github.com/semih-yildiz/notification-service/internal/domain/notification"
+	"github.com/semih-yildiz/notification-service/internal/domain/profile"
+)
+
+// toStatus maps domain errors to gRPC status errors, as mapNotificationError does
+// for HTTP. Invalid fields are reported as BadRequest details.
+func toStatus(err error) error {
+	switch err {
+	case notification.ErrNotFound:
+		return status.Error(codes.NotFound, "notification or batch not found")
+
+	case notification.ErrInvalidChannel:
+		return invalidArgument(api.ValidationError{Field: "channel", Message: "channel must be one of: " + notification.ChannelList()})
+
+	case notification.ErrInvalidWindow:
+		return invalidArgument(api.ValidationError{Field: "delivery_window", Message: api.DeliveryWindowMessage})
+
+	case notification.ErrInvalidFallback:
+		return invalidArgument(api.ValidationError{Field: "fallback", Message: notification.ErrInvalidFallback.Error()})
+
+	case notification.ErrInvalidPriority:
+		return invalidArgument(api.ValidationError{Field: "priority", Message: "priority must be one of: high, normal, low"})
+
+	case notification.ErrInvalidContent:
+		return invalidArgument(api.ValidationError{Field: "content", Message: "content is required and must be within the channel's character limit"})
+
+	case notification.ErrInvalidVariants, notification.ErrNoVariant:
+		return invalidArgument(api.ValidationError{Field: "content_variants", Message: err.Error()})
+
+	case notification.ErrInvalidLocale:
+		return invalidArgument(api.ValidationError{Field: "locale", Message: notification.ErrInvalidLocale.Error()})
+
+	case notification.ErrInvalidChatRecipient:
+		return invalidArgument(api.ValidationError{Field: "recipient", Message: notification.ErrInvalidChatRecipient.Error()})
+
+	case notification.ErrInvalidChatBlocks:
+		return invalidArgument(api.ValidationError{Field: "content", Message: notification.ErrInvalidChatBlocks.Error()})
+
+	case notification.ErrInvalidCursor:
+		return invalidArgument(api.ValidationError{Field: "cursor", Message: "cursor is invalid; use next_cursor from a previous page"})
+
+	case notification.ErrBatchTooLarge:
+		return invalidArgument(api.ValidationError{
+			Field:   "items",
+			Message: fmt.Sprintf("batch must contain 1-%d items", notification.MaxBatchSize),
+		})
+
+	case notification.ErrDuplicateRequest:
+		return status.Error(codes.Aborted, "duplicate request: idempotency key already used")
+
+	case notification.ErrIdempotencyConflict:
+		return status.Error(codes.FailedPrecondition, "idempotency key already used with a different payload")
+
+	case notification.ErrQuotaExceeded:
+		return status.Error(codes.ResourceExhausted, "daily channel quota exceeded for this tenant")
+
+	case notification.ErrNotOwner:
+		return status.Error(codes.PermissionDenied, "notification or batch belongs to another client")
+
+	case notification.ErrAlreadyTerminal:
+		return status.Error(codes.FailedPrecondition, "notification already in terminal state")
+
+	case profile.ErrNotFound:
+		return invalidArgument(api.ValidationError{Field: "user_id", Message: "no profile exists for this user_id"})
+
+	case profile.ErrInvalidCategory:
+		return invalidArgument(api.ValidationError{Field: "category", Message: api.CategoryMessage})
+
+	case profile.ErrOptedOut:
+		return status.Error(codes.FailedPrecondition, "user opted out of this category on every channel")
+
+	case profile.ErrUnreachable:
+		return status.Error(codes.FailedPrecondition, "user has no address on a channel this key can send on")
+	}
+
+	var verrs api.ValidationErrors
+	if errors.As(err, &verrs) {
+		return invalidArgument(verrs...)
+	}
+	return status.Error(codes.Internal, "internal server error")
+}
+
+// invalidArgument returns an InvalidArgument status listing every invalid field.
+// Batch item fields are named like items[3].content.
+func invalidArgument(errs ...api.ValidationError) error {
+	br := &errdetails.BadRequest{}
+	for _, ve := range errs {
+		field := ve.Field
+		if ve.Index != nil {
+			field = fmt.Sprintf("items[%d].%s", *ve.Index, ve.Field)
+		}
+		br.FieldViolations = append(br.FieldViolations, &errdetails.BadRequest_FieldViolation{
+			Field:       field,
+			Description: ve.Message,
+		})
+	}
+	st, err := status.New(codes.InvalidArgument, "validation failed").WithDetails(br)
+	if err != nil {
+		return status.Error(codes.InvalidArgument, "validation failed")
+	}
+	return st.Err()
+}
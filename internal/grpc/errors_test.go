@@ -0,0 +1,69 @@
+package grpc
+
+import (
+	"errors"
+	"testing"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/semih-yildiz/notification-service/internal/api"
+	"github.com/semih-yildiz/notification-service/internal/domain/notification"
+	"github.com/semih-yildiz/notification-service/internal/domain/profile"
+)
+
+func TestToStatus_Codes(t *testing.T) {
+	tests := []struct {
+		err  error
+		code codes.Code
+	}{
+		{notification.ErrNotFound, codes.NotFound},
+		{notification.ErrInvalidChannel, codes.InvalidArgument},
+		{notification.ErrInvalidCursor, codes.InvalidArgument},
+		{notification.ErrDuplicateRequest, codes.Aborted},
+		{notification.ErrIdempotencyConflict, codes.FailedPrecondition},
+		{notification.ErrQuotaExceeded, codes.ResourceExhausted},
+		{notification.ErrNotOwner, codes.PermissionDenied},
+		{notification.ErrAlreadyTerminal, codes.FailedPrecondition},
+		{notification.ErrInvalidWindow, codes.InvalidArgument},
+		{profile.ErrNotFound, codes.InvalidArgument},
+		{profile.ErrOptedOut, codes.FailedPrecondition},
+		{errors.New("db down"), codes.Internal},
+	}
+
+	for _, tt := range tests {
+		if got := status.Code(toStatus(tt.err)); got != tt.code {
+			t.Errorf("%v: expected %s, got %s", tt.err, tt.code, got)
+		}
+	}
+}
+
+func TestToStatus_FieldViolations(t *testing.T) {
+	index := 2
+	err := toStatus(api.ValidationErrors{
+		{Field: "channel", Message: "channel is required"},
+		{Field: "content", Message: "content is required", Index: &index},
+	})
+
+	st := status.Convert(err)
+	if st.Code() != codes.InvalidArgument {
+		t.Fatalf("expected InvalidArgument, got %s", st.Code())
+	}
+	if len(st.Details()) != 1 {
+		t.Fatalf("expected 1 detail, got %d", len(st.Details()))
+	}
+	br, ok := st.Details()[0].(*errdetails.BadRequest)
+	if !ok {
+		t.Fatalf("expected BadRequest detail, got %T", st.Details()[0])
+	}
+	want := []string{"channel", "items[2].content"}
+	if len(br.FieldViolations) != len(want) {
+		t.Fatalf("expected %d violations, got %d", len(want), len(br.FieldViolations))
+	}
+	for i, f := range want {
+		if br.FieldViolations[i].Field != f {
+			t.Errorf("violation %d: expected field %s, got %s", i, f, br.FieldViolations[i].Field)
+		}
+	}
+}
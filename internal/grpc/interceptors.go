@@ -0,0 +1,194 @@
+package grpc
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	notificationv1 "github.com/semih-yildiz/notification-service/api/proto/notification/v1"
+	"github.com/semih-yildiz/notification-service/internal/api"
+	"github.com/semih-yildiz/notification-service/internal/domain/auth"
+	sharedctx "github.com/semih-yildiz/notification-service/internal/shared/context"
+)
+
+// Metadata keys; gRPC metadata keys are lowercase.
+const (
+	MetadataCorrelationID = "x-correlation-id"
+	MetadataAPIKey        = "x-api-key"
+	MetadataRetryAfter    = "retry-after" // trailer of rate limited calls, in seconds
+	metadataAuthorization = "authorization"
+)
+
+// Authenticator resolves an API key to its client.
+type Authenticator interface {
+	Authenticate(ctx context.Context, key string) (*auth.Client, error)
+}
+
+// methodScopes are the scopes each method needs beyond a valid key. Creates check
+// the send scope of each channel themselves.
+var methodScopes = map[string]auth.Scope{
+	notificationv1.NotificationService_GetNotification_FullMethodName:    auth.ScopeRead,
+	notificationv1.NotificationService_ListNotifications_FullMethodName:  auth.ScopeRead,
+	notificationv1.NotificationService_StreamStatus_FullMethodName:       auth.ScopeRead,
+	notificationv1.NotificationService_CancelNotification_FullMethodName: auth.ScopeCancel,
+	notificationv1.NotificationService_CancelBatch_FullMethodName:        auth.ScopeCancel,
+}
+
+// UnaryInterceptor carries the correlation ID and authenticates unary calls.
+func UnaryInterceptor(a Authenticator) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, id := withCorrelationID(ctx)
+		_ = grpc.SetHeader(ctx, metadata.Pairs(MetadataCorrelationID, id))
+		ctx, err := authenticate(ctx, a, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamInterceptor carries the correlation ID and authenticates streaming calls.
+func StreamInterceptor(a Authenticator) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, id := withCorrelationID(ss.Context())
+		_ = ss.SetHeader(metadata.Pairs(MetadataCorrelationID, id))
+		ctx, err := authenticate(ctx, a, info.FullMethod)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// RateLimits are the per-client limits of the service's methods. They share the
+// REST API's store and policies, so a client's REST and gRPC calls count together.
+type RateLimits struct {
+	Store  api.RateLimitStore
+	Create api.RateLimitPolicy
+	Batch  api.RateLimitPolicy
+	Read   api.RateLimitPolicy
+}
+
+// policy returns the policy limiting method. Cancels are not limited, as in REST.
+func (l RateLimits) policy(method string) (api.RateLimitPolicy, bool) {
+	switch method {
+	case notificationv1.NotificationService_CreateNotification_FullMethodName:
+		return l.Create, true
+	case notificationv1.NotificationService_CreateBatch_FullMethodName:
+		return l.Batch, true
+	case notificationv1.NotificationService_GetNotification_FullMethodName,
+		notificationv1.NotificationService_ListNotifications_FullMethodName,
+		notificationv1.NotificationService_StreamStatus_FullMethodName:
+		return l.Read, true
+	}
+	return api.RateLimitPolicy{}, false
+}
+
+// exceeded counts the call against its client's bucket and returns the seconds to
+// wait once the client is over the limit, or 0. Store errors let calls through.
+func (l RateLimits) exceeded(ctx context.Context, method string) int {
+	p, ok := l.policy(method)
+	client := clientFromContext(ctx)
+	if !ok || l.Store == nil || !p.Enabled() || client == nil {
+		return 0
+	}
+	count, reset, err := l.Store.Hit(ctx, p.Key(client.ID), p.Window)
+	if err != nil || count <= p.Limit {
+		return 0
+	}
+	return max(int(math.Ceil(time.Until(reset).Seconds())), 1)
+}
+
+// UnaryRateLimitInterceptor rejects unary calls over their client's limit with
+// ResourceExhausted. It runs after UnaryInterceptor.
+func UnaryRateLimitInterceptor(l RateLimits) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if retryAfter := l.exceeded(ctx, info.FullMethod); retryAfter > 0 {
+			_ = grpc.SetTrailer(ctx, metadata.Pairs(MetadataRetryAfter, strconv.Itoa(retryAfter)))
+			return nil, rateLimited(retryAfter)
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamRateLimitInterceptor rejects streams over their client's limit with
+// ResourceExhausted. It runs after StreamInterceptor.
+func StreamRateLimitInterceptor(l RateLimits) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if retryAfter := l.exceeded(ss.Context(), info.FullMethod); retryAfter > 0 {
+			ss.SetTrailer(metadata.Pairs(MetadataRetryAfter, strconv.Itoa(retryAfter)))
+			return rateLimited(retryAfter)
+		}
+		return handler(srv, ss)
+	}
+}
+
+func rateLimited(retryAfter int) error {
+	return status.Errorf(codes.ResourceExhausted, "rate limit exceeded, retry in %d seconds", retryAfter)
+}
+
+// serverStream replaces the context of a stream.
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context { return s.ctx }
+
+// withCorrelationID stores the caller's correlation ID, or a new one, in ctx.
+func withCorrelationID(ctx context.Context) (context.Context, string) {
+	id := metadataValue(ctx, MetadataCorrelationID)
+	if id == "" {
+		b := make([]byte, 8)
+		_, _ = rand.Read(b)
+		id = hex.EncodeToString(b)
+	}
+	return context.WithValue(ctx, sharedctx.CorrelationIDKey(), id), id
+}
+
+// authenticate stores the calling client in ctx, scoped to its tenant, and checks
+// the method's scope.
+func authenticate(ctx context.Context, a Authenticator, method string) (context.Context, error) {
+	key := metadataValue(ctx, MetadataAPIKey)
+	if key == "" {
+		if token, ok := strings.CutPrefix(metadataValue(ctx, metadataAuthorization), "Bearer "); ok {
+			key = strings.TrimSpace(token)
+		}
+	}
+	client, err := a.Authenticate(ctx, key)
+	if errors.Is(err, auth.ErrInvalidKey) {
+		return nil, status.Error(codes.Unauthenticated, "invalid or missing api key")
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, "internal server error")
+	}
+	if scope, ok := methodScopes[method]; ok && !client.Can(scope) {
+		return nil, status.Error(codes.PermissionDenied, "api key lacks the "+scope.String()+" scope")
+	}
+	ctx = context.WithValue(ctx, sharedctx.APIClientKey(), client)
+	return sharedctx.WithTenantID(ctx, client.TenantID), nil
+}
+
+func metadataValue(ctx context.Context, key string) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if v := md.Get(key); len(v) > 0 {
+		return v[0]
+	}
+	return ""
+}
+
+// clientFromContext returns the authenticated client, or nil.
+func clientFromContext(ctx context.Context) *auth.Client {
+	client, _ := ctx.Value(sharedctx.APIClientKey()).(*auth.Client)
+	return client
+}
@@ -0,0 +1,125 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	notificationv1 "github.com/semih-yildiz/notification-service/api/proto/notification/v1"
+	"github.com/semih-yildiz/notification-service/internal/api"
+	"github.com/semih-yildiz/notification-service/internal/domain/auth"
+	sharedctx "github.com/semih-yildiz/notification-service/internal/shared/context"
+)
+
+type mockAuthenticator struct {
+	clients map[string]*auth.Client
+}
+
+func (m *mockAuthenticator) Authenticate(ctx context.Context, key string) (*auth.Client, error) {
+	if c, ok := m.clients[key]; ok {
+		return c, nil
+	}
+	return nil, auth.ErrInvalidKey
+}
+
+func newAuthenticator() *mockAuthenticator {
+	return &mockAuthenticator{clients: map[string]*auth.Client{
+		"reader": {ID: "c-1", TenantID: "acme", Scopes: []auth.Scope{auth.ScopeRead}},
+		"sender": {ID: "c-2", TenantID: "acme", Scopes: []auth.Scope{auth.ScopeSendSMS}},
+	}}
+}
+
+func callUnary(t *testing.T, md metadata.MD, method string) (context.Context, error) {
+	t.Helper()
+	var got context.Context
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		got = ctx
+		return nil, nil
+	}
+	ctx := metadata.NewIncomingContext(context.Background(), md)
+	_, err := UnaryInterceptor(newAuthenticator())(ctx, nil, &grpc.UnaryServerInfo{FullMethod: method}, handler)
+	return got, err
+}
+
+func TestUnaryInterceptor_AuthenticatesAndScopes(t *testing.T) {
+	ctx, err := callUnary(t, metadata.Pairs("authorization", "Bearer reader"), notificationv1.NotificationService_GetNotification_FullMethodName)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if c := clientFromContext(ctx); c == nil || c.ID != "c-1" {
+		t.Errorf("expected client c-1, got %+v", c)
+	}
+	if tenantID, _ := sharedctx.TenantID(ctx); tenantID != "acme" {
+		t.Errorf("expected tenant acme, got %q", tenantID)
+	}
+
+	if _, err := callUnary(t, metadata.Pairs("x-api-key", "sender"), notificationv1.NotificationService_ListNotifications_FullMethodName); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("expected PermissionDenied without read scope, got %v", err)
+	}
+	if _, err := callUnary(t, metadata.Pairs("x-api-key", "sender"), notificationv1.NotificationService_CreateNotification_FullMethodName); err != nil {
+		t.Errorf("expected creates to pass the interceptor, got %v", err)
+	}
+	if _, err := callUnary(t, metadata.MD{}, notificationv1.NotificationService_GetNotification_FullMethodName); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("expected Unauthenticated without a key, got %v", err)
+	}
+}
+
+func TestUnaryInterceptor_CorrelationID(t *testing.T) {
+	md := metadata.Pairs("x-api-key", "reader", MetadataCorrelationID, "corr-1")
+	ctx, err := callUnary(t, md, notificationv1.NotificationService_GetNotification_FullMethodName)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if id, _ := ctx.Value(sharedctx.CorrelationIDKey()).(string); id != "corr-1" {
+		t.Errorf("expected correlation id corr-1, got %q", id)
+	}
+
+	ctx, err = callUnary(t, metadata.Pairs("x-api-key", "reader"), notificationv1.NotificationService_GetNotification_FullMethodName)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if id, _ := ctx.Value(sharedctx.CorrelationIDKey()).(string); id == "" {
+		t.Error("expected a generated correlation id")
+	}
+}
+
+type memoryRateLimitStore struct {
+	counts map[string]int
+}
+
+func (m *memoryRateLimitStore) Hit(ctx context.Context, key string, window time.Duration) (int, time.Time, error) {
+	m.counts[key]++
+	return m.counts[key], time.Now().Add(window), nil
+}
+
+func TestUnaryRateLimitInterceptor(t *testing.T) {
+	store := &memoryRateLimitStore{counts: make(map[string]int)}
+	limits := RateLimits{
+		Store:  store,
+		Create: api.RateLimitPolicy{Bucket: "create", Limit: 1, Window: time.Minute},
+	}
+	intercept := UnaryRateLimitInterceptor(limits)
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) { return nil, nil }
+	ctx := context.WithValue(context.Background(), sharedctx.APIClientKey(), &auth.Client{ID: "c-2"})
+	create := &grpc.UnaryServerInfo{FullMethod: notificationv1.NotificationService_CreateNotification_FullMethodName}
+
+	if _, err := intercept(ctx, nil, create, handler); err != nil {
+		t.Fatalf("expected the first create to pass, got %v", err)
+	}
+	if _, err := intercept(ctx, nil, create, handler); status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("expected ResourceExhausted over the limit, got %v", err)
+	}
+	if store.counts["create:client:c-2"] != 2 {
+		t.Errorf("expected the REST bucket key, got %v", store.counts)
+	}
+
+	cancel := &grpc.UnaryServerInfo{FullMethod: notificationv1.NotificationService_CancelNotification_FullMethodName}
+	if _, err := intercept(ctx, nil, cancel, handler); err != nil {
+		t.Errorf("expected cancels not to be limited, got %v", err)
+	}
+}
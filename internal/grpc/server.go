@@ -0,0 +1,500 @@
+// Package grpc serves the notification API over gRPC. It calls the same use cases
+// as the REST handlers.
+package grpc
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	notificationv1 "github.com/semih-yildiz/notification-service/api/proto/notification/v1"
+	"github.com/semih-yildiz/notification-service/internal/api"
+	"github.com/semih-yildiz/notification-service/internal/application/notification/command/cancel"
+	"github.com/semih-yildiz/notification-service/internal/application/notification/command/create"
+	"github.com/semih-yildiz/notification-service/internal/application/notification/port"
+	"github.com/semih-yildiz/notification-service/internal/application/notification/query/get"
+	"github.com/semih-yildiz/notification-service/internal/application/notification/query/list"
+	"github.com/semih-yildiz/notification-service/internal/domain/auth"
+	"github.com/semih-yildiz/notification-service/internal/domain/notification"
+)
+
+// batchRefreshInterval limits how often a batch stream reloads the batch's counts.
+const batchRefreshInterval = time.Second
+
+// NewServer returns a gRPC server exposing svc to clients accepted by authenticator,
+// limited by limits.
+func NewServer(svc *NotificationServer, authenticator Authenticator, limits RateLimits) *grpc.Server {
+	s := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(UnaryInterceptor(authenticator), UnaryRateLimitInterceptor(limits)),
+		grpc.ChainStreamInterceptor(StreamInterceptor(authenticator), StreamRateLimitInterceptor(limits)),
+	)
+	notificationv1.RegisterNotificationServiceServer(s, svc)
+	return s
+}
+
+// NotificationServer implements notificationv1.NotificationServiceServer.
+type NotificationServer struct {
+	notificationv1.UnimplementedNotificationServiceServer
+
+	createUsecase *create.UseCase
+	cancelUsecase *cancel.UseCase
+	getUsecase    *get.UseCase
+	listUsecase   *list.UseCase
+	events        port.StatusSubscriber // optional; nil disables StreamStatus
+}
+
+func NewNotificationServer(
+	createUsecase *create.UseCase,
+	cancelUsecase *cancel.UseCase,
+	getUsecase *get.UseCase,
+	listUsecase *list.UseCase,
+) *NotificationServer {
+	return &NotificationServer{
+		createUsecase: createUsecase,
+		cancelUsecase: cancelUsecase,
+		getUsecase:    getUsecase,
+		listUsecase:   listUsecase,
+	}
+}
+
+// WithStatusEvents enables StreamStatus.
+func (s *NotificationServer) WithStatusEvents(events port.StatusSubscriber) *NotificationServer {
+	s.events = events
+	return s
+}
+
+func (s *NotificationServer) CreateNotification(ctx context.Context, req *notificationv1.CreateNotificationRequest) (*notificationv1.CreateNotificationResponse, error) {
+	item := toNotificationItem(req.GetNotification())
+	if err := item.Validate(); err != nil {
+		return nil, toStatus(err)
+	}
+
+	client := clientFromContext(ctx)
+	if client == nil {
+		return nil, status.Error(codes.PermissionDenied, "api key required")
+	}
+	if len(item.Fallback) > 0 {
+		return s.createFallback(ctx, client, &item)
+	}
+	// A user's notification is only resolved to channels the key can send on.
+	if ch := notification.Channel(item.Channel); item.UserID == "" && !client.CanSend(ch) {
+		return nil, status.Error(codes.PermissionDenied, "api key lacks the "+auth.SendScope(ch).String()+" scope")
+	}
+
+	window, _ := item.DeliveryWindow.Window() // checked by Validate
+	result, err := s.createUsecase.CreateNotification(ctx, &create.Command{
+		Recipient:      item.Recipient,
+		Channel:        item.Channel,
+		UserID:         item.UserID,
+		Category:       item.Category,
+		CanSend:        client.CanSend,
+		Content:        item.Content,
+		Variants:       item.ContentVariants,
+		Locale:         item.Locale,
+		Priority:       item.Priority,
+		IdempotencyKey: item.IdempotencyKey,
+		DeliveryWindow: window,
+		Track:          item.Track,
+		ClientID:       &client.ID,
+		TenantID:       client.TenantID,
+	})
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &notificationv1.CreateNotificationResponse{
+		Notification:        toProtoNotification(result.Notification),
+		Replayed:            result.Replayed,
+		DeduplicatedAgainst: result.DeduplicatedAgainst,
+	}, nil
+}
+
+// createFallback creates a notification delivered through the item's fallback chain.
+// The key needs the send scope of every step.
+func (s *NotificationServer) createFallback(ctx context.Context, client *auth.Client, item *api.NotificationItem) (*notificationv1.CreateNotificationResponse, error) {
+	steps := item.Steps()
+	for _, step := range steps {
+		if !client.CanSend(step.Channel) {
+			return nil, status.Error(codes.PermissionDenied, "api key lacks the "+auth.SendScope(step.Channel).String()+" scope")
+		}
+	}
+
+	result, err := s.createUsecase.CreateFallback(ctx, &create.FallbackCommand{
+		Steps:          steps,
+		Content:        item.Content,
+		Variants:       item.ContentVariants,
+		Locale:         item.Locale,
+		Priority:       item.Priority,
+		IdempotencyKey: item.IdempotencyKey,
+		ClientID:       &client.ID,
+		TenantID:       client.TenantID,
+	})
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &notificationv1.CreateNotificationResponse{
+		Notification: toProtoNotification(result.Notification),
+		Replayed:     result.Replayed,
+	}, nil
+}
+
+func (s *NotificationServer) CreateBatch(ctx context.Context, req *notificationv1.CreateBatchRequest) (*notificationv1.CreateBatchResponse, error) {
+	items := make([]api.NotificationItem, len(req.GetItems()))
+	for i, in := range req.GetItems() {
+		items[i] = toNotificationItem(in)
+	}
+
+	// In partial mode invalid items are rejected individually by the use case.
+	if req.GetPartial() {
+		if len(items) == 0 || len(items) > notification.MaxBatchSize {
+			return nil, toStatus(notification.ErrBatchTooLarge)
+		}
+	} else if err := api.ValidateBatch(items); err != nil {
+		return nil, toStatus(err)
+	}
+
+	client := clientFromContext(ctx)
+	if client == nil {
+		return nil, status.Error(codes.PermissionDenied, "api key required")
+	}
+	for _, item := range items {
+		ch := notification.Channel(item.Channel)
+		if ch.Valid() && !client.CanSend(ch) {
+			return nil, status.Error(codes.PermissionDenied, "api key lacks the "+auth.SendScope(ch).String()+" scope")
+		}
+	}
+
+	batchItems := make([]create.BatchItem, len(items))
+	for i, item := range items {
+		batchItems[i] = create.BatchItem{
+			Recipient:      item.Recipient,
+			Channel:        item.Channel,
+			Content:        item.Content,
+			Variants:       item.ContentVariants,
+			Locale:         item.Locale,
+			Priority:       item.Priority,
+			IdempotencyKey: item.IdempotencyKey,
+			DeliveryWindow: item.DeliveryWindow.BatchWindow(),
+			Track:          item.Track,
+		}
+	}
+
+	result, err := s.createUsecase.CreateNotificationBatches(ctx, &create.BatchCommand{
+		Items:          batchItems,
+		IdempotencyKey: optionalString(req.GetIdempotencyKey()),
+		ClientID:       &client.ID,
+		TenantID:       client.TenantID,
+		PartialAccept:  req.GetPartial(),
+	})
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	resp := &notificationv1.CreateBatchResponse{BatchId: result.BatchID, Replayed: result.Replayed}
+	for _, n := range result.Notifications {
+		resp.Notifications = append(resp.Notifications, toProtoNotification(n))
+	}
+	if req.GetPartial() {
+		for _, it := range result.Items {
+			r := &notificationv1.BatchItemResult{
+				Index:          int32(it.Index),
+				Status:         string(it.Status),
+				NotificationId: it.NotificationID,
+			}
+			for _, fe := range it.Errors {
+				r.Errors = append(r.Errors, &notificationv1.FieldViolation{Field: fe.Field, Message: fe.Message})
+			}
+			resp.Items = append(resp.Items, r)
+		}
+	}
+	return resp, nil
+}
+
+func (s *NotificationServer) GetNotification(ctx context.Context, req *notificationv1.GetNotificationRequest) (*notificationv1.Notification, error) {
+	n, err := s.getUsecase.Notification(ctx, &get.ByID{ID: req.GetId()})
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return toProtoNotification(n), nil
+}
+
+func (s *NotificationServer) ListNotifications(ctx context.Context, req *notificationv1.ListNotificationsRequest) (*notificationv1.ListNotificationsResponse, error) {
+	// Validate exactly like the REST query string.
+	params, err := api.ParseListParams(listValues(req))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	result, err := s.listUsecase.ListByQuery(ctx, &list.Query{
+		Statuses:        params.Statuses,
+		Channel:         params.Channel,
+		Priority:        params.Priority,
+		Recipient:       params.Recipient,
+		RecipientPrefix: params.RecipientPrefix,
+		FromTime:        params.From,
+		ToTime:          params.To,
+		SentFrom:        params.SentFrom,
+		SentTo:          params.SentTo,
+		BatchID:         params.BatchID,
+		ClientID:        params.ClientID,
+		IdempotencyKey:  params.IdempotencyKey,
+		FailureReason:   params.FailureReason,
+		Sort:            port.ListSort{Field: port.SortField(params.SortBy), Ascending: params.SortAscending},
+		Limit:           params.Limit,
+		Cursor:          params.Cursor,
+		WithTotal:       params.IncludeTotal,
+	})
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	resp := &notificationv1.ListNotificationsResponse{NextCursor: result.NextCursor}
+	for _, n := range result.Notifications {
+		resp.Notifications = append(resp.Notifications, toProtoNotification(n))
+	}
+	if result.Total != nil {
+		resp.Total = wrapperspb.Int32(int32(*result.Total))
+	}
+	return resp, nil
+}
+
+func (s *NotificationServer) CancelNotification(ctx context.Context, req *notificationv1.CancelNotificationRequest) (*notificationv1.CancelNotificationResponse, error) {
+	cmd := &cancel.Command{NotificationID: req.GetId(), ClientID: ownerScope(ctx)}
+	if err := s.cancelUsecase.CancelPendingNotification(ctx, cmd); err != nil {
+		return nil, toStatus(err)
+	}
+	return &notificationv1.CancelNotificationResponse{}, nil
+}
+
+func (s *NotificationServer) CancelBatch(ctx context.Context, req *notificationv1.CancelBatchRequest) (*notificationv1.CancelBatchResponse, error) {
+	cmd := &cancel.BatchCommand{BatchID: req.GetBatchId(), ClientID: ownerScope(ctx)}
+	count, err := s.cancelUsecase.CancelPendingNotificationBatch(ctx, cmd)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &notificationv1.CancelBatchResponse{Cancelled: int32(count)}, nil
+}
+
+func (s *NotificationServer) StreamStatus(req *notificationv1.StreamStatusRequest, stream notificationv1.NotificationService_StreamStatusServer) error {
+	if s.events == nil {
+		return status.Error(codes.Unimplemented, "status streaming is not enabled")
+	}
+	switch target := req.GetTarget().(type) {
+	case *notificationv1.StreamStatusRequest_NotificationId:
+		return s.streamNotification(target.NotificationId, stream)
+	case *notificationv1.StreamStatusRequest_BatchId:
+		return s.streamBatch(target.BatchId, stream)
+	default:
+		return invalidArgument(api.ValidationError{Field: "target", Message: "notification_id or batch_id is required"})
+	}
+}
+
+// streamNotification sends the notification's current status, then each change
+// until it is terminal.
+func (s *NotificationServer) streamNotification(id string, stream notificationv1.NotificationService_StreamStatusServer) error {
+	ctx := stream.Context()
+
+	// Subscribe before reading the current state so no change falls in between.
+	events, err := s.events.SubscribeNotification(ctx, id)
+	if err != nil {
+		return toStatus(err)
+	}
+	n, err := s.getUsecase.Notification(ctx, &get.ByID{ID: id})
+	if err != nil {
+		return toStatus(err)
+	}
+
+	current := &port.StatusEvent{
+		NotificationID: n.ID,
+		BatchID:        n.BatchID,
+		Status:         n.Status,
+		FailureReason:  n.FailureReason,
+		OccurredAt:     n.UpdatedAt,
+	}
+	if err := stream.Send(toProtoStatusEvent(current)); err != nil || n.Status.Terminal() {
+		return err
+	}
+
+	for evt := range events {
+		if err := stream.Send(toProtoStatusEvent(evt)); err != nil || evt.Status.Terminal() {
+			return err
+		}
+	}
+	return ctx.Err()
+}
+
+// streamBatch sends each change of the batch until every notification is terminal.
+func (s *NotificationServer) streamBatch(batchID string, stream notificationv1.NotificationService_StreamStatusServer) error {
+	ctx := stream.Context()
+
+	events, err := s.events.SubscribeBatch(ctx, batchID)
+	if err != nil {
+		return toStatus(err)
+	}
+	batch, err := s.getUsecase.BatchSummary(ctx, &get.BatchByID{BatchID: batchID})
+	if err != nil {
+		return toStatus(err)
+	}
+	if batch.Status() != notification.BatchStatusInProgress {
+		return nil
+	}
+
+	refresh := time.NewTicker(batchRefreshInterval)
+	defer refresh.Stop()
+	changed := false
+	for {
+		select {
+		case evt, ok := <-events:
+			if !ok {
+				return ctx.Err()
+			}
+			if err := stream.Send(toProtoStatusEvent(evt)); err != nil {
+				return err
+			}
+			changed = true
+		case <-refresh.C:
+			if !changed {
+				continue
+			}
+			changed = false
+			batch, err := s.getUsecase.BatchSummary(ctx, &get.BatchByID{BatchID: batchID})
+			if err == nil && batch.Status() != notification.BatchStatusInProgress {
+				return nil
+			}
+		}
+	}
+}
+
+// ownerScope returns the client whose notifications a call may modify, or nil for
+// admins, who may modify any.
+func ownerScope(ctx context.Context) *string {
+	client := clientFromContext(ctx)
+	if client == nil {
+		return new(string) // matches no owner
+	}
+	if client.Can(auth.ScopeAdmin) {
+		return nil
+	}
+	return &client.ID
+}
+
+// listValues renders a list request as the REST query string.
+func listValues(req *notificationv1.ListNotificationsRequest) url.Values {
+	q := url.Values{}
+	q["status"] = req.GetStatuses()
+	for k, v := range map[string]string{
+		"channel":          req.GetChannel(),
+		"priority":         req.GetPriority(),
+		"recipient":        req.GetRecipient(),
+		"recipient_prefix": req.GetRecipientPrefix(),
+		"batch_id":         req.GetBatchId(),
+		"client_id":        req.GetClientId(),
+		"idempotency_key":  req.GetIdempotencyKey(),
+		"failure_reason":   req.GetFailureReason(),
+		"sort":             req.GetSort(),
+		"cursor":           req.GetCursor(),
+	} {
+		if v != "" {
+			q.Set(k, v)
+		}
+	}
+	for k, ts := range map[string]*timestamppb.Timestamp{
+		"from":      req.GetCreatedFrom(),
+		"to":        req.GetCreatedTo(),
+		"sent_from": req.GetSentFrom(),
+		"sent_to":   req.GetSentTo(),
+	} {
+		if ts != nil {
+			q.Set(k, ts.AsTime().Format(time.RFC3339Nano))
+		}
+	}
+	if req.GetLimit() != 0 {
+		q.Set("limit", strconv.Itoa(int(req.GetLimit())))
+	}
+	if req.GetIncludeTotal() {
+		q.Set("include_total", "true")
+	}
+	return q
+}
+
+func toNotificationItem(in *notificationv1.NotificationInput) api.NotificationItem {
+	item := api.NotificationItem{
+		Recipient:       in.GetRecipient(),
+		Channel:         in.GetChannel(),
+		UserID:          in.GetUserId(),
+		Category:        in.GetCategory(),
+		Content:         in.GetContent(),
+		Priority:        in.GetPriority(),
+		IdempotencyKey:  optionalString(in.GetIdempotencyKey()),
+		ContentVariants: in.GetContentVariants(),
+		Locale:          in.GetLocale(),
+		Track:           in.GetTrack(),
+	}
+	if w := in.GetDeliveryWindow(); w != nil {
+		item.DeliveryWindow = &api.DeliveryWindowRequest{Start: w.GetStart(), End: w.GetEnd(), TimeZone: w.GetTimeZone()}
+	}
+	for _, step := range in.GetFallback() {
+		item.Fallback = append(item.Fallback, api.FallbackStepRequest{
+			Channel:        step.GetChannel(),
+			Recipient:      step.GetRecipient(),
+			TimeoutSeconds: int(step.GetTimeoutSeconds()),
+		})
+	}
+	return item
+}
+
+func toProtoNotification(n *notification.Notification) *notificationv1.Notification {
+	return &notificationv1.Notification{
+		Id:             n.ID,
+		BatchId:        deref(n.BatchID),
+		Recipient:      n.Recipient,
+		Channel:        n.Channel.String(),
+		Content:        n.Content,
+		Priority:       n.Priority.String(),
+		Status:         n.Status.String(),
+		IdempotencyKey: deref(n.IdempotencyKey),
+		ClientId:       deref(n.ClientID),
+		CreatedAt:      timestamppb.New(n.CreatedAt),
+		UpdatedAt:      timestamppb.New(n.UpdatedAt),
+		SentAt:         optionalTimestamp(n.SentAt),
+		FailureReason:  deref(n.FailureReason),
+	}
+}
+
+func toProtoStatusEvent(evt *port.StatusEvent) *notificationv1.StatusEvent {
+	return &notificationv1.StatusEvent{
+		NotificationId: evt.NotificationID,
+		BatchId:        deref(evt.BatchID),
+		Status:         evt.Status.String(),
+		FailureReason:  deref(evt.FailureReason),
+		OccurredAt:     timestamppb.New(evt.OccurredAt),
+	}
+}
+
+func optionalString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+func optionalTimestamp(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+	return timestamppb.New(*t)
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -0,0 +1,50 @@
+package grpc
+
+import (
+	"testing"
+
+	notificationv1 "github.com/semih-yildiz/notification-service/api/proto/notification/v1"
+)
+
+func TestToNotificationItem_MapsEveryField(t *testing.T) {
+	item := toNotificationItem(&notificationv1.NotificationInput{
+		UserId:          "user-1",
+		Category:        "billing",
+		Content:         "Hello",
+		ContentVariants: map[string]string{"tr-TR": "Merhaba"},
+		Locale:          "tr-TR",
+		DeliveryWindow:  &notificationv1.DeliveryWindow{Start: "09:00", End: "21:00", TimeZone: "Europe/Istanbul"},
+		Track:           true,
+	})
+
+	if item.UserID != "user-1" || item.Category != "billing" || item.Locale != "tr-TR" || !item.Track {
+		t.Errorf("unexpected item %+v", item)
+	}
+	if item.ContentVariants["tr-TR"] != "Merhaba" {
+		t.Errorf("expected the tr-TR variant, got %v", item.ContentVariants)
+	}
+	if item.DeliveryWindow == nil || item.DeliveryWindow.TimeZone != "Europe/Istanbul" {
+		t.Errorf("expected the delivery window, got %+v", item.DeliveryWindow)
+	}
+	if err := item.Validate(); err != nil {
+		t.Errorf("expected a valid item, got %v", err)
+	}
+}
+
+func TestToNotificationItem_Fallback(t *testing.T) {
+	item := toNotificationItem(&notificationv1.NotificationInput{
+		Content: "Your code is 123456",
+		Fallback: []*notificationv1.FallbackStep{
+			{Channel: "push", Recipient: "device-token", TimeoutSeconds: 60},
+			{Channel: "sms", Recipient: "+905551234567"},
+		},
+	})
+
+	steps := item.Steps()
+	if len(steps) != 2 || steps[0].Channel != "push" || steps[0].Timeout.Seconds() != 60 || steps[1].Recipient != "+905551234567" {
+		t.Errorf("unexpected steps %+v", steps)
+	}
+	if err := item.Validate(); err != nil {
+		t.Errorf("expected a valid item, got %v", err)
+	}
+}
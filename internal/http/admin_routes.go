@@ -0,0 +1,294 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/semih-yildiz/notification-service/internal/api"
+	"github.com/semih-yildiz/notification-service/internal/application/auth/command/apikey"
+	"github.com/semih-yildiz/notification-service/internal/application/auth/query/client"
+	"github.com/semih-yildiz/notification-service/internal/application/tenant/command/manage"
+	"github.com/semih-yildiz/notification-service/internal/application/tenant/query/lookup"
+	"github.com/semih-yildiz/notification-service/internal/domain/auth"
+	"github.com/semih-yildiz/notification-service/internal/domain/notification"
+	"github.com/semih-yildiz/notification-service/internal/domain/profile"
+	"github.com/semih-yildiz/notification-service/internal/domain/tenant"
+	"github.com/semih-yildiz/notification-service/internal/http/dto"
+	httpmw "github.com/semih-yildiz/notification-service/internal/http/middleware"
+)
+
+type AdminHandler struct {
+	apikeyUsecase *apikey.UseCase
+	clientUsecase *client.UseCase
+	manageUsecase *manage.UseCase
+	lookupUsecase *lookup.UseCase
+}
+
+func NewAdminHandler(
+	apikeyUsecase *apikey.UseCase,
+	clientUsecase *client.UseCase,
+	manageUsecase *manage.UseCase,
+	lookupUsecase *lookup.UseCase,
+) *AdminHandler {
+	return &AdminHandler{
+		apikeyUsecase: apikeyUsecase,
+		clientUsecase: clientUsecase,
+		manageUsecase: manageUsecase,
+		lookupUsecase: lookupUsecase,
+	}
+}
+
+// RegisterAdminRoutes registers the admin API. Admin keys manage their own tenant;
+// creating and listing tenants and setting quotas need the platform scope.
+func RegisterAdminRoutes(g *echo.Group, handler *AdminHandler) {
+	admin := g.Group("/admin", httpmw.RequireScope(auth.ScopeAdmin))
+	platform := httpmw.RequireScope(auth.ScopePlatform)
+	admin.POST("/api-keys", handler.IssueAPIKey)
+	admin.GET("/api-keys", handler.ListAPIKeys)
+	admin.POST("/api-keys/:id/rotate", handler.RotateAPIKey)
+	admin.POST("/tenants", handler.CreateTenant, platform)
+	admin.GET("/tenants", handler.ListTenants, platform)
+	admin.GET("/tenants/:id", handler.GetTenant, ownTenant)
+	admin.PUT("/tenants/:id/quotas", handler.SetTenantQuotas, platform)
+	admin.PUT("/tenants/:id/delivery-windows", handler.SetTenantDeliveryWindows, ownTenant)
+	admin.PUT("/tenants/:id/digest-policies", handler.SetTenantDigestPolicies, ownTenant)
+}
+
+// ownTenant rejects requests for a tenant other than the caller's, unless the
+// caller holds the platform scope.
+func ownTenant(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		client := httpmw.ClientFromContext(c.Request().Context())
+		if client == nil || !client.CanManageTenant(c.Param("id")) {
+			return httpmw.Forbidden(c, "admin keys can only manage their own tenant")
+		}
+		return next(c)
+	}
+}
+
+// IssueAPIKey handles POST /admin/api-keys
+func (h *AdminHandler) IssueAPIKey(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	var req dto.IssueAPIKeyRequest
+	if err := c.Bind(&req); err != nil {
+		return badRequest(c, "request body must be a JSON object")
+	}
+
+	result, err := h.apikeyUsecase.Issue(ctx, &apikey.IssueCommand{
+		Name:     req.Name,
+		Scopes:   req.Scopes,
+		TenantID: req.TenantID,
+		Issuer:   httpmw.ClientFromContext(ctx),
+	})
+	if err != nil {
+		return mapAuthError(c, err)
+	}
+
+	return c.JSON(http.StatusCreated, toAPIKeyResponse(result))
+}
+
+// ListAPIKeys handles GET /admin/api-keys
+func (h *AdminHandler) ListAPIKeys(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	clients, err := h.clientUsecase.List(ctx)
+	if err != nil {
+		return mapAuthError(c, err)
+	}
+
+	response := make([]dto.APIClientResponse, len(clients))
+	for i, cl := range clients {
+		response[i] = toAPIClientResponse(cl)
+	}
+	return c.JSON(http.StatusOK, response)
+}
+
+// RotateAPIKey handles POST /admin/api-keys/:id/rotate
+func (h *AdminHandler) RotateAPIKey(c echo.Context) error {
+	ctx := c.Request().Context()
+	id := c.Param("id")
+
+	result, err := h.apikeyUsecase.Rotate(ctx, &apikey.RotateCommand{ClientID: id, Issuer: httpmw.ClientFromContext(ctx)})
+	if err != nil {
+		return mapAuthError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, toAPIKeyResponse(result))
+}
+
+// CreateTenant handles POST /admin/tenants
+func (h *AdminHandler) CreateTenant(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	var req dto.CreateTenantRequest
+	if err := c.Bind(&req); err != nil {
+		return badRequest(c, "request body must be a JSON object")
+	}
+
+	t, err := h.manageUsecase.Create(ctx, &manage.CreateCommand{ID: req.ID, Name: req.Name, DailyQuotas: req.DailyQuotas})
+	if err != nil {
+		return mapTenantError(c, err)
+	}
+
+	return c.JSON(http.StatusCreated, toTenantResponse(t, nil))
+}
+
+// ListTenants handles GET /admin/tenants
+func (h *AdminHandler) ListTenants(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	tenants, err := h.lookupUsecase.List(ctx)
+	if err != nil {
+		return mapTenantError(c, err)
+	}
+
+	response := make([]dto.TenantResponse, len(tenants))
+	for i, t := range tenants {
+		response[i] = toTenantResponse(t, nil)
+	}
+	return c.JSON(http.StatusOK, response)
+}
+
+// GetTenant handles GET /admin/tenants/:id
+func (h *AdminHandler) GetTenant(c echo.Context) error {
+	ctx := c.Request().Context()
+	id := c.Param("id")
+
+	usage, err := h.lookupUsecase.Tenant(ctx, id)
+	if err != nil {
+		return mapTenantError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, toTenantResponse(usage.Tenant, usage.UsedToday))
+}
+
+// SetTenantQuotas handles PUT /admin/tenants/:id/quotas
+func (h *AdminHandler) SetTenantQuotas(c echo.Context) error {
+	ctx := c.Request().Context()
+	id := c.Param("id")
+
+	var req dto.SetTenantQuotasRequest
+	if err := c.Bind(&req); err != nil {
+		return badRequest(c, "request body must be a JSON object")
+	}
+
+	t, err := h.manageUsecase.SetQuotas(ctx, &manage.SetQuotasCommand{TenantID: id, DailyQuotas: req.DailyQuotas})
+	if err != nil {
+		return mapTenantError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, toTenantResponse(t, nil))
+}
+
+// SetTenantDeliveryWindows handles PUT /admin/tenants/:id/delivery-windows
+func (h *AdminHandler) SetTenantDeliveryWindows(c echo.Context) error {
+	ctx := c.Request().Context()
+	id := c.Param("id")
+
+	var req dto.SetTenantDeliveryWindowsRequest
+	if err := c.Bind(&req); err != nil {
+		return badRequest(c, "request body must be a JSON object")
+	}
+	windows := make(map[string]notification.DeliveryWindow, len(req.DeliveryWindows))
+	for category, w := range req.DeliveryWindows {
+		window, err := w.Window()
+		if err != nil {
+			return validationFailed(c, api.ValidationError{Field: "delivery_windows." + category, Message: api.DeliveryWindowMessage})
+		}
+		windows[category] = *window
+	}
+
+	t, err := h.manageUsecase.SetDeliveryWindows(ctx, &manage.SetDeliveryWindowsCommand{TenantID: id, Windows: windows})
+	if err != nil {
+		return mapTenantError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, toTenantResponse(t, nil))
+}
+
+// SetTenantDigestPolicies handles PUT /admin/tenants/:id/digest-policies
+func (h *AdminHandler) SetTenantDigestPolicies(c echo.Context) error {
+	ctx := c.Request().Context()
+	id := c.Param("id")
+
+	var req dto.SetTenantDigestPoliciesRequest
+	if err := c.Bind(&req); err != nil {
+		return badRequest(c, "request body must be a JSON object")
+	}
+	policies := make(map[string]notification.DigestPolicy, len(req.DigestPolicies))
+	for category, p := range req.DigestPolicies {
+		policies[category] = p.Policy()
+	}
+
+	t, err := h.manageUsecase.SetDigestPolicies(ctx, &manage.SetDigestPoliciesCommand{TenantID: id, Policies: policies})
+	if errors.Is(err, profile.ErrInvalidCategory) {
+		return validationFailed(c, api.ValidationError{Field: "digest_policies", Message: api.CategoryMessage})
+	}
+	if err != nil {
+		return mapTenantError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, toTenantResponse(t, nil))
+}
+
+func toTenantResponse(t *tenant.Tenant, used map[notification.Channel]int) dto.TenantResponse {
+	quotas := make(map[string]int, len(t.DailyQuotas))
+	for ch, limit := range t.DailyQuotas {
+		quotas[ch.String()] = limit
+	}
+	var usedToday map[string]int
+	if used != nil {
+		usedToday = make(map[string]int, len(used))
+		for ch, n := range used {
+			usedToday[ch.String()] = n
+		}
+	}
+	windows := make(map[string]dto.DeliveryWindowResponse, len(t.DeliveryWindows))
+	for category, w := range t.DeliveryWindows {
+		windows[category] = toDeliveryWindowResponse(&w)
+	}
+	policies := make(map[string]dto.DigestPolicyResponse, len(t.DigestPolicies))
+	for category, p := range t.DigestPolicies {
+		policies[category] = dto.DigestPolicyResponse{WindowSeconds: int(p.Window / time.Second), TemplateID: p.TemplateID}
+	}
+	return dto.TenantResponse{
+		ID:              t.ID,
+		Name:            t.Name,
+		DailyQuotas:     quotas,
+		DeliveryWindows: windows,
+		DigestPolicies:  policies,
+		UsedToday:       usedToday,
+		CreatedAt:       t.CreatedAt,
+	}
+}
+
+func toAPIClientResponse(cl *auth.Client) dto.APIClientResponse {
+	scopes := make([]string, len(cl.Scopes))
+	for i, s := range cl.Scopes {
+		scopes[i] = s.String()
+	}
+	return dto.APIClientResponse{
+		ID:        cl.ID,
+		TenantID:  cl.TenantID,
+		Name:      cl.Name,
+		KeyPrefix: cl.KeyPrefix,
+		Scopes:    scopes,
+		CreatedAt: cl.CreatedAt,
+		RotatedAt: cl.RotatedAt,
+	}
+}
+
+func toAPIKeyResponse(r *apikey.Result) dto.APIKeyResponse {
+	return dto.APIKeyResponse{
+		APIClientResponse: toAPIClientResponse(r.Client),
+		APIKey:            r.Key,
+	}
+}
+
+func toDeliveryWindowResponse(w *notification.DeliveryWindow) dto.DeliveryWindowResponse {
+	return dto.DeliveryWindowResponse{Start: w.StartClock(), End: w.EndClock(), TimeZone: w.TimeZone}
+}
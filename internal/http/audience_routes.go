@@ -0,0 +1,219 @@
+package http
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/semih-yildiz/notification-service/internal/api"
+	"github.com/semih-yildiz/notification-service/internal/application/audience/command/manage"
+	"github.com/semih-yildiz/notification-service/internal/application/audience/query/lookup"
+	"github.com/semih-yildiz/notification-service/internal/application/notification/command/fanout"
+	"github.com/semih-yildiz/notification-service/internal/domain/audience"
+	"github.com/semih-yildiz/notification-service/internal/domain/auth"
+	"github.com/semih-yildiz/notification-service/internal/domain/notification"
+	"github.com/semih-yildiz/notification-service/internal/http/dto"
+	httpmw "github.com/semih-yildiz/notification-service/internal/http/middleware"
+)
+
+// CreateAudience handles POST /audiences
+func (h *NotificationHandler) CreateAudience(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	var req dto.CreateAudienceRequest
+	if err := c.Bind(&req); err != nil {
+		return badRequest(c, "request body must be a JSON object")
+	}
+
+	client := httpmw.ClientFromContext(ctx)
+	if client == nil {
+		return httpmw.Forbidden(c, "api key required")
+	}
+
+	a, err := h.audienceManage.Create(ctx, &manage.CreateCommand{Name: req.Name, ClientID: &client.ID, TenantID: client.TenantID})
+	if err != nil {
+		return mapAudienceError(c, err)
+	}
+
+	return c.JSON(http.StatusCreated, toAudienceResponse(a))
+}
+
+// ListAudiences handles GET /audiences
+func (h *NotificationHandler) ListAudiences(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	audiences, err := h.audienceLookup.List(ctx)
+	if err != nil {
+		return mapAudienceError(c, err)
+	}
+
+	response := make([]dto.AudienceResponse, len(audiences))
+	for i, a := range audiences {
+		response[i] = toAudienceResponse(a)
+	}
+	return c.JSON(http.StatusOK, response)
+}
+
+// GetAudience handles GET /audiences/:id
+func (h *NotificationHandler) GetAudience(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	a, err := h.audienceLookup.Audience(ctx, c.Param("id"))
+	if err != nil {
+		return mapAudienceError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, toAudienceResponse(a))
+}
+
+// DeleteAudience handles DELETE /audiences/:id
+func (h *NotificationHandler) DeleteAudience(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	if err := h.audienceManage.Delete(ctx, c.Param("id")); err != nil {
+		return mapAudienceError(c, err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// AddAudienceMembers handles POST /audiences/:id/members. Members already on the list
+// take the given suppressed flag, so the same call suppresses or restores them.
+func (h *NotificationHandler) AddAudienceMembers(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	var req dto.AddAudienceMembersRequest
+	if err := c.Bind(&req); err != nil {
+		return badRequest(c, "request body must be a JSON object")
+	}
+
+	members := make([]manage.MemberInput, len(req.Members))
+	for i, m := range req.Members {
+		members[i] = manage.MemberInput{Channel: m.Channel, Recipient: m.Recipient, Suppressed: m.Suppressed}
+	}
+	if err := h.audienceManage.AddMembers(ctx, &manage.AddMembersCommand{AudienceID: c.Param("id"), Members: members}); err != nil {
+		return mapAudienceError(c, err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// RemoveAudienceMember handles DELETE /audiences/:id/members/:channel/:recipient. The
+// recipient is path-escaped, e.g. a "/" in a push token as %2F.
+func (h *NotificationHandler) RemoveAudienceMember(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	recipient, err := url.PathUnescape(c.Param("recipient"))
+	if err != nil {
+		return badRequest(c, "recipient must be path-escaped")
+	}
+	cmd := &manage.RemoveMemberCommand{
+		AudienceID: c.Param("id"),
+		Channel:    c.Param("channel"),
+		Recipient:  recipient,
+	}
+	if err := h.audienceManage.RemoveMember(ctx, cmd); err != nil {
+		return mapAudienceError(c, err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// ListAudienceMembers handles GET /audiences/:id/members
+func (h *NotificationHandler) ListAudienceMembers(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	limit := 0
+	if v := c.QueryParam("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			return validationFailed(c, api.ValidationError{Field: "limit", Message: "limit must be a positive integer"})
+		}
+		limit = n
+	}
+
+	page, err := h.audienceLookup.Members(ctx, &lookup.MembersQuery{
+		AudienceID: c.Param("id"),
+		Channel:    c.QueryParam("channel"),
+		Cursor:     c.QueryParam("cursor"),
+		Limit:      limit,
+	})
+	if err != nil {
+		return mapAudienceError(c, err)
+	}
+
+	response := dto.AudienceMembersResponse{
+		Members:    make([]dto.AudienceMemberResponse, len(page.Members)),
+		NextCursor: page.NextCursor,
+	}
+	for i, m := range page.Members {
+		response.Members[i] = dto.AudienceMemberResponse{
+			Channel:    m.Channel.String(),
+			Recipient:  notification.RedactRecipient(m.Channel, m.Recipient),
+			Suppressed: m.Suppressed,
+			CreatedAt:  m.CreatedAt,
+			UpdatedAt:  m.UpdatedAt,
+		}
+	}
+	return c.JSON(http.StatusOK, response)
+}
+
+// SendToAudience handles POST /audiences/:id/send. It responds 202 with the new batch;
+// the worker then creates one notification per unsuppressed member on the given
+// channels. The client needs the send scope of every channel.
+func (h *NotificationHandler) SendToAudience(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	var req dto.SendToAudienceRequest
+	if err := c.Bind(&req); err != nil {
+		return badRequest(c, "request body must be a JSON object")
+	}
+
+	client := httpmw.ClientFromContext(ctx)
+	if client == nil {
+		return httpmw.Forbidden(c, "api key required")
+	}
+	for _, ch := range req.Channels {
+		if notification.Channel(ch).Valid() && !client.CanSend(notification.Channel(ch)) {
+			return httpmw.Forbidden(c, "api key lacks the "+auth.SendScope(notification.Channel(ch)).String()+" scope")
+		}
+	}
+
+	window, err := req.DeliveryWindow.Window()
+	if err != nil {
+		return validationFailed(c, api.ValidationError{Field: "delivery_window", Message: api.DeliveryWindowMessage})
+	}
+
+	var idempotencyKey *string
+	if key := c.Request().Header.Get(HeaderIdempotencyKey); key != "" {
+		idempotencyKey = &key
+	}
+
+	result, err := h.fanoutUsecase.Send(ctx, &fanout.Command{
+		AudienceID:     c.Param("id"),
+		Channels:       req.Channels,
+		Content:        req.Content,
+		Variants:       req.ContentVariants,
+		Locale:         req.Locale,
+		Priority:       req.Priority,
+		DeliveryWindow: window,
+		Track:          req.Track,
+		IdempotencyKey: idempotencyKey,
+		ClientID:       &client.ID,
+		TenantID:       client.TenantID,
+	})
+	if err != nil {
+		return mapAudienceError(c, err)
+	}
+
+	if result.Replayed {
+		c.Response().Header().Set(HeaderIdempotentReplayed, "true")
+	}
+	return c.JSON(http.StatusAccepted, toBatchResponse(result.Batch))
+}
+
+func toAudienceResponse(a *audience.Audience) dto.AudienceResponse {
+	return dto.AudienceResponse{ID: a.ID, Name: a.Name, CreatedAt: a.CreatedAt}
+}
@@ -1,6 +1,10 @@
 package dto
 
-import "time"
+import (
+	"time"
+
+	"github.com/semih-yildiz/notification-service/internal/api"
+)
 
 // It follows RFC 7807 Problem Details for HTTP APIs principles.
 type ErrorResponse struct {
@@ -16,18 +20,6 @@ type ErrorDetail struct {
 	Details map[string]interface{} `json:"details,omitempty"`
 }
 
-const (
-	ErrCodeBadRequest          = "bad_request"
-	ErrCodeNotFound            = "not_found"
-	ErrCodeConflict            = "conflict"
-	ErrCodeInternalServerError = "internal_server_error"
-	ErrCodeValidation          = "validation_error"
-	ErrCodeDuplicateRequest    = "duplicate_request"
-	ErrCodeRateLimitExceeded   = "rate_limit_exceeded"
-	ErrCodeUnauthorized        = "unauthorized"
-	ErrCodeForbidden           = "forbidden"
-)
-
 func NewErrorResponse(code, message string) *ErrorResponse {
 	return &ErrorResponse{
 		Success: false,
@@ -56,20 +48,15 @@ func (e *ErrorResponse) WithRequestID(requestID string) *ErrorResponse {
 	return e
 }
 
-type ValidationError struct {
-	Field   string `json:"field"`
-	Message string `json:"message"`
-}
-
 // NewValidationErrorResponse creates a validation error response with field details.
-func NewValidationErrorResponse(errors []ValidationError) *ErrorResponse {
+func NewValidationErrorResponse(errors []api.ValidationError) *ErrorResponse {
 	details := make(map[string]interface{})
 	details["validation_errors"] = errors
 
 	return &ErrorResponse{
 		Success: false,
 		Error: &ErrorDetail{
-			Code:    ErrCodeValidation,
+			Code:    api.ErrCodeValidation,
 			Message: "validation failed",
 			Details: details,
 		},
@@ -1,58 +1,109 @@
 package dto
 
-import "fmt"
-
-// NotificationItem represents a single notification (used in both single and batch requests).
-type NotificationItem struct {
-	Recipient      string  `json:"recipient"`
-	Channel        string  `json:"channel"`
-	Content        string  `json:"content"`
-	Priority       string  `json:"priority"`
-	IdempotencyKey *string `json:"idempotency_key,omitempty"`
-}
-
-func (item *NotificationItem) Validate() error {
-	var validationErrors []ValidationError
-
-	if item.Recipient == "" {
-		validationErrors = append(validationErrors, ValidationError{
-			Field:   "recipient",
-			Message: "recipient is required",
-		})
-	}
-
-	if item.Channel == "" {
-		validationErrors = append(validationErrors, ValidationError{
-			Field:   "channel",
-			Message: "channel is required",
-		})
-	} else if item.Channel != "sms" && item.Channel != "email" && item.Channel != "push" {
-		validationErrors = append(validationErrors, ValidationError{
-			Field:   "channel",
-			Message: "channel must be one of: sms, email, push",
-		})
-	}
-
-	if item.Content == "" {
-		validationErrors = append(validationErrors, ValidationError{
-			Field:   "content",
-			Message: "content is required",
-		})
-	}
-
-	// Set default priority if not provided
-	if item.Priority == "" {
-		item.Priority = "normal"
-	} else if item.Priority != "high" && item.Priority != "normal" && item.Priority != "low" {
-		validationErrors = append(validationErrors, ValidationError{
-			Field:   "priority",
-			Message: "priority must be one of: high, normal, low",
-		})
-	}
-
-	if len(validationErrors) > 0 {
-		return fmt.Errorf("validation failed: %d errors", len(validationErrors))
-	}
-
-	return nil
+import (
+	"time"
+
+	"github.com/semih-yildiz/notification-service/internal/api"
+	"github.com/semih-yildiz/notification-service/internal/domain/notification"
+)
+
+// IssueAPIKeyRequest for POST /admin/api-keys.
+type IssueAPIKeyRequest struct {
+	Name     string   `json:"name"`
+	Scopes   []string `json:"scopes"`
+	TenantID string   `json:"tenant_id,omitempty"` // defaults to the issuing admin's tenant; others need the platform scope
+}
+
+// CreateTenantRequest for POST /admin/tenants.
+type CreateTenantRequest struct {
+	ID          string         `json:"id"`
+	Name        string         `json:"name"`
+	DailyQuotas map[string]int `json:"daily_quotas,omitempty"`
+}
+
+// SetTenantQuotasRequest for PUT /admin/tenants/:id/quotas.
+type SetTenantQuotasRequest struct {
+	DailyQuotas map[string]int `json:"daily_quotas"`
+}
+
+// CreateAudienceRequest for POST /audiences.
+type CreateAudienceRequest struct {
+	Name string `json:"name"`
+}
+
+// AudienceMemberItem is one member in POST /audiences/:id/members.
+type AudienceMemberItem struct {
+	Channel    string `json:"channel"`
+	Recipient  string `json:"recipient"`
+	Suppressed bool   `json:"suppressed,omitempty"`
+}
+
+// AddAudienceMembersRequest for POST /audiences/:id/members.
+type AddAudienceMembersRequest struct {
+	Members []AudienceMemberItem `json:"members"`
+}
+
+// SendToAudienceRequest for POST /audiences/:id/send.
+type SendToAudienceRequest struct {
+	Channels        []string                   `json:"channels"`
+	Content         string                     `json:"content,omitempty"`
+	ContentVariants notification.Variants      `json:"content_variants,omitempty"` // content in other languages, keyed by locale
+	Locale          string                     `json:"locale,omitempty"`           // picks the variant sent to every member
+	Priority        string                     `json:"priority,omitempty"`
+	DeliveryWindow  *api.DeliveryWindowRequest `json:"delivery_window,omitempty"`
+	Track           bool                       `json:"track,omitempty"` // open and click tracking of HTML email
+}
+
+// SetTenantDeliveryWindowsRequest for PUT /admin/tenants/:id/delivery-windows.
+type SetTenantDeliveryWindowsRequest struct {
+	DeliveryWindows map[string]api.DeliveryWindowRequest `json:"delivery_windows"` // category -> window
+}
+
+// DigestPolicyRequest batches a category's low priority notifications into one digest
+// every window_seconds, rendered from template_id or the default summary.
+type DigestPolicyRequest struct {
+	WindowSeconds int    `json:"window_seconds"`
+	TemplateID    string `json:"template_id,omitempty"`
+}
+
+// Policy converts the request.
+func (p DigestPolicyRequest) Policy() notification.DigestPolicy {
+	return notification.DigestPolicy{Window: time.Duration(p.WindowSeconds) * time.Second, TemplateID: p.TemplateID}
+}
+
+// SetTenantDigestPoliciesRequest for PUT /admin/tenants/:id/digest-policies.
+type SetTenantDigestPoliciesRequest struct {
+	DigestPolicies map[string]DigestPolicyRequest `json:"digest_policies"` // category -> policy
+}
+
+// CreateTemplateRequest for POST /templates and PUT /templates/:id.
+type CreateTemplateRequest struct {
+	Name     string            `json:"name"`
+	Body     string            `json:"body"`
+	Variants map[string]string `json:"variants,omitempty"` // locale -> body, e.g. {"tr": "..."}
+}
+
+// MarkInboxMessageRequest for PUT /users/:id/inbox/:message_id/state.
+type MarkInboxMessageRequest struct {
+	State string `json:"state"` // unread, read or archived
+}
+
+// SaveUserProfileRequest for PUT /users/:id.
+type SaveUserProfileRequest struct {
+	Phone          string              `json:"phone,omitempty"`
+	Email          string              `json:"email,omitempty"`
+	PushTokens     []string            `json:"push_tokens,omitempty"`
+	Locale         string              `json:"locale,omitempty"`
+	TimeZone       string              `json:"time_zone,omitempty"`
+	Preferences    map[string][]string `json:"preferences,omitempty"` // category -> channels; [] opts out
+	OptOuts        []string            `json:"opt_outs,omitempty"`
+	TrackingOptOut bool                `json:"tracking_opt_out,omitempty"` // no open and click tracking of emails
+}
+
+// DeliveryReceiptRequest for POST /receipts, in the provider's callback format.
+type DeliveryReceiptRequest struct {
+	MessageID string     `json:"messageId"`
+	Status    string     `json:"status"`           // delivered or undelivered
+	Reason    string     `json:"reason,omitempty"` // provider error code or text
+	Timestamp *time.Time `json:"timestamp,omitempty"`
 }
@@ -1,9 +1,55 @@
 package dto
 
+import (
+	"time"
+
+	"github.com/semih-yildiz/notification-service/internal/api"
+)
+
 // ListResponse for GET /notifications (paginated).
 type ListResponse struct {
 	Notifications interface{} `json:"notifications"`
-	Total         int         `json:"total"`
+	Total         *int        `json:"total,omitempty"`       // only with include_total=true
+	NextCursor    string      `json:"next_cursor,omitempty"` // absent on the last page
+}
+
+// BatchCreatedResponse for POST /notifications/batches outside partial mode.
+type BatchCreatedResponse struct {
+	BatchID       string      `json:"batch_id,omitempty"` // omitted when replayed items span several batches
+	Replayed      bool        `json:"replayed,omitempty"`
+	Notifications interface{} `json:"notifications"`
+}
+
+// BatchCreateResponse for POST /notifications/batches?mode=partial.
+type BatchCreateResponse struct {
+	BatchID       string            `json:"batch_id,omitempty"`
+	Accepted      int               `json:"accepted"`
+	Rejected      int               `json:"rejected"`
+	Duplicates    int               `json:"duplicates"`
+	Replayed      bool              `json:"replayed,omitempty"`
+	Items         []BatchItemResult `json:"items"`
+	Notifications interface{}       `json:"notifications"`
+}
+
+// BatchItemResult is the outcome of one input item, by its index in the request.
+type BatchItemResult struct {
+	Index          int                   `json:"index"`
+	Status         string                `json:"status"`
+	NotificationID string                `json:"notification_id,omitempty"`
+	Errors         []api.ValidationError `json:"errors,omitempty"`
+}
+
+// BatchResponse is a batch with its aggregate progress.
+type BatchResponse struct {
+	ID                   string         `json:"id"`
+	IdempotencyKey       *string        `json:"idempotency_key,omitempty"`
+	AudienceID           *string        `json:"audience_id,omitempty"` // set for sends to an audience
+	Status               string         `json:"status"`
+	Total                int            `json:"total"`
+	Counts               map[string]int `json:"counts"`
+	CompletionPercentage float64        `json:"completion_percentage"`
+	CreatedAt            time.Time      `json:"created_at"`
+	CompletedAt          *time.Time     `json:"completed_at,omitempty"`
 }
 
 // BatchWithNotificationsResponse for GET /batches/:id/notifications.
@@ -12,7 +58,179 @@ type BatchWithNotificationsResponse struct {
 	Notifications interface{} `json:"notifications"`
 }
 
+// StatusEventResponse is the data of a "status" event on the events streams. Without
+// a notification_id it covers every notification of the batch, e.g. when the batch
+// is cancelled.
+type StatusEventResponse struct {
+	NotificationID string    `json:"notification_id,omitempty"`
+	BatchID        *string   `json:"batch_id,omitempty"`
+	Status         string    `json:"status"`
+	FailureReason  *string   `json:"failure_reason,omitempty"`
+	OccurredAt     time.Time `json:"occurred_at"`
+}
+
+// ImportJobResponse for POST /imports and GET /imports/:id.
+type ImportJobResponse struct {
+	ID            string     `json:"id"`
+	Format        string     `json:"format"`
+	Status        string     `json:"status"`
+	TotalRows     int        `json:"total_rows"`
+	AcceptedRows  int        `json:"accepted_rows"`
+	RejectedRows  int        `json:"rejected_rows"`
+	DuplicateRows int        `json:"duplicate_rows"`
+	BatchIDs      []string   `json:"batch_ids"`
+	FailureReason *string    `json:"failure_reason,omitempty"`
+	ErrorsURL     string     `json:"errors_url"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+	CompletedAt   *time.Time `json:"completed_at,omitempty"`
+}
+
 // CancelBatchResponse for POST /batches/:id/cancel.
 type CancelBatchResponse struct {
 	Cancelled int `json:"cancelled"`
 }
+
+// APIClientResponse describes an API client without its key.
+type APIClientResponse struct {
+	ID        string     `json:"id"`
+	TenantID  string     `json:"tenant_id"`
+	Name      string     `json:"name"`
+	KeyPrefix string     `json:"key_prefix"`
+	Scopes    []string   `json:"scopes"`
+	CreatedAt time.Time  `json:"created_at"`
+	RotatedAt *time.Time `json:"rotated_at,omitempty"`
+}
+
+// APIKeyResponse for POST /admin/api-keys and its rotation. The key is only shown here.
+type APIKeyResponse struct {
+	APIClientResponse
+	APIKey string `json:"api_key"`
+}
+
+// TenantResponse describes a tenant, its daily quotas, delivery windows and digest
+// policies.
+type TenantResponse struct {
+	ID              string                            `json:"id"`
+	Name            string                            `json:"name"`
+	DailyQuotas     map[string]int                    `json:"daily_quotas"`
+	DeliveryWindows map[string]DeliveryWindowResponse `json:"delivery_windows"`
+	DigestPolicies  map[string]DigestPolicyResponse   `json:"digest_policies"`
+	UsedToday       map[string]int                    `json:"used_today,omitempty"`
+	CreatedAt       time.Time                         `json:"created_at"`
+}
+
+// DigestPolicyResponse describes a digest policy.
+type DigestPolicyResponse struct {
+	WindowSeconds int    `json:"window_seconds"`
+	TemplateID    string `json:"template_id,omitempty"`
+}
+
+// DeliveryWindowResponse describes a delivery window.
+type DeliveryWindowResponse struct {
+	Start    string `json:"start"`
+	End      string `json:"end"`
+	TimeZone string `json:"time_zone,omitempty"`
+}
+
+// HistoryEntryResponse is one event in GET /notifications/:id/history.
+type HistoryEntryResponse struct {
+	Event      string    `json:"event"`
+	Detail     string    `json:"detail,omitempty"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
+
+// AudienceResponse describes an audience.
+type AudienceResponse struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// AudienceMemberResponse describes one member of an audience.
+type AudienceMemberResponse struct {
+	Channel    string    `json:"channel"`
+	Recipient  string    `json:"recipient"`
+	Suppressed bool      `json:"suppressed"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// AudienceMembersResponse for GET /audiences/:id/members (paginated).
+type AudienceMembersResponse struct {
+	Members    []AudienceMemberResponse `json:"members"`
+	NextCursor string                   `json:"next_cursor,omitempty"` // absent on the last page
+}
+
+// InboxMessageResponse describes a message in a user's inbox.
+type InboxMessageResponse struct {
+	ID         string     `json:"id"` // the notification it was delivered from
+	Category   string     `json:"category,omitempty"`
+	Content    string     `json:"content"`
+	State      string     `json:"state"`
+	ReadAt     *time.Time `json:"read_at,omitempty"`
+	ArchivedAt *time.Time `json:"archived_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+// InboxResponse for GET /users/:id/inbox (paginated).
+type InboxResponse struct {
+	Messages   []InboxMessageResponse `json:"messages"`
+	Unread     int64                  `json:"unread"`
+	NextCursor string                 `json:"next_cursor,omitempty"` // absent on the last page
+}
+
+// InboxUnreadResponse for GET /users/:id/inbox/unread, and the data of the "inbox"
+// event that opens the inbox stream.
+type InboxUnreadResponse struct {
+	Unread int64 `json:"unread"`
+}
+
+// InboxEventResponse is the data of "message" and "state" events on the inbox stream.
+type InboxEventResponse struct {
+	Message InboxMessageResponse `json:"message"`
+	Unread  int64                `json:"unread"`
+}
+
+// TemplateResponse describes a template.
+type TemplateResponse struct {
+	ID        string            `json:"id"`
+	Name      string            `json:"name"`
+	Body      string            `json:"body"`
+	Variants  map[string]string `json:"variants,omitempty"`
+	CreatedAt time.Time         `json:"created_at"`
+	UpdatedAt time.Time         `json:"updated_at"`
+}
+
+// UserProfileResponse describes a user profile.
+type UserProfileResponse struct {
+	UserID         string              `json:"user_id"`
+	Phone          string              `json:"phone,omitempty"`
+	Email          string              `json:"email,omitempty"`
+	PushTokens     []string            `json:"push_tokens"`
+	Locale         string              `json:"locale,omitempty"`
+	TimeZone       string              `json:"time_zone,omitempty"`
+	Preferences    map[string][]string `json:"preferences"`
+	OptOuts        []string            `json:"opt_outs"`
+	TrackingOptOut bool                `json:"tracking_opt_out"`
+	CreatedAt      time.Time           `json:"created_at"`
+	UpdatedAt      time.Time           `json:"updated_at"`
+}
+
+// EngagementResponse for GET /batches/:id/engagement and /templates/:id/engagement.
+// Rates are percentages of the tracked emails sent.
+type EngagementResponse struct {
+	Tracked   int64   `json:"tracked"`
+	Opened    int64   `json:"opened"`
+	Clicked   int64   `json:"clicked"`
+	OpenRate  float64 `json:"open_rate"`
+	ClickRate float64 `json:"click_rate"`
+}
+
+// DeliveryReceiptResponse for POST /receipts.
+type DeliveryReceiptResponse struct {
+	NotificationID string `json:"notification_id"`
+	Status         string `json:"status"`
+	Applied        bool   `json:"applied"` // false for a duplicate or out-of-order receipt
+}
@@ -1,11 +1,20 @@
 package http
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
 
 	"github.com/labstack/echo/v4"
 
+	"github.com/semih-yildiz/notification-service/internal/api"
+	"github.com/semih-yildiz/notification-service/internal/domain/audience"
+	"github.com/semih-yildiz/notification-service/internal/domain/auth"
+	"github.com/semih-yildiz/notification-service/internal/domain/inbox"
 	"github.com/semih-yildiz/notification-service/internal/domain/notification"
+	"github.com/semih-yildiz/notification-service/internal/domain/profile"
+	"github.com/semih-yildiz/notification-service/internal/domain/template"
+	"github.com/semih-yildiz/notification-service/internal/domain/tenant"
 	"github.com/semih-yildiz/notification-service/internal/http/dto"
 )
 
@@ -16,46 +25,320 @@ func mapNotificationError(c echo.Context, err error) error {
 
 	switch err {
 	case notification.ErrNotFound:
-		errResp = dto.NewErrorResponse(dto.ErrCodeNotFound, "notification or batch not found")
+		errResp = dto.NewErrorResponse(api.ErrCodeNotFound, "notification or batch not found")
 		statusCode = http.StatusNotFound
 
 	case notification.ErrInvalidChannel:
-		errResp = dto.NewErrorResponse(dto.ErrCodeValidation, "invalid channel: must be sms, email, or push")
-		statusCode = http.StatusBadRequest
+		return validationFailed(c, api.ValidationError{Field: "channel", Message: "channel must be one of: " + notification.ChannelList()})
+
+	case notification.ErrInvalidWindow:
+		return validationFailed(c, api.ValidationError{Field: "delivery_window", Message: api.DeliveryWindowMessage})
+
+	case notification.ErrInvalidFallback:
+		return validationFailed(c, api.ValidationError{Field: "fallback", Message: notification.ErrInvalidFallback.Error()})
+
+	case notification.ErrInvalidReceipt:
+		return validationFailed(c, api.ValidationError{Field: "status", Message: notification.ErrInvalidReceipt.Error()})
+
+	case notification.ErrReceiptTooEarly:
+		errResp = dto.NewErrorResponse(api.ErrCodeConflict, "notification not marked sent yet; retry the receipt")
+		statusCode = http.StatusConflict
 
 	case notification.ErrInvalidPriority:
-		errResp = dto.NewErrorResponse(dto.ErrCodeValidation, "invalid priority: must be high, normal, or low")
-		statusCode = http.StatusBadRequest
+		return validationFailed(c, api.ValidationError{Field: "priority", Message: "priority must be one of: high, normal, low"})
 
 	case notification.ErrInvalidContent:
-		errResp = dto.NewErrorResponse(dto.ErrCodeValidation, "invalid content: check character limits and required fields")
-		statusCode = http.StatusBadRequest
+		return validationFailed(c, api.ValidationError{Field: "content", Message: "content is required and must be within the channel's character limit"})
+
+	case notification.ErrInvalidVariants, notification.ErrNoVariant:
+		return validationFailed(c, api.ValidationError{Field: "content_variants", Message: err.Error()})
+
+	case notification.ErrInvalidLocale:
+		return validationFailed(c, api.ValidationError{Field: "locale", Message: notification.ErrInvalidLocale.Error()})
+
+	case notification.ErrInvalidChatRecipient:
+		return validationFailed(c, api.ValidationError{Field: "recipient", Message: notification.ErrInvalidChatRecipient.Error()})
+
+	case notification.ErrInvalidChatBlocks:
+		return validationFailed(c, api.ValidationError{Field: "content", Message: notification.ErrInvalidChatBlocks.Error()})
 
 	case notification.ErrDuplicateRequest:
-		errResp = dto.NewErrorResponse(dto.ErrCodeDuplicateRequest, "duplicate request: idempotency key already used")
+		errResp = dto.NewErrorResponse(api.ErrCodeDuplicateRequest, "duplicate request: idempotency key already used")
 		statusCode = http.StatusConflict
 
+	case notification.ErrIdempotencyConflict:
+		errResp = dto.NewErrorResponse(api.ErrCodeIdempotencyConflict, "idempotency key already used with a different payload")
+		statusCode = http.StatusUnprocessableEntity
+	case notification.ErrInvalidCursor:
+		return validationFailed(c, api.ValidationError{Field: "cursor", Message: "cursor is invalid; use next_cursor from a previous page"})
+
 	case notification.ErrBatchTooLarge:
-		errResp = dto.NewErrorResponseWithDetails(
-			dto.ErrCodeValidation,
-			"batch size exceeds maximum",
-			map[string]interface{}{"max_size": 1000},
-		)
-		statusCode = http.StatusBadRequest
+		return validationFailed(c, api.ValidationError{
+			Field:   "items",
+			Message: fmt.Sprintf("batch must contain 1-%d items", notification.MaxBatchSize),
+		})
+
+	case notification.ErrQuotaExceeded:
+		errResp = dto.NewErrorResponse(api.ErrCodeQuotaExceeded, "daily channel quota exceeded for this tenant")
+		statusCode = http.StatusTooManyRequests
+
+	case notification.ErrNotOwner:
+		errResp = dto.NewErrorResponse(api.ErrCodeForbidden, "notification or batch belongs to another client")
+		statusCode = http.StatusForbidden
+
+	case notification.ErrImportNotFound:
+		errResp = dto.NewErrorResponse(api.ErrCodeNotFound, "import not found")
+		statusCode = http.StatusNotFound
+
+	case notification.ErrInvalidImportFormat:
+		return validationFailed(c, api.ValidationError{Field: "format", Message: "format must be ndjson or csv"})
+
+	case notification.ErrImportTooLarge:
+		errResp = dto.NewErrorResponse(api.ErrCodePayloadTooLarge, "import file exceeds the upload size limit")
+		statusCode = http.StatusRequestEntityTooLarge
 
 	case notification.ErrAlreadyTerminal:
-		errResp = dto.NewErrorResponse(dto.ErrCodeConflict, "notification already in terminal state")
+		errResp = dto.NewErrorResponse(api.ErrCodeConflict, "notification already in terminal state")
+		statusCode = http.StatusConflict
+
+	case profile.ErrNotFound:
+		return validationFailed(c, api.ValidationError{Field: "user_id", Message: "no profile exists for this user_id"})
+
+	case profile.ErrInvalidCategory:
+		return validationFailed(c, api.ValidationError{Field: "category", Message: "category must be lowercase letters, digits, '.', '_' and '-', at most 64 characters"})
+
+	case profile.ErrOptedOut:
+		errResp = dto.NewErrorResponse(api.ErrCodeUserOptedOut, "user opted out of this category on every channel")
+		statusCode = http.StatusUnprocessableEntity
+
+	case profile.ErrUnreachable:
+		errResp = dto.NewErrorResponse(api.ErrCodeUserUnreachable, "user has no address on a channel this key can send on")
+		statusCode = http.StatusUnprocessableEntity
+
+	default:
+		errResp = dto.NewErrorResponse(api.ErrCodeInternalServerError, "internal server error")
+		statusCode = http.StatusInternalServerError
+	}
+
+	return writeError(c, statusCode, errResp)
+}
+
+// mapAuthError maps API client errors to standardized HTTP error responses.
+func mapAuthError(c echo.Context, err error) error {
+	var errResp *dto.ErrorResponse
+	var statusCode int
+
+	switch err {
+	case auth.ErrNotFound:
+		errResp = dto.NewErrorResponse(api.ErrCodeNotFound, "api client not found")
+		statusCode = http.StatusNotFound
+
+	case auth.ErrInvalidName:
+		return validationFailed(c, api.ValidationError{Field: "name", Message: "name is required and must be at most 100 characters"})
+
+	case auth.ErrForbidden:
+		errResp = dto.NewErrorResponse(api.ErrCodeForbidden, "admin keys can only manage their own tenant's keys and cannot grant or rotate the platform scope")
+		statusCode = http.StatusForbidden
+
+	case auth.ErrInvalidScope:
+		return validationFailed(c, api.ValidationError{Field: "scopes", Message: "scopes must be one or more of: " + auth.ScopeList()})
+
+	case tenant.ErrNotFound:
+		return validationFailed(c, api.ValidationError{Field: "tenant_id", Message: "tenant does not exist"})
+
+	default:
+		errResp = dto.NewErrorResponse(api.ErrCodeInternalServerError, "internal server error")
+		statusCode = http.StatusInternalServerError
+	}
+
+	return writeError(c, statusCode, errResp)
+}
+
+// mapTenantError maps tenant errors to standardized HTTP error responses.
+func mapTenantError(c echo.Context, err error) error {
+	var errResp *dto.ErrorResponse
+	var statusCode int
+
+	switch err {
+	case tenant.ErrNotFound:
+		errResp = dto.NewErrorResponse(api.ErrCodeNotFound, "tenant not found")
+		statusCode = http.StatusNotFound
+
+	case tenant.ErrInvalidID:
+		return validationFailed(c, api.ValidationError{Field: "id", Message: "id must be lowercase letters, digits and dashes, at most 63 characters"})
+
+	case tenant.ErrInvalidName:
+		return validationFailed(c, api.ValidationError{Field: "name", Message: "name is required and must be at most 100 characters"})
+
+	case tenant.ErrInvalidQuota:
+		return validationFailed(c, api.ValidationError{Field: "daily_quotas", Message: "quotas must name channels (" + notification.ChannelList() + ") and not be negative"})
+
+	case notification.ErrInvalidWindow:
+		return validationFailed(c, api.ValidationError{Field: "delivery_windows", Message: api.DeliveryWindowMessage})
+
+	case notification.ErrInvalidDigestPolicy:
+		return validationFailed(c, api.ValidationError{
+			Field:   "digest_policies",
+			Message: fmt.Sprintf("window_seconds must be between %d and %d", int(notification.MinDigestWindow.Seconds()), int(notification.MaxDigestWindow.Seconds())),
+		})
+
+	case profile.ErrInvalidCategory:
+		return validationFailed(c, api.ValidationError{Field: "delivery_windows", Message: api.CategoryMessage})
+
+	case tenant.ErrExists:
+		errResp = dto.NewErrorResponse(api.ErrCodeConflict, "tenant already exists")
+		statusCode = http.StatusConflict
+
+	default:
+		errResp = dto.NewErrorResponse(api.ErrCodeInternalServerError, "internal server error")
+		statusCode = http.StatusInternalServerError
+	}
+
+	return writeError(c, statusCode, errResp)
+}
+
+// mapTemplateError maps template errors to standardized HTTP error responses.
+func mapTemplateError(c echo.Context, err error) error {
+	var errResp *dto.ErrorResponse
+	var statusCode int
+
+	switch {
+	case errors.Is(err, template.ErrNotFound):
+		errResp = dto.NewErrorResponse(api.ErrCodeNotFound, "template not found")
+		statusCode = http.StatusNotFound
+
+	case errors.Is(err, template.ErrInvalidName):
+		return validationFailed(c, api.ValidationError{Field: "name", Message: fmt.Sprintf("name is required and must be at most %d characters", template.MaxNameLength)})
+
+	case errors.Is(err, template.ErrInvalidBody):
+		return validationFailed(c, api.ValidationError{Field: "body", Message: err.Error()})
+
+	case errors.Is(err, template.ErrInvalidVariants):
+		return validationFailed(c, api.ValidationError{Field: "variants", Message: err.Error()})
+
+	case errors.Is(err, template.ErrExists):
+		errResp = dto.NewErrorResponse(api.ErrCodeConflict, "a template with this name already exists")
 		statusCode = http.StatusConflict
 
 	default:
-		errResp = dto.NewErrorResponse(dto.ErrCodeInternalServerError, "internal server error")
+		return mapNotificationError(c, err)
+	}
+
+	return writeError(c, statusCode, errResp)
+}
+
+// mapProfileError maps user profile errors to standardized HTTP error responses.
+func mapProfileError(c echo.Context, err error) error {
+	var errResp *dto.ErrorResponse
+	var statusCode int
+
+	switch err {
+	case profile.ErrNotFound:
+		errResp = dto.NewErrorResponse(api.ErrCodeNotFound, "user profile not found")
+		statusCode = http.StatusNotFound
+
+	case profile.ErrInvalidUserID:
+		return validationFailed(c, api.ValidationError{Field: "id", Message: fmt.Sprintf("user id must be 1-%d characters", profile.MaxUserIDLength)})
+
+	case profile.ErrInvalidCategory:
+		return validationFailed(c, api.ValidationError{Field: "preferences", Message: api.CategoryMessage})
+
+	case profile.ErrInvalidProfile:
+		return validationFailed(c, api.ValidationError{
+			Field:   "profile",
+			Message: fmt.Sprintf("channels must be one of %s, addresses at most %d characters, at most %d push tokens, the locale a BCP 47 tag and the time zone an IANA name", notification.ChannelList(), notification.MaxRecipientLength, profile.MaxPushTokens),
+		})
+
+	default:
+		errResp = dto.NewErrorResponse(api.ErrCodeInternalServerError, "internal server error")
 		statusCode = http.StatusInternalServerError
 	}
 
-	// Add request ID from context if available
+	return writeError(c, statusCode, errResp)
+}
+
+// mapAudienceError maps audience errors to standardized HTTP error responses. Errors
+// of sends to an audience fall through to mapNotificationError.
+func mapAudienceError(c echo.Context, err error) error {
+	var errResp *dto.ErrorResponse
+	var statusCode int
+
+	switch err {
+	case audience.ErrNotFound:
+		errResp = dto.NewErrorResponse(api.ErrCodeNotFound, "audience not found")
+		statusCode = http.StatusNotFound
+
+	case audience.ErrMemberNotFound:
+		errResp = dto.NewErrorResponse(api.ErrCodeNotFound, "audience member not found")
+		statusCode = http.StatusNotFound
+
+	case audience.ErrInvalidName:
+		return validationFailed(c, api.ValidationError{Field: "name", Message: fmt.Sprintf("name is required and must be at most %d characters", audience.MaxNameLength)})
+
+	case audience.ErrExists:
+		errResp = dto.NewErrorResponse(api.ErrCodeConflict, "an audience with this name already exists")
+		statusCode = http.StatusConflict
+
+	case audience.ErrInvalidMember:
+		return validationFailed(c, api.ValidationError{Field: "members", Message: "members need a channel (" + notification.ChannelList() + ") and a recipient valid for it"})
+
+	case audience.ErrTooManyMembers:
+		return validationFailed(c, api.ValidationError{
+			Field:   "members",
+			Message: fmt.Sprintf("request must contain 1-%d members", audience.MaxMembersPerRequest),
+		})
+
+	default:
+		return mapNotificationError(c, err)
+	}
+
+	return writeError(c, statusCode, errResp)
+}
+
+// mapInboxError maps inbox errors to standardized HTTP error responses.
+func mapInboxError(c echo.Context, err error) error {
+	var errResp *dto.ErrorResponse
+	var statusCode int
+
+	switch err {
+	case inbox.ErrNotFound:
+		errResp = dto.NewErrorResponse(api.ErrCodeNotFound, "inbox message not found")
+		statusCode = http.StatusNotFound
+
+	case inbox.ErrInvalidState:
+		return validationFailed(c, api.ValidationError{Field: "state", Message: "state must be one of: unread, read, archived"})
+
+	default:
+		return mapNotificationError(c, err)
+	}
+
+	return writeError(c, statusCode, errResp)
+}
+
+// validationError writes a 400 response listing every invalid field of err, which
+// should be api.ValidationErrors; any other error becomes a bad request.
+func validationError(c echo.Context, err error) error {
+	var verrs api.ValidationErrors
+	if errors.As(err, &verrs) {
+		return validationFailed(c, verrs...)
+	}
+	return badRequest(c, err.Error())
+}
+
+// validationFailed writes a 400 validation error response.
+func validationFailed(c echo.Context, errs ...api.ValidationError) error {
+	return writeError(c, http.StatusBadRequest, dto.NewValidationErrorResponse(errs))
+}
+
+// badRequest writes a 400 response for a request that could not be read at all.
+func badRequest(c echo.Context, message string) error {
+	return writeError(c, http.StatusBadRequest, dto.NewErrorResponse(api.ErrCodeBadRequest, message))
+}
+
+// writeError writes errResp with the request ID, if any.
+func writeError(c echo.Context, statusCode int, errResp *dto.ErrorResponse) error {
 	if reqID := c.Response().Header().Get(echo.HeaderXRequestID); reqID != "" {
 		errResp = errResp.WithRequestID(reqID)
 	}
-
 	return c.JSON(statusCode, errResp)
 }
@@ -0,0 +1,166 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/semih-yildiz/notification-service/internal/application/notification/port"
+	"github.com/semih-yildiz/notification-service/internal/application/notification/query/get"
+	"github.com/semih-yildiz/notification-service/internal/domain/notification"
+	"github.com/semih-yildiz/notification-service/internal/http/dto"
+)
+
+const (
+	// sseHeartbeat keeps idle streams open through proxies that drop silent connections.
+	sseHeartbeat = 15 * time.Second
+	// batchRefreshInterval limits how often a batch stream reloads the batch's counts.
+	batchRefreshInterval = time.Second
+)
+
+// NotificationEvents handles GET /notifications/:id/events. It sends the notification
+// as a "notification" event, then a "status" event per change, and ends once the
+// notification is terminal.
+func (h *NotificationHandler) NotificationEvents(c echo.Context) error {
+	ctx := c.Request().Context()
+	id := c.Param("id")
+
+	// Subscribe before reading the current state so no change falls in between.
+	events, err := h.events.SubscribeNotification(ctx, id)
+	if err != nil {
+		return mapNotificationError(c, err)
+	}
+	n, err := h.getUsecase.Notification(ctx, &get.ByID{ID: id})
+	if err != nil {
+		return mapNotificationError(c, err)
+	}
+
+	stream := openEventStream(c)
+	if err := stream.send("notification", n); err != nil || n.Status.Terminal() {
+		return nil
+	}
+
+	heartbeat := time.NewTicker(sseHeartbeat)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-heartbeat.C:
+			if err := stream.ping(); err != nil {
+				return nil
+			}
+		case evt, ok := <-events:
+			if !ok {
+				return nil
+			}
+			if err := stream.send("status", toStatusEventResponse(evt)); err != nil || evt.Status.Terminal() {
+				return nil
+			}
+		}
+	}
+}
+
+// BatchEvents handles GET /batches/:id/events. It sends the batch summary as a
+// "batch" event, a "status" event per change and, at most once a second, the
+// updated summary. It ends once every notification of the batch is terminal.
+func (h *NotificationHandler) BatchEvents(c echo.Context) error {
+	ctx := c.Request().Context()
+	batchID := c.Param("id")
+
+	events, err := h.events.SubscribeBatch(ctx, batchID)
+	if err != nil {
+		return mapNotificationError(c, err)
+	}
+	batch, err := h.getUsecase.BatchSummary(ctx, &get.BatchByID{BatchID: batchID})
+	if err != nil {
+		return mapNotificationError(c, err)
+	}
+
+	stream := openEventStream(c)
+	if err := stream.send("batch", toBatchResponse(batch)); err != nil || batch.Status() != notification.BatchStatusInProgress {
+		return nil
+	}
+
+	heartbeat := time.NewTicker(sseHeartbeat)
+	defer heartbeat.Stop()
+	refresh := time.NewTicker(batchRefreshInterval)
+	defer refresh.Stop()
+	changed := false
+	for {
+		select {
+		case <-heartbeat.C:
+			if err := stream.ping(); err != nil {
+				return nil
+			}
+		case evt, ok := <-events:
+			if !ok {
+				return nil
+			}
+			if err := stream.send("status", toStatusEventResponse(evt)); err != nil {
+				return nil
+			}
+			changed = true
+		case <-refresh.C:
+			if !changed {
+				continue
+			}
+			changed = false
+			batch, err := h.getUsecase.BatchSummary(ctx, &get.BatchByID{BatchID: batchID})
+			if err != nil {
+				// Keep streaming status events; the summary catches up on the next change.
+				continue
+			}
+			if err := stream.send("batch", toBatchResponse(batch)); err != nil || batch.Status() != notification.BatchStatusInProgress {
+				return nil
+			}
+		}
+	}
+}
+
+// eventStream writes Server-Sent Events to a response.
+type eventStream struct {
+	res *echo.Response
+}
+
+func openEventStream(c echo.Context) eventStream {
+	res := c.Response()
+	res.Header().Set(echo.HeaderContentType, "text/event-stream")
+	res.Header().Set(echo.HeaderCacheControl, "no-cache")
+	res.Header().Set(echo.HeaderConnection, "keep-alive")
+	res.Header().Set("X-Accel-Buffering", "no") // disable proxy buffering (nginx)
+	res.WriteHeader(http.StatusOK)
+	res.Flush()
+	return eventStream{res: res}
+}
+
+func (s eventStream) send(event string, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(s.res, "event: %s\ndata: %s\n\n", event, data); err != nil {
+		return err
+	}
+	s.res.Flush()
+	return nil
+}
+
+func (s eventStream) ping() error {
+	if _, err := fmt.Fprint(s.res, ": ping\n\n"); err != nil {
+		return err
+	}
+	s.res.Flush()
+	return nil
+}
+
+func toStatusEventResponse(evt *port.StatusEvent) dto.StatusEventResponse {
+	return dto.StatusEventResponse{
+		NotificationID: evt.NotificationID,
+		BatchID:        evt.BatchID,
+		Status:         evt.Status.String(),
+		FailureReason:  evt.FailureReason,
+		OccurredAt:     evt.OccurredAt,
+	}
+}
@@ -0,0 +1,43 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/semih-yildiz/notification-service/internal/application/notification/port"
+	"github.com/semih-yildiz/notification-service/internal/domain/notification"
+)
+
+func TestEventStream_Framing(t *testing.T) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/notifications/n1/events", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	stream := openEventStream(c)
+	evt := &port.StatusEvent{
+		NotificationID: "n1",
+		Status:         notification.StatusSent,
+		OccurredAt:     time.Date(2024, 1, 31, 10, 0, 0, 0, time.UTC),
+	}
+	if err := stream.send("status", toStatusEventResponse(evt)); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if err := stream.ping(); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if ct := rec.Header().Get(echo.HeaderContentType); ct != "text/event-stream" {
+		t.Errorf("expected text/event-stream, got %q", ct)
+	}
+	want := "event: status\n" +
+		`data: {"notification_id":"n1","status":"sent","occurred_at":"2024-01-31T10:00:00Z"}` + "\n\n" +
+		": ping\n\n"
+	if rec.Body.String() != want {
+		t.Errorf("expected body %q, got %q", want, rec.Body.String())
+	}
+}
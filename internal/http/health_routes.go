@@ -7,7 +7,10 @@ import (
 	"github.com/redis/go-redis/v9"
 
 	"github.com/semih-yildiz/notification-service/internal/application/notification/port"
+	"github.com/semih-yildiz/notification-service/internal/domain/auth"
+	httpmw "github.com/semih-yildiz/notification-service/internal/http/middleware"
 	"github.com/semih-yildiz/notification-service/internal/infrastructure/messaging/rabbitmq"
+	sharedctx "github.com/semih-yildiz/notification-service/internal/shared/context"
 )
 
 func RegisterHealthRoutes(e *echo.Echo, handler *HealthHandler) {
@@ -15,6 +18,12 @@ func RegisterHealthRoutes(e *echo.Echo, handler *HealthHandler) {
 	e.GET("/metrics", handler.Metrics)
 }
 
+// RegisterTenantMetricsRoutes registers the per-tenant breakdown of /metrics, which
+// names every tenant and so needs the platform scope.
+func RegisterTenantMetricsRoutes(g *echo.Group, handler *HealthHandler) {
+	g.GET("/admin/metrics/tenants", handler.TenantMetrics, httpmw.RequireScope(auth.ScopePlatform))
+}
+
 type HealthHandler struct {
 	db              interface{ Ping() error }
 	redis           *redis.Client
@@ -56,7 +65,8 @@ func (h *HealthHandler) Health(c echo.Context) error {
 }
 
 func (h *HealthHandler) Metrics(c echo.Context) error {
-	ctx := c.Request().Context()
+	// Totals over every tenant; the endpoint is not authenticated, so no tenant.
+	ctx := sharedctx.Unscoped(c.Request().Context())
 
 	if h.metricsProvider == nil {
 		return c.String(http.StatusServiceUnavailable, "metrics provider not configured")
@@ -68,17 +78,7 @@ func (h *HealthHandler) Metrics(c echo.Context) error {
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
 	}
 
-	response := map[string]interface{}{
-		"notifications": map[string]int64{
-			"pending": stats.Pending,
-			"queued":  stats.Queued,
-			"sent":    stats.Sent,
-			"failed":  stats.Failed,
-			"total":   stats.Total,
-		},
-		"success_rate": calculateRate(stats.Sent, stats.Total),
-		"failure_rate": calculateRate(stats.Failed, stats.Total),
-	}
+	response := statsResponse(stats)
 
 	// Get RabbitMQ queue depths (if management client configured)
 	if h.mqManagement != nil {
@@ -99,6 +99,43 @@ func (h *HealthHandler) Metrics(c echo.Context) error {
 	return c.JSON(http.StatusOK, response)
 }
 
+// TenantMetrics returns notification counts per tenant.
+func (h *HealthHandler) TenantMetrics(c echo.Context) error {
+	if h.metricsProvider == nil {
+		return c.String(http.StatusServiceUnavailable, "metrics provider not configured")
+	}
+
+	// Every tenant, whichever tenant the platform key belongs to.
+	tenantStats, err := h.metricsProvider.GetTenantStats(sharedctx.Unscoped(c.Request().Context()))
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	tenants := make(map[string]interface{}, len(tenantStats))
+	for id, ts := range tenantStats {
+		tenants[id] = statsResponse(ts)
+	}
+	return c.JSON(http.StatusOK, map[string]interface{}{"tenants": tenants})
+}
+
+func statsResponse(stats *port.NotificationStats) map[string]interface{} {
+	return map[string]interface{}{
+		"notifications": map[string]int64{
+			"pending":     stats.Pending,
+			"queued":      stats.Queued,
+			"deferred":    stats.Deferred,
+			"buffered":    stats.Buffered,
+			"digested":    stats.Digested,
+			"sent":        stats.Sent,
+			"delivered":   stats.Delivered,
+			"undelivered": stats.Undelivered,
+			"failed":      stats.Failed,
+			"total":       stats.Total,
+		},
+		"success_rate": calculateRate(stats.Sent+stats.Delivered, stats.Total),
+		"failure_rate": calculateRate(stats.Failed+stats.Undelivered, stats.Total),
+	}
+}
+
 func calculateRate(part, total int64) float64 {
 	if total == 0 {
 		return 0.0
@@ -0,0 +1,148 @@
+package http
+
+import (
+	"encoding/csv"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/semih-yildiz/notification-service/internal/api"
+	"github.com/semih-yildiz/notification-service/internal/application/notification/command/bulk"
+	"github.com/semih-yildiz/notification-service/internal/application/notification/query/imports"
+	"github.com/semih-yildiz/notification-service/internal/domain/notification"
+	"github.com/semih-yildiz/notification-service/internal/http/dto"
+	httpmw "github.com/semih-yildiz/notification-service/internal/http/middleware"
+)
+
+// importRouteName names GET /imports/:id, to build job URLs under any base path.
+const importRouteName = "imports.get"
+
+// importErrorsPage is how many rows of errors the error report reads at a time.
+const importErrorsPage = 1000
+
+// importContentTypes maps upload media types to import formats.
+var importContentTypes = map[string]notification.ImportFormat{
+	"application/x-ndjson": notification.ImportFormatNDJSON,
+	"application/ndjson":   notification.ImportFormatNDJSON,
+	"application/jsonl":    notification.ImportFormatNDJSON,
+	"text/csv":             notification.ImportFormatCSV,
+}
+
+// CreateImport handles POST /imports. The body is the file itself; its format comes
+// from ?format= or else the Content-Type. It responds 202 with the pending job as
+// soon as the file is stored. Send scopes are checked per row.
+func (h *NotificationHandler) CreateImport(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	format := notification.ImportFormat(c.QueryParam("format"))
+	if format == "" {
+		mediaType, _, _ := mime.ParseMediaType(c.Request().Header.Get(echo.HeaderContentType))
+		format = importContentTypes[mediaType]
+	}
+	if !format.Valid() {
+		return validationFailed(c, api.ValidationError{
+			Field:   "format",
+			Message: "format must be ndjson or csv, given as ?format= or by a Content-Type of application/x-ndjson or text/csv",
+		})
+	}
+
+	client := httpmw.ClientFromContext(ctx)
+	if client == nil {
+		return httpmw.Forbidden(c, "api key required")
+	}
+
+	job, err := h.importUsecase.Start(ctx, &bulk.Command{
+		Format:   format,
+		Body:     c.Request().Body,
+		ClientID: &client.ID,
+		TenantID: client.TenantID,
+		CanSend:  client.CanSend,
+	})
+	if err != nil {
+		return mapNotificationError(c, err)
+	}
+
+	c.Response().Header().Set(echo.HeaderLocation, importPath(c, job.ID))
+	return c.JSON(http.StatusAccepted, toImportJobResponse(c, job))
+}
+
+// GetImport handles GET /imports/:id
+func (h *NotificationHandler) GetImport(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	job, err := h.importQuery.Job(ctx, &imports.ByID{ID: c.Param("id")})
+	if err != nil {
+		return mapNotificationError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, toImportJobResponse(c, job))
+}
+
+// GetImportErrors handles GET /imports/:id/errors. It streams the rejected rows as
+// CSV with the columns row, field and message; row is the line number in the upload.
+// While the import runs the report covers the rows processed so far.
+func (h *NotificationHandler) GetImportErrors(c echo.Context) error {
+	ctx := c.Request().Context()
+	id := c.Param("id")
+
+	page, err := h.importQuery.RowErrors(ctx, &imports.RowErrors{ImportID: id, Limit: importErrorsPage})
+	if err != nil {
+		return mapNotificationError(c, err)
+	}
+
+	res := c.Response()
+	res.Header().Set(echo.HeaderContentType, "text/csv; charset=utf-8")
+	res.Header().Set(echo.HeaderContentDisposition, `attachment; filename="import-`+id+`-errors.csv"`)
+	res.WriteHeader(http.StatusOK)
+
+	w := csv.NewWriter(res)
+	if err := w.Write([]string{"row", "field", "message"}); err != nil {
+		return nil
+	}
+	for len(page) > 0 {
+		for _, re := range page {
+			if err := w.Write([]string{strconv.Itoa(re.Row), re.Field, re.Message}); err != nil {
+				return nil
+			}
+		}
+		w.Flush()
+		if w.Error() != nil {
+			return nil
+		}
+		page, err = h.importQuery.RowErrors(ctx, &imports.RowErrors{ImportID: id, AfterRow: page[len(page)-1].Row, Limit: importErrorsPage})
+		if err != nil {
+			// Headers are already sent; the truncated report is all we can give.
+			return nil
+		}
+	}
+	w.Flush()
+	return nil
+}
+
+func importPath(c echo.Context, id string) string {
+	return c.Echo().Reverse(importRouteName, id)
+}
+
+func toImportJobResponse(c echo.Context, job *notification.ImportJob) dto.ImportJobResponse {
+	batchIDs := job.BatchIDs
+	if batchIDs == nil {
+		batchIDs = []string{}
+	}
+	return dto.ImportJobResponse{
+		ID:            job.ID,
+		Format:        job.Format.String(),
+		Status:        job.Status.String(),
+		TotalRows:     job.TotalRows,
+		AcceptedRows:  job.AcceptedRows,
+		RejectedRows:  job.RejectedRows,
+		DuplicateRows: job.DuplicateRows,
+		BatchIDs:      batchIDs,
+		FailureReason: job.FailureReason,
+		ErrorsURL:     importPath(c, job.ID) + "/errors",
+		CreatedAt:     job.CreatedAt,
+		UpdatedAt:     job.UpdatedAt,
+		CompletedAt:   job.CompletedAt,
+	}
+}
@@ -0,0 +1,169 @@
+package http
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/semih-yildiz/notification-service/internal/api"
+	inboxmanage "github.com/semih-yildiz/notification-service/internal/application/inbox/command/manage"
+	inboxport "github.com/semih-yildiz/notification-service/internal/application/inbox/port"
+	inboxlookup "github.com/semih-yildiz/notification-service/internal/application/inbox/query/lookup"
+	"github.com/semih-yildiz/notification-service/internal/domain/inbox"
+	"github.com/semih-yildiz/notification-service/internal/http/dto"
+	httpmw "github.com/semih-yildiz/notification-service/internal/http/middleware"
+)
+
+// ListInbox handles GET /users/:id/inbox. Messages are newest first; archived ones
+// are only listed with ?state=archived.
+func (h *NotificationHandler) ListInbox(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, err := url.PathUnescape(c.Param("id"))
+	if err != nil {
+		return badRequest(c, "user id must be path-escaped")
+	}
+	limit := 0
+	if v := c.QueryParam("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			return validationFailed(c, api.ValidationError{Field: "limit", Message: "limit must be a positive integer"})
+		}
+		limit = n
+	}
+
+	page, err := h.inboxLookup.Messages(ctx, &inboxlookup.MessagesQuery{
+		UserID: userID,
+		State:  c.QueryParam("state"),
+		Cursor: c.QueryParam("cursor"),
+		Limit:  limit,
+	})
+	if err != nil {
+		return mapInboxError(c, err)
+	}
+
+	response := dto.InboxResponse{
+		Messages:   make([]dto.InboxMessageResponse, len(page.Messages)),
+		Unread:     page.Unread,
+		NextCursor: page.NextCursor,
+	}
+	for i, m := range page.Messages {
+		response.Messages[i] = toInboxMessageResponse(m)
+	}
+	return c.JSON(http.StatusOK, response)
+}
+
+// GetInboxUnread handles GET /users/:id/inbox/unread
+func (h *NotificationHandler) GetInboxUnread(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, err := url.PathUnescape(c.Param("id"))
+	if err != nil {
+		return badRequest(c, "user id must be path-escaped")
+	}
+	unread, err := h.inboxLookup.Unread(ctx, userID)
+	if err != nil {
+		return mapInboxError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, dto.InboxUnreadResponse{Unread: unread})
+}
+
+// MarkInboxMessage handles PUT /users/:id/inbox/:message_id/state. It marks the
+// message read, unread or archived and responds with the message.
+func (h *NotificationHandler) MarkInboxMessage(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, err := url.PathUnescape(c.Param("id"))
+	if err != nil {
+		return badRequest(c, "user id must be path-escaped")
+	}
+	var req dto.MarkInboxMessageRequest
+	if err := c.Bind(&req); err != nil {
+		return badRequest(c, "request body must be a JSON object")
+	}
+
+	m, err := h.inboxManage.Mark(ctx, &inboxmanage.MarkCommand{
+		UserID:    userID,
+		MessageID: c.Param("message_id"),
+		State:     req.State,
+	})
+	if err != nil {
+		return mapInboxError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, toInboxMessageResponse(m))
+}
+
+// InboxEvents handles GET /users/:id/inbox/events. It sends the unread count as an
+// "inbox" event, then a "message" event per delivered message and a "state" event
+// per message marked read, unread or archived, each with the new unread count. It
+// runs until the client disconnects.
+func (h *NotificationHandler) InboxEvents(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, err := url.PathUnescape(c.Param("id"))
+	if err != nil {
+		return badRequest(c, "user id must be path-escaped")
+	}
+	client := httpmw.ClientFromContext(ctx)
+	if client == nil {
+		return httpmw.Forbidden(c, "api key required")
+	}
+
+	// Subscribe before reading the count so no change falls in between.
+	events, err := h.inboxEvents.Subscribe(ctx, client.TenantID, userID)
+	if err != nil {
+		return mapInboxError(c, err)
+	}
+	unread, err := h.inboxLookup.Unread(ctx, userID)
+	if err != nil {
+		return mapInboxError(c, err)
+	}
+
+	stream := openEventStream(c)
+	if err := stream.send("inbox", dto.InboxUnreadResponse{Unread: unread}); err != nil {
+		return nil
+	}
+
+	heartbeat := time.NewTicker(sseHeartbeat)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-heartbeat.C:
+			if err := stream.ping(); err != nil {
+				return nil
+			}
+		case evt, ok := <-events:
+			if !ok {
+				return nil
+			}
+			if err := stream.send(string(evt.Kind), toInboxEventResponse(evt)); err != nil {
+				return nil
+			}
+		}
+	}
+}
+
+func toInboxMessageResponse(m *inbox.Message) dto.InboxMessageResponse {
+	return dto.InboxMessageResponse{
+		ID:         m.ID,
+		Category:   m.Category,
+		Content:    m.Content,
+		State:      m.State.String(),
+		ReadAt:     m.ReadAt,
+		ArchivedAt: m.ArchivedAt,
+		CreatedAt:  m.CreatedAt,
+		UpdatedAt:  m.UpdatedAt,
+	}
+}
+
+func toInboxEventResponse(evt *inboxport.Event) dto.InboxEventResponse {
+	return dto.InboxEventResponse{
+		Message: toInboxMessageResponse(evt.Message),
+		Unread:  evt.Unread,
+	}
+}
@@ -0,0 +1,150 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	inboxmanage "github.com/semih-yildiz/notification-service/internal/application/inbox/command/manage"
+	inboxport "github.com/semih-yildiz/notification-service/internal/application/inbox/port"
+	inboxlookup "github.com/semih-yildiz/notification-service/internal/application/inbox/query/lookup"
+	"github.com/semih-yildiz/notification-service/internal/domain/auth"
+	"github.com/semih-yildiz/notification-service/internal/domain/inbox"
+	"github.com/semih-yildiz/notification-service/internal/http/dto"
+)
+
+// keyAuthenticator accepts keys named after the scope they grant.
+type keyAuthenticator struct{}
+
+func (keyAuthenticator) Authenticate(ctx context.Context, key string) (*auth.Client, error) {
+	return &auth.Client{ID: "c-1", TenantID: "acme", Scopes: []auth.Scope{auth.Scope(key)}}, nil
+}
+
+type stubInboxRepo struct {
+	messages []*inbox.Message // newest first
+}
+
+func (r *stubInboxRepo) Add(ctx context.Context, m *inbox.Message) (bool, error) {
+	return false, errors.New("not implemented")
+}
+
+func (r *stubInboxRepo) GetByID(ctx context.Context, userID, id string) (*inbox.Message, error) {
+	for _, m := range r.messages {
+		if m.ID == id && m.UserID == userID {
+			return m, nil
+		}
+	}
+	return nil, inbox.ErrNotFound
+}
+
+func (r *stubInboxRepo) Update(ctx context.Context, m *inbox.Message) error { return nil }
+
+func (r *stubInboxRepo) List(ctx context.Context, userID string, filter inboxport.MessageFilter) ([]*inbox.Message, error) {
+	var out []*inbox.Message
+	for _, m := range r.messages {
+		if filter.After != nil && !m.CreatedAt.Before(filter.After.CreatedAt) {
+			continue
+		}
+		for _, s := range filter.States {
+			if m.State == s && m.UserID == userID {
+				out = append(out, m)
+			}
+		}
+	}
+	if len(out) > filter.Limit {
+		out = out[:filter.Limit]
+	}
+	return out, nil
+}
+
+func (r *stubInboxRepo) CountUnread(ctx context.Context, userID string) (int64, error) {
+	var n int64
+	for _, m := range r.messages {
+		if m.UserID == userID && m.State == inbox.StateUnread {
+			n++
+		}
+	}
+	return n, nil
+}
+
+func serveInbox(repo *stubInboxRepo, method, target, body, key string) *httptest.ResponseRecorder {
+	handler := NewNotificationHandler(nil, nil, nil, nil).
+		WithInbox(inboxmanage.NewUseCase(repo, nopLogger{}), inboxlookup.NewUseCase(repo), nil)
+	e := NewEcho(handler, nil, nil, keyAuthenticator{}, RateLimits{}, "")
+
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+key)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func newStubInbox() *stubInboxRepo {
+	now := time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)
+	return &stubInboxRepo{messages: []*inbox.Message{
+		{ID: "m-3", UserID: "u-1", State: inbox.StateUnread, CreatedAt: now.Add(2 * time.Minute)},
+		{ID: "m-2", UserID: "u-1", State: inbox.StateArchived, CreatedAt: now.Add(time.Minute)},
+		{ID: "m-1", UserID: "u-1", State: inbox.StateRead, CreatedAt: now},
+	}}
+}
+
+func TestListInbox_Pages(t *testing.T) {
+	repo := newStubInbox()
+
+	rec := serveInbox(repo, http.MethodGet, "/users/u-1/inbox?limit=1", "", "read")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var first dto.InboxResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &first); err != nil {
+		t.Fatal(err)
+	}
+	if len(first.Messages) != 1 || first.Messages[0].ID != "m-3" || first.Unread != 1 || first.NextCursor == "" {
+		t.Fatalf("expected m-3, 1 unread and a next cursor, got %+v", first)
+	}
+
+	rec = serveInbox(repo, http.MethodGet, "/users/u-1/inbox?limit=1&cursor="+first.NextCursor, "", "read")
+	var second dto.InboxResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &second); err != nil {
+		t.Fatal(err)
+	}
+	if len(second.Messages) != 1 || second.Messages[0].ID != "m-1" || second.NextCursor != "" {
+		t.Errorf("expected m-1 on the last page, skipping the archived m-2, got %+v", second)
+	}
+}
+
+func TestInboxRoutes(t *testing.T) {
+	tests := []struct {
+		name     string
+		method   string
+		target   string
+		body     string
+		key      string
+		wantCode int
+	}{
+		{"Unread count", http.MethodGet, "/users/u-1/inbox/unread", "", "read", http.StatusOK},
+		{"Archived only", http.MethodGet, "/users/u-1/inbox?state=archived", "", "read", http.StatusOK},
+		{"Invalid state filter", http.MethodGet, "/users/u-1/inbox?state=deleted", "", "read", http.StatusBadRequest},
+		{"Invalid cursor", http.MethodGet, "/users/u-1/inbox?cursor=bm9wZQ", "", "read", http.StatusBadRequest},
+		{"Mark read", http.MethodPut, "/users/u-1/inbox/m-3/state", `{"state":"read"}`, "inbox", http.StatusOK},
+		{"Mark without inbox scope", http.MethodPut, "/users/u-1/inbox/m-3/state", `{"state":"read"}`, "read", http.StatusForbidden},
+		{"Mark invalid state", http.MethodPut, "/users/u-1/inbox/m-3/state", `{"state":"deleted"}`, "inbox", http.StatusBadRequest},
+		{"Mark another user's message", http.MethodPut, "/users/u-2/inbox/m-3/state", `{"state":"read"}`, "inbox", http.StatusNotFound},
+		{"No stream without events", http.MethodGet, "/users/u-1/inbox/events", "", "read", http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serveInbox(newStubInbox(), tt.method, tt.target, tt.body, tt.key)
+			if rec.Code != tt.wantCode {
+				t.Errorf("expected %d, got %d: %s", tt.wantCode, rec.Code, rec.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/semih-yildiz/notification-service/internal/api"
+	"github.com/semih-yildiz/notification-service/internal/domain/auth"
+	"github.com/semih-yildiz/notification-service/internal/http/dto"
+	sharedctx "github.com/semih-yildiz/notification-service/internal/shared/context"
+)
+
+// HeaderAPIKey is an alternative to "Authorization: Bearer <key>".
+const HeaderAPIKey = "X-API-Key"
+
+// Authenticator resolves an API key to its client.
+type Authenticator interface {
+	Authenticate(ctx context.Context, key string) (*auth.Client, error)
+}
+
+// APIKeyAuth returns middleware that rejects requests without a valid API key and
+// stores the authenticated client in the request context, scoped to its tenant.
+func APIKeyAuth(a Authenticator) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			ctx := c.Request().Context()
+			client, err := a.Authenticate(ctx, apiKey(c.Request()))
+			if errors.Is(err, auth.ErrInvalidKey) {
+				c.Response().Header().Set(echo.HeaderWWWAuthenticate, "Bearer")
+				return c.JSON(http.StatusUnauthorized, dto.NewErrorResponse(api.ErrCodeUnauthorized, "invalid or missing api key"))
+			}
+			if err != nil {
+				return c.JSON(http.StatusInternalServerError, dto.NewErrorResponse(api.ErrCodeInternalServerError, "internal server error"))
+			}
+			ctx = context.WithValue(ctx, sharedctx.APIClientKey(), client)
+			ctx = sharedctx.WithTenantID(ctx, client.TenantID)
+			c.SetRequest(c.Request().WithContext(ctx))
+			return next(c)
+		}
+	}
+}
+
+// RequireScope returns middleware that rejects clients without scope.
+// It must run after APIKeyAuth.
+func RequireScope(scope auth.Scope) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			client := ClientFromContext(c.Request().Context())
+			if client == nil || !client.Can(scope) {
+				return Forbidden(c, "api key lacks the "+scope.String()+" scope")
+			}
+			return next(c)
+		}
+	}
+}
+
+// ClientFromContext returns the authenticated client, or nil.
+func ClientFromContext(ctx context.Context) *auth.Client {
+	client, _ := ctx.Value(sharedctx.APIClientKey()).(*auth.Client)
+	return client
+}
+
+// Forbidden writes a 403 error response.
+func Forbidden(c echo.Context, message string) error {
+	return c.JSON(http.StatusForbidden, dto.NewErrorResponse(api.ErrCodeForbidden, message))
+}
+
+func apiKey(r *http.Request) string {
+	if k := r.Header.Get(HeaderAPIKey); k != "" {
+		return k
+	}
+	if token, ok := strings.CutPrefix(r.Header.Get(echo.HeaderAuthorization), "Bearer "); ok {
+		return strings.TrimSpace(token)
+	}
+	return ""
+}
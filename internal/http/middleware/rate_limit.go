@@ -0,0 +1,69 @@
+package middleware
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/semih-yildiz/notification-service/internal/api"
+	"github.com/semih-yildiz/notification-service/internal/http/dto"
+)
+
+// Rate limit response headers.
+const (
+	HeaderRateLimitLimit     = "X-RateLimit-Limit"
+	HeaderRateLimitRemaining = "X-RateLimit-Remaining"
+	HeaderRateLimitReset     = "X-RateLimit-Reset" // unix time the window resets
+)
+
+// RateLimit returns middleware that limits requests per API client, or per IP on
+// routes without one, and rejects those over the policy with 429. Behind
+// APIKeyAuth every request has a client. Store errors let requests through.
+func RateLimit(store api.RateLimitStore, p api.RateLimitPolicy) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		if store == nil || !p.Enabled() {
+			return next
+		}
+		return func(c echo.Context) error {
+			ctx := c.Request().Context()
+			key := p.IPKey(c.RealIP())
+			if client := ClientFromContext(ctx); client != nil {
+				key = p.Key(client.ID)
+			}
+			count, reset, err := store.Hit(ctx, key, p.Window)
+			if err != nil {
+				return next(c)
+			}
+
+			remaining := p.Limit - count
+			if remaining < 0 {
+				remaining = 0
+			}
+			h := c.Response().Header()
+			h.Set(HeaderRateLimitLimit, strconv.Itoa(p.Limit))
+			h.Set(HeaderRateLimitRemaining, strconv.Itoa(remaining))
+			h.Set(HeaderRateLimitReset, strconv.FormatInt(reset.Unix(), 10))
+
+			if count > p.Limit {
+				retryAfter := int(math.Ceil(time.Until(reset).Seconds()))
+				if retryAfter < 1 {
+					retryAfter = 1
+				}
+				h.Set(echo.HeaderRetryAfter, strconv.Itoa(retryAfter))
+				errResp := dto.NewErrorResponseWithDetails(
+					api.ErrCodeRateLimitExceeded,
+					"rate limit exceeded, retry later",
+					map[string]interface{}{"bucket": p.Bucket, "limit": p.Limit, "retry_after_seconds": retryAfter},
+				)
+				if reqID := h.Get(echo.HeaderXRequestID); reqID != "" {
+					errResp = errResp.WithRequestID(reqID)
+				}
+				return c.JSON(http.StatusTooManyRequests, errResp)
+			}
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/semih-yildiz/notification-service/internal/api"
+	"github.com/semih-yildiz/notification-service/internal/domain/auth"
+	sharedctx "github.com/semih-yildiz/notification-service/internal/shared/context"
+)
+
+type memoryRateLimitStore struct {
+	counts map[string]int
+	err    error
+}
+
+func (m *memoryRateLimitStore) Hit(ctx context.Context, key string, window time.Duration) (int, time.Time, error) {
+	if m.err != nil {
+		return 0, time.Time{}, m.err
+	}
+	if m.counts == nil {
+		m.counts = make(map[string]int)
+	}
+	m.counts[key]++
+	return m.counts[key], time.Now().Add(window), nil
+}
+
+func serveLimited(store api.RateLimitStore, p api.RateLimitPolicy, client *auth.Client) *httptest.ResponseRecorder {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.RemoteAddr = "192.0.2.1:1234"
+	if client != nil {
+		req = req.WithContext(context.WithValue(req.Context(), sharedctx.APIClientKey(), client))
+	}
+	rec := httptest.NewRecorder()
+	h := RateLimit(store, p)(func(c echo.Context) error { return c.NoContent(http.StatusOK) })
+	_ = h(e.NewContext(req, rec))
+	return rec
+}
+
+func TestRateLimit_RejectsOverLimit(t *testing.T) {
+	store := &memoryRateLimitStore{}
+	p := api.RateLimitPolicy{Bucket: "create", Limit: 2, Window: time.Minute}
+	client := &auth.Client{ID: "client-1"}
+
+	for i := 0; i < 2; i++ {
+		if rec := serveLimited(store, p, client); rec.Code != http.StatusOK {
+			t.Fatalf("request %d: expected 200, got %d", i, rec.Code)
+		}
+	}
+	rec := serveLimited(store, p, client)
+
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected 429, got %d", rec.Code)
+	}
+	if rec.Header().Get(echo.HeaderRetryAfter) == "" {
+		t.Error("expected Retry-After header")
+	}
+	if rec.Header().Get(HeaderRateLimitLimit) != "2" || rec.Header().Get(HeaderRateLimitRemaining) != "0" {
+		t.Errorf("unexpected rate limit headers: %v", rec.Header())
+	}
+	if _, ok := store.counts["create:client:client-1"]; !ok {
+		t.Errorf("expected bucket keyed by client, got %v", store.counts)
+	}
+}
+
+func TestRateLimit_KeysByIPWithoutClient(t *testing.T) {
+	store := &memoryRateLimitStore{}
+	p := api.RateLimitPolicy{Bucket: "public", Limit: 1, Window: time.Minute}
+
+	serveLimited(store, p, nil)
+	rec := serveLimited(store, p, nil)
+
+	if _, ok := store.counts["public:ip:192.0.2.1"]; !ok {
+		t.Errorf("expected bucket keyed by IP, got %v", store.counts)
+	}
+	if rec.Code != http.StatusTooManyRequests {
+		t.Errorf("expected 429 over the per-IP limit, got %d", rec.Code)
+	}
+}
+
+func TestRateLimit_FailsOpen(t *testing.T) {
+	store := &memoryRateLimitStore{err: errors.New("redis down")}
+
+	rec := serveLimited(store, api.RateLimitPolicy{Bucket: "batch", Limit: 1, Window: time.Minute}, &auth.Client{ID: "client-1"})
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("expected 200 when the store fails, got %d", rec.Code)
+	}
+}
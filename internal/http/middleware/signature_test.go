@@ -0,0 +1,79 @@
+package middleware
+
+import (
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+const testSecret = "provider-secret"
+
+func serveSigned(body, timestamp, signature string) (*httptest.ResponseRecorder, string) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	req.Header.Set(HeaderSignatureTimestamp, timestamp)
+	req.Header.Set(HeaderSignature, signature)
+	rec := httptest.NewRecorder()
+	var seen string
+	h := ProviderSignature(testSecret, 5*time.Minute)(func(c echo.Context) error {
+		b, _ := io.ReadAll(c.Request().Body)
+		seen = string(b)
+		return c.NoContent(http.StatusOK)
+	})
+	_ = h(e.NewContext(req, rec))
+	return rec, seen
+}
+
+func signature(secret, timestamp, body string) string {
+	return "sha256=" + hex.EncodeToString(Sign(secret, timestamp, []byte(body)))
+}
+
+func TestProviderSignature_Accepts(t *testing.T) {
+	body := `{"messageId":"msg-1","status":"delivered"}`
+	ts := strconv.FormatInt(time.Now().Unix(), 10)
+
+	rec, seen := serveSigned(body, ts, signature(testSecret, ts, body))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if seen != body {
+		t.Errorf("expected the handler to read the body, got %q", seen)
+	}
+}
+
+func TestProviderSignature_Rejects(t *testing.T) {
+	body := `{"messageId":"msg-1","status":"delivered"}`
+	now := strconv.FormatInt(time.Now().Unix(), 10)
+	stale := strconv.FormatInt(time.Now().Add(-10*time.Minute).Unix(), 10)
+
+	tests := []struct {
+		name      string
+		timestamp string
+		signature string
+	}{
+		{"Wrong secret", now, signature("other-secret", now, body)},
+		{"Tampered body", now, signature(testSecret, now, `{"messageId":"msg-2","status":"delivered"}`)},
+		{"Signature for another timestamp", now, signature(testSecret, stale, body)},
+		{"Stale timestamp", stale, signature(testSecret, stale, body)},
+		{"Missing timestamp", "", signature(testSecret, "", body)},
+		{"Missing prefix", now, hex.EncodeToString(Sign(testSecret, now, []byte(body)))},
+		{"Not hex", now, "sha256=zz"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec, _ := serveSigned(body, tt.timestamp, tt.signature)
+			if rec.Code != http.StatusUnauthorized {
+				t.Errorf("expected 401, got %d", rec.Code)
+			}
+		})
+	}
+}
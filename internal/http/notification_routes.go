@@ -1,18 +1,45 @@
 package http
 
 import (
+	"context"
 	"net/http"
-	"strconv"
-	"time"
 
 	"github.com/labstack/echo/v4"
 
+	"github.com/semih-yildiz/notification-service/internal/api"
+	audiencemanage "github.com/semih-yildiz/notification-service/internal/application/audience/command/manage"
+	audiencelookup "github.com/semih-yildiz/notification-service/internal/application/audience/query/lookup"
+	inboxmanage "github.com/semih-yildiz/notification-service/internal/application/inbox/command/manage"
+	inboxport "github.com/semih-yildiz/notification-service/internal/application/inbox/port"
+	inboxlookup "github.com/semih-yildiz/notification-service/internal/application/inbox/query/lookup"
+	"github.com/semih-yildiz/notification-service/internal/application/notification/command/bulk"
 	"github.com/semih-yildiz/notification-service/internal/application/notification/command/cancel"
 	"github.com/semih-yildiz/notification-service/internal/application/notification/command/create"
+	"github.com/semih-yildiz/notification-service/internal/application/notification/command/engage"
+	"github.com/semih-yildiz/notification-service/internal/application/notification/command/fanout"
+	"github.com/semih-yildiz/notification-service/internal/application/notification/command/receipt"
+	"github.com/semih-yildiz/notification-service/internal/application/notification/port"
 	"github.com/semih-yildiz/notification-service/internal/application/notification/query/get"
+	"github.com/semih-yildiz/notification-service/internal/application/notification/query/imports"
 	"github.com/semih-yildiz/notification-service/internal/application/notification/query/list"
+	profilemanage "github.com/semih-yildiz/notification-service/internal/application/profile/command/manage"
+	profilelookup "github.com/semih-yildiz/notification-service/internal/application/profile/query/lookup"
+	templatemanage "github.com/semih-yildiz/notification-service/internal/application/template/command/manage"
+	templatelookup "github.com/semih-yildiz/notification-service/internal/application/template/query/lookup"
+	"github.com/semih-yildiz/notification-service/internal/domain/auth"
 	"github.com/semih-yildiz/notification-service/internal/domain/notification"
 	"github.com/semih-yildiz/notification-service/internal/http/dto"
+	httpmw "github.com/semih-yildiz/notification-service/internal/http/middleware"
+)
+
+const (
+	// HeaderIdempotencyKey carries the idempotency key of a batch request.
+	// Single creates take the key from the request body.
+	HeaderIdempotencyKey = "Idempotency-Key"
+	// HeaderIdempotentReplayed marks a response replayed for a repeated idempotency key.
+	HeaderIdempotentReplayed = "Idempotent-Replayed"
+	// HeaderDeduplicatedAgainst names the earlier notification a create was collapsed into.
+	HeaderDeduplicatedAgainst = "Deduplicated-Against"
 )
 
 type NotificationHandler struct {
@@ -20,6 +47,28 @@ type NotificationHandler struct {
 	cancelUsecase *cancel.UseCase
 	getUsecase    *get.UseCase
 	listUsecase   *list.UseCase
+	events        port.StatusSubscriber // optional; nil disables the events streams
+	importUsecase *bulk.UseCase         // optional; nil disables bulk imports
+	importQuery   *imports.UseCase
+	// optional; nil disables audiences
+	audienceManage *audiencemanage.UseCase
+	audienceLookup *audiencelookup.UseCase
+	fanoutUsecase  *fanout.UseCase
+	// optional; nil disables user profiles
+	profileManage *profilemanage.UseCase
+	profileLookup *profilelookup.UseCase
+	// optional; nil disables templates
+	templateManage *templatemanage.UseCase
+	templateLookup *templatelookup.UseCase
+	// optional; nil disables delivery receipts
+	receiptUsecase *receipt.UseCase
+	receiptSecret  string
+	// optional; nil disables the open pixel and click redirect
+	engageUsecase *engage.UseCase
+	// optional; nil disables the in-app inbox, and nil events its live stream
+	inboxManage *inboxmanage.UseCase
+	inboxLookup *inboxlookup.UseCase
+	inboxEvents inboxport.EventSubscriber
 }
 
 func NewNotificationHandler(
@@ -36,34 +85,169 @@ func NewNotificationHandler(
 	}
 }
 
-func RegisterNotificationRoutes(g *echo.Group, handler *NotificationHandler) {
-	g.POST("/notifications", handler.CreateNotification)
-	g.POST("/notifications/batches", handler.CreateNotificationBatches)
-	g.GET("/notifications/:id", handler.GetByID)
-	g.GET("/notifications", handler.List)
-	g.POST("/notifications/:id/cancel", handler.Cancel)
-	g.GET("/batches/:id/notifications", handler.GetBatch)
-	g.POST("/batches/:id/cancel", handler.CancelBatch)
+// WithStatusEvents enables the live status streams of notifications and batches.
+func (h *NotificationHandler) WithStatusEvents(events port.StatusSubscriber) *NotificationHandler {
+	h.events = events
+	return h
+}
+
+// WithImports enables bulk imports of NDJSON and CSV files.
+func (h *NotificationHandler) WithImports(start *bulk.UseCase, query *imports.UseCase) *NotificationHandler {
+	h.importUsecase = start
+	h.importQuery = query
+	return h
+}
+
+// WithAudiences enables recipient lists and sends to them.
+func (h *NotificationHandler) WithAudiences(manage *audiencemanage.UseCase, lookup *audiencelookup.UseCase, send *fanout.UseCase) *NotificationHandler {
+	h.audienceManage = manage
+	h.audienceLookup = lookup
+	h.fanoutUsecase = send
+	return h
+}
+
+// WithProfiles enables the user directory. Sending to a user ID also needs the
+// create use case built with profiles.
+func (h *NotificationHandler) WithProfiles(manage *profilemanage.UseCase, lookup *profilelookup.UseCase) *NotificationHandler {
+	h.profileManage = manage
+	h.profileLookup = lookup
+	return h
+}
+
+// WithTemplates enables templates, which render digests.
+func (h *NotificationHandler) WithTemplates(manage *templatemanage.UseCase, lookup *templatelookup.UseCase) *NotificationHandler {
+	h.templateManage = manage
+	h.templateLookup = lookup
+	return h
+}
+
+// WithReceipts enables provider delivery receipts, signed with secret.
+func (h *NotificationHandler) WithReceipts(uc *receipt.UseCase, secret string) *NotificationHandler {
+	h.receiptUsecase = uc
+	h.receiptSecret = secret
+	return h
+}
+
+// WithTracking enables the open pixel and click redirect of tracked emails.
+func (h *NotificationHandler) WithTracking(uc *engage.UseCase) *NotificationHandler {
+	h.engageUsecase = uc
+	return h
+}
+
+// WithInbox enables the in-app inbox of users. A nil events leaves out its live stream.
+func (h *NotificationHandler) WithInbox(manage *inboxmanage.UseCase, lookup *inboxlookup.UseCase, events inboxport.EventSubscriber) *NotificationHandler {
+	h.inboxManage = manage
+	h.inboxLookup = lookup
+	h.inboxEvents = events
+	return h
+}
+
+// RegisterNotificationRoutes mounts the notification API. Send scopes are checked per
+// channel by the create handlers. Creates, batch creates and reads are rate limited
+// in separate buckets.
+func RegisterNotificationRoutes(g *echo.Group, handler *NotificationHandler, limits RateLimits) {
+	read := httpmw.RequireScope(auth.ScopeRead)
+	cancel := httpmw.RequireScope(auth.ScopeCancel)
+	createLimit := httpmw.RateLimit(limits.Store, limits.Create)
+	batchLimit := httpmw.RateLimit(limits.Store, limits.Batch)
+	readLimit := httpmw.RateLimit(limits.Store, limits.Read)
+
+	g.POST("/notifications", handler.CreateNotification, createLimit)
+	g.POST("/notifications/batches", handler.CreateNotificationBatches, batchLimit)
+	g.GET("/notifications/:id", handler.GetByID, readLimit, read)
+	g.GET("/notifications/:id/history", handler.GetHistory, readLimit, read)
+	g.GET("/notifications", handler.List, readLimit, read)
+	g.POST("/notifications/:id/cancel", handler.Cancel, cancel)
+	g.GET("/batches/:id", handler.GetBatchSummary, readLimit, read)
+	g.GET("/batches/:id/notifications", handler.GetBatch, readLimit, read)
+	g.GET("/batches/:id/engagement", handler.GetBatchEngagement, readLimit, read)
+	g.POST("/batches/:id/cancel", handler.CancelBatch, cancel)
+	if handler.events != nil {
+		g.GET("/notifications/:id/events", handler.NotificationEvents, readLimit, read)
+		g.GET("/batches/:id/events", handler.BatchEvents, readLimit, read)
+	}
+	if handler.importUsecase != nil {
+		g.POST("/imports", handler.CreateImport, batchLimit)
+		g.GET("/imports/:id", handler.GetImport, readLimit, read).Name = importRouteName
+		g.GET("/imports/:id/errors", handler.GetImportErrors, readLimit, read)
+	}
+	if handler.audienceManage != nil {
+		audiences := httpmw.RequireScope(auth.ScopeAudiences)
+		g.POST("/audiences", handler.CreateAudience, createLimit, audiences)
+		g.GET("/audiences", handler.ListAudiences, readLimit, read)
+		g.GET("/audiences/:id", handler.GetAudience, readLimit, read)
+		g.DELETE("/audiences/:id", handler.DeleteAudience, audiences)
+		g.POST("/audiences/:id/members", handler.AddAudienceMembers, batchLimit, audiences)
+		g.GET("/audiences/:id/members", handler.ListAudienceMembers, readLimit, read)
+		g.DELETE("/audiences/:id/members/:channel/:recipient", handler.RemoveAudienceMember, audiences)
+		g.POST("/audiences/:id/send", handler.SendToAudience, batchLimit)
+	}
+	if handler.profileManage != nil {
+		users := httpmw.RequireScope(auth.ScopeUsers)
+		g.PUT("/users/:id", handler.SaveUserProfile, createLimit, users)
+		g.GET("/users/:id", handler.GetUserProfile, readLimit, read)
+		g.DELETE("/users/:id", handler.DeleteUserProfile, users)
+	}
+	if handler.inboxManage != nil {
+		inboxScope := httpmw.RequireScope(auth.ScopeInbox)
+		g.GET("/users/:id/inbox", handler.ListInbox, readLimit, read)
+		g.GET("/users/:id/inbox/unread", handler.GetInboxUnread, readLimit, read)
+		g.PUT("/users/:id/inbox/:message_id/state", handler.MarkInboxMessage, createLimit, inboxScope)
+		if handler.inboxEvents != nil {
+			g.GET("/users/:id/inbox/events", handler.InboxEvents, readLimit, read)
+		}
+	}
+	if handler.templateManage != nil {
+		templates := httpmw.RequireScope(auth.ScopeTemplates)
+		g.POST("/templates", handler.CreateTemplate, createLimit, templates)
+		g.GET("/templates", handler.ListTemplates, readLimit, read)
+		g.GET("/templates/:id", handler.GetTemplate, readLimit, read)
+		g.GET("/templates/:id/engagement", handler.GetTemplateEngagement, readLimit, read)
+		g.PUT("/templates/:id", handler.UpdateTemplate, createLimit, templates)
+		g.DELETE("/templates/:id", handler.DeleteTemplate, templates)
+	}
 }
 
 func (h *NotificationHandler) CreateNotification(c echo.Context) error {
 	ctx := c.Request().Context()
 
-	var item dto.NotificationItem
+	var item api.NotificationItem
 	if err := c.Bind(&item); err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid json object"})
+		return badRequest(c, "request body must be a JSON object")
 	}
 
 	if err := item.Validate(); err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return validationError(c, err)
+	}
+
+	client := httpmw.ClientFromContext(ctx)
+	if client == nil {
+		return httpmw.Forbidden(c, "api key required")
+	}
+	if len(item.Fallback) > 0 {
+		return h.createFallback(c, client, &item)
+	}
+	// A user's notification is only resolved to channels the key can send on.
+	if item.UserID == "" && !client.CanSend(notification.Channel(item.Channel)) {
+		return httpmw.Forbidden(c, "api key lacks the "+auth.SendScope(notification.Channel(item.Channel)).String()+" scope")
 	}
 
+	window, _ := item.DeliveryWindow.Window() // checked by Validate
 	cmd := &create.Command{
 		Recipient:      item.Recipient,
 		Channel:        item.Channel,
+		UserID:         item.UserID,
+		Category:       item.Category,
+		CanSend:        client.CanSend,
 		Content:        item.Content,
+		Variants:       item.ContentVariants,
+		Locale:         item.Locale,
 		Priority:       item.Priority,
 		IdempotencyKey: item.IdempotencyKey,
+		DeliveryWindow: window,
+		Track:          item.Track,
+		ClientID:       &client.ID,
+		TenantID:       client.TenantID,
 	}
 
 	result, err := h.createUsecase.CreateNotification(ctx, cmd)
@@ -71,46 +255,110 @@ func (h *NotificationHandler) CreateNotification(c echo.Context) error {
 		return mapNotificationError(c, err)
 	}
 
-	return c.JSON(http.StatusCreated, result)
+	if result.Replayed {
+		c.Response().Header().Set(HeaderIdempotentReplayed, "true")
+	}
+	if result.DeduplicatedAgainst != "" {
+		c.Response().Header().Set(HeaderDeduplicatedAgainst, result.DeduplicatedAgainst)
+		return c.JSON(http.StatusOK, result.Notification)
+	}
+
+	return c.JSON(http.StatusCreated, result.Notification)
+}
+
+// createFallback creates a notification delivered through the item's fallback chain.
+// The key needs the send scope of every step.
+func (h *NotificationHandler) createFallback(c echo.Context, client *auth.Client, item *api.NotificationItem) error {
+	steps := item.Steps()
+	for _, step := range steps {
+		if !client.CanSend(step.Channel) {
+			return httpmw.Forbidden(c, "api key lacks the "+auth.SendScope(step.Channel).String()+" scope")
+		}
+	}
+
+	result, err := h.createUsecase.CreateFallback(c.Request().Context(), &create.FallbackCommand{
+		Steps:          steps,
+		Content:        item.Content,
+		Variants:       item.ContentVariants,
+		Locale:         item.Locale,
+		Priority:       item.Priority,
+		IdempotencyKey: item.IdempotencyKey,
+		ClientID:       &client.ID,
+		TenantID:       client.TenantID,
+	})
+	if err != nil {
+		return mapNotificationError(c, err)
+	}
+	if result.Replayed {
+		c.Response().Header().Set(HeaderIdempotentReplayed, "true")
+	}
+	return c.JSON(http.StatusCreated, result.Notification)
 }
 
 // CreateNotificationBatches creates multiple notifications (batch).
 func (h *NotificationHandler) CreateNotificationBatches(c echo.Context) error {
 	ctx := c.Request().Context()
 
-	var items []dto.NotificationItem
+	var items []api.NotificationItem
 	if err := c.Bind(&items); err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid json array"})
+		return badRequest(c, "request body must be a JSON array")
 	}
 
-	if len(items) == 0 || len(items) > 1000 {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "batch must contain 1-1000 items"})
+	mode := c.QueryParam("mode")
+	if mode != "" && mode != "partial" {
+		return validationFailed(c, api.ValidationError{Field: "mode", Message: "mode must be partial or omitted"})
 	}
 
-	for i := range items {
-		if err := items[i].Validate(); err != nil {
-			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	// In partial mode invalid items are rejected individually by the use case.
+	partial := mode == "partial"
+	if partial {
+		if len(items) == 0 || len(items) > notification.MaxBatchSize {
+			return mapNotificationError(c, notification.ErrBatchTooLarge)
+		}
+	} else if err := api.ValidateBatch(items); err != nil {
+		return validationError(c, err)
+	}
+
+	// Items with unknown channels are rejected by validation, not by scope.
+	client := httpmw.ClientFromContext(ctx)
+	if client == nil {
+		return httpmw.Forbidden(c, "api key required")
+	}
+	for _, item := range items {
+		ch := notification.Channel(item.Channel)
+		if ch.Valid() && !client.CanSend(ch) {
+			return httpmw.Forbidden(c, "api key lacks the "+auth.SendScope(ch).String()+" scope")
 		}
 	}
 
 	batchItems := make([]create.BatchItem, len(items))
 	for i, item := range items {
 		batchItems[i] = create.BatchItem{
-			Recipient: item.Recipient,
-			Channel:   item.Channel,
-			Content:   item.Content,
-			Priority:  item.Priority,
+			Recipient:      item.Recipient,
+			Channel:        item.Channel,
+			Content:        item.Content,
+			Variants:       item.ContentVariants,
+			Locale:         item.Locale,
+			Priority:       item.Priority,
+			IdempotencyKey: item.IdempotencyKey,
+			DeliveryWindow: item.DeliveryWindow.BatchWindow(),
+			Track:          item.Track,
 		}
 	}
 
+	// The batch key comes from the header; item keys dedupe items individually. The
+	// first item's idempotency_key no longer keys the batch.
 	var batchIdempotencyKey *string
-	if len(items) > 0 && items[0].IdempotencyKey != nil {
-		batchIdempotencyKey = items[0].IdempotencyKey
+	if k := c.Request().Header.Get(HeaderIdempotencyKey); k != "" {
+		batchIdempotencyKey = &k
 	}
 
 	cmd := &create.BatchCommand{
 		Items:          batchItems,
 		IdempotencyKey: batchIdempotencyKey,
+		ClientID:       &client.ID,
+		TenantID:       client.TenantID,
+		PartialAccept:  partial,
 	}
 
 	result, err := h.createUsecase.CreateNotificationBatches(ctx, cmd)
@@ -118,7 +366,23 @@ func (h *NotificationHandler) CreateNotificationBatches(c echo.Context) error {
 		return mapNotificationError(c, err)
 	}
 
-	return c.JSON(http.StatusCreated, result)
+	if result.Replayed {
+		c.Response().Header().Set(HeaderIdempotentReplayed, "true")
+	}
+
+	if partial {
+		status := http.StatusCreated
+		if result.Accepted() == 0 {
+			status = http.StatusUnprocessableEntity
+		}
+		return c.JSON(status, toBatchCreateResponse(result))
+	}
+
+	return c.JSON(http.StatusCreated, dto.BatchCreatedResponse{
+		BatchID:       result.BatchID,
+		Replayed:      result.Replayed,
+		Notifications: result.Notifications,
+	})
 }
 
 // GetByID handles GET /notifications/:id
@@ -134,72 +398,62 @@ func (h *NotificationHandler) GetByID(c echo.Context) error {
 	return c.JSON(http.StatusOK, result)
 }
 
-// List handles GET /notifications
-func (h *NotificationHandler) List(c echo.Context) error {
+// GetHistory handles GET /notifications/:id/history
+func (h *NotificationHandler) GetHistory(c echo.Context) error {
 	ctx := c.Request().Context()
 
-	var fromTime, toTime *time.Time
-	if s := c.QueryParam("from"); s != "" {
-		if t, err := time.Parse(time.RFC3339, s); err == nil {
-			fromTime = &t
-		}
-	}
-	if s := c.QueryParam("to"); s != "" {
-		if t, err := time.Parse(time.RFC3339, s); err == nil {
-			toTime = &t
-		}
-	}
-
-	var batchID *string
-	if s := c.QueryParam("batch_id"); s != "" {
-		batchID = &s
+	entries, err := h.getUsecase.History(ctx, &get.ByID{ID: c.Param("id")})
+	if err != nil {
+		return mapNotificationError(c, err)
 	}
 
-	var status *notification.Status
-	var ch *notification.Channel
-	if s := c.QueryParam("status"); s != "" {
-		st := notification.Status(s)
-		if st.Valid() {
-			status = &st
-		}
-	}
-	if s := c.QueryParam("channel"); s != "" {
-		chVal := notification.Channel(s)
-		if chVal.Valid() {
-			ch = &chVal
-		}
+	response := make([]dto.HistoryEntryResponse, len(entries))
+	for i, e := range entries {
+		response[i] = dto.HistoryEntryResponse{Event: e.Event.String(), Detail: e.Detail, OccurredAt: e.OccurredAt}
 	}
+	return c.JSON(http.StatusOK, response)
+}
 
-	limit := 100
-	if l := c.QueryParam("limit"); l != "" {
-		if n, err := strconv.Atoi(l); err == nil && n > 0 && n <= 1000 {
-			limit = n
-		}
-	}
+// List handles GET /notifications
+func (h *NotificationHandler) List(c echo.Context) error {
+	ctx := c.Request().Context()
 
-	offset := 0
-	if o := c.QueryParam("offset"); o != "" {
-		if n, err := strconv.Atoi(o); err == nil && n >= 0 {
-			offset = n
-		}
+	params, err := api.ParseListParams(c.QueryParams())
+	if err != nil {
+		return validationError(c, err)
 	}
 
 	query := &list.Query{
-		Status:   status,
-		Channel:  ch,
-		FromTime: fromTime,
-		ToTime:   toTime,
-		BatchID:  batchID,
-		Limit:    limit,
-		Offset:   offset,
+		Statuses:        params.Statuses,
+		Channel:         params.Channel,
+		Priority:        params.Priority,
+		Recipient:       params.Recipient,
+		RecipientPrefix: params.RecipientPrefix,
+		FromTime:        params.From,
+		ToTime:          params.To,
+		SentFrom:        params.SentFrom,
+		SentTo:          params.SentTo,
+		BatchID:         params.BatchID,
+		ClientID:        params.ClientID,
+		IdempotencyKey:  params.IdempotencyKey,
+		FailureReason:   params.FailureReason,
+		Sort:            port.ListSort{Field: port.SortField(params.SortBy), Ascending: params.SortAscending},
+		Limit:           params.Limit,
+		Offset:          params.Offset,
+		Cursor:          params.Cursor,
+		WithTotal:       params.IncludeTotal,
 	}
 
 	result, err := h.listUsecase.ListByQuery(ctx, query)
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "internal error"})
+		return mapNotificationError(c, err)
 	}
 
-	return c.JSON(http.StatusOK, result)
+	return c.JSON(http.StatusOK, dto.ListResponse{
+		Notifications: result.Notifications,
+		Total:         result.Total,
+		NextCursor:    result.NextCursor,
+	})
 }
 
 // Cancel handles POST /notifications/:id/cancel
@@ -207,7 +461,7 @@ func (h *NotificationHandler) Cancel(c echo.Context) error {
 	ctx := c.Request().Context()
 	id := c.Param("id")
 
-	cmd := &cancel.Command{NotificationID: id}
+	cmd := &cancel.Command{NotificationID: id, ClientID: ownerScope(ctx)}
 	if err := h.cancelUsecase.CancelPendingNotification(ctx, cmd); err != nil {
 		return mapNotificationError(c, err)
 	}
@@ -226,19 +480,44 @@ func (h *NotificationHandler) GetBatch(c echo.Context) error {
 	}
 
 	response := dto.BatchWithNotificationsResponse{
-		Batch:         batch,
+		Batch:         toBatchResponse(batch),
 		Notifications: notifications,
 	}
 
 	return c.JSON(http.StatusOK, response)
 }
 
+// GetBatchSummary handles GET /batches/:id
+func (h *NotificationHandler) GetBatchSummary(c echo.Context) error {
+	ctx := c.Request().Context()
+	batchID := c.Param("id")
+
+	batch, err := h.getUsecase.BatchSummary(ctx, &get.BatchByID{BatchID: batchID})
+	if err != nil {
+		return mapNotificationError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, toBatchResponse(batch))
+}
+
+// GetBatchEngagement handles GET /batches/:id/engagement
+func (h *NotificationHandler) GetBatchEngagement(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	stats, err := h.getUsecase.BatchEngagement(ctx, &get.BatchByID{BatchID: c.Param("id")})
+	if err != nil {
+		return mapNotificationError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, toEngagementResponse(stats))
+}
+
 // CancelBatch handles POST /batches/:id/cancel
 func (h *NotificationHandler) CancelBatch(c echo.Context) error {
 	ctx := c.Request().Context()
 	batchID := c.Param("id")
 
-	cmd := &cancel.BatchCommand{BatchID: batchID}
+	cmd := &cancel.BatchCommand{BatchID: batchID, ClientID: ownerScope(ctx)}
 	count, err := h.cancelUsecase.CancelPendingNotificationBatch(ctx, cmd)
 	if err != nil {
 		return mapNotificationError(c, err)
@@ -247,3 +526,72 @@ func (h *NotificationHandler) CancelBatch(c echo.Context) error {
 	response := dto.CancelBatchResponse{Cancelled: count}
 	return c.JSON(http.StatusOK, response)
 }
+
+// ownerScope returns the client whose notifications a request may modify, or nil for
+// admins, who may modify any.
+func ownerScope(ctx context.Context) *string {
+	client := httpmw.ClientFromContext(ctx)
+	if client == nil {
+		return new(string) // matches no owner
+	}
+	if client.Can(auth.ScopeAdmin) {
+		return nil
+	}
+	return &client.ID
+}
+
+func toEngagementResponse(s *notification.EngagementStats) dto.EngagementResponse {
+	return dto.EngagementResponse{
+		Tracked:   s.Tracked,
+		Opened:    s.Opened,
+		Clicked:   s.Clicked,
+		OpenRate:  s.OpenRate(),
+		ClickRate: s.ClickRate(),
+	}
+}
+
+func toBatchResponse(b *notification.Batch) dto.BatchResponse {
+	counts := make(map[string]int, len(notification.Statuses()))
+	for _, s := range notification.Statuses() {
+		counts[s.String()] = b.Counts[s]
+	}
+	return dto.BatchResponse{
+		ID:                   b.ID,
+		IdempotencyKey:       b.IdempotencyKey,
+		AudienceID:           b.AudienceID,
+		Status:               b.Status().String(),
+		Total:                b.Counts.Total(),
+		Counts:               counts,
+		CompletionPercentage: b.CompletionPercentage(),
+		CreatedAt:            b.CreatedAt,
+		CompletedAt:          b.CompletedAt,
+	}
+}
+
+func toBatchCreateResponse(r *create.BatchResult) dto.BatchCreateResponse {
+	resp := dto.BatchCreateResponse{
+		BatchID:       r.BatchID,
+		Accepted:      r.Accepted(),
+		Replayed:      r.Replayed,
+		Items:         make([]dto.BatchItemResult, len(r.Items)),
+		Notifications: r.Notifications,
+	}
+	for i, it := range r.Items {
+		item := dto.BatchItemResult{
+			Index:          it.Index,
+			Status:         string(it.Status),
+			NotificationID: it.NotificationID,
+		}
+		for _, fe := range it.Errors {
+			item.Errors = append(item.Errors, api.ValidationError{Field: fe.Field, Message: fe.Message})
+		}
+		switch it.Status {
+		case create.ItemStatusRejected:
+			resp.Rejected++
+		case create.ItemStatusDuplicate:
+			resp.Duplicates++
+		}
+		resp.Items[i] = item
+	}
+	return resp
+}
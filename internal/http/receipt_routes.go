@@ -0,0 +1,49 @@
+package http
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/semih-yildiz/notification-service/internal/application/notification/command/receipt"
+	"github.com/semih-yildiz/notification-service/internal/http/dto"
+	httpmw "github.com/semih-yildiz/notification-service/internal/http/middleware"
+)
+
+// receiptSignatureTolerance is how far a receipt's signature timestamp may be
+// from now.
+const receiptSignatureTolerance = 5 * time.Minute
+
+// RegisterReceiptRoutes mounts the provider callbacks. They carry no API key; each
+// request must be signed with the shared provider secret, and is limited per IP.
+func RegisterReceiptRoutes(g *echo.Group, handler *NotificationHandler, limits RateLimits) {
+	g.POST("/receipts", handler.CreateReceipt,
+		httpmw.RateLimit(limits.Store, limits.Public),
+		httpmw.ProviderSignature(handler.receiptSecret, receiptSignatureTolerance))
+}
+
+// CreateReceipt handles POST /receipts
+func (h *NotificationHandler) CreateReceipt(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	var req dto.DeliveryReceiptRequest
+	if err := c.Bind(&req); err != nil {
+		return badRequest(c, "request body must be a JSON object")
+	}
+
+	cmd := &receipt.Command{MessageID: req.MessageID, Status: req.Status, Reason: req.Reason}
+	if req.Timestamp != nil {
+		cmd.OccurredAt = *req.Timestamp
+	}
+	res, err := h.receiptUsecase.Execute(ctx, cmd)
+	if err != nil {
+		return mapNotificationError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, dto.DeliveryReceiptResponse{
+		NotificationID: res.NotificationID,
+		Status:         res.Status.String(),
+		Applied:        res.Applied,
+	})
+}
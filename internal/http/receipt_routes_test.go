@@ -0,0 +1,80 @@
+package http
+
+import (
+	"context"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/semih-yildiz/notification-service/internal/application/notification/command/receipt"
+	"github.com/semih-yildiz/notification-service/internal/application/notification/port"
+	"github.com/semih-yildiz/notification-service/internal/domain/auth"
+	"github.com/semih-yildiz/notification-service/internal/domain/notification"
+	httpmw "github.com/semih-yildiz/notification-service/internal/http/middleware"
+)
+
+type rejectAllAuthenticator struct{}
+
+func (rejectAllAuthenticator) Authenticate(ctx context.Context, key string) (*auth.Client, error) {
+	return nil, auth.ErrInvalidKey
+}
+
+type stubReceiptRepo struct{}
+
+func (stubReceiptRepo) ApplyReceipt(ctx context.Context, r *notification.DeliveryReceipt) (*notification.Notification, bool, error) {
+	if r.MessageID != "msg-1" {
+		return nil, false, notification.ErrNotFound
+	}
+	return &notification.Notification{ID: "n-1", Status: notification.StatusSent}, true, nil
+}
+
+type nopLogger struct{}
+
+func (nopLogger) Info(ctx context.Context, msg string, fields ...port.Field)  {}
+func (nopLogger) Warn(ctx context.Context, msg string, fields ...port.Field)  {}
+func (nopLogger) Error(ctx context.Context, msg string, fields ...port.Field) {}
+
+func postReceipt(body string, sign bool) *httptest.ResponseRecorder {
+	const secret = "provider-secret"
+	handler := NewNotificationHandler(nil, nil, nil, nil).
+		WithReceipts(receipt.NewUseCase(stubReceiptRepo{}, nopLogger{}), secret)
+	e := NewEcho(handler, nil, nil, rejectAllAuthenticator{}, RateLimits{}, "/api/v1")
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/receipts", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if sign {
+		ts := strconv.FormatInt(time.Now().Unix(), 10)
+		req.Header.Set(httpmw.HeaderSignatureTimestamp, ts)
+		req.Header.Set(httpmw.HeaderSignature, "sha256="+hex.EncodeToString(httpmw.Sign(secret, ts, []byte(body))))
+	}
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestReceiptRoute(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		sign     bool
+		wantCode int
+	}{
+		{"Signed receipt without api key", `{"messageId":"msg-1","status":"delivered"}`, true, http.StatusOK},
+		{"Unsigned receipt", `{"messageId":"msg-1","status":"delivered"}`, false, http.StatusUnauthorized},
+		{"Unknown message id", `{"messageId":"msg-2","status":"delivered"}`, true, http.StatusNotFound},
+		{"Invalid status", `{"messageId":"msg-1","status":"read"}`, true, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := postReceipt(tt.body, tt.sign)
+			if rec.Code != tt.wantCode {
+				t.Errorf("expected %d, got %d: %s", tt.wantCode, rec.Code, rec.Body.String())
+			}
+		})
+	}
+}
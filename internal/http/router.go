@@ -4,13 +4,29 @@ import (
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
 
+	"github.com/semih-yildiz/notification-service/internal/api"
 	httpmw "github.com/semih-yildiz/notification-service/internal/http/middleware"
 )
 
-// NewEcho creates Echo instance with middleware and routes.
+// RateLimits are the per-client request limits of the notification API, and the
+// per-IP limit of the routes without an API key.
+type RateLimits struct {
+	Store  api.RateLimitStore
+	Create api.RateLimitPolicy
+	Batch  api.RateLimitPolicy
+	Read   api.RateLimitPolicy
+	Public api.RateLimitPolicy // provider callbacks and email tracking links
+}
+
+// NewEcho creates Echo instance with middleware and routes. API routes require a
+// key accepted by authenticator; health and metrics stay open, and provider
+// callbacks and email tracking links are checked by their signature instead.
 func NewEcho(
 	notificationHandler *NotificationHandler,
+	adminHandler *AdminHandler,
 	healthHandler *HealthHandler,
+	authenticator httpmw.Authenticator,
+	limits RateLimits,
 	basePath string,
 ) *echo.Echo {
 	e := echo.New()
@@ -26,10 +42,24 @@ func NewEcho(
 		RegisterHealthRoutes(e, healthHandler)
 	}
 
+	// Provider callbacks
+	if notificationHandler != nil && notificationHandler.receiptUsecase != nil {
+		RegisterReceiptRoutes(e.Group(basePath), notificationHandler, limits)
+	}
+	if notificationHandler != nil && notificationHandler.engageUsecase != nil {
+		RegisterTrackingRoutes(e.Group(basePath), notificationHandler, limits)
+	}
+
 	// API routes (base group)
+	g := e.Group(basePath, httpmw.APIKeyAuth(authenticator))
 	if notificationHandler != nil {
-		g := e.Group(basePath)
-		RegisterNotificationRoutes(g, notificationHandler)
+		RegisterNotificationRoutes(g, notificationHandler, limits)
+	}
+	if adminHandler != nil {
+		RegisterAdminRoutes(g, adminHandler)
+	}
+	if healthHandler != nil {
+		RegisterTenantMetricsRoutes(g, healthHandler)
 	}
 
 	return e
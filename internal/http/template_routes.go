@@ -0,0 +1,118 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/semih-yildiz/notification-service/internal/application/notification/query/get"
+	"github.com/semih-yildiz/notification-service/internal/application/template/command/manage"
+	"github.com/semih-yildiz/notification-service/internal/domain/template"
+	"github.com/semih-yildiz/notification-service/internal/http/dto"
+	httpmw "github.com/semih-yildiz/notification-service/internal/http/middleware"
+)
+
+// CreateTemplate handles POST /templates
+func (h *NotificationHandler) CreateTemplate(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	var req dto.CreateTemplateRequest
+	if err := c.Bind(&req); err != nil {
+		return badRequest(c, "request body must be a JSON object")
+	}
+
+	client := httpmw.ClientFromContext(ctx)
+	if client == nil {
+		return httpmw.Forbidden(c, "api key required")
+	}
+
+	t, err := h.templateManage.Create(ctx, &manage.CreateCommand{Name: req.Name, Body: req.Body, Variants: req.Variants, ClientID: &client.ID, TenantID: client.TenantID})
+	if err != nil {
+		return mapTemplateError(c, err)
+	}
+
+	return c.JSON(http.StatusCreated, toTemplateResponse(t))
+}
+
+// ListTemplates handles GET /templates
+func (h *NotificationHandler) ListTemplates(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	templates, err := h.templateLookup.List(ctx)
+	if err != nil {
+		return mapTemplateError(c, err)
+	}
+
+	response := make([]dto.TemplateResponse, len(templates))
+	for i, t := range templates {
+		response[i] = toTemplateResponse(t)
+	}
+	return c.JSON(http.StatusOK, response)
+}
+
+// GetTemplate handles GET /templates/:id
+func (h *NotificationHandler) GetTemplate(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	t, err := h.templateLookup.Template(ctx, c.Param("id"))
+	if err != nil {
+		return mapTemplateError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, toTemplateResponse(t))
+}
+
+// GetTemplateEngagement handles GET /templates/:id/engagement
+func (h *NotificationHandler) GetTemplateEngagement(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	t, err := h.templateLookup.Template(ctx, c.Param("id"))
+	if err != nil {
+		return mapTemplateError(c, err)
+	}
+	stats, err := h.getUsecase.DigestEngagement(ctx, &get.ByID{ID: t.ID})
+	if err != nil {
+		return mapNotificationError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, toEngagementResponse(stats))
+}
+
+// UpdateTemplate handles PUT /templates/:id
+func (h *NotificationHandler) UpdateTemplate(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	var req dto.CreateTemplateRequest
+	if err := c.Bind(&req); err != nil {
+		return badRequest(c, "request body must be a JSON object")
+	}
+
+	t, err := h.templateManage.Update(ctx, &manage.UpdateCommand{ID: c.Param("id"), Name: req.Name, Body: req.Body, Variants: req.Variants})
+	if err != nil {
+		return mapTemplateError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, toTemplateResponse(t))
+}
+
+// DeleteTemplate handles DELETE /templates/:id
+func (h *NotificationHandler) DeleteTemplate(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	if err := h.templateManage.Delete(ctx, c.Param("id")); err != nil {
+		return mapTemplateError(c, err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func toTemplateResponse(t *template.Template) dto.TemplateResponse {
+	return dto.TemplateResponse{
+		ID:        t.ID,
+		Name:      t.Name,
+		Body:      t.Body,
+		Variants:  t.Variants,
+		CreatedAt: t.CreatedAt,
+		UpdatedAt: t.UpdatedAt,
+	}
+}
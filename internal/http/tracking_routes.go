@@ -0,0 +1,56 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/semih-yildiz/notification-service/internal/api"
+	"github.com/semih-yildiz/notification-service/internal/application/notification/command/engage"
+	"github.com/semih-yildiz/notification-service/internal/http/dto"
+	httpmw "github.com/semih-yildiz/notification-service/internal/http/middleware"
+)
+
+// pixel is a transparent 1x1 GIF.
+var pixel = []byte{
+	0x47, 0x49, 0x46, 0x38, 0x39, 0x61, 0x01, 0x00, 0x01, 0x00, 0x80, 0x00, 0x00, 0x00, 0x00, 0x00,
+	0xff, 0xff, 0xff, 0x21, 0xf9, 0x04, 0x01, 0x00, 0x00, 0x00, 0x00, 0x2c, 0x00, 0x00, 0x00, 0x00,
+	0x01, 0x00, 0x01, 0x00, 0x00, 0x02, 0x02, 0x44, 0x01, 0x00, 0x3b,
+}
+
+// RegisterTrackingRoutes mounts the links of tracked emails. They carry no API
+// key; each link is signed for its notification and target, and requests are
+// limited per IP.
+func RegisterTrackingRoutes(g *echo.Group, handler *NotificationHandler, limits RateLimits) {
+	limit := httpmw.RateLimit(limits.Store, limits.Public)
+	g.GET("/track/open/:id", handler.TrackOpen, limit)
+	g.GET("/track/click/:id", handler.TrackClick, limit)
+}
+
+// TrackOpen handles GET /track/open/:id. The pixel is served whether or not the
+// open could be recorded, so the email never shows a broken image.
+func (h *NotificationHandler) TrackOpen(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	_ = h.engageUsecase.Open(ctx, &engage.OpenCommand{NotificationID: c.Param("id"), Signature: c.QueryParam("sig")})
+
+	c.Response().Header().Set(echo.HeaderCacheControl, "no-store, max-age=0")
+	return c.Blob(http.StatusOK, "image/gif", pixel)
+}
+
+// TrackClick handles GET /track/click/:id
+func (h *NotificationHandler) TrackClick(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	target, err := h.engageUsecase.Click(ctx, &engage.ClickCommand{
+		NotificationID: c.Param("id"),
+		URL:            c.QueryParam("url"),
+		Signature:      c.QueryParam("sig"),
+	})
+	if err != nil {
+		return writeError(c, http.StatusNotFound, dto.NewErrorResponse(api.ErrCodeNotFound, "tracking link not found"))
+	}
+
+	c.Response().Header().Set(echo.HeaderCacheControl, "no-store")
+	return c.Redirect(http.StatusFound, target)
+}
@@ -0,0 +1,118 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"image/gif"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/semih-yildiz/notification-service/internal/api"
+	"github.com/semih-yildiz/notification-service/internal/application/notification/command/engage"
+	"github.com/semih-yildiz/notification-service/internal/domain/notification"
+)
+
+// stubLinks accepts the signature "ok".
+type stubLinks struct{}
+
+func (stubLinks) OpenURL(id string) string                { return "" }
+func (stubLinks) ClickURL(id, target string) string       { return "" }
+func (stubLinks) VerifyOpen(id, sig string) bool          { return sig == "ok" }
+func (stubLinks) VerifyClick(id, target, sig string) bool { return sig == "ok" }
+
+type stubEngagementRepo struct {
+	recorded []notification.HistoryEvent
+}
+
+func (r *stubEngagementRepo) RecordEngagement(ctx context.Context, id string, e *notification.Engagement) error {
+	r.recorded = append(r.recorded, e.Event)
+	return nil
+}
+
+func (r *stubEngagementRepo) BatchEngagement(ctx context.Context, batchID string) (*notification.EngagementStats, error) {
+	return &notification.EngagementStats{}, nil
+}
+
+func (r *stubEngagementRepo) DigestEngagement(ctx context.Context, templateID string) (*notification.EngagementStats, error) {
+	return &notification.EngagementStats{}, nil
+}
+
+func serveTracking(repo *stubEngagementRepo, target string) *httptest.ResponseRecorder {
+	handler := NewNotificationHandler(nil, nil, nil, nil).
+		WithTracking(engage.NewUseCase(stubLinks{}, repo, nopLogger{}))
+	e := NewEcho(handler, nil, nil, rejectAllAuthenticator{}, RateLimits{}, "")
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+	return rec
+}
+
+func TestTrackOpen(t *testing.T) {
+	for _, sig := range []string{"ok", "forged"} {
+		repo := &stubEngagementRepo{}
+		rec := serveTracking(repo, "/track/open/n-1?sig="+sig)
+
+		if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != "image/gif" {
+			t.Fatalf("sig %s: expected a GIF, got %d %s", sig, rec.Code, rec.Header().Get("Content-Type"))
+		}
+		if _, err := gif.Decode(bytes.NewReader(rec.Body.Bytes())); err != nil {
+			t.Errorf("sig %s: invalid pixel: %v", sig, err)
+		}
+		if recorded := len(repo.recorded) == 1; recorded != (sig == "ok") {
+			t.Errorf("sig %s: recorded %v", sig, repo.recorded)
+		}
+	}
+}
+
+func TestTrackClick(t *testing.T) {
+	target := "https://shop.example/p?a=1"
+	repo := &stubEngagementRepo{}
+
+	rec := serveTracking(repo, "/track/click/n-1?sig=ok&url="+url.QueryEscape(target))
+	if rec.Code != http.StatusFound || rec.Header().Get("Location") != target {
+		t.Errorf("expected redirect to %s, got %d %q", target, rec.Code, rec.Header().Get("Location"))
+	}
+
+	rec = serveTracking(repo, "/track/click/n-1?sig=forged&url="+url.QueryEscape("https://evil.example"))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("expected 404 for a forged link, got %d", rec.Code)
+	}
+	if len(repo.recorded) != 1 || repo.recorded[0] != notification.HistoryClicked {
+		t.Errorf("expected one click recorded, got %v", repo.recorded)
+	}
+}
+
+type countingRateLimitStore struct {
+	counts map[string]int
+}
+
+func (s *countingRateLimitStore) Hit(ctx context.Context, key string, window time.Duration) (int, time.Time, error) {
+	s.counts[key]++
+	return s.counts[key], time.Now().Add(window), nil
+}
+
+func TestTrackingLinksLimitedPerIP(t *testing.T) {
+	store := &countingRateLimitStore{counts: make(map[string]int)}
+	handler := NewNotificationHandler(nil, nil, nil, nil).
+		WithTracking(engage.NewUseCase(stubLinks{}, &stubEngagementRepo{}, nopLogger{}))
+	limits := RateLimits{Store: store, Public: api.RateLimitPolicy{Bucket: "public", Limit: 1, Window: time.Minute}}
+	e := NewEcho(handler, nil, nil, rejectAllAuthenticator{}, limits, "")
+
+	var codes []int
+	for i := 0; i < 2; i++ {
+		req := httptest.NewRequest(http.MethodGet, "/track/open/n-1?sig=ok", nil)
+		req.RemoteAddr = "192.0.2.1:1234"
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		codes = append(codes, rec.Code)
+	}
+
+	if codes[0] != http.StatusOK || codes[1] != http.StatusTooManyRequests {
+		t.Errorf("expected 200 then 429, got %v", codes)
+	}
+	if store.counts["public:ip:192.0.2.1"] != 2 {
+		t.Errorf("expected requests counted per IP, got %v", store.counts)
+	}
+}
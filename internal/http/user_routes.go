@@ -0,0 +1,118 @@
+package http
+
+import (
+	"net/http"
+	"net/url"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/semih-yildiz/notification-service/internal/application/profile/command/manage"
+	"github.com/semih-yildiz/notification-service/internal/domain/notification"
+	"github.com/semih-yildiz/notification-service/internal/domain/profile"
+	"github.com/semih-yildiz/notification-service/internal/http/dto"
+	httpmw "github.com/semih-yildiz/notification-service/internal/http/middleware"
+)
+
+// SaveUserProfile handles PUT /users/:id. It creates the profile (201) or replaces
+// every field of an existing one (200).
+func (h *NotificationHandler) SaveUserProfile(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, err := url.PathUnescape(c.Param("id"))
+	if err != nil {
+		return badRequest(c, "user id must be path-escaped")
+	}
+	var req dto.SaveUserProfileRequest
+	if err := c.Bind(&req); err != nil {
+		return badRequest(c, "request body must be a JSON object")
+	}
+
+	client := httpmw.ClientFromContext(ctx)
+	if client == nil {
+		return httpmw.Forbidden(c, "api key required")
+	}
+
+	p, created, err := h.profileManage.Save(ctx, &manage.SaveCommand{
+		UserID:         userID,
+		TenantID:       client.TenantID,
+		Phone:          req.Phone,
+		Email:          req.Email,
+		PushTokens:     req.PushTokens,
+		Locale:         req.Locale,
+		TimeZone:       req.TimeZone,
+		Preferences:    req.Preferences,
+		OptOuts:        req.OptOuts,
+		TrackingOptOut: req.TrackingOptOut,
+	})
+	if err != nil {
+		return mapProfileError(c, err)
+	}
+
+	status := http.StatusOK
+	if created {
+		status = http.StatusCreated
+	}
+	return c.JSON(status, toUserProfileResponse(p))
+}
+
+// GetUserProfile handles GET /users/:id
+func (h *NotificationHandler) GetUserProfile(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, err := url.PathUnescape(c.Param("id"))
+	if err != nil {
+		return badRequest(c, "user id must be path-escaped")
+	}
+	p, err := h.profileLookup.Profile(ctx, userID)
+	if err != nil {
+		return mapProfileError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, toUserProfileResponse(p))
+}
+
+// DeleteUserProfile handles DELETE /users/:id
+func (h *NotificationHandler) DeleteUserProfile(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, err := url.PathUnescape(c.Param("id"))
+	if err != nil {
+		return badRequest(c, "user id must be path-escaped")
+	}
+	if err := h.profileManage.Delete(ctx, userID); err != nil {
+		return mapProfileError(c, err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func toUserProfileResponse(p *profile.Profile) dto.UserProfileResponse {
+	resp := dto.UserProfileResponse{
+		UserID:         p.UserID,
+		Phone:          p.Phone,
+		Email:          p.Email,
+		PushTokens:     p.PushTokens,
+		Locale:         p.Locale,
+		TimeZone:       p.TimeZone,
+		Preferences:    make(map[string][]string, len(p.Preferences)),
+		OptOuts:        channelNames(p.OptOuts),
+		TrackingOptOut: p.TrackingOptOut,
+		CreatedAt:      p.CreatedAt,
+		UpdatedAt:      p.UpdatedAt,
+	}
+	if resp.PushTokens == nil {
+		resp.PushTokens = []string{}
+	}
+	for category, channels := range p.Preferences {
+		resp.Preferences[category] = channelNames(channels)
+	}
+	return resp
+}
+
+func channelNames(channels []notification.Channel) []string {
+	names := make([]string, len(channels))
+	for i, ch := range channels {
+		names[i] = ch.String()
+	}
+	return names
+}
@@ -0,0 +1,36 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const apiRateLimitKeyPrefix = "ratelimit:api:"
+
+// APIRateLimiter counts API requests in fixed windows. It implements the HTTP
+// rate limit middleware's store.
+type APIRateLimiter struct {
+	client *redis.Client
+}
+
+// NewAPIRateLimiter returns a new Redis API rate limiter.
+func NewAPIRateLimiter(client *redis.Client) *APIRateLimiter {
+	return &APIRateLimiter{client: client}
+}
+
+// Hit counts one request against key in the current window.
+func (r *APIRateLimiter) Hit(ctx context.Context, key string, window time.Duration) (int, time.Time, error) {
+	start := time.Now().Truncate(window)
+	windowKey := fmt.Sprintf("%s%s:%d", apiRateLimitKeyPrefix, key, start.Unix())
+
+	pipe := r.client.Pipeline()
+	incr := pipe.Incr(ctx, windowKey)
+	pipe.Expire(ctx, windowKey, window*2)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, time.Time{}, err
+	}
+	return int(incr.Val()), start.Add(window), nil
+}
@@ -0,0 +1,49 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/semih-yildiz/notification-service/internal/application/notification/port"
+)
+
+const dedupeKeyPrefix = "dedupe:"
+
+// maxClaimAttempts bounds how often Claim retries a claim that expired while it
+// was being read.
+const maxClaimAttempts = 3
+
+var _ port.DedupeStore = (*DedupeStore)(nil)
+
+// DedupeStore implements port.DedupeStore with one expiring key per content hash.
+type DedupeStore struct {
+	client *redis.Client
+}
+
+func NewDedupeStore(client *redis.Client) *DedupeStore {
+	return &DedupeStore{client: client}
+}
+
+func (s *DedupeStore) Claim(ctx context.Context, hash, notificationID string, window time.Duration) (bool, string, error) {
+	k := dedupeKeyPrefix + hash
+	for attempt := 0; attempt < maxClaimAttempts; attempt++ {
+		ok, err := s.client.SetNX(ctx, k, notificationID, window).Result()
+		if err != nil || ok {
+			return ok, "", err
+		}
+		existing, err := s.client.Get(ctx, k).Result()
+		if errors.Is(err, redis.Nil) {
+			// Expired between SETNX and GET; the window is over, so try again.
+			continue
+		}
+		return false, existing, err
+	}
+	return false, "", errors.New("dedupe claim kept expiring")
+}
+
+func (s *DedupeStore) Release(ctx context.Context, hash string) error {
+	return s.client.Del(ctx, dedupeKeyPrefix+hash).Err()
+}
@@ -2,6 +2,8 @@ package redis
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"time"
 
 	"github.com/redis/go-redis/v9"
@@ -36,3 +38,48 @@ func (s *IdempotencyStore) Exists(ctx context.Context, key string) (bool, error)
 	n, err := s.client.Exists(ctx, k).Result()
 	return n > 0, err
 }
+
+func (s *IdempotencyStore) Reserve(ctx context.Context, key string, rec *port.IdempotencyRecord, ttlSeconds int) (bool, *port.IdempotencyRecord, error) {
+	if ttlSeconds <= 0 {
+		ttlSeconds = defaultIdempotencyTTL
+	}
+	val, err := json.Marshal(rec)
+	if err != nil {
+		return false, nil, err
+	}
+	k := idempotencyKeyPrefix + key
+	ok, err := s.client.SetNX(ctx, k, val, time.Duration(ttlSeconds)*time.Second).Result()
+	if err != nil || ok {
+		return ok, nil, err
+	}
+	raw, err := s.client.Get(ctx, k).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return false, nil, nil
+	}
+	if err != nil {
+		return false, nil, err
+	}
+	var existing port.IdempotencyRecord
+	if json.Unmarshal(raw, &existing) != nil {
+		// Keys set by SetIfNotExists carry no record
+		return false, nil, nil
+	}
+	return false, &existing, nil
+}
+
+func (s *IdempotencyStore) Complete(ctx context.Context, key string, rec *port.IdempotencyRecord, ttlSeconds int) error {
+	if ttlSeconds <= 0 {
+		ttlSeconds = defaultIdempotencyTTL
+	}
+	val, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	k := idempotencyKeyPrefix + key
+	return s.client.Set(ctx, k, val, time.Duration(ttlSeconds)*time.Second).Err()
+}
+
+func (s *IdempotencyStore) Delete(ctx context.Context, key string) error {
+	k := idempotencyKeyPrefix + key
+	return s.client.Del(ctx, k).Err()
+}
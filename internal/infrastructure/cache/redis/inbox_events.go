@@ -0,0 +1,124 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/semih-yildiz/notification-service/internal/application/inbox/port"
+	"github.com/semih-yildiz/notification-service/internal/domain/inbox"
+)
+
+const inboxEventsPrefix = "events:inbox:"
+
+var (
+	_ port.EventBroadcaster = (*InboxEvents)(nil)
+	_ port.EventSubscriber  = (*InboxEvents)(nil)
+)
+
+// InboxEvents implements the inbox port.EventBroadcaster and port.EventSubscriber
+// with Redis pub/sub, one channel per tenant and user.
+type InboxEvents struct {
+	client *redis.Client
+}
+
+func NewInboxEvents(client *redis.Client) *InboxEvents {
+	return &InboxEvents{client: client}
+}
+
+type inboxMessage struct {
+	Kind       string     `json:"kind"`
+	Unread     int64      `json:"unread"`
+	ID         string     `json:"id"`
+	TenantID   string     `json:"tenant_id"`
+	UserID     string     `json:"user_id"`
+	Category   string     `json:"category,omitempty"`
+	Content    string     `json:"content"`
+	State      string     `json:"state"`
+	ReadAt     *time.Time `json:"read_at,omitempty"`
+	ArchivedAt *time.Time `json:"archived_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+func (s *InboxEvents) Broadcast(ctx context.Context, evt *port.Event) error {
+	m := evt.Message
+	payload, err := json.Marshal(inboxMessage{
+		Kind:       string(evt.Kind),
+		Unread:     evt.Unread,
+		ID:         m.ID,
+		TenantID:   m.TenantID,
+		UserID:     m.UserID,
+		Category:   m.Category,
+		Content:    m.Content,
+		State:      m.State.String(),
+		ReadAt:     m.ReadAt,
+		ArchivedAt: m.ArchivedAt,
+		CreatedAt:  m.CreatedAt,
+		UpdatedAt:  m.UpdatedAt,
+	})
+	if err != nil {
+		return err
+	}
+	return s.client.Publish(ctx, inboxChannel(m.TenantID, m.UserID), payload).Err()
+}
+
+func (s *InboxEvents) Subscribe(ctx context.Context, tenantID, userID string) (<-chan *port.Event, error) {
+	sub := s.client.Subscribe(ctx, inboxChannel(tenantID, userID))
+	// Wait for the confirmation so nothing published after we return is missed.
+	if _, err := sub.Receive(ctx); err != nil {
+		_ = sub.Close()
+		return nil, err
+	}
+
+	out := make(chan *port.Event)
+	go func() {
+		defer close(out)
+		defer sub.Close()
+		msgs := sub.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case msg, ok := <-msgs:
+				if !ok {
+					return
+				}
+				var m inboxMessage
+				if err := json.Unmarshal([]byte(msg.Payload), &m); err != nil {
+					continue
+				}
+				evt := &port.Event{
+					Kind:   port.EventKind(m.Kind),
+					Unread: m.Unread,
+					Message: &inbox.Message{
+						ID:         m.ID,
+						TenantID:   m.TenantID,
+						UserID:     m.UserID,
+						Category:   m.Category,
+						Content:    m.Content,
+						State:      inbox.State(m.State),
+						ReadAt:     m.ReadAt,
+						ArchivedAt: m.ArchivedAt,
+						CreatedAt:  m.CreatedAt,
+						UpdatedAt:  m.UpdatedAt,
+					},
+				}
+				select {
+				case out <- evt:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return out, nil
+}
+
+// inboxChannel names a user's channel. User IDs are only unique within a tenant
+// and may contain ":", so the tenant comes first and the user ID last.
+func inboxChannel(tenantID, userID string) string {
+	return inboxEventsPrefix + tenantID + ":" + userID
+}
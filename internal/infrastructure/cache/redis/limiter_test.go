@@ -66,3 +66,40 @@ func TestRateLimitWindow(t *testing.T) {
 		t.Errorf("expected rateLimitWindow=1s, got %v", rateLimitWindow)
 	}
 }
+
+func TestTenantRateLimit(t *testing.T) {
+	if tenantRateLimitMaxPerSecond >= rateLimitMaxPerSecond {
+		t.Errorf("expected tenant limit below channel limit, got %d", tenantRateLimitMaxPerSecond)
+	}
+
+	key := tenantRateLimitKey("billing", notification.ChannelSMS)
+	if key != "ratelimit:tenant:billing:channel:sms" {
+		t.Errorf("unexpected tenant key '%s'", key)
+	}
+}
+
+func TestWithinRate(t *testing.T) {
+	tests := []struct {
+		name         string
+		channelCount int64
+		tenantCount  int64
+		expected     bool
+	}{
+		{"No tenant under limit", 80, 0, true},
+		{"No tenant over limit", 101, 0, false},
+		{"Tenant alone past its share", 80, 80, true},
+		{"Tenant alone at channel limit", 100, 100, true},
+		{"Tenant alone over channel limit", 101, 101, false},
+		{"Contended tenant within share", 90, 50, true},
+		{"Contended tenant past share", 90, 51, false},
+		{"Contended channel over limit", 101, 10, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := withinRate(tt.channelCount, tt.tenantCount); got != tt.expected {
+				t.Errorf("withinRate(%d, %d) = %v, want %v", tt.channelCount, tt.tenantCount, got, tt.expected)
+			}
+		})
+	}
+}
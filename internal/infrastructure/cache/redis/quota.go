@@ -0,0 +1,96 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/semih-yildiz/notification-service/internal/application/notification/port"
+	tenantport "github.com/semih-yildiz/notification-service/internal/application/tenant/port"
+	"github.com/semih-yildiz/notification-service/internal/domain/notification"
+	"github.com/semih-yildiz/notification-service/internal/domain/tenant"
+)
+
+const (
+	quotaKeyPrefix = "quota:"
+	quotaKeyTTL    = 48 * time.Hour
+)
+
+var (
+	_ port.QuotaLimiter     = (*QuotaLimiter)(nil)
+	_ tenantport.QuotaUsage = (*QuotaLimiter)(nil)
+)
+
+// consumeQuota adds ARGV[1] to today's counter unless that would pass the limit ARGV[2].
+var consumeQuota = redis.NewScript(`
+local used = redis.call('INCRBY', KEYS[1], ARGV[1])
+if used == tonumber(ARGV[1]) then
+	redis.call('EXPIRE', KEYS[1], ARGV[3])
+end
+if used > tonumber(ARGV[2]) then
+	redis.call('DECRBY', KEYS[1], ARGV[1])
+	return 0
+end
+return 1
+`)
+
+// QuotaLimiter implements port.QuotaLimiter with one counter per tenant, channel
+// and UTC day. Limits are read from the tenant repository.
+type QuotaLimiter struct {
+	client  *redis.Client
+	tenants tenantport.TenantRepository
+}
+
+func NewQuotaLimiter(client *redis.Client, tenants tenantport.TenantRepository) *QuotaLimiter {
+	return &QuotaLimiter{client: client, tenants: tenants}
+}
+
+func (q *QuotaLimiter) Consume(ctx context.Context, tenantID string, ch notification.Channel, n int) (bool, error) {
+	if n <= 0 {
+		return true, nil
+	}
+	t, err := q.tenants.GetByID(ctx, tenantID)
+	if errors.Is(err, tenant.ErrNotFound) {
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	limit, limited := t.DailyQuota(ch)
+	if !limited {
+		return true, nil
+	}
+	ok, err := consumeQuota.Run(ctx, q.client, []string{quotaKey(tenantID, ch, time.Now())}, n, limit, int(quotaKeyTTL.Seconds())).Int()
+	if err != nil {
+		return false, err
+	}
+	return ok == 1, nil
+}
+
+func (q *QuotaLimiter) Refund(ctx context.Context, tenantID string, ch notification.Channel, n int) error {
+	if n <= 0 {
+		return nil
+	}
+	key := quotaKey(tenantID, ch, time.Now())
+	// Only refund a counter that exists, so unlimited channels never go negative.
+	n64, err := q.client.Exists(ctx, key).Result()
+	if err != nil || n64 == 0 {
+		return err
+	}
+	return q.client.DecrBy(ctx, key, int64(n)).Err()
+}
+
+func (q *QuotaLimiter) UsedToday(ctx context.Context, tenantID string, ch notification.Channel) (int, error) {
+	used, err := q.client.Get(ctx, quotaKey(tenantID, ch, time.Now())).Int()
+	if errors.Is(err, redis.Nil) {
+		return 0, nil
+	}
+	return used, err
+}
+
+func quotaKey(tenantID string, ch notification.Channel, now time.Time) string {
+	return fmt.Sprintf("%s%s:%s:%s", quotaKeyPrefix, tenantID, ch, now.UTC().Format("2006-01-02"))
+}
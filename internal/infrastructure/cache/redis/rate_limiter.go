@@ -15,11 +15,18 @@ const (
 	rateLimitMaxPerSecond = 100
 	rateLimitKeyPrefix    = "ratelimit:channel:"
 	rateLimitWindow       = time.Second
+
+	// While other tenants use the channel in the same window, a single tenant may use
+	// at most half of its rate, so one noisy tenant cannot take all of it. A tenant
+	// alone on the channel gets the full rate.
+	tenantRateLimitMaxPerSecond = rateLimitMaxPerSecond / 2
+	tenantRateLimitKeyPrefix    = "ratelimit:tenant:"
 )
 
 var _ port.RateLimiter = (*RateLimiter)(nil)
 
-// RateLimiter implements port.RateLimiter (max 100/sec per channel).
+// RateLimiter implements port.RateLimiter (max 100/sec per channel, 50/sec per
+// tenant and channel when tenants contend for it).
 type RateLimiter struct {
 	client *redis.Client
 }
@@ -29,17 +36,49 @@ func NewRateLimiter(client *redis.Client) *RateLimiter {
 	return &RateLimiter{client: client}
 }
 
-// Allow returns true if the channel is under the limit.
-func (r *RateLimiter) Allow(ctx context.Context, channel notification.Channel) (bool, error) {
-	key := rateLimitKeyPrefix + channel.String()
+// Allow returns true if the channel is under the limit and, when other tenants use it
+// too, the tenant is within its share. An empty tenantID only checks the channel.
+func (r *RateLimiter) Allow(ctx context.Context, tenantID string, channel notification.Channel) (bool, error) {
 	now := time.Now().Truncate(rateLimitWindow)
-	windowKey := fmt.Sprintf("%s:%d", key, now.Unix())
 	pipe := r.client.Pipeline()
-	incr := pipe.Incr(ctx, windowKey)
-	pipe.Expire(ctx, windowKey, rateLimitWindow*2)
+	incr := r.incrWindow(ctx, pipe, rateLimitKeyPrefix+channel.String(), now)
+	var tenantIncr *redis.IntCmd
+	if tenantID != "" {
+		tenantIncr = r.incrWindow(ctx, pipe, tenantRateLimitKey(tenantID, channel), now)
+	}
 	_, err := pipe.Exec(ctx)
 	if err != nil {
 		return false, err
 	}
-	return incr.Val() <= rateLimitMaxPerSecond, nil
+	var tenantCount int64
+	if tenantIncr != nil {
+		tenantCount = tenantIncr.Val()
+	}
+	return withinRate(incr.Val(), tenantCount), nil
+}
+
+// withinRate checks a window's channel count and the tenant's part of it. The
+// tenant share only applies when the channel count includes other tenants' sends.
+//
+// The share caps what a tenant sends, not how long others wait: a noisy tenant's
+// rejected messages are retried from its queue shard, delaying the tenants hashed
+// onto the same shard. How fair delivery is therefore depends on which tenants share
+// a shard; more shards (RABBITMQ_TENANT_SHARDS) make that less likely.
+func withinRate(channelCount, tenantCount int64) bool {
+	if channelCount > rateLimitMaxPerSecond {
+		return false
+	}
+	contended := channelCount > tenantCount
+	return !contended || tenantCount <= tenantRateLimitMaxPerSecond
+}
+
+func (r *RateLimiter) incrWindow(ctx context.Context, pipe redis.Pipeliner, key string, now time.Time) *redis.IntCmd {
+	windowKey := fmt.Sprintf("%s:%d", key, now.Unix())
+	incr := pipe.Incr(ctx, windowKey)
+	pipe.Expire(ctx, windowKey, rateLimitWindow*2)
+	return incr
+}
+
+func tenantRateLimitKey(tenantID string, channel notification.Channel) string {
+	return tenantRateLimitKeyPrefix + tenantID + ":channel:" + channel.String()
 }
@@ -0,0 +1,114 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/semih-yildiz/notification-service/internal/application/notification/port"
+	"github.com/semih-yildiz/notification-service/internal/domain/notification"
+)
+
+const (
+	notificationEventsPrefix = "events:notification:"
+	batchEventsPrefix        = "events:batch:"
+)
+
+var (
+	_ port.StatusBroadcaster = (*StatusEvents)(nil)
+	_ port.StatusSubscriber  = (*StatusEvents)(nil)
+)
+
+// StatusEvents implements port.StatusBroadcaster and port.StatusSubscriber with Redis
+// pub/sub: one channel per notification and one per batch, so every API replica
+// receives the changes its clients watch.
+type StatusEvents struct {
+	client *redis.Client
+}
+
+func NewStatusEvents(client *redis.Client) *StatusEvents {
+	return &StatusEvents{client: client}
+}
+
+type statusMessage struct {
+	NotificationID string    `json:"notification_id,omitempty"`
+	BatchID        *string   `json:"batch_id,omitempty"`
+	Status         string    `json:"status"`
+	FailureReason  *string   `json:"failure_reason,omitempty"`
+	OccurredAt     time.Time `json:"occurred_at"`
+}
+
+func (s *StatusEvents) Broadcast(ctx context.Context, evt *port.StatusEvent) error {
+	payload, err := json.Marshal(statusMessage{
+		NotificationID: evt.NotificationID,
+		BatchID:        evt.BatchID,
+		Status:         evt.Status.String(),
+		FailureReason:  evt.FailureReason,
+		OccurredAt:     evt.OccurredAt,
+	})
+	if err != nil {
+		return err
+	}
+	pipe := s.client.Pipeline()
+	if evt.NotificationID != "" {
+		pipe.Publish(ctx, notificationEventsPrefix+evt.NotificationID, payload)
+	}
+	if evt.BatchID != nil {
+		pipe.Publish(ctx, batchEventsPrefix+*evt.BatchID, payload)
+	}
+	_, err = pipe.Exec(ctx)
+	return err
+}
+
+func (s *StatusEvents) SubscribeNotification(ctx context.Context, id string) (<-chan *port.StatusEvent, error) {
+	return s.subscribe(ctx, notificationEventsPrefix+id)
+}
+
+func (s *StatusEvents) SubscribeBatch(ctx context.Context, batchID string) (<-chan *port.StatusEvent, error) {
+	return s.subscribe(ctx, batchEventsPrefix+batchID)
+}
+
+func (s *StatusEvents) subscribe(ctx context.Context, channel string) (<-chan *port.StatusEvent, error) {
+	sub := s.client.Subscribe(ctx, channel)
+	// Wait for the confirmation so nothing published after we return is missed.
+	if _, err := sub.Receive(ctx); err != nil {
+		_ = sub.Close()
+		return nil, err
+	}
+
+	out := make(chan *port.StatusEvent)
+	go func() {
+		defer close(out)
+		defer sub.Close()
+		msgs := sub.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case msg, ok := <-msgs:
+				if !ok {
+					return
+				}
+				var m statusMessage
+				if err := json.Unmarshal([]byte(msg.Payload), &m); err != nil {
+					continue
+				}
+				evt := &port.StatusEvent{
+					NotificationID: m.NotificationID,
+					BatchID:        m.BatchID,
+					Status:         notification.Status(m.Status),
+					FailureReason:  m.FailureReason,
+					OccurredAt:     m.OccurredAt,
+				}
+				select {
+				case out <- evt:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return out, nil
+}
@@ -1,5 +1,7 @@
 package config
 
+import "time"
+
 type Config struct {
 	Env      string
 	App      AppConfig
@@ -7,10 +9,21 @@ type Config struct {
 	Redis    RedisConfig
 	RabbitMQ RabbitMQConfig
 	Webhook  WebhookConfig
+	Dedupe   DedupeConfig
+	Auth     AuthConfig
+	APILimit APIRateLimitConfig
+	Import   ImportConfig
+	Deferral DeferralConfig
+	Fallback FallbackConfig
+	Digest   DigestConfig
+	Tracking TrackingConfig
+	Chat     ChatConfig
+	Locale   LocaleConfig
 }
 
 type AppConfig struct {
-	Port string
+	Port     string
+	GRPCPort string
 }
 
 type DBConfig struct {
@@ -26,8 +39,80 @@ type RabbitMQConfig struct {
 	ManagementURL  string
 	ManagementUser string
 	ManagementPass string
+	// TenantShards is how many queues each channel is split into by tenant.
+	TenantShards int
 }
 
 type WebhookConfig struct {
 	URL string
+	// ReceiptSecret verifies the provider's delivery receipt callbacks. Empty
+	// disables the receipts endpoint.
+	ReceiptSecret string
+}
+
+// AuthConfig configures API key authentication.
+type AuthConfig struct {
+	// BootstrapAdminKey is registered as an admin key at startup, so the first
+	// clients can be issued. Empty skips it.
+	BootstrapAdminKey string
+}
+
+// DedupeConfig configures recipient-level deduplication of single creates; a zero
+// Window disables it.
+type DedupeConfig struct {
+	Window time.Duration
+}
+
+// APIRateLimitConfig limits API requests per client, and requests without an API
+// key per IP, in each Window. A zero limit disables that bucket.
+type APIRateLimitConfig struct {
+	Create int // POST /notifications
+	Batch  int // POST /notifications/batches
+	Read   int // GET endpoints
+	Public int // per IP: POST /receipts and the tracking links
+	Window time.Duration
+}
+
+// ImportConfig configures bulk imports. Uploads are kept in Dir until processed.
+type ImportConfig struct {
+	Dir      string
+	MaxBytes int64
+}
+
+// DeferralConfig configures how often workers requeue notifications whose delivery
+// window has opened.
+type DeferralConfig struct {
+	ReleaseInterval time.Duration
+}
+
+// FallbackConfig configures how often workers check fallback chains for steps that
+// were sent, failed or timed out.
+type FallbackConfig struct {
+	CheckInterval time.Duration
+}
+
+// DigestConfig configures how often workers send the digests that are due.
+type DigestConfig struct {
+	FlushInterval time.Duration
+}
+
+// TrackingConfig configures open and click tracking of HTML email. Tracking is off
+// unless both are set.
+type TrackingConfig struct {
+	BaseURL string // the API's public address, which tracking links point to
+	Secret  string // signs tracking links
+}
+
+// Enabled reports whether tracking links can be built.
+func (c TrackingConfig) Enabled() bool { return c.BaseURL != "" && c.Secret != "" }
+
+// ChatConfig configures the chat channel's incoming webhooks.
+type ChatConfig struct {
+	Workspaces   map[string]string // workspace name -> webhook URL, for workspace#channel recipients
+	AllowedHosts []string          // hosts a recipient may give a webhook URL on; "*.example.com" matches subdomains
+}
+
+// LocaleConfig configures how content variants are chosen.
+type LocaleConfig struct {
+	Fallback []string // locales tried, in order, after the recipient's own
 }
@@ -2,7 +2,9 @@ package config
 
 import (
 	"log"
+	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -21,7 +23,8 @@ func Load() *Config {
 	cfg := &Config{
 		Env: env,
 		App: AppConfig{
-			Port: mustGetEnv("APP_PORT"),
+			Port:     mustGetEnv("APP_PORT"),
+			GRPCPort: getEnv("GRPC_PORT", "9090"),
 		},
 		DB: DBConfig{
 			DSN: mustGetEnv("DB_DSN"),
@@ -34,9 +37,48 @@ func Load() *Config {
 			ManagementURL:  getEnv("RABBITMQ_MANAGEMENT_URL", "http://localhost:15672"),
 			ManagementUser: getEnv("RABBITMQ_MANAGEMENT_USER", "guest"),
 			ManagementPass: getEnv("RABBITMQ_MANAGEMENT_PASS", "guest"),
+			TenantShards:   getEnvInt("RABBITMQ_TENANT_SHARDS", 4),
 		},
 		Webhook: WebhookConfig{
-			URL: getEnv("WEBHOOK_URL", "https://webhook.site/211a3436-a302-48e2-a34c-a4d1cbe2294a"),
+			URL:           getEnv("WEBHOOK_URL", "https://webhook.site/211a3436-a302-48e2-a34c-a4d1cbe2294a"),
+			ReceiptSecret: getEnv("WEBHOOK_RECEIPT_SECRET", ""),
+		},
+		Auth: AuthConfig{
+			BootstrapAdminKey: getEnv("ADMIN_API_KEY", ""),
+		},
+		Dedupe: DedupeConfig{
+			Window: getEnvDuration("DEDUPE_WINDOW", 0),
+		},
+		APILimit: APIRateLimitConfig{
+			Create: getEnvInt("API_RATE_LIMIT_CREATE", 600),
+			Batch:  getEnvInt("API_RATE_LIMIT_BATCH", 20),
+			Read:   getEnvInt("API_RATE_LIMIT_READ", 1200),
+			Public: getEnvInt("API_RATE_LIMIT_PUBLIC", 6000),
+			Window: getEnvDuration("API_RATE_LIMIT_WINDOW", time.Minute),
+		},
+		Import: ImportConfig{
+			Dir:      getEnv("IMPORT_DIR", filepath.Join(os.TempDir(), "notification-imports")),
+			MaxBytes: int64(getEnvInt("IMPORT_MAX_BYTES", 200<<20)),
+		},
+		Deferral: DeferralConfig{
+			ReleaseInterval: getEnvDuration("DEFERRAL_RELEASE_INTERVAL", 30*time.Second),
+		},
+		Fallback: FallbackConfig{
+			CheckInterval: getEnvDuration("FALLBACK_CHECK_INTERVAL", 10*time.Second),
+		},
+		Digest: DigestConfig{
+			FlushInterval: getEnvDuration("DIGEST_FLUSH_INTERVAL", 30*time.Second),
+		},
+		Tracking: TrackingConfig{
+			BaseURL: getEnv("TRACKING_BASE_URL", ""),
+			Secret:  getEnv("TRACKING_SECRET", ""),
+		},
+		Chat: ChatConfig{
+			Workspaces:   getEnvMap("CHAT_WORKSPACES"),
+			AllowedHosts: getEnvList("CHAT_ALLOWED_HOSTS", "hooks.slack.com"),
+		},
+		Locale: LocaleConfig{
+			Fallback: getEnvList("LOCALE_FALLBACK", "en"),
 		},
 	}
 
@@ -3,6 +3,7 @@ package rabbitmq
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"log"
 	"sync"
 	"time"
@@ -15,6 +16,7 @@ import (
 type Consumer struct {
 	conn    *amqp.Connection
 	queues  []string
+	shards  int
 	mu      sync.Mutex
 	connURL string // Store URL for reconnection
 }
@@ -29,7 +31,7 @@ func NewConsumer(cfg Config) (*Consumer, error) {
 		_ = conn.Close()
 		return nil, err
 	}
-	if err := DeclareTopology(ch); err != nil {
+	if err := DeclareTopology(ch, cfg.shards()); err != nil {
 		_ = ch.Close()
 		_ = conn.Close()
 		return nil, err
@@ -37,7 +39,8 @@ func NewConsumer(cfg Config) (*Consumer, error) {
 	_ = ch.Close()
 	return &Consumer{
 		conn:    conn,
-		queues:  QueueNames(),
+		queues:  QueueNames(cfg.shards()),
+		shards:  cfg.shards(),
 		connURL: cfg.URL,
 	}, nil
 }
@@ -45,22 +48,52 @@ func NewConsumer(cfg Config) (*Consumer, error) {
 // ProcessFunc is called for each message; return nil to ack.
 type ProcessFunc func(ctx context.Context, evt *port.NotificationEvent) error
 
+// FanoutFunc is called for each audience fan-out message; return nil to ack.
+type FanoutFunc func(ctx context.Context, evt *port.FanoutEvent) error
+
+// errMalformed marks a body that does not decode; it is dead-lettered without retries.
+var errMalformed = errors.New("malformed message")
+
+// handleFunc decodes and processes one message body. id names the message in logs.
+type handleFunc func(ctx context.Context, body []byte) (id string, err error)
+
 func (c *Consumer) Run(ctx context.Context, process ProcessFunc) error {
+	handle := func(ctx context.Context, body []byte) (string, error) {
+		var evt port.NotificationEvent
+		if json.Unmarshal(body, &evt) != nil {
+			return "", errMalformed
+		}
+		return evt.NotificationID, process(ctx, &evt)
+	}
+
 	var wg sync.WaitGroup
 	for _, q := range c.queues {
 		q := q
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			c.consumeQueueWithRestart(ctx, q, process)
+			c.consumeQueueWithRestart(ctx, q, handle)
 		}()
 	}
 	wg.Wait()
 	return nil
 }
 
+// RunFanout consumes the audience fan-out queue until ctx is done.
+func (c *Consumer) RunFanout(ctx context.Context, expand FanoutFunc) error {
+	handle := func(ctx context.Context, body []byte) (string, error) {
+		var evt port.FanoutEvent
+		if json.Unmarshal(body, &evt) != nil {
+			return "", errMalformed
+		}
+		return "batch " + evt.BatchID, expand(ctx, &evt)
+	}
+	c.consumeQueueWithRestart(ctx, QueueFanout, handle)
+	return nil
+}
+
 // consumeQueueWithRestart wraps consumeQueue with panic recovery and auto-restart.
-func (c *Consumer) consumeQueueWithRestart(ctx context.Context, queue string, process ProcessFunc) {
+func (c *Consumer) consumeQueueWithRestart(ctx context.Context, queue string, handle handleFunc) {
 	backoff := time.Second
 	maxBackoff := 30 * time.Second
 
@@ -79,7 +112,7 @@ func (c *Consumer) consumeQueueWithRestart(ctx context.Context, queue string, pr
 					log.Printf("rabbitmq consumer panic recovered in queue %s: %v", queue, r)
 				}
 			}()
-			c.consumeQueue(ctx, queue, process)
+			c.consumeQueue(ctx, queue, handle)
 		}()
 
 		// If we reach here, consumption ended (panic, connection loss, or normal exit)
@@ -108,7 +141,7 @@ func (c *Consumer) consumeQueueWithRestart(ctx context.Context, queue string, pr
 	}
 }
 
-func (c *Consumer) consumeQueue(ctx context.Context, queue string, process ProcessFunc) {
+func (c *Consumer) consumeQueue(ctx context.Context, queue string, handle handleFunc) {
 	ch, err := c.conn.Channel()
 	if err != nil {
 		log.Printf("rabbitmq consumer channel %s: %v", queue, err)
@@ -139,26 +172,24 @@ func (c *Consumer) consumeQueue(ctx context.Context, queue string, process Proce
 				log.Printf("rabbitmq consumer %s: delivery channel closed, reconnecting...", queue)
 				return
 			}
-			var evt port.NotificationEvent
-			if json.Unmarshal(d.Body, &evt) != nil {
-				// Invalid JSON: send to DLQ immediately
-				_ = d.Nack(false, false)
-				continue
-			}
-
 			// Check death count to prevent infinite retries
 			deathCount := getDeathCount(d.Headers)
 			const maxRetries = 3
 
 			msgCtx, cancel := context.WithTimeout(ctx, 60*time.Second)
-			err := process(msgCtx, &evt)
+			id, err := handle(msgCtx, d.Body)
 			cancel()
 
+			if errors.Is(err, errMalformed) {
+				// Invalid JSON: send to DLQ immediately
+				_ = d.Nack(false, false)
+				continue
+			}
 			if err != nil {
-				log.Printf("rabbitmq process %s: %v (attempt %d/%d)", evt.NotificationID, err, deathCount+1, maxRetries)
+				log.Printf("rabbitmq process %s: %v (attempt %d/%d)", id, err, deathCount+1, maxRetries)
 
 				if deathCount >= maxRetries {
-					log.Printf("rabbitmq max retries reached for %s, sending to DLQ", evt.NotificationID)
+					log.Printf("rabbitmq max retries reached for %s, sending to DLQ", id)
 					_ = d.Nack(false, false)
 				} else {
 					_ = d.Nack(false, true)
@@ -205,7 +236,7 @@ func (c *Consumer) reconnect() error {
 		_ = conn.Close()
 		return err
 	}
-	if err := DeclareTopology(ch); err != nil {
+	if err := DeclareTopology(ch, c.shards); err != nil {
 		_ = ch.Close()
 		_ = conn.Close()
 		return err
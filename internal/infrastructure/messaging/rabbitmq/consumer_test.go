@@ -198,13 +198,13 @@ func TestConsumerExponentialBackoff(t *testing.T) {
 
 	startTime := time.Now()
 
-	// Mock process function
-	processFn := func(ctx context.Context, evt *port.NotificationEvent) error {
-		return nil
+	// Mock handler
+	handle := func(ctx context.Context, body []byte) (string, error) {
+		return "", nil
 	}
 
 	// Run in background (will fail to connect)
-	go consumer.consumeQueueWithRestart(ctx, "test", processFn)
+	go consumer.consumeQueueWithRestart(ctx, "test", handle)
 
 	// Wait for multiple reconnection attempts
 	time.Sleep(4 * time.Second)
@@ -13,6 +13,7 @@ type ManagementClient struct {
 	baseURL  string
 	username string
 	password string
+	shards   int
 	client   *http.Client
 }
 
@@ -28,10 +29,20 @@ func NewManagementClient(baseURL, username, password string) *ManagementClient {
 		baseURL:  baseURL,
 		username: username,
 		password: password,
+		shards:   DefaultTenantShards,
 		client:   &http.Client{Timeout: 5 * time.Second},
 	}
 }
 
+// WithTenantShards reports the depth of n shards per channel; it should match
+// Config.TenantShards.
+func (m *ManagementClient) WithTenantShards(n int) *ManagementClient {
+	if n > 0 {
+		m.shards = n
+	}
+	return m
+}
+
 func (m *ManagementClient) GetQueueStats(ctx context.Context, vhost, queueName string) (*QueueStats, error) {
 	if vhost == "/" {
 		vhost = "%2F"
@@ -88,10 +99,8 @@ type QueueDepth struct {
 }
 
 func (m *ManagementClient) GetQueueDepths(ctx context.Context) ([]QueueDepth, error) {
-	queues := []string{
-		QueueSMS, QueueEmail, QueuePush,
-		QueueSMSDLQ, QueueEmailDLQ, QueuePushDLQ,
-	}
+	queues := append(QueueNames(m.shards), DLQNames()...)
+	queues = append(queues, QueueFanout, QueueFanoutDLQ)
 
 	stats, err := m.GetAllQueueStats(ctx, "/", queues)
 	if err != nil {
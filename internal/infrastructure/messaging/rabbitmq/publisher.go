@@ -13,13 +13,25 @@ import (
 )
 
 type Publisher struct {
-	conn *amqp.Connection
-	ch   *amqp.Channel
-	mu   sync.RWMutex
+	conn   *amqp.Connection
+	ch     *amqp.Channel
+	shards int
+	mu     sync.RWMutex
 }
 
 type Config struct {
 	URL string
+	// TenantShards is how many queues each channel is split into; zero uses
+	// DefaultTenantShards. The API and every worker must use the same value.
+	TenantShards int
+}
+
+// shards returns the configured shard count, or the default.
+func (c Config) shards() int {
+	if c.TenantShards < 1 {
+		return DefaultTenantShards
+	}
+	return c.TenantShards
 }
 
 func NewPublisher(cfg Config) (*Publisher, error) {
@@ -32,12 +44,12 @@ func NewPublisher(cfg Config) (*Publisher, error) {
 		_ = conn.Close()
 		return nil, fmt.Errorf("rabbitmq channel: %w", err)
 	}
-	if err := DeclareTopology(ch); err != nil {
+	if err := DeclareTopology(ch, cfg.shards()); err != nil {
 		_ = ch.Close()
 		_ = conn.Close()
 		return nil, fmt.Errorf("rabbitmq topology: %w", err)
 	}
-	return &Publisher{conn: conn, ch: ch}, nil
+	return &Publisher{conn: conn, ch: ch, shards: cfg.shards()}, nil
 }
 
 func (p *Publisher) Publish(ctx context.Context, evt *port.NotificationEvent) error {
@@ -61,7 +73,7 @@ func (p *Publisher) publish(ctx context.Context, evt *port.NotificationEvent) er
 		return fmt.Errorf("rabbitmq: channel closed")
 	}
 
-	routingKey := evt.Channel.String()
+	routingKey := RoutingKey(evt.Channel, evt.TenantID, p.shards)
 	body, err := json.Marshal(evt)
 	if err != nil {
 		return err
@@ -80,6 +92,28 @@ func (p *Publisher) publish(ctx context.Context, evt *port.NotificationEvent) er
 	return p.ch.PublishWithContext(ctx, ExchangeName, routingKey, false, false, msg)
 }
 
+// PublishFanout queues an audience send, or the next part of one, for the worker.
+func (p *Publisher) PublishFanout(ctx context.Context, evt *port.FanoutEvent) error {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if p.ch == nil {
+		return fmt.Errorf("rabbitmq: channel closed")
+	}
+
+	body, err := json.Marshal(evt)
+	if err != nil {
+		return err
+	}
+	msg := amqp.Publishing{
+		DeliveryMode: amqp.Persistent,
+		Timestamp:    time.Now(),
+		ContentType:  "application/json",
+		Body:         body,
+	}
+	return p.ch.PublishWithContext(ctx, ExchangeName, RoutingKeyFanout, false, false, msg)
+}
+
 func (p *Publisher) Close() error {
 	p.mu.Lock()
 	defer p.mu.Unlock()
@@ -1,12 +1,74 @@
 package rabbitmq
 
-// Routing keys: channel name -> queue notifications.sms, .email, .push
-const (
-	RoutingKeySMS   = "sms"
-	RoutingKeyEmail = "email"
-	RoutingKeyPush  = "push"
+import (
+	"fmt"
+	"hash/fnv"
+
+	"github.com/semih-yildiz/notification-service/internal/domain/notification"
 )
 
-func QueueNames() []string {
-	return []string{QueueSMS, QueueEmail, QueuePush}
+// DefaultTenantShards is how many queues each channel is split into unless
+// Config.TenantShards says otherwise. Tenants are hashed onto shards and every shard
+// has its own consumer, so a tenant flooding one shard does not hold up tenants on
+// the others; tenants hashed onto the same shard still wait behind each other.
+const DefaultTenantShards = 4
+
+type channelQueue struct {
+	routingKey string
+	queue      string
+	dlq        string
+}
+
+// channelQueues returns the queues of every registered channel: the routing key
+// is the channel name, the dead letter queue the channel queue plus ".dlq".
+func channelQueues() []channelQueue {
+	specs := notification.Channels()
+	queues := make([]channelQueue, len(specs))
+	for i, spec := range specs {
+		queues[i] = channelQueue{routingKey: spec.Name.String(), queue: spec.Queue, dlq: spec.Queue + ".dlq"}
+	}
+	return queues
+}
+
+// RoutingKey returns the routing key of a channel's shard for tenantID among shards,
+// e.g. "sms.2".
+func RoutingKey(ch notification.Channel, tenantID string, shards int) string {
+	return fmt.Sprintf("%s.%d", ch, tenantShard(tenantID, shards))
+}
+
+// ShardQueueName returns the queue of one shard. Shard 0 keeps the channel's
+// original queue name.
+func ShardQueueName(queue string, shard int) string {
+	if shard == 0 {
+		return queue
+	}
+	return fmt.Sprintf("%s.%d", queue, shard)
+}
+
+// QueueNames returns every main queue, all shards of all channels.
+func QueueNames(shards int) []string {
+	queues := channelQueues()
+	names := make([]string, 0, len(queues)*shards)
+	for _, cq := range queues {
+		for shard := 0; shard < shards; shard++ {
+			names = append(names, ShardQueueName(cq.queue, shard))
+		}
+	}
+	return names
+}
+
+// DLQNames returns the dead letter queue of every channel.
+func DLQNames() []string {
+	queues := channelQueues()
+	names := make([]string, len(queues))
+	for i, cq := range queues {
+		names[i] = cq.dlq
+	}
+	return names
+}
+
+func tenantShard(tenantID string, shards int) int {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(tenantID))
+	return int(h.Sum32() % uint32(shards))
 }
@@ -0,0 +1,53 @@
+package rabbitmq
+
+import (
+	"testing"
+
+	"github.com/semih-yildiz/notification-service/internal/domain/notification"
+)
+
+func TestRoutingKey_StablePerTenant(t *testing.T) {
+	first := RoutingKey(notification.ChannelSMS, "acme", DefaultTenantShards)
+	if again := RoutingKey(notification.ChannelSMS, "acme", DefaultTenantShards); again != first {
+		t.Errorf("expected stable routing key, got %s and %s", first, again)
+	}
+
+	shards := make(map[string]bool)
+	for _, tenantID := range []string{"default", "acme", "billing", "marketing", "ops", "search", "growth", "support"} {
+		shards[RoutingKey(notification.ChannelSMS, tenantID, DefaultTenantShards)] = true
+	}
+	if len(shards) < 2 {
+		t.Errorf("expected tenants spread over shards, got %v", shards)
+	}
+}
+
+func TestRoutingKey_WithinShards(t *testing.T) {
+	for _, tenantID := range []string{"default", "acme", "billing", "marketing", "ops", "search", "growth", "support"} {
+		if key := RoutingKey(notification.ChannelSMS, tenantID, 1); key != "sms.0" {
+			t.Errorf("expected every tenant on the only shard, got %s for %s", key, tenantID)
+		}
+	}
+}
+
+func TestQueueNames_AllShards(t *testing.T) {
+	names := QueueNames(DefaultTenantShards)
+
+	channels := len(notification.Channels())
+	if len(names) != channels*DefaultTenantShards {
+		t.Fatalf("expected %d queues, got %d", channels*DefaultTenantShards, len(names))
+	}
+	if names[0] != "notifications.sms" || names[1] != "notifications.sms.1" {
+		t.Errorf("expected shard 0 to keep the channel queue name, got %v", names[:2])
+	}
+}
+
+func TestDLQNames_FollowRegistry(t *testing.T) {
+	names := DLQNames()
+
+	if len(names) != len(notification.Channels()) {
+		t.Fatalf("expected one DLQ per channel, got %v", names)
+	}
+	if names[len(names)-1] != "notifications.chat.dlq" {
+		t.Errorf("expected the chat DLQ last, got %v", names)
+	}
+}
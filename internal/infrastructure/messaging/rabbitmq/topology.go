@@ -1,6 +1,8 @@
 package rabbitmq
 
 import (
+	"fmt"
+
 	amqp "github.com/rabbitmq/amqp091-go"
 )
 
@@ -8,17 +10,15 @@ const (
 	ExchangeName    = "notifications"
 	DLXExchangeName = "notifications.dlx"
 
-	QueueSMS   = "notifications.sms"
-	QueueEmail = "notifications.email"
-	QueuePush  = "notifications.push"
-
-	QueueSMSDLQ   = "notifications.sms.dlq"
-	QueueEmailDLQ = "notifications.email.dlq"
-	QueuePushDLQ  = "notifications.push.dlq"
+	// QueueFanout holds audience sends waiting to be expanded into notifications.
+	QueueFanout      = "notifications.fanout"
+	QueueFanoutDLQ   = "notifications.fanout.dlq"
+	RoutingKeyFanout = "fanout"
 )
 
-// DeclareTopology declares exchange and channel-based queues with priority and DLQ.
-func DeclareTopology(ch *amqp.Channel) error {
+// DeclareTopology declares exchange and channel-based queues (split into shards by
+// tenant) with priority and DLQ.
+func DeclareTopology(ch *amqp.Channel, shards int) error {
 	// Main exchange
 	if err := ch.ExchangeDeclare(ExchangeName, "topic", true, false, false, false, nil); err != nil {
 		return err
@@ -29,45 +29,56 @@ func DeclareTopology(ch *amqp.Channel) error {
 		return err
 	}
 
-	// Main queues with priority and DLX
-	mainQueues := []struct {
-		name       string
-		routingKey string
-	}{
-		{QueueSMS, RoutingKeySMS},
-		{QueueEmail, RoutingKeyEmail},
-		{QueuePush, RoutingKeyPush},
+	// Main queues with priority and DLX, one per shard of every registered channel.
+	// Shard 0 also takes the plain channel routing key used before sharding.
+	queues := channelQueues()
+	for _, cq := range queues {
+		for shard := 0; shard < shards; shard++ {
+			name := ShardQueueName(cq.queue, shard)
+			args := amqp.Table{
+				"x-max-priority":         int32(4),
+				"x-dead-letter-exchange": DLXExchangeName,
+			}
+			if _, err := ch.QueueDeclare(name, true, false, false, false, args); err != nil {
+				return err
+			}
+			keys := []string{fmt.Sprintf("%s.%d", cq.routingKey, shard)}
+			if shard == 0 {
+				keys = append(keys, cq.routingKey)
+			}
+			for _, key := range keys {
+				if err := ch.QueueBind(name, key, ExchangeName, false, nil); err != nil {
+					return err
+				}
+			}
+		}
 	}
 
-	for _, q := range mainQueues {
-		args := amqp.Table{
-			"x-max-priority":         int32(4),
-			"x-dead-letter-exchange": DLXExchangeName,
-		}
-		if _, err := ch.QueueDeclare(q.name, true, false, false, false, args); err != nil {
+	// Dead-lettered messages keep their shard routing key; one DLQ per channel.
+	for _, cq := range queues {
+		if _, err := ch.QueueDeclare(cq.dlq, true, false, false, false, nil); err != nil {
 			return err
 		}
-		if err := ch.QueueBind(q.name, q.routingKey, ExchangeName, false, nil); err != nil {
-			return err
+		for _, key := range []string{cq.routingKey, cq.routingKey + ".*"} {
+			if err := ch.QueueBind(cq.dlq, key, DLXExchangeName, false, nil); err != nil {
+				return err
+			}
 		}
 	}
 
-	dlqQueues := []struct {
-		name       string
-		routingKey string
-	}{
-		{QueueSMSDLQ, RoutingKeySMS},
-		{QueueEmailDLQ, RoutingKeyEmail},
-		{QueuePushDLQ, RoutingKeyPush},
+	// Audience fan-out: a single queue, expansion is cheap next to delivery.
+	fanoutArgs := amqp.Table{"x-dead-letter-exchange": DLXExchangeName}
+	if _, err := ch.QueueDeclare(QueueFanout, true, false, false, false, fanoutArgs); err != nil {
+		return err
 	}
-
-	for _, q := range dlqQueues {
-		if _, err := ch.QueueDeclare(q.name, true, false, false, false, nil); err != nil {
-			return err
-		}
-		if err := ch.QueueBind(q.name, q.routingKey, DLXExchangeName, false, nil); err != nil {
-			return err
-		}
+	if err := ch.QueueBind(QueueFanout, RoutingKeyFanout, ExchangeName, false, nil); err != nil {
+		return err
+	}
+	if _, err := ch.QueueDeclare(QueueFanoutDLQ, true, false, false, false, nil); err != nil {
+		return err
+	}
+	if err := ch.QueueBind(QueueFanoutDLQ, RoutingKeyFanout, DLXExchangeName, false, nil); err != nil {
+		return err
 	}
 
 	return nil
@@ -0,0 +1,163 @@
+package postgres
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/semih-yildiz/notification-service/internal/application/audience/port"
+	"github.com/semih-yildiz/notification-service/internal/domain/audience"
+	"github.com/semih-yildiz/notification-service/internal/domain/notification"
+)
+
+var _ port.AudienceRepository = (*AudienceRepository)(nil)
+
+type AudienceRepository struct {
+	db *gorm.DB
+}
+
+func NewAudienceRepository(db *gorm.DB) *AudienceRepository {
+	return &AudienceRepository{db: db}
+}
+
+func (r *AudienceRepository) Create(ctx context.Context, a *audience.Audience) error {
+	m := &AudienceModel{
+		ID:        a.ID,
+		TenantID:  a.TenantID,
+		Name:      a.Name,
+		ClientID:  a.ClientID,
+		CreatedAt: a.CreatedAt,
+	}
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var taken int64
+		if err := tx.Model(&AudienceModel{}).Where("tenant_id = ? AND name = ?", a.TenantID, a.Name).Count(&taken).Error; err != nil {
+			return err
+		}
+		if taken > 0 {
+			return audience.ErrExists
+		}
+		return tx.Create(m).Error
+	})
+}
+
+func (r *AudienceRepository) GetByID(ctx context.Context, id string) (*audience.Audience, error) {
+	var m AudienceModel
+	err := r.db.WithContext(ctx).Scopes(tenantScope(ctx)).Where("id = ?", id).First(&m).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, audience.ErrNotFound
+		}
+		return nil, err
+	}
+	return toAudienceDomain(&m), nil
+}
+
+func (r *AudienceRepository) List(ctx context.Context) ([]*audience.Audience, error) {
+	var list []AudienceModel
+	if err := r.db.WithContext(ctx).Scopes(tenantScope(ctx)).Order("name").Find(&list).Error; err != nil {
+		return nil, err
+	}
+	out := make([]*audience.Audience, len(list))
+	for i := range list {
+		out[i] = toAudienceDomain(&list[i])
+	}
+	return out, nil
+}
+
+func (r *AudienceRepository) Delete(ctx context.Context, id string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("audience_id = ?", id).Delete(&AudienceMemberModel{}).Error; err != nil {
+			return err
+		}
+		res := tx.Scopes(tenantScope(ctx)).Where("id = ?", id).Delete(&AudienceModel{})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return audience.ErrNotFound
+		}
+		return nil
+	})
+}
+
+func (r *AudienceRepository) AddMembers(ctx context.Context, audienceID string, members []audience.Member) error {
+	if len(members) == 0 {
+		return nil
+	}
+	rows := make([]AudienceMemberModel, len(members))
+	for i, m := range members {
+		rows[i] = AudienceMemberModel{
+			AudienceID: audienceID,
+			Channel:    m.Channel.String(),
+			Recipient:  m.Recipient,
+			Suppressed: m.Suppressed,
+			CreatedAt:  m.CreatedAt,
+			UpdatedAt:  m.UpdatedAt,
+		}
+	}
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "audience_id"}, {Name: "channel"}, {Name: "recipient"}},
+		DoUpdates: clause.AssignmentColumns([]string{"suppressed", "updated_at"}),
+	}).CreateInBatches(rows, 500).Error
+}
+
+func (r *AudienceRepository) RemoveMember(ctx context.Context, audienceID string, ch notification.Channel, recipient string) error {
+	res := r.db.WithContext(ctx).
+		Where("audience_id = ? AND channel = ? AND recipient = ?", audienceID, ch.String(), recipient).
+		Delete(&AudienceMemberModel{})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return audience.ErrMemberNotFound
+	}
+	return nil
+}
+
+func (r *AudienceRepository) ListMembers(ctx context.Context, audienceID string, filter port.MemberFilter) ([]audience.Member, error) {
+	q := r.db.WithContext(ctx).Where("audience_id = ?", audienceID)
+	if len(filter.Channels) > 0 {
+		channels := make([]string, len(filter.Channels))
+		for i, ch := range filter.Channels {
+			channels[i] = ch.String()
+		}
+		q = q.Where("channel IN ?", channels)
+	}
+	if filter.SkipSuppressed {
+		q = q.Where("NOT suppressed")
+	}
+	if filter.After != nil {
+		q = q.Where("(channel, recipient) > (?, ?)", filter.After.Channel.String(), filter.After.Recipient)
+	}
+	if filter.Limit > 0 {
+		q = q.Limit(filter.Limit)
+	}
+
+	var list []AudienceMemberModel
+	if err := q.Order("channel, recipient").Find(&list).Error; err != nil {
+		return nil, err
+	}
+	out := make([]audience.Member, len(list))
+	for i, m := range list {
+		out[i] = audience.Member{
+			AudienceID: m.AudienceID,
+			Channel:    notification.Channel(m.Channel),
+			Recipient:  m.Recipient,
+			Suppressed: m.Suppressed,
+			CreatedAt:  m.CreatedAt,
+			UpdatedAt:  m.UpdatedAt,
+		}
+	}
+	return out, nil
+}
+
+func toAudienceDomain(m *AudienceModel) *audience.Audience {
+	return &audience.Audience{
+		ID:        m.ID,
+		TenantID:  m.TenantID,
+		Name:      m.Name,
+		ClientID:  m.ClientID,
+		CreatedAt: m.CreatedAt,
+	}
+}
@@ -2,10 +2,13 @@ package postgres
 
 import (
 	"context"
+	"sort"
+	"time"
 
 	"github.com/semih-yildiz/notification-service/internal/application/notification/port"
 	"github.com/semih-yildiz/notification-service/internal/domain/notification"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 var _ port.BatchRepository = (*BatchRepository)(nil)
@@ -19,26 +22,106 @@ func NewBatchRepository(db *gorm.DB) *BatchRepository {
 }
 
 func (r *BatchRepository) Create(ctx context.Context, b *notification.Batch) error {
-	m := &BatchModel{
-		ID:             b.ID,
-		IdempotencyKey: b.IdempotencyKey,
-		CreatedAt:      b.CreatedAt,
-	}
-	return r.db.WithContext(ctx).Create(m).Error
+	return r.db.WithContext(ctx).Create(toBatchModel(b)).Error
 }
 
 func (r *BatchRepository) GetByID(ctx context.Context, id string) (*notification.Batch, error) {
+	return r.getWhere(ctx, "id = ?", id)
+}
+
+func (r *BatchRepository) GetByIdempotencyKey(ctx context.Context, key string) (*notification.Batch, error) {
+	return r.getWhere(ctx, "idempotency_key = ?", key)
+}
+
+func (r *BatchRepository) FinishExpansion(ctx context.Context, id string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&BatchModel{}).Where("id = ?", id).Update("expanding", false).Error; err != nil {
+			return err
+		}
+		return refreshCompletedAt(tx, id)
+	})
+}
+
+func (r *BatchRepository) getWhere(ctx context.Context, query string, args ...interface{}) (*notification.Batch, error) {
 	var m BatchModel
-	err := r.db.WithContext(ctx).Where("id = ?", id).First(&m).Error
+	err := r.db.WithContext(ctx).Scopes(tenantScope(ctx)).Where(query, args...).First(&m).Error
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, notification.ErrNotFound
 		}
 		return nil, err
 	}
-	return &notification.Batch{
+	var counts []BatchStatusCountModel
+	if err := r.db.WithContext(ctx).Where("batch_id = ? AND count > 0", m.ID).Find(&counts).Error; err != nil {
+		return nil, err
+	}
+	b := &notification.Batch{
 		ID:             m.ID,
+		TenantID:       m.TenantID,
 		IdempotencyKey: m.IdempotencyKey,
+		ClientID:       m.ClientID,
+		AudienceID:     m.AudienceID,
+		Fingerprint:    m.Fingerprint,
+		Expanding:      m.Expanding,
+		Counts:         make(notification.BatchCounts, len(counts)),
 		CreatedAt:      m.CreatedAt,
-	}, nil
+		CompletedAt:    m.CompletedAt,
+	}
+	for _, c := range counts {
+		b.Counts[notification.Status(c.Status)] = c.Count
+	}
+	return b, nil
+}
+
+// adjustBatchCounts applies per-status deltas to a batch's counters and refreshes
+// completed_at. It must run in the same transaction as the status change.
+func adjustBatchCounts(tx *gorm.DB, batchID string, deltas map[notification.Status]int) error {
+	var rows []BatchStatusCountModel
+	for status, delta := range deltas {
+		if delta != 0 {
+			rows = append(rows, BatchStatusCountModel{BatchID: batchID, Status: status.String(), Count: delta})
+		}
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+	// One upsert for every status, in a fixed order so concurrent ones lock alike.
+	sort.Slice(rows, func(i, j int) bool { return rows[i].Status < rows[j].Status })
+	err := tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "batch_id"}, {Name: "status"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{"count": gorm.Expr("batch_status_counts.count + excluded.count")}),
+	}).Create(&rows).Error
+	if err != nil {
+		return err
+	}
+	return refreshCompletedAt(tx, batchID)
+}
+
+// refreshCompletedAt sets completed_at once the batch is expanded and no counter for
+// a non-terminal status is left, and clears it otherwise.
+func refreshCompletedAt(tx *gorm.DB, batchID string) error {
+	var open []string
+	for _, s := range notification.Statuses() {
+		if !s.Terminal() {
+			open = append(open, s.String())
+		}
+	}
+	return tx.Model(&BatchModel{}).Where("id = ?", batchID).
+		Update("completed_at", gorm.Expr(
+			"CASE WHEN expanding OR EXISTS (SELECT 1 FROM batch_status_counts WHERE batch_id = ? AND status IN ? AND count > 0) THEN NULL ELSE COALESCE(completed_at, ?) END",
+			batchID, open, time.Now(),
+		)).Error
+}
+
+func toBatchModel(b *notification.Batch) *BatchModel {
+	return &BatchModel{
+		ID:             b.ID,
+		TenantID:       b.TenantID,
+		IdempotencyKey: b.IdempotencyKey,
+		ClientID:       b.ClientID,
+		AudienceID:     b.AudienceID,
+		Fingerprint:    b.Fingerprint,
+		Expanding:      b.Expanding,
+		CreatedAt:      b.CreatedAt,
+	}
 }
@@ -0,0 +1,123 @@
+package postgres
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/semih-yildiz/notification-service/internal/application/auth/port"
+	"github.com/semih-yildiz/notification-service/internal/domain/auth"
+)
+
+var _ port.ClientRepository = (*ClientRepository)(nil)
+
+type ClientRepository struct {
+	db *gorm.DB
+}
+
+func NewClientRepository(db *gorm.DB) *ClientRepository {
+	return &ClientRepository{db: db}
+}
+
+func (r *ClientRepository) Create(ctx context.Context, c *auth.Client) error {
+	return r.db.WithContext(ctx).Create(toClientModel(c)).Error
+}
+
+func (r *ClientRepository) GetByID(ctx context.Context, id string) (*auth.Client, error) {
+	return r.getWhere(r.db.WithContext(ctx).Scopes(tenantScope(ctx)), "id = ?", id)
+}
+
+// GetByKeyHash looks in every tenant: it authenticates the key that tells which
+// tenant the request is for.
+func (r *ClientRepository) GetByKeyHash(ctx context.Context, hash string) (*auth.Client, error) {
+	return r.getWhere(r.db.WithContext(ctx), "key_hash = ?", hash)
+}
+
+func (r *ClientRepository) getWhere(db *gorm.DB, query string, args ...interface{}) (*auth.Client, error) {
+	var m APIClientModel
+	err := db.Where(query, args...).First(&m).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, auth.ErrNotFound
+		}
+		return nil, err
+	}
+	return toClientDomain(&m), nil
+}
+
+func (r *ClientRepository) List(ctx context.Context) ([]*auth.Client, error) {
+	var list []APIClientModel
+	if err := r.db.WithContext(ctx).Scopes(tenantScope(ctx)).Order("created_at").Find(&list).Error; err != nil {
+		return nil, err
+	}
+	out := make([]*auth.Client, len(list))
+	for i := range list {
+		out[i] = toClientDomain(&list[i])
+	}
+	return out, nil
+}
+
+func (r *ClientRepository) UpdateKey(ctx context.Context, id, keyPrefix, keyHash string, rotatedAt time.Time) error {
+	res := r.db.WithContext(ctx).Model(&APIClientModel{}).Scopes(tenantScope(ctx)).Where("id = ?", id).Updates(map[string]interface{}{
+		"key_prefix": keyPrefix,
+		"key_hash":   keyHash,
+		"rotated_at": rotatedAt,
+	})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return auth.ErrNotFound
+	}
+	return nil
+}
+
+func (r *ClientRepository) UpdateScopes(ctx context.Context, id string, scopes []auth.Scope) error {
+	res := r.db.WithContext(ctx).Model(&APIClientModel{}).Scopes(tenantScope(ctx)).Where("id = ?", id).Update("scopes", joinScopes(scopes))
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return auth.ErrNotFound
+	}
+	return nil
+}
+
+func joinScopes(scopes []auth.Scope) string {
+	names := make([]string, len(scopes))
+	for i, s := range scopes {
+		names[i] = s.String()
+	}
+	return strings.Join(names, " ")
+}
+
+func toClientModel(c *auth.Client) *APIClientModel {
+	return &APIClientModel{
+		ID:        c.ID,
+		TenantID:  c.TenantID,
+		Name:      c.Name,
+		KeyPrefix: c.KeyPrefix,
+		KeyHash:   c.KeyHash,
+		Scopes:    joinScopes(c.Scopes),
+		CreatedAt: c.CreatedAt,
+		RotatedAt: c.RotatedAt,
+	}
+}
+
+func toClientDomain(m *APIClientModel) *auth.Client {
+	c := &auth.Client{
+		ID:        m.ID,
+		TenantID:  m.TenantID,
+		Name:      m.Name,
+		KeyPrefix: m.KeyPrefix,
+		KeyHash:   m.KeyHash,
+		CreatedAt: m.CreatedAt,
+		RotatedAt: m.RotatedAt,
+	}
+	for _, s := range strings.Fields(m.Scopes) {
+		c.Scopes = append(c.Scopes, auth.Scope(s))
+	}
+	return c
+}
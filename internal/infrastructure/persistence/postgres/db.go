@@ -33,10 +33,50 @@ func New(ctx context.Context, cfg Config) (*DB, error) {
 	}
 	if err := db.WithContext(ctx).AutoMigrate(
 		&BatchModel{},
+		&BatchStatusCountModel{},
 		&NotificationModel{},
 		&DeliveryAttemptModel{},
+		&NotificationHistoryModel{},
+		&FallbackStepModel{},
+		&APIClientModel{},
+		&TenantModel{},
+		&TenantQuotaModel{},
+		&TenantDeliveryWindowModel{},
+		&TenantDigestPolicyModel{},
+		&ImportJobModel{},
+		&ImportRowErrorModel{},
+		&AudienceModel{},
+		&AudienceMemberModel{},
+		&TemplateModel{},
+		&TemplateVariantModel{},
+		&UserProfileModel{},
+		&UserPreferenceModel{},
+		&InboxMessageModel{},
 	); err != nil {
 		return nil, fmt.Errorf("postgres: migrate: %w", err)
 	}
+	// AutoMigrate keeps indexes that newer ones replaced: idempotency keys are unique
+	// per tenant, and listings page by (created_at, id).
+	for _, idx := range []string{
+		"idx_batches_idempotency_key",
+		"idx_notifications_idempotency_key",
+		"idx_notifications_tenant_created_at",
+	} {
+		if err := db.WithContext(ctx).Exec("DROP INDEX IF EXISTS " + idx).Error; err != nil {
+			return nil, fmt.Errorf("postgres: migrate: %w", err)
+		}
+	}
+	// Recipient prefix matches need a pattern operator class, which AutoMigrate
+	// cannot express.
+	if err := db.WithContext(ctx).Exec("CREATE INDEX IF NOT EXISTS idx_notifications_tenant_recipient ON notifications (tenant_id, recipient text_pattern_ops)").Error; err != nil {
+		return nil, fmt.Errorf("postgres: migrate: %w", err)
+	}
+	// Failure reason search uses a trigram index when pg_trgm can be installed; without
+	// it the search still works but scans the tenant's rows.
+	if db.WithContext(ctx).Exec("CREATE EXTENSION IF NOT EXISTS pg_trgm").Error == nil {
+		if err := db.WithContext(ctx).Exec("CREATE INDEX IF NOT EXISTS idx_notifications_failure_reason_trgm ON notifications USING gin (failure_reason gin_trgm_ops)").Error; err != nil {
+			return nil, fmt.Errorf("postgres: migrate: %w", err)
+		}
+	}
 	return &DB{DB: db}, nil
 }
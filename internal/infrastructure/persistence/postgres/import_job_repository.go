@@ -0,0 +1,164 @@
+package postgres
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/semih-yildiz/notification-service/internal/application/notification/port"
+	"github.com/semih-yildiz/notification-service/internal/domain/notification"
+)
+
+var _ port.ImportJobRepository = (*ImportJobRepository)(nil)
+
+type ImportJobRepository struct {
+	db *gorm.DB
+}
+
+func NewImportJobRepository(db *gorm.DB) *ImportJobRepository {
+	return &ImportJobRepository{db: db}
+}
+
+func (r *ImportJobRepository) Create(ctx context.Context, job *notification.ImportJob) error {
+	return r.db.WithContext(ctx).Create(toImportJobModel(job)).Error
+}
+
+func (r *ImportJobRepository) GetByID(ctx context.Context, id string) (*notification.ImportJob, error) {
+	var m ImportJobModel
+	err := r.db.WithContext(ctx).Scopes(tenantScope(ctx)).Where("id = ?", id).First(&m).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, notification.ErrImportNotFound
+		}
+		return nil, err
+	}
+	return toImportJobDomain(&m), nil
+}
+
+func (r *ImportJobRepository) Update(ctx context.Context, job *notification.ImportJob) error {
+	m := toImportJobModel(job)
+	res := r.db.WithContext(ctx).Model(&ImportJobModel{}).
+		Where("id = ? AND status IN ?", job.ID, unfinishedImportStatuses()).
+		Updates(map[string]interface{}{
+			"status":         m.Status,
+			"total_rows":     m.TotalRows,
+			"accepted_rows":  m.AcceptedRows,
+			"rejected_rows":  m.RejectedRows,
+			"duplicate_rows": m.DuplicateRows,
+			"batch_ids":      m.BatchIDs,
+			"failure_reason": m.FailureReason,
+			"updated_at":     m.UpdatedAt,
+			"completed_at":   m.CompletedAt,
+		})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return notification.ErrImportNotFound
+	}
+	return nil
+}
+
+func (r *ImportJobRepository) AddRowErrors(ctx context.Context, jobID string, errs []notification.RowError) error {
+	if len(errs) == 0 {
+		return nil
+	}
+	rows := make([]ImportRowErrorModel, len(errs))
+	for i, e := range errs {
+		rows[i] = ImportRowErrorModel{JobID: jobID, Line: e.Row, Field: e.Field, Message: e.Message}
+	}
+	return r.db.WithContext(ctx).CreateInBatches(rows, 500).Error
+}
+
+func (r *ImportJobRepository) ListRowErrors(ctx context.Context, jobID string, afterRow, limit int) ([]notification.RowError, error) {
+	// Page by rows, not errors, so that a row's errors are never split across pages.
+	lastLine := r.db.Table("(?) AS page",
+		r.db.Model(&ImportRowErrorModel{}).Distinct("line").
+			Where("job_id = ? AND line > ?", jobID, afterRow).
+			Order("line").Limit(limit),
+	).Select("MAX(line)")
+
+	var list []ImportRowErrorModel
+	err := r.db.WithContext(ctx).
+		Where("job_id = ? AND line > ? AND line <= (?)", jobID, afterRow, lastLine).
+		Order("line, id").
+		Find(&list).Error
+	if err != nil {
+		return nil, err
+	}
+	out := make([]notification.RowError, len(list))
+	for i, m := range list {
+		out[i] = notification.RowError{Row: m.Line, Field: m.Field, Message: m.Message}
+	}
+	return out, nil
+}
+
+func (r *ImportJobRepository) RenewLeases(ctx context.Context, ids []string, until time.Time) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Model(&ImportJobModel{}).
+		Where("id IN ? AND status IN ?", ids, unfinishedImportStatuses()).
+		Update("lease_expires_at", until).Error
+}
+
+func (r *ImportJobRepository) FailExpired(ctx context.Context, now time.Time, reason string) (int, error) {
+	res := r.db.WithContext(ctx).Model(&ImportJobModel{}).
+		Where("status IN ? AND (lease_expires_at IS NULL OR lease_expires_at < ?)", unfinishedImportStatuses(), now).
+		Updates(map[string]interface{}{
+			"status":         notification.ImportStatusFailed.String(),
+			"failure_reason": reason,
+			"updated_at":     now,
+			"completed_at":   now,
+		})
+	return int(res.RowsAffected), res.Error
+}
+
+func unfinishedImportStatuses() []string {
+	return []string{
+		notification.ImportStatusPending.String(),
+		notification.ImportStatusProcessing.String(),
+	}
+}
+
+func toImportJobModel(job *notification.ImportJob) *ImportJobModel {
+	return &ImportJobModel{
+		ID:             job.ID,
+		TenantID:       job.TenantID,
+		ClientID:       job.ClientID,
+		Format:         job.Format.String(),
+		Status:         job.Status.String(),
+		TotalRows:      job.TotalRows,
+		AcceptedRows:   job.AcceptedRows,
+		RejectedRows:   job.RejectedRows,
+		DuplicateRows:  job.DuplicateRows,
+		BatchIDs:       strings.Join(job.BatchIDs, " "),
+		FailureReason:  job.FailureReason,
+		LeaseExpiresAt: job.LeaseExpiresAt,
+		CreatedAt:      job.CreatedAt,
+		UpdatedAt:      job.UpdatedAt,
+		CompletedAt:    job.CompletedAt,
+	}
+}
+
+func toImportJobDomain(m *ImportJobModel) *notification.ImportJob {
+	return &notification.ImportJob{
+		ID:             m.ID,
+		TenantID:       m.TenantID,
+		ClientID:       m.ClientID,
+		Format:         notification.ImportFormat(m.Format),
+		Status:         notification.ImportStatus(m.Status),
+		TotalRows:      m.TotalRows,
+		AcceptedRows:   m.AcceptedRows,
+		RejectedRows:   m.RejectedRows,
+		DuplicateRows:  m.DuplicateRows,
+		BatchIDs:       strings.Fields(m.BatchIDs),
+		FailureReason:  m.FailureReason,
+		LeaseExpiresAt: m.LeaseExpiresAt,
+		CreatedAt:      m.CreatedAt,
+		UpdatedAt:      m.UpdatedAt,
+		CompletedAt:    m.CompletedAt,
+	}
+}
@@ -0,0 +1,128 @@
+package postgres
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/semih-yildiz/notification-service/internal/application/inbox/port"
+	"github.com/semih-yildiz/notification-service/internal/domain/inbox"
+)
+
+var _ port.InboxRepository = (*InboxRepository)(nil)
+
+// InboxRepository stores users' in-app messages. User IDs are only unique within a
+// tenant, so it is meant for tenant-scoped contexts.
+type InboxRepository struct {
+	db *gorm.DB
+}
+
+func NewInboxRepository(db *gorm.DB) *InboxRepository {
+	return &InboxRepository{db: db}
+}
+
+func (r *InboxRepository) Add(ctx context.Context, m *inbox.Message) (bool, error) {
+	res := r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(toInboxMessageModel(m))
+	if res.Error != nil {
+		return false, res.Error
+	}
+	return res.RowsAffected == 1, nil
+}
+
+func (r *InboxRepository) GetByID(ctx context.Context, userID, id string) (*inbox.Message, error) {
+	var m InboxMessageModel
+	err := r.db.WithContext(ctx).Scopes(tenantScope(ctx)).Where("id = ? AND user_id = ?", id, userID).First(&m).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, inbox.ErrNotFound
+		}
+		return nil, err
+	}
+	return toInboxMessageDomain(&m), nil
+}
+
+func (r *InboxRepository) Update(ctx context.Context, m *inbox.Message) error {
+	res := r.db.WithContext(ctx).Model(&InboxMessageModel{}).
+		Scopes(tenantScope(ctx)).
+		Where("id = ? AND user_id = ?", m.ID, m.UserID).
+		Updates(map[string]interface{}{
+			"state":       m.State.String(),
+			"read_at":     m.ReadAt,
+			"archived_at": m.ArchivedAt,
+			"updated_at":  m.UpdatedAt,
+		})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return inbox.ErrNotFound
+	}
+	return nil
+}
+
+func (r *InboxRepository) List(ctx context.Context, userID string, filter port.MessageFilter) ([]*inbox.Message, error) {
+	q := r.db.WithContext(ctx).Scopes(tenantScope(ctx)).Where("user_id = ?", userID)
+	if len(filter.States) > 0 {
+		states := make([]string, len(filter.States))
+		for i, s := range filter.States {
+			states[i] = s.String()
+		}
+		q = q.Where("state IN ?", states)
+	}
+	if filter.After != nil {
+		q = q.Where("(created_at, id) < (?, ?)", filter.After.CreatedAt, filter.After.ID)
+	}
+	if filter.Limit > 0 {
+		q = q.Limit(filter.Limit)
+	}
+
+	var list []InboxMessageModel
+	if err := q.Order("created_at DESC, id DESC").Find(&list).Error; err != nil {
+		return nil, err
+	}
+	out := make([]*inbox.Message, len(list))
+	for i := range list {
+		out[i] = toInboxMessageDomain(&list[i])
+	}
+	return out, nil
+}
+
+func (r *InboxRepository) CountUnread(ctx context.Context, userID string) (int64, error) {
+	var n int64
+	err := r.db.WithContext(ctx).Model(&InboxMessageModel{}).
+		Scopes(tenantScope(ctx)).
+		Where("user_id = ? AND state = ?", userID, inbox.StateUnread.String()).
+		Count(&n).Error
+	return n, err
+}
+
+func toInboxMessageModel(m *inbox.Message) *InboxMessageModel {
+	return &InboxMessageModel{
+		ID:         m.ID,
+		TenantID:   m.TenantID,
+		UserID:     m.UserID,
+		State:      m.State.String(),
+		Category:   m.Category,
+		Content:    m.Content,
+		ReadAt:     m.ReadAt,
+		ArchivedAt: m.ArchivedAt,
+		CreatedAt:  m.CreatedAt,
+		UpdatedAt:  m.UpdatedAt,
+	}
+}
+
+func toInboxMessageDomain(m *InboxMessageModel) *inbox.Message {
+	return &inbox.Message{
+		ID:         m.ID,
+		TenantID:   m.TenantID,
+		UserID:     m.UserID,
+		Category:   m.Category,
+		Content:    m.Content,
+		State:      inbox.State(m.State),
+		ReadAt:     m.ReadAt,
+		ArchivedAt: m.ArchivedAt,
+		CreatedAt:  m.CreatedAt,
+		UpdatedAt:  m.UpdatedAt,
+	}
+}
@@ -29,6 +29,7 @@ func (r *MetricsRepository) GetNotificationStats(ctx context.Context) (*port.Not
 	var results []result
 	err := r.db.WithContext(ctx).
 		Model(&NotificationModel{}).
+		Scopes(tenantScope(ctx)).
 		Select("status, count(*) as count").
 		Group("status").
 		Scan(&results).Error
@@ -36,17 +37,57 @@ func (r *MetricsRepository) GetNotificationStats(ctx context.Context) (*port.Not
 		return nil, err
 	}
 	for _, res := range results {
-		switch res.Status {
-		case "pending":
-			stats.Pending = res.Count
-		case "queued":
-			stats.Queued = res.Count
-		case "sent":
-			stats.Sent = res.Count
-		case "failed":
-			stats.Failed = res.Count
-		}
-		stats.Total += res.Count
+		addStatusCount(stats, res.Status, res.Count)
 	}
 	return stats, nil
 }
+
+func (r *MetricsRepository) GetTenantStats(ctx context.Context) (map[string]*port.NotificationStats, error) {
+	type result struct {
+		TenantID string
+		Status   string
+		Count    int64
+	}
+	var results []result
+	err := r.db.WithContext(ctx).
+		Model(&NotificationModel{}).
+		Scopes(tenantScope(ctx)).
+		Select("tenant_id, status, count(*) as count").
+		Group("tenant_id, status").
+		Scan(&results).Error
+	if err != nil {
+		return nil, err
+	}
+	out := make(map[string]*port.NotificationStats)
+	for _, res := range results {
+		if out[res.TenantID] == nil {
+			out[res.TenantID] = &port.NotificationStats{}
+		}
+		addStatusCount(out[res.TenantID], res.Status, res.Count)
+	}
+	return out, nil
+}
+
+func addStatusCount(stats *port.NotificationStats, status string, count int64) {
+	switch status {
+	case "pending":
+		stats.Pending = count
+	case "queued":
+		stats.Queued = count
+	case "deferred":
+		stats.Deferred = count
+	case "buffered":
+		stats.Buffered = count
+	case "digested":
+		stats.Digested = count
+	case "sent":
+		stats.Sent = count
+	case "delivered":
+		stats.Delivered = count
+	case "undelivered":
+		stats.Undelivered = count
+	case "failed":
+		stats.Failed = count
+	}
+	stats.Total += count
+}
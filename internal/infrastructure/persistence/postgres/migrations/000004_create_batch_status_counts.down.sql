@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS batch_status_counts;
+
+ALTER TABLE batches DROP COLUMN IF EXISTS completed_at;
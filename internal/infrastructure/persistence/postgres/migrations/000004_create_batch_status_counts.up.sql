@@ -0,0 +1,25 @@
+ALTER TABLE batches ADD COLUMN IF NOT EXISTS completed_at TIMESTAMPTZ;
+
+CREATE TABLE IF NOT EXISTS batch_status_counts (
+    batch_id TEXT NOT NULL REFERENCES batches(id) ON DELETE CASCADE,
+    status   TEXT NOT NULL,
+    count    INT NOT NULL DEFAULT 0,
+    PRIMARY KEY (batch_id, status)
+);
+
+-- Backfill counters for batches created before aggregation existed
+INSERT INTO batch_status_counts (batch_id, status, count)
+SELECT batch_id, status, COUNT(*)
+FROM notifications
+WHERE batch_id IS NOT NULL
+GROUP BY batch_id, status
+ON CONFLICT (batch_id, status) DO UPDATE SET count = EXCLUDED.count;
+
+UPDATE batches b
+SET completed_at = NOW()
+WHERE completed_at IS NULL
+  AND EXISTS (SELECT 1 FROM notifications n WHERE n.batch_id = b.id)
+  AND NOT EXISTS (
+      SELECT 1 FROM notifications n
+      WHERE n.batch_id = b.id AND n.status IN ('pending', 'queued')
+  );
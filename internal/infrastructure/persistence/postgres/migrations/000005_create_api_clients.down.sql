@@ -0,0 +1,4 @@
+ALTER TABLE batches DROP COLUMN IF EXISTS client_id;
+ALTER TABLE notifications DROP COLUMN IF EXISTS client_id;
+
+DROP TABLE IF EXISTS api_clients;
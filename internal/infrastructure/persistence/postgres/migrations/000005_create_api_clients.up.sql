@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS api_clients (
+    id          TEXT PRIMARY KEY,
+    name        TEXT NOT NULL,
+    key_prefix  TEXT NOT NULL,
+    key_hash    TEXT NOT NULL,
+    scopes      TEXT NOT NULL,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    rotated_at  TIMESTAMPTZ
+);
+
+CREATE UNIQUE INDEX idx_api_clients_key_hash ON api_clients(key_hash);
+
+ALTER TABLE notifications ADD COLUMN IF NOT EXISTS client_id TEXT REFERENCES api_clients(id) ON DELETE SET NULL;
+ALTER TABLE batches ADD COLUMN IF NOT EXISTS client_id TEXT REFERENCES api_clients(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_notifications_client_id ON notifications(client_id) WHERE client_id IS NOT NULL;
+CREATE INDEX idx_batches_client_id ON batches(client_id) WHERE client_id IS NOT NULL;
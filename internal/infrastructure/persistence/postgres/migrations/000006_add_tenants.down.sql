@@ -0,0 +1,15 @@
+DROP INDEX IF EXISTS idx_api_clients_tenant_id;
+DROP INDEX IF EXISTS idx_notifications_tenant_created_at;
+DROP INDEX IF EXISTS idx_notifications_tenant_idempotency_key;
+DROP INDEX IF EXISTS idx_batches_tenant_idempotency_key;
+
+CREATE UNIQUE INDEX idx_notifications_idempotency_key ON notifications(idempotency_key) WHERE idempotency_key IS NOT NULL;
+CREATE INDEX idx_batches_idempotency_key ON batches(idempotency_key) WHERE idempotency_key IS NOT NULL;
+ALTER TABLE batches ADD CONSTRAINT batches_idempotency_key_key UNIQUE (idempotency_key);
+
+ALTER TABLE api_clients DROP COLUMN IF EXISTS tenant_id;
+ALTER TABLE batches DROP COLUMN IF EXISTS tenant_id;
+ALTER TABLE notifications DROP COLUMN IF EXISTS tenant_id;
+
+DROP TABLE IF EXISTS tenant_quotas;
+DROP TABLE IF EXISTS tenants;
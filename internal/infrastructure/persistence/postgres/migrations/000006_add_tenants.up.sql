@@ -0,0 +1,28 @@
+CREATE TABLE IF NOT EXISTS tenants (
+    id          TEXT PRIMARY KEY,
+    name        TEXT NOT NULL,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS tenant_quotas (
+    tenant_id    TEXT NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    channel      TEXT NOT NULL,
+    daily_limit  INT NOT NULL,
+    PRIMARY KEY (tenant_id, channel)
+);
+
+INSERT INTO tenants (id, name) VALUES ('default', 'Default') ON CONFLICT (id) DO NOTHING;
+
+ALTER TABLE notifications ADD COLUMN IF NOT EXISTS tenant_id TEXT NOT NULL DEFAULT 'default' REFERENCES tenants(id);
+ALTER TABLE batches ADD COLUMN IF NOT EXISTS tenant_id TEXT NOT NULL DEFAULT 'default' REFERENCES tenants(id);
+ALTER TABLE api_clients ADD COLUMN IF NOT EXISTS tenant_id TEXT NOT NULL DEFAULT 'default' REFERENCES tenants(id);
+
+-- Idempotency keys are unique per tenant
+ALTER TABLE batches DROP CONSTRAINT IF EXISTS batches_idempotency_key_key;
+DROP INDEX IF EXISTS idx_batches_idempotency_key;
+DROP INDEX IF EXISTS idx_notifications_idempotency_key;
+CREATE UNIQUE INDEX idx_batches_tenant_idempotency_key ON batches(tenant_id, idempotency_key) WHERE idempotency_key IS NOT NULL;
+CREATE UNIQUE INDEX idx_notifications_tenant_idempotency_key ON notifications(tenant_id, idempotency_key) WHERE idempotency_key IS NOT NULL;
+
+CREATE INDEX idx_notifications_tenant_created_at ON notifications(tenant_id, created_at DESC);
+CREATE INDEX idx_api_clients_tenant_id ON api_clients(tenant_id);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_notifications_tenant_created_at_id;
+CREATE INDEX idx_notifications_tenant_created_at ON notifications(tenant_id, created_at DESC);
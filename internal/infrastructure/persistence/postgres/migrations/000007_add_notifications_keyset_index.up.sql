@@ -0,0 +1,3 @@
+-- Keyset pagination orders listings by (created_at, id) within a tenant
+DROP INDEX IF EXISTS idx_notifications_tenant_created_at;
+CREATE INDEX idx_notifications_tenant_created_at_id ON notifications(tenant_id, created_at DESC, id DESC);
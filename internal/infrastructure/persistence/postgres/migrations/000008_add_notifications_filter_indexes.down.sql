@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_notifications_failure_reason_trgm;
+DROP INDEX IF EXISTS idx_notifications_tenant_recipient;
+DROP INDEX IF EXISTS idx_notifications_tenant_sent_at;
+DROP INDEX IF EXISTS idx_notifications_tenant_updated_at_id;
@@ -0,0 +1,6 @@
+-- Indexes for the list filters and sort options
+CREATE INDEX idx_notifications_tenant_updated_at_id ON notifications(tenant_id, updated_at DESC, id DESC);
+CREATE INDEX idx_notifications_tenant_sent_at ON notifications(tenant_id, sent_at) WHERE sent_at IS NOT NULL;
+CREATE INDEX idx_notifications_tenant_recipient ON notifications(tenant_id, recipient text_pattern_ops);
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+CREATE INDEX idx_notifications_failure_reason_trgm ON notifications USING gin (failure_reason gin_trgm_ops);
@@ -0,0 +1,30 @@
+-- Bulk import jobs and the rows they rejected
+CREATE TABLE IF NOT EXISTS import_jobs (
+    id TEXT PRIMARY KEY,
+    tenant_id TEXT NOT NULL DEFAULT 'default',
+    client_id TEXT,
+    format TEXT NOT NULL,
+    status TEXT NOT NULL,
+    total_rows INTEGER NOT NULL DEFAULT 0,
+    accepted_rows INTEGER NOT NULL DEFAULT 0,
+    rejected_rows INTEGER NOT NULL DEFAULT 0,
+    duplicate_rows INTEGER NOT NULL DEFAULT 0,
+    batch_ids TEXT NOT NULL DEFAULT '',
+    failure_reason TEXT,
+    created_at TIMESTAMPTZ NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL,
+    completed_at TIMESTAMPTZ
+);
+
+CREATE INDEX idx_import_jobs_tenant_id ON import_jobs(tenant_id);
+CREATE INDEX idx_import_jobs_status_updated_at ON import_jobs(status, updated_at);
+
+CREATE TABLE IF NOT EXISTS import_row_errors (
+    id BIGSERIAL PRIMARY KEY,
+    job_id TEXT NOT NULL REFERENCES import_jobs(id) ON DELETE CASCADE,
+    line INTEGER NOT NULL,
+    field TEXT NOT NULL DEFAULT '',
+    message TEXT NOT NULL
+);
+
+CREATE INDEX idx_import_row_errors_job_line ON import_row_errors(job_id, line);
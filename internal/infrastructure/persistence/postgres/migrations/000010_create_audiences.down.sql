@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_batches_audience_id;
+ALTER TABLE batches DROP COLUMN IF EXISTS expanding;
+ALTER TABLE batches DROP COLUMN IF EXISTS audience_id;
+DROP TABLE IF EXISTS audience_members;
+DROP TABLE IF EXISTS audiences;
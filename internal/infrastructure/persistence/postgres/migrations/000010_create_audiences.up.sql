@@ -0,0 +1,24 @@
+-- Recipient lists (audiences) and batches sent to them
+CREATE TABLE IF NOT EXISTS audiences (
+    id TEXT PRIMARY KEY,
+    tenant_id TEXT NOT NULL DEFAULT 'default' REFERENCES tenants(id),
+    name TEXT NOT NULL,
+    client_id TEXT,
+    created_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE UNIQUE INDEX idx_audiences_tenant_name ON audiences(tenant_id, name);
+
+CREATE TABLE IF NOT EXISTS audience_members (
+    audience_id TEXT NOT NULL REFERENCES audiences(id) ON DELETE CASCADE,
+    channel TEXT NOT NULL,
+    recipient TEXT NOT NULL,
+    suppressed BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMPTZ NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (audience_id, channel, recipient)
+);
+
+ALTER TABLE batches ADD COLUMN IF NOT EXISTS audience_id TEXT;
+ALTER TABLE batches ADD COLUMN IF NOT EXISTS expanding BOOLEAN NOT NULL DEFAULT FALSE;
+CREATE INDEX idx_batches_audience_id ON batches(audience_id);
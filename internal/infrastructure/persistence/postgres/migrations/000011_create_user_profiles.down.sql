@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_notifications_user_id;
+ALTER TABLE notifications DROP COLUMN IF EXISTS resolved_by;
+ALTER TABLE notifications DROP COLUMN IF EXISTS category;
+ALTER TABLE notifications DROP COLUMN IF EXISTS user_id;
+DROP TABLE IF EXISTS user_preferences;
+DROP TABLE IF EXISTS user_profiles;
@@ -0,0 +1,28 @@
+-- User directory: addresses and per-category channel preferences
+CREATE TABLE IF NOT EXISTS user_profiles (
+    tenant_id TEXT NOT NULL REFERENCES tenants(id),
+    user_id TEXT NOT NULL,
+    phone TEXT NOT NULL DEFAULT '',
+    email TEXT NOT NULL DEFAULT '',
+    push_tokens TEXT NOT NULL DEFAULT '',
+    locale TEXT NOT NULL DEFAULT '',
+    opt_outs TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (tenant_id, user_id)
+);
+
+CREATE TABLE IF NOT EXISTS user_preferences (
+    tenant_id TEXT NOT NULL,
+    user_id TEXT NOT NULL,
+    category TEXT NOT NULL,
+    channels TEXT NOT NULL DEFAULT '',
+    PRIMARY KEY (tenant_id, user_id, category),
+    FOREIGN KEY (tenant_id, user_id) REFERENCES user_profiles(tenant_id, user_id) ON DELETE CASCADE
+);
+
+-- How notifications sent to a user ID were resolved
+ALTER TABLE notifications ADD COLUMN IF NOT EXISTS user_id TEXT;
+ALTER TABLE notifications ADD COLUMN IF NOT EXISTS category TEXT;
+ALTER TABLE notifications ADD COLUMN IF NOT EXISTS resolved_by TEXT;
+CREATE INDEX idx_notifications_user_id ON notifications(user_id);
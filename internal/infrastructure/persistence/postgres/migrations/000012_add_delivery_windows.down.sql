@@ -0,0 +1,12 @@
+DROP TABLE IF EXISTS notification_history;
+ALTER TABLE user_profiles DROP COLUMN IF EXISTS time_zone;
+DROP TABLE IF EXISTS tenant_delivery_windows;
+-- NOT VALID keeps rows still deferred
+ALTER TABLE notifications DROP CONSTRAINT IF EXISTS notifications_status_check;
+ALTER TABLE notifications ADD CONSTRAINT notifications_status_check
+    CHECK (status IN ('pending', 'queued', 'sent', 'failed', 'cancelled')) NOT VALID;
+DROP INDEX IF EXISTS idx_notifications_deferred_until;
+ALTER TABLE notifications DROP COLUMN IF EXISTS deferred_until;
+ALTER TABLE notifications DROP COLUMN IF EXISTS window_time_zone;
+ALTER TABLE notifications DROP COLUMN IF EXISTS window_end;
+ALTER TABLE notifications DROP COLUMN IF EXISTS window_start;
@@ -0,0 +1,31 @@
+-- Delivery windows: notifications outside theirs are deferred until it opens
+ALTER TABLE notifications ADD COLUMN IF NOT EXISTS window_start INTEGER;
+ALTER TABLE notifications ADD COLUMN IF NOT EXISTS window_end INTEGER;
+ALTER TABLE notifications ADD COLUMN IF NOT EXISTS window_time_zone TEXT;
+ALTER TABLE notifications ADD COLUMN IF NOT EXISTS deferred_until TIMESTAMPTZ;
+CREATE INDEX idx_notifications_deferred_until ON notifications(deferred_until);
+ALTER TABLE notifications DROP CONSTRAINT IF EXISTS notifications_status_check;
+ALTER TABLE notifications ADD CONSTRAINT notifications_status_check
+    CHECK (status IN ('pending', 'queued', 'deferred', 'sent', 'failed', 'cancelled'));
+
+CREATE TABLE IF NOT EXISTS tenant_delivery_windows (
+    tenant_id TEXT NOT NULL REFERENCES tenants(id),
+    category TEXT NOT NULL,
+    start_minute INTEGER NOT NULL,
+    end_minute INTEGER NOT NULL,
+    time_zone TEXT NOT NULL DEFAULT '',
+    PRIMARY KEY (tenant_id, category)
+);
+
+ALTER TABLE user_profiles ADD COLUMN IF NOT EXISTS time_zone TEXT NOT NULL DEFAULT '';
+
+-- Events besides delivery attempts, e.g. deferrals
+CREATE TABLE IF NOT EXISTS notification_history (
+    id TEXT PRIMARY KEY,
+    notification_id TEXT NOT NULL REFERENCES notifications(id),
+    event TEXT NOT NULL,
+    detail TEXT NOT NULL DEFAULT '',
+    occurred_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX idx_notification_history_notification_id ON notification_history(notification_id);
@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS notification_fallback_steps;
+DROP INDEX IF EXISTS idx_notifications_parent_id;
+DROP INDEX IF EXISTS idx_notifications_fallback_child_id;
+ALTER TABLE notifications DROP COLUMN IF EXISTS parent_id;
+ALTER TABLE notifications DROP COLUMN IF EXISTS fallback_deadline;
+ALTER TABLE notifications DROP COLUMN IF EXISTS fallback_child_id;
+ALTER TABLE notifications DROP COLUMN IF EXISTS fallback_step;
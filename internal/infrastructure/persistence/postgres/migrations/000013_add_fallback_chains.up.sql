@@ -0,0 +1,16 @@
+-- Fallback chains: a parent notification delivered through one child per step
+ALTER TABLE notifications ADD COLUMN IF NOT EXISTS fallback_step INTEGER;
+ALTER TABLE notifications ADD COLUMN IF NOT EXISTS fallback_child_id TEXT;
+ALTER TABLE notifications ADD COLUMN IF NOT EXISTS fallback_deadline TIMESTAMPTZ;
+ALTER TABLE notifications ADD COLUMN IF NOT EXISTS parent_id TEXT REFERENCES notifications(id);
+CREATE INDEX idx_notifications_fallback_child_id ON notifications(fallback_child_id);
+CREATE INDEX idx_notifications_parent_id ON notifications(parent_id);
+
+CREATE TABLE IF NOT EXISTS notification_fallback_steps (
+    notification_id TEXT NOT NULL REFERENCES notifications(id),
+    position INTEGER NOT NULL,
+    channel TEXT NOT NULL,
+    recipient TEXT NOT NULL,
+    timeout_seconds INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (notification_id, position)
+);
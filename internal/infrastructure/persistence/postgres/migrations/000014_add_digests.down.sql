@@ -0,0 +1,11 @@
+DROP TABLE IF EXISTS tenant_digest_policies;
+-- NOT VALID keeps rows still buffered or digested
+ALTER TABLE notifications DROP CONSTRAINT IF EXISTS notifications_status_check;
+ALTER TABLE notifications ADD CONSTRAINT notifications_status_check
+    CHECK (status IN ('pending', 'queued', 'deferred', 'sent', 'failed', 'cancelled')) NOT VALID;
+DROP INDEX IF EXISTS idx_notifications_digest_id;
+DROP INDEX IF EXISTS idx_notifications_digest_due;
+ALTER TABLE notifications DROP COLUMN IF EXISTS template_id;
+ALTER TABLE notifications DROP COLUMN IF EXISTS digest_id;
+ALTER TABLE notifications DROP COLUMN IF EXISTS digest_due;
+DROP TABLE IF EXISTS templates;
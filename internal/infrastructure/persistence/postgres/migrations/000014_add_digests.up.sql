@@ -0,0 +1,30 @@
+-- Message templates, used to render digests
+CREATE TABLE IF NOT EXISTS templates (
+    id TEXT PRIMARY KEY,
+    tenant_id TEXT NOT NULL DEFAULT 'default' REFERENCES tenants(id),
+    name TEXT NOT NULL,
+    body TEXT NOT NULL,
+    client_id TEXT,
+    created_at TIMESTAMPTZ NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE UNIQUE INDEX idx_templates_tenant_name ON templates(tenant_id, name);
+
+-- Digests: buffered notifications wait for digest_due, then point to their digest
+ALTER TABLE notifications ADD COLUMN IF NOT EXISTS digest_due TIMESTAMPTZ;
+ALTER TABLE notifications ADD COLUMN IF NOT EXISTS digest_id TEXT REFERENCES notifications(id);
+ALTER TABLE notifications ADD COLUMN IF NOT EXISTS template_id TEXT;
+CREATE INDEX idx_notifications_digest_due ON notifications(digest_due);
+CREATE INDEX idx_notifications_digest_id ON notifications(digest_id);
+ALTER TABLE notifications DROP CONSTRAINT IF EXISTS notifications_status_check;
+ALTER TABLE notifications ADD CONSTRAINT notifications_status_check
+    CHECK (status IN ('pending', 'queued', 'deferred', 'buffered', 'digested', 'sent', 'failed', 'cancelled'));
+
+CREATE TABLE IF NOT EXISTS tenant_digest_policies (
+    tenant_id TEXT NOT NULL REFERENCES tenants(id),
+    category TEXT NOT NULL,
+    window_seconds INTEGER NOT NULL,
+    template_id TEXT NOT NULL DEFAULT '',
+    PRIMARY KEY (tenant_id, category)
+);
@@ -0,0 +1,5 @@
+-- NOT VALID keeps rows already delivered or undelivered
+ALTER TABLE notifications DROP CONSTRAINT IF EXISTS notifications_status_check;
+ALTER TABLE notifications ADD CONSTRAINT notifications_status_check
+    CHECK (status IN ('pending', 'queued', 'deferred', 'buffered', 'digested', 'sent', 'failed', 'cancelled')) NOT VALID;
+DROP INDEX IF EXISTS idx_delivery_attempts_response_body;
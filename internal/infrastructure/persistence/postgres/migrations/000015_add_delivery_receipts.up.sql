@@ -0,0 +1,7 @@
+-- Delivery receipts are matched to the successful attempt by the provider's message id
+CREATE INDEX idx_delivery_attempts_response_body ON delivery_attempts(response_body) WHERE success;
+
+-- Receipts move sent notifications to delivered or undelivered
+ALTER TABLE notifications DROP CONSTRAINT IF EXISTS notifications_status_check;
+ALTER TABLE notifications ADD CONSTRAINT notifications_status_check
+    CHECK (status IN ('pending', 'queued', 'deferred', 'buffered', 'digested', 'sent', 'delivered', 'undelivered', 'failed', 'cancelled'));
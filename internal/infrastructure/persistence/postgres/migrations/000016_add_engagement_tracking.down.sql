@@ -0,0 +1,5 @@
+ALTER TABLE user_profiles DROP COLUMN IF EXISTS tracking_opt_out;
+DROP INDEX IF EXISTS idx_notifications_template_id;
+ALTER TABLE notifications DROP COLUMN IF EXISTS clicked_at;
+ALTER TABLE notifications DROP COLUMN IF EXISTS opened_at;
+ALTER TABLE notifications DROP COLUMN IF EXISTS tracking;
@@ -0,0 +1,7 @@
+-- Open and click tracking of HTML email
+ALTER TABLE notifications ADD COLUMN IF NOT EXISTS tracking BOOLEAN NOT NULL DEFAULT false;
+ALTER TABLE notifications ADD COLUMN IF NOT EXISTS opened_at TIMESTAMPTZ;
+ALTER TABLE notifications ADD COLUMN IF NOT EXISTS clicked_at TIMESTAMPTZ;
+CREATE INDEX idx_notifications_template_id ON notifications(template_id);
+
+ALTER TABLE user_profiles ADD COLUMN IF NOT EXISTS tracking_opt_out BOOLEAN NOT NULL DEFAULT false;
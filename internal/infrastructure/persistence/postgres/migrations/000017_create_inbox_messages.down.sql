@@ -0,0 +1,6 @@
+-- NOT VALID keeps existing in-app notifications
+ALTER TABLE notifications DROP CONSTRAINT IF EXISTS notifications_channel_check;
+ALTER TABLE notifications ADD CONSTRAINT notifications_channel_check
+    CHECK (channel IN ('sms', 'email', 'push')) NOT VALID;
+DROP INDEX IF EXISTS idx_inbox_messages_user;
+DROP TABLE IF EXISTS inbox_messages;
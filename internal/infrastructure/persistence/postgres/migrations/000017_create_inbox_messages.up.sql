@@ -0,0 +1,19 @@
+-- In-app inbox: one message per in-app notification
+CREATE TABLE IF NOT EXISTS inbox_messages (
+    id TEXT PRIMARY KEY,
+    tenant_id TEXT NOT NULL REFERENCES tenants(id),
+    user_id TEXT NOT NULL,
+    state TEXT NOT NULL DEFAULT 'unread',
+    category TEXT NOT NULL DEFAULT '',
+    content TEXT NOT NULL,
+    read_at TIMESTAMPTZ,
+    archived_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX idx_inbox_messages_user ON inbox_messages(tenant_id, user_id, state, created_at);
+
+ALTER TABLE notifications DROP CONSTRAINT IF EXISTS notifications_channel_check;
+ALTER TABLE notifications ADD CONSTRAINT notifications_channel_check
+    CHECK (channel IN ('sms', 'email', 'push', 'inapp'));
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS template_variants;
+ALTER TABLE notifications DROP COLUMN IF EXISTS locale;
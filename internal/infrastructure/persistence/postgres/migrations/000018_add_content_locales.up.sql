@@ -0,0 +1,10 @@
+-- Locale of the content variant a notification was sent in
+ALTER TABLE notifications ADD COLUMN IF NOT EXISTS locale TEXT NOT NULL DEFAULT '';
+
+-- Template bodies per locale
+CREATE TABLE IF NOT EXISTS template_variants (
+    template_id TEXT NOT NULL REFERENCES templates(id) ON DELETE CASCADE,
+    locale TEXT NOT NULL,
+    body TEXT NOT NULL,
+    PRIMARY KEY (template_id, locale)
+);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_import_jobs_status_lease;
+CREATE INDEX IF NOT EXISTS idx_import_jobs_status_updated_at ON import_jobs(status, updated_at);
+ALTER TABLE import_jobs DROP COLUMN IF EXISTS lease_expires_at;
@@ -0,0 +1,8 @@
+-- Import jobs are held by a lease their process renews; expired jobs are failed
+ALTER TABLE import_jobs ADD COLUMN IF NOT EXISTS lease_expires_at TIMESTAMPTZ;
+
+UPDATE import_jobs SET lease_expires_at = updated_at + INTERVAL '1 minute'
+WHERE lease_expires_at IS NULL AND status IN ('pending', 'processing');
+
+DROP INDEX IF EXISTS idx_import_jobs_status_updated_at;
+CREATE INDEX IF NOT EXISTS idx_import_jobs_status_lease ON import_jobs(status, lease_expires_at);
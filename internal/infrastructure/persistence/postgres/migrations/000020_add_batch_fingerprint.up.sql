@@ -0,0 +1,2 @@
+-- Hash of the items of the request that created a batch under its idempotency key
+ALTER TABLE batches ADD COLUMN IF NOT EXISTS fingerprint TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,4 @@
+-- NOT VALID keeps existing chat notifications
+ALTER TABLE notifications DROP CONSTRAINT IF EXISTS notifications_channel_check;
+ALTER TABLE notifications ADD CONSTRAINT notifications_channel_check
+    CHECK (channel IN ('sms', 'email', 'push', 'inapp')) NOT VALID;
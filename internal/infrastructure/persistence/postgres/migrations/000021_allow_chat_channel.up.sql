@@ -0,0 +1,4 @@
+-- Chat notifications posted to incoming webhooks
+ALTER TABLE notifications DROP CONSTRAINT IF EXISTS notifications_channel_check;
+ALTER TABLE notifications ADD CONSTRAINT notifications_channel_check
+    CHECK (channel IN ('sms', 'email', 'push', 'inapp', 'chat'));
@@ -0,0 +1,2 @@
+ALTER TABLE notifications ADD CONSTRAINT notifications_channel_check
+    CHECK (channel IN ('sms', 'email', 'push', 'inapp', 'chat')) NOT VALID;
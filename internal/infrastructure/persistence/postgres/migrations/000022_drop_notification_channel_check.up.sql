@@ -0,0 +1,2 @@
+-- Channels come from the application's registry; new ones need no migration
+ALTER TABLE notifications DROP CONSTRAINT IF EXISTS notifications_channel_check;
@@ -8,41 +8,281 @@ import (
 
 type BatchModel struct {
 	ID             string         `gorm:"type:text;primaryKey"`
-	IdempotencyKey *string        `gorm:"type:text;uniqueIndex"`
+	TenantID       string         `gorm:"type:text;not null;default:'default';uniqueIndex:idx_batches_tenant_idempotency_key,priority:1"`
+	IdempotencyKey *string        `gorm:"type:text;uniqueIndex:idx_batches_tenant_idempotency_key,priority:2"`
+	ClientID       *string        `gorm:"type:text;index"`
+	AudienceID     *string        `gorm:"type:text;index"`
+	Fingerprint    string         `gorm:"type:text;not null;default:''"`
+	Expanding      bool           `gorm:"not null;default:false"`
 	CreatedAt      time.Time      `gorm:"not null"`
+	CompletedAt    *time.Time     `gorm:"type:timestamptz"`
 	DeletedAt      gorm.DeletedAt `gorm:"index"`
 }
 
 func (BatchModel) TableName() string { return "batches" }
 
+// BatchStatusCountModel keeps one counter row per (batch, status) so batch progress
+// can be read without scanning the batch's notifications.
+type BatchStatusCountModel struct {
+	BatchID string `gorm:"type:text;primaryKey"`
+	Status  string `gorm:"type:text;primaryKey"`
+	Count   int    `gorm:"not null;default:0"`
+}
+
+func (BatchStatusCountModel) TableName() string { return "batch_status_counts" }
+
 type NotificationModel struct {
-	ID             string         `gorm:"type:text;primaryKey"`
-	BatchID        *string        `gorm:"type:text;index"`
-	Recipient      string         `gorm:"type:text;not null"`
-	Channel        string         `gorm:"type:text;not null"`
-	Content        string         `gorm:"type:text;not null"`
-	Priority       string         `gorm:"type:text;not null"`
-	Status         string         `gorm:"type:text;not null"`
-	IdempotencyKey *string        `gorm:"type:text;uniqueIndex"`
-	CreatedAt      time.Time      `gorm:"not null"`
-	UpdatedAt      time.Time      `gorm:"not null"`
-	SentAt         *time.Time     `gorm:"type:timestamptz"`
-	FailureReason  *string        `gorm:"type:text"`
-	DeletedAt      gorm.DeletedAt `gorm:"index"`
+	ID             string     `gorm:"type:text;primaryKey;index:idx_notifications_tenant_created_at_id,priority:3;index:idx_notifications_tenant_updated_at_id,priority:3"`
+	TenantID       string     `gorm:"type:text;not null;default:'default';index:idx_notifications_tenant_created_at_id,priority:1;index:idx_notifications_tenant_updated_at_id,priority:1;index:idx_notifications_tenant_sent_at,priority:1;uniqueIndex:idx_notifications_tenant_idempotency_key,priority:1"`
+	BatchID        *string    `gorm:"type:text;index"`
+	Recipient      string     `gorm:"type:text;not null"`
+	Channel        string     `gorm:"type:text;not null"`
+	Content        string     `gorm:"type:text;not null"`
+	Priority       string     `gorm:"type:text;not null"`
+	Status         string     `gorm:"type:text;not null"`
+	IdempotencyKey *string    `gorm:"type:text;uniqueIndex:idx_notifications_tenant_idempotency_key,priority:2"`
+	ClientID       *string    `gorm:"type:text;index"`
+	CreatedAt      time.Time  `gorm:"not null;index:idx_notifications_tenant_created_at_id,priority:2"`
+	UpdatedAt      time.Time  `gorm:"not null;index:idx_notifications_tenant_updated_at_id,priority:2"`
+	SentAt         *time.Time `gorm:"type:timestamptz;index:idx_notifications_tenant_sent_at,priority:2"`
+	FailureReason  *string    `gorm:"type:text"`
+	// Resolution of notifications sent to a user ID
+	UserID     *string `gorm:"type:text;index"`
+	Category   *string `gorm:"type:text"`
+	ResolvedBy *string `gorm:"type:text"`
+	// Delivery window, in minutes after midnight
+	WindowStart    *int
+	WindowEnd      *int
+	WindowTimeZone *string    `gorm:"type:text"`
+	DeferredUntil  *time.Time `gorm:"type:timestamptz;index"`
+	// Fallback chains: the parent tracks its current step, children point to it
+	FallbackStep     *int
+	FallbackChildID  *string    `gorm:"type:text;index"`
+	FallbackDeadline *time.Time `gorm:"type:timestamptz"`
+	ParentID         *string    `gorm:"type:text;index"`
+	// Digests: buffered notifications wait for DigestDue, then point to the digest
+	DigestDue  *time.Time `gorm:"type:timestamptz;index"`
+	DigestID   *string    `gorm:"type:text;index"`
+	TemplateID *string    `gorm:"type:text;index"`
+	Locale     string     `gorm:"type:text;not null;default:''"`
+	// Engagement of tracked emails: the first open and click
+	Tracking  bool           `gorm:"not null;default:false"`
+	OpenedAt  *time.Time     `gorm:"type:timestamptz"`
+	ClickedAt *time.Time     `gorm:"type:timestamptz"`
+	DeletedAt gorm.DeletedAt `gorm:"index"`
 }
 
 func (NotificationModel) TableName() string { return "notifications" }
 
+// FallbackStepModel is one step of a fallback chain, in the order it is tried.
+type FallbackStepModel struct {
+	NotificationID string `gorm:"type:text;primaryKey"`
+	Position       int    `gorm:"primaryKey"`
+	Channel        string `gorm:"type:text;not null"`
+	Recipient      string `gorm:"type:text;not null"`
+	TimeoutSeconds int    `gorm:"not null;default:0"`
+}
+
+func (FallbackStepModel) TableName() string { return "notification_fallback_steps" }
+
 type DeliveryAttemptModel struct {
 	ID             string         `gorm:"type:text;primaryKey"`
 	NotificationID string         `gorm:"type:text;not null;index"`
 	AttemptNumber  int            `gorm:"not null"`
 	Success        bool           `gorm:"not null"`
 	StatusCode     int            `gorm:"not null"`
-	ResponseBody   string         `gorm:"type:text"`
+	ResponseBody   string         `gorm:"type:text;index:idx_delivery_attempts_response_body,where:success"`
 	ErrorMessage   *string        `gorm:"type:text"`
 	CreatedAt      time.Time      `gorm:"not null"`
 	DeletedAt      gorm.DeletedAt `gorm:"index"`
 }
 
 func (DeliveryAttemptModel) TableName() string { return "delivery_attempts" }
+
+// NotificationHistoryModel is one event in a notification's history.
+type NotificationHistoryModel struct {
+	ID             string    `gorm:"type:text;primaryKey"`
+	NotificationID string    `gorm:"type:text;not null;index"`
+	Event          string    `gorm:"type:text;not null"`
+	Detail         string    `gorm:"type:text;not null;default:''"`
+	OccurredAt     time.Time `gorm:"not null"`
+}
+
+func (NotificationHistoryModel) TableName() string { return "notification_history" }
+
+// APIClientModel stores an API client. Only the SHA-256 of its key is kept.
+type APIClientModel struct {
+	ID        string     `gorm:"type:text;primaryKey"`
+	TenantID  string     `gorm:"type:text;not null;default:'default';index"`
+	Name      string     `gorm:"type:text;not null"`
+	KeyPrefix string     `gorm:"type:text;not null"`
+	KeyHash   string     `gorm:"type:text;not null;uniqueIndex"`
+	Scopes    string     `gorm:"type:text;not null"` // space-separated
+	CreatedAt time.Time  `gorm:"not null"`
+	RotatedAt *time.Time `gorm:"type:timestamptz"`
+}
+
+func (APIClientModel) TableName() string { return "api_clients" }
+
+type TenantModel struct {
+	ID        string    `gorm:"type:text;primaryKey"`
+	Name      string    `gorm:"type:text;not null"`
+	CreatedAt time.Time `gorm:"not null"`
+}
+
+func (TenantModel) TableName() string { return "tenants" }
+
+// TenantQuotaModel is one tenant's daily send limit on one channel.
+type TenantQuotaModel struct {
+	TenantID   string `gorm:"type:text;primaryKey"`
+	Channel    string `gorm:"type:text;primaryKey"`
+	DailyLimit int    `gorm:"not null"`
+}
+
+func (TenantQuotaModel) TableName() string { return "tenant_quotas" }
+
+// TenantDeliveryWindowModel is one tenant's delivery window for one category, in
+// minutes after midnight. An empty time zone means the recipient's.
+type TenantDeliveryWindowModel struct {
+	TenantID    string `gorm:"type:text;primaryKey"`
+	Category    string `gorm:"type:text;primaryKey"`
+	StartMinute int    `gorm:"not null"`
+	EndMinute   int    `gorm:"not null"`
+	TimeZone    string `gorm:"type:text;not null;default:''"`
+}
+
+func (TenantDeliveryWindowModel) TableName() string { return "tenant_delivery_windows" }
+
+// TenantDigestPolicyModel is one tenant's digest policy for one category. An empty
+// template ID uses the default summary.
+type TenantDigestPolicyModel struct {
+	TenantID      string `gorm:"type:text;primaryKey"`
+	Category      string `gorm:"type:text;primaryKey"`
+	WindowSeconds int    `gorm:"not null"`
+	TemplateID    string `gorm:"type:text;not null;default:''"`
+}
+
+func (TenantDigestPolicyModel) TableName() string { return "tenant_digest_policies" }
+
+// ImportJobModel stores a bulk import job. BatchIDs lists the batches created so
+// far, space-separated.
+type ImportJobModel struct {
+	ID             string     `gorm:"type:text;primaryKey"`
+	TenantID       string     `gorm:"type:text;not null;default:'default';index"`
+	ClientID       *string    `gorm:"type:text"`
+	Format         string     `gorm:"type:text;not null"`
+	Status         string     `gorm:"type:text;not null;index:idx_import_jobs_status_lease,priority:1"`
+	TotalRows      int        `gorm:"not null;default:0"`
+	AcceptedRows   int        `gorm:"not null;default:0"`
+	RejectedRows   int        `gorm:"not null;default:0"`
+	DuplicateRows  int        `gorm:"not null;default:0"`
+	BatchIDs       string     `gorm:"type:text;not null;default:''"`
+	FailureReason  *string    `gorm:"type:text"`
+	LeaseExpiresAt *time.Time `gorm:"type:timestamptz;index:idx_import_jobs_status_lease,priority:2"`
+	CreatedAt      time.Time  `gorm:"not null"`
+	UpdatedAt      time.Time  `gorm:"not null"`
+	CompletedAt    *time.Time `gorm:"type:timestamptz"`
+}
+
+func (ImportJobModel) TableName() string { return "import_jobs" }
+
+// ImportRowErrorModel is one reason a row of an import was rejected.
+type ImportRowErrorModel struct {
+	ID      int64  `gorm:"primaryKey;autoIncrement"`
+	JobID   string `gorm:"type:text;not null;index:idx_import_row_errors_job_line,priority:1"`
+	Line    int    `gorm:"not null;index:idx_import_row_errors_job_line,priority:2"`
+	Field   string `gorm:"type:text;not null;default:''"`
+	Message string `gorm:"type:text;not null"`
+}
+
+func (ImportRowErrorModel) TableName() string { return "import_row_errors" }
+
+type AudienceModel struct {
+	ID        string    `gorm:"type:text;primaryKey"`
+	TenantID  string    `gorm:"type:text;not null;default:'default';uniqueIndex:idx_audiences_tenant_name,priority:1"`
+	Name      string    `gorm:"type:text;not null;uniqueIndex:idx_audiences_tenant_name,priority:2"`
+	ClientID  *string   `gorm:"type:text"`
+	CreatedAt time.Time `gorm:"not null"`
+}
+
+func (AudienceModel) TableName() string { return "audiences" }
+
+type TemplateModel struct {
+	ID        string    `gorm:"type:text;primaryKey"`
+	TenantID  string    `gorm:"type:text;not null;default:'default';uniqueIndex:idx_templates_tenant_name,priority:1"`
+	Name      string    `gorm:"type:text;not null;uniqueIndex:idx_templates_tenant_name,priority:2"`
+	Body      string    `gorm:"type:text;not null"`
+	ClientID  *string   `gorm:"type:text"`
+	CreatedAt time.Time `gorm:"not null"`
+	UpdatedAt time.Time `gorm:"not null"`
+}
+
+func (TemplateModel) TableName() string { return "templates" }
+
+// TemplateVariantModel is a template's body in one locale.
+type TemplateVariantModel struct {
+	TemplateID string `gorm:"type:text;primaryKey"`
+	Locale     string `gorm:"type:text;primaryKey"`
+	Body       string `gorm:"type:text;not null"`
+}
+
+func (TemplateVariantModel) TableName() string { return "template_variants" }
+
+// AudienceMemberModel is one recipient of an audience on one channel. The primary
+// key orders members for paging.
+type AudienceMemberModel struct {
+	AudienceID string    `gorm:"type:text;primaryKey"`
+	Channel    string    `gorm:"type:text;primaryKey"`
+	Recipient  string    `gorm:"type:text;primaryKey"`
+	Suppressed bool      `gorm:"not null;default:false"`
+	CreatedAt  time.Time `gorm:"not null"`
+	UpdatedAt  time.Time `gorm:"not null"`
+}
+
+func (AudienceMemberModel) TableName() string { return "audience_members" }
+
+// UserProfileModel is a user of a tenant's system with their addresses. Push tokens
+// and opt-out channels are stored space-separated.
+type UserProfileModel struct {
+	TenantID       string    `gorm:"type:text;primaryKey"`
+	UserID         string    `gorm:"type:text;primaryKey"`
+	Phone          string    `gorm:"type:text;not null;default:''"`
+	Email          string    `gorm:"type:text;not null;default:''"`
+	PushTokens     string    `gorm:"type:text;not null;default:''"`
+	Locale         string    `gorm:"type:text;not null;default:''"`
+	TimeZone       string    `gorm:"type:text;not null;default:''"`
+	OptOuts        string    `gorm:"type:text;not null;default:''"`
+	TrackingOptOut bool      `gorm:"not null;default:false"`
+	CreatedAt      time.Time `gorm:"not null"`
+	UpdatedAt      time.Time `gorm:"not null"`
+}
+
+func (UserProfileModel) TableName() string { return "user_profiles" }
+
+// UserPreferenceModel is a user's channels for one category, most preferred first and
+// space-separated. Empty channels opt the user out of the category.
+type UserPreferenceModel struct {
+	TenantID string `gorm:"type:text;primaryKey"`
+	UserID   string `gorm:"type:text;primaryKey"`
+	Category string `gorm:"type:text;primaryKey"`
+	Channels string `gorm:"type:text;not null;default:''"`
+}
+
+func (UserPreferenceModel) TableName() string { return "user_preferences" }
+
+// InboxMessageModel is an in-app notification in a user's inbox, keyed by the
+// notification's ID. Inboxes are listed newest first per state.
+type InboxMessageModel struct {
+	ID         string     `gorm:"type:text;primaryKey"`
+	TenantID   string     `gorm:"type:text;not null;index:idx_inbox_messages_user,priority:1"`
+	UserID     string     `gorm:"type:text;not null;index:idx_inbox_messages_user,priority:2"`
+	State      string     `gorm:"type:text;not null;default:'unread';index:idx_inbox_messages_user,priority:3"`
+	Category   string     `gorm:"type:text;not null;default:''"`
+	Content    string     `gorm:"type:text;not null"`
+	ReadAt     *time.Time `gorm:"type:timestamptz"`
+	ArchivedAt *time.Time `gorm:"type:timestamptz"`
+	CreatedAt  time.Time  `gorm:"not null;index:idx_inbox_messages_user,priority:4"`
+	UpdatedAt  time.Time  `gorm:"not null"`
+}
+
+func (InboxMessageModel) TableName() string { return "inbox_messages" }
@@ -2,14 +2,26 @@ package postgres
 
 import (
 	"context"
+	"fmt"
+	"strings"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/semih-yildiz/notification-service/internal/application/notification/port"
 	"github.com/semih-yildiz/notification-service/internal/domain/notification"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
-var _ port.NotificationRepository = (*NotificationRepository)(nil)
+var (
+	_ port.NotificationRepository = (*NotificationRepository)(nil)
+	_ port.DeferralRepository     = (*NotificationRepository)(nil)
+	_ port.HistoryRepository      = (*NotificationRepository)(nil)
+	_ port.FallbackRepository     = (*NotificationRepository)(nil)
+	_ port.DigestRepository       = (*NotificationRepository)(nil)
+	_ port.ReceiptRepository      = (*NotificationRepository)(nil)
+	_ port.EngagementRepository   = (*NotificationRepository)(nil)
+)
 
 type NotificationRepository struct {
 	db *gorm.DB
@@ -21,38 +33,75 @@ func NewNotificationRepository(db *gorm.DB) *NotificationRepository {
 
 func (r *NotificationRepository) Create(ctx context.Context, n *notification.Notification) error {
 	m := toNotificationModel(n)
-	return r.db.WithContext(ctx).Create(&m).Error
+	if n.BatchID == nil {
+		return r.db.WithContext(ctx).Create(&m).Error
+	}
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&m).Error; err != nil {
+			return err
+		}
+		return adjustBatchCounts(tx, *n.BatchID, map[notification.Status]int{n.Status: 1})
+	})
 }
 
-func (r *NotificationRepository) CreateBatch(ctx context.Context, notifications []*notification.Notification) error {
-	if len(notifications) == 0 {
+func (r *NotificationRepository) CreateBatch(ctx context.Context, b *notification.Batch, notifications []*notification.Notification) error {
+	if b == nil && len(notifications) == 0 {
 		return nil
 	}
 
 	models := make([]*NotificationModel, len(notifications))
+	deltas := make(map[string]map[notification.Status]int)
 	for i, n := range notifications {
 		models[i] = toNotificationModel(n)
+		if n.BatchID != nil {
+			if deltas[*n.BatchID] == nil {
+				deltas[*n.BatchID] = make(map[notification.Status]int)
+			}
+			deltas[*n.BatchID][n.Status]++
+		}
 	}
 
-	// GORM CreateInBatches: inserts in chunks of 100 to avoid parameter limits
-	return r.db.WithContext(ctx).CreateInBatches(models, 100).Error
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if b != nil {
+			if err := tx.Create(toBatchModel(b)).Error; err != nil {
+				return err
+			}
+		}
+		if len(models) == 0 {
+			return nil
+		}
+		// GORM CreateInBatches: inserts in chunks of 100 to avoid parameter limits
+		if err := tx.CreateInBatches(models, 100).Error; err != nil {
+			return err
+		}
+		for batchID, d := range deltas {
+			if err := adjustBatchCounts(tx, batchID, d); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
 }
 
 func (r *NotificationRepository) GetByID(ctx context.Context, id string) (*notification.Notification, error) {
 	var m NotificationModel
-	err := r.db.WithContext(ctx).Where("id = ?", id).First(&m).Error
+	err := r.db.WithContext(ctx).Scopes(tenantScope(ctx)).Where("id = ?", id).First(&m).Error
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, notification.ErrNotFound
 		}
 		return nil, err
 	}
-	return toNotificationDomain(&m), nil
+	n := toNotificationDomain(&m)
+	if err := loadFallbackSteps(r.db.WithContext(ctx), n); err != nil {
+		return nil, err
+	}
+	return n, nil
 }
 
 func (r *NotificationRepository) GetByBatchID(ctx context.Context, batchID string) ([]*notification.Notification, error) {
 	var list []NotificationModel
-	err := r.db.WithContext(ctx).Where("batch_id = ?", batchID).Order("created_at").Find(&list).Error
+	err := r.db.WithContext(ctx).Scopes(tenantScope(ctx)).Where("batch_id = ?", batchID).Order("created_at").Find(&list).Error
 	if err != nil {
 		return nil, err
 	}
@@ -74,85 +123,826 @@ func (r *NotificationRepository) UpdateStatus(ctx context.Context, id string, st
 	if failureReason != nil {
 		updates["failure_reason"] = failureReason
 	}
-	res := r.db.WithContext(ctx).Model(&NotificationModel{}).Where("id = ?", id).Updates(updates)
-	if res.Error != nil {
-		return res.Error
-	}
-	if res.RowsAffected == 0 {
-		return notification.ErrNotFound
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var cur NotificationModel
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Scopes(tenantScope(ctx)).
+			Select("id", "batch_id", "status").Where("id = ?", id).First(&cur).Error
+		if err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return notification.ErrNotFound
+			}
+			return err
+		}
+		if err := tx.Model(&NotificationModel{}).Where("id = ?", id).Updates(updates).Error; err != nil {
+			return err
+		}
+		if cur.BatchID == nil || cur.Status == status.String() {
+			return nil
+		}
+		return adjustBatchCounts(tx, *cur.BatchID, map[notification.Status]int{
+			notification.Status(cur.Status): -1,
+			status:                          1,
+		})
+	})
+}
+
+func (r *NotificationRepository) UpdateStatuses(ctx context.Context, ids []string, status notification.Status) error {
+	if len(ids) == 0 {
+		return nil
 	}
-	return nil
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var cur []NotificationModel
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Scopes(tenantScope(ctx)).
+			Select("id", "batch_id", "status").Where("id IN ?", ids).Order("id").Find(&cur).Error
+		if err != nil {
+			return err
+		}
+		if len(cur) == 0 {
+			return nil
+		}
+		locked := make([]string, len(cur))
+		deltas := make(map[string]map[notification.Status]int)
+		for i, m := range cur {
+			locked[i] = m.ID
+			if m.BatchID == nil || m.Status == status.String() {
+				continue
+			}
+			if deltas[*m.BatchID] == nil {
+				deltas[*m.BatchID] = make(map[notification.Status]int)
+			}
+			deltas[*m.BatchID][notification.Status(m.Status)]--
+			deltas[*m.BatchID][status]++
+		}
+		err = tx.Model(&NotificationModel{}).Where("id IN ?", locked).
+			Updates(map[string]interface{}{"status": status.String(), "updated_at": time.Now()}).Error
+		if err != nil {
+			return err
+		}
+		for batchID, d := range deltas {
+			if err := adjustBatchCounts(tx, batchID, d); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
 }
 
 func (r *NotificationRepository) List(ctx context.Context, filter port.ListFilter) (*port.ListResult, error) {
-	q := r.db.WithContext(ctx).Model(&NotificationModel{})
-	if filter.Status != nil {
-		q = q.Where("status = ?", filter.Status.String())
-	}
-	if filter.Channel != nil {
-		q = q.Where("channel = ?", filter.Channel.String())
-	}
-	if filter.FromTime != nil {
-		q = q.Where("created_at >= ?", *filter.FromTime)
-	}
-	if filter.ToTime != nil {
-		q = q.Where("created_at <= ?", *filter.ToTime)
-	}
-	if filter.BatchID != nil {
-		q = q.Where("batch_id = ?", *filter.BatchID)
-	}
-	var total int64
-	if err := q.Count(&total).Error; err != nil {
-		return nil, err
+	q := r.db.WithContext(ctx).Model(&NotificationModel{}).Scopes(tenantScope(ctx), listFilterScope(filter))
+	result := &port.ListResult{}
+	if filter.WithTotal {
+		var total int64
+		if err := q.Count(&total).Error; err != nil {
+			return nil, err
+		}
+		n := int(total)
+		result.Total = &n
 	}
 	limit := filter.Limit
 	if limit <= 0 || limit > 1000 {
 		limit = 100
 	}
 	offset := filter.Offset
-	if offset < 0 {
+	if offset < 0 || filter.After != nil {
 		offset = 0
 	}
+
+	col, dir, cmp := "created_at", "DESC", "<"
+	if filter.Sort.Field.Valid() {
+		col = string(filter.Sort.Field)
+	}
+	if filter.Sort.Ascending {
+		dir, cmp = "ASC", ">"
+	}
+	if filter.After != nil && filter.Sort.Keyset() {
+		q = q.Where(fmt.Sprintf("(created_at, id) %s (?, ?)", cmp), filter.After.At, filter.After.ID)
+	}
+	// One extra row tells whether there is a next page.
 	var list []NotificationModel
-	if err := q.Order("created_at DESC").Limit(limit).Offset(offset).Find(&list).Error; err != nil {
+	order := fmt.Sprintf("%s %s, id %s", col, dir, dir)
+	if err := q.Order(order).Limit(limit + 1).Offset(offset).Find(&list).Error; err != nil {
 		return nil, err
 	}
-	out := make([]*notification.Notification, len(list))
+	if len(list) > limit {
+		list = list[:limit]
+		if filter.Sort.Keyset() {
+			last := list[limit-1]
+			result.NextCursor = &port.Cursor{At: last.CreatedAt, ID: last.ID}
+		}
+	}
+	result.Notifications = make([]*notification.Notification, len(list))
 	for i := range list {
-		out[i] = toNotificationDomain(&list[i])
+		result.Notifications[i] = toNotificationDomain(&list[i])
+	}
+	if err := loadFallbackSteps(r.db.WithContext(ctx), result.Notifications...); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// listFilterScope applies the filters of a listing.
+func listFilterScope(filter port.ListFilter) func(*gorm.DB) *gorm.DB {
+	return func(q *gorm.DB) *gorm.DB {
+		if len(filter.Statuses) > 0 {
+			statuses := make([]string, len(filter.Statuses))
+			for i, st := range filter.Statuses {
+				statuses[i] = st.String()
+			}
+			q = q.Where("status IN ?", statuses)
+		}
+		if filter.Channel != nil {
+			q = q.Where("channel = ?", filter.Channel.String())
+		}
+		if filter.Priority != nil {
+			q = q.Where("priority = ?", filter.Priority.String())
+		}
+		if filter.Recipient != nil {
+			q = q.Where("recipient = ?", *filter.Recipient)
+		}
+		if filter.RecipientPrefix != nil {
+			q = q.Where(`recipient LIKE ? ESCAPE '\'`, escapeLike(*filter.RecipientPrefix)+"%")
+		}
+		if filter.FromTime != nil {
+			q = q.Where("created_at >= ?", *filter.FromTime)
+		}
+		if filter.ToTime != nil {
+			q = q.Where("created_at <= ?", *filter.ToTime)
+		}
+		if filter.SentFrom != nil {
+			q = q.Where("sent_at >= ?", *filter.SentFrom)
+		}
+		if filter.SentTo != nil {
+			q = q.Where("sent_at <= ?", *filter.SentTo)
+		}
+		if filter.BatchID != nil {
+			q = q.Where("batch_id = ?", *filter.BatchID)
+		}
+		if filter.ClientID != nil {
+			q = q.Where("client_id = ?", *filter.ClientID)
+		}
+		if filter.IdempotencyKey != nil {
+			q = q.Where("idempotency_key = ?", *filter.IdempotencyKey)
+		}
+		if filter.FailureReason != nil {
+			q = q.Where(`failure_reason ILIKE ? ESCAPE '\'`, "%"+escapeLike(*filter.FailureReason)+"%")
+		}
+		return q
 	}
-	return &port.ListResult{Notifications: out, Total: int(total)}, nil
 }
 
+// escapeLike escapes LIKE wildcards so s matches literally.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
+// CancelPending cancels a notification and, for a fallback parent, its current child.
 func (r *NotificationRepository) CancelPending(ctx context.Context, id string) error {
-	res := r.db.WithContext(ctx).Model(&NotificationModel{}).
-		Where("id = ? AND status IN ?", id, []string{"pending", "queued"}).
-		Updates(map[string]interface{}{"status": "cancelled", "updated_at": time.Now()})
-	if res.Error != nil {
-		return res.Error
+	n, err := r.cancelWhere(ctx, "id = ? OR parent_id = ?", id, id)
+	if err != nil {
+		return err
 	}
-	if res.RowsAffected == 0 {
+	if n == 0 {
 		return notification.ErrNotFound
 	}
 	return nil
 }
 
 func (r *NotificationRepository) CancelPendingByBatchID(ctx context.Context, batchID string) (int, error) {
-	res := r.db.WithContext(ctx).Model(&NotificationModel{}).
-		Where("batch_id = ? AND status IN ?", batchID, []string{"pending", "queued"}).
-		Updates(map[string]interface{}{"status": "cancelled", "updated_at": time.Now()})
-	return int(res.RowsAffected), res.Error
+	return r.cancelWhere(ctx, "batch_id = ?", batchID)
+}
+
+// cancelWhere cancels pending/queued/deferred/buffered notifications matching the condition and keeps
+// batch counters in sync. It returns the number of cancelled notifications.
+func (r *NotificationRepository) cancelWhere(ctx context.Context, query string, args ...interface{}) (int, error) {
+	cancelled := 0
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var rows []NotificationModel
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Scopes(tenantScope(ctx)).
+			Select("id", "batch_id", "status").
+			Where(query, args...).
+			Where("status IN ?", cancellableStatuses()).
+			Find(&rows).Error
+		if err != nil || len(rows) == 0 {
+			return err
+		}
+
+		ids := make([]string, len(rows))
+		deltas := make(map[string]map[notification.Status]int)
+		for i, row := range rows {
+			ids[i] = row.ID
+			if row.BatchID != nil {
+				if deltas[*row.BatchID] == nil {
+					deltas[*row.BatchID] = make(map[notification.Status]int)
+				}
+				deltas[*row.BatchID][notification.Status(row.Status)]--
+				deltas[*row.BatchID][notification.StatusCancelled]++
+			}
+		}
+
+		res := tx.Model(&NotificationModel{}).Where("id IN ?", ids).
+			Updates(map[string]interface{}{"status": "cancelled", "deferred_until": nil, "digest_due": nil, "updated_at": time.Now()})
+		if res.Error != nil {
+			return res.Error
+		}
+		cancelled = int(res.RowsAffected)
+
+		for batchID, d := range deltas {
+			if err := adjustBatchCounts(tx, batchID, d); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return cancelled, err
+}
+
+func (r *NotificationRepository) Defer(ctx context.Context, id string, until time.Time, detail string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var cur NotificationModel
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Scopes(tenantScope(ctx)).
+			Select("id", "batch_id", "status").Where("id = ?", id).First(&cur).Error
+		if err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return notification.ErrNotFound
+			}
+			return err
+		}
+		if notification.Status(cur.Status).Terminal() {
+			return nil
+		}
+		now := time.Now()
+		err = tx.Model(&NotificationModel{}).Where("id = ?", id).Updates(map[string]interface{}{
+			"status":         notification.StatusDeferred.String(),
+			"deferred_until": until,
+			"updated_at":     now,
+		}).Error
+		if err != nil {
+			return err
+		}
+		if err := tx.Create(newHistoryModel(id, notification.HistoryDeferred, detail, now)).Error; err != nil {
+			return err
+		}
+		if cur.BatchID == nil || cur.Status == notification.StatusDeferred.String() {
+			return nil
+		}
+		return adjustBatchCounts(tx, *cur.BatchID, map[notification.Status]int{
+			notification.Status(cur.Status): -1,
+			notification.StatusDeferred:     1,
+		})
+	})
+}
+
+func (r *NotificationRepository) ReleaseDue(ctx context.Context, now time.Time, limit int) ([]*notification.Notification, error) {
+	var released []*notification.Notification
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// SKIP LOCKED lets several workers release different notifications at once.
+		var rows []NotificationModel
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND deferred_until <= ?", notification.StatusDeferred.String(), now).
+			Order("deferred_until").Limit(limit).Find(&rows).Error
+		if err != nil || len(rows) == 0 {
+			return err
+		}
+
+		ids := make([]string, len(rows))
+		history := make([]*NotificationHistoryModel, len(rows))
+		deltas := make(map[string]map[notification.Status]int)
+		for i := range rows {
+			ids[i] = rows[i].ID
+			history[i] = newHistoryModel(rows[i].ID, notification.HistoryReleased, "", now)
+			if b := rows[i].BatchID; b != nil {
+				if deltas[*b] == nil {
+					deltas[*b] = make(map[notification.Status]int)
+				}
+				deltas[*b][notification.StatusDeferred]--
+				deltas[*b][notification.StatusQueued]++
+			}
+			rows[i].Status = notification.StatusQueued.String()
+			rows[i].DeferredUntil = nil
+			rows[i].UpdatedAt = now
+			released = append(released, toNotificationDomain(&rows[i]))
+		}
+
+		err = tx.Model(&NotificationModel{}).Where("id IN ?", ids).Updates(map[string]interface{}{
+			"status":         notification.StatusQueued.String(),
+			"deferred_until": nil,
+			"updated_at":     now,
+		}).Error
+		if err != nil {
+			return err
+		}
+		if err := tx.Create(&history).Error; err != nil {
+			return err
+		}
+		for batchID, d := range deltas {
+			if err := adjustBatchCounts(tx, batchID, d); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return released, nil
+}
+
+func (r *NotificationRepository) CreateFallback(ctx context.Context, parent, first *notification.Notification) error {
+	steps := make([]FallbackStepModel, len(parent.Fallback.Steps))
+	for i, step := range parent.Fallback.Steps {
+		steps[i] = FallbackStepModel{
+			NotificationID: parent.ID,
+			Position:       i,
+			Channel:        step.Channel.String(),
+			Recipient:      step.Recipient,
+			TimeoutSeconds: int(step.Timeout / time.Second),
+		}
+	}
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(toNotificationModel(parent)).Error; err != nil {
+			return err
+		}
+		if err := tx.Create(&steps).Error; err != nil {
+			return err
+		}
+		if err := tx.Create(toNotificationModel(first)).Error; err != nil {
+			return err
+		}
+		return tx.Create(newHistoryModel(parent.ID, notification.HistoryStepStarted, parent.Fallback.Describe(), parent.CreatedAt)).Error
+	})
+}
+
+func (r *NotificationRepository) DueFallbacks(ctx context.Context, now time.Time, limit int) ([]*port.DueFallback, error) {
+	db := r.db.WithContext(ctx)
+	var parents []NotificationModel
+	err := db.Where("status = ? AND fallback_child_id IS NOT NULL", notification.StatusQueued.String()).
+		Where("fallback_deadline <= ? OR EXISTS (SELECT 1 FROM notifications c WHERE c.id = notifications.fallback_child_id AND (c.status IN ? OR (c.status = ? AND notifications.fallback_deadline IS NULL)))",
+			now, []string{"delivered", "undelivered", "failed", "cancelled"}, notification.StatusSent.String()).
+		Order("updated_at").Limit(limit).Find(&parents).Error
+	if err != nil || len(parents) == 0 {
+		return nil, err
+	}
+
+	childIDs := make([]string, len(parents))
+	for i, p := range parents {
+		childIDs[i] = *p.FallbackChildID
+	}
+	var children []NotificationModel
+	if err := db.Where("id IN ?", childIDs).Find(&children).Error; err != nil {
+		return nil, err
+	}
+	byID := make(map[string]*notification.Notification, len(children))
+	for i := range children {
+		byID[children[i].ID] = toNotificationDomain(&children[i])
+	}
+
+	due := make([]*port.DueFallback, 0, len(parents))
+	domain := make([]*notification.Notification, 0, len(parents))
+	for i := range parents {
+		child, ok := byID[*parents[i].FallbackChildID]
+		if !ok {
+			continue
+		}
+		parent := toNotificationDomain(&parents[i])
+		due = append(due, &port.DueFallback{Parent: parent, Child: child})
+		domain = append(domain, parent)
+	}
+	if err := loadFallbackSteps(db, domain...); err != nil {
+		return nil, err
+	}
+	return due, nil
+}
+
+func (r *NotificationRepository) AdvanceFallback(ctx context.Context, parentID string, from int, next *notification.Notification, deadline *time.Time, events []*notification.HistoryEntry) (bool, error) {
+	advanced := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		cur, ok, err := lockFallbackStep(ctx, tx, parentID, from)
+		if err != nil || !ok {
+			return err
+		}
+		if err := cancelFallbackChild(tx, cur); err != nil {
+			return err
+		}
+		if err := tx.Create(toNotificationModel(next)).Error; err != nil {
+			return err
+		}
+		err = tx.Model(&NotificationModel{}).Where("id = ?", parentID).Updates(map[string]interface{}{
+			"fallback_step":     from + 1,
+			"fallback_child_id": next.ID,
+			"fallback_deadline": deadline,
+			"updated_at":        time.Now(),
+		}).Error
+		if err != nil {
+			return err
+		}
+		if err := createHistory(tx, parentID, events); err != nil {
+			return err
+		}
+		advanced = true
+		return nil
+	})
+	return advanced, err
+}
+
+func (r *NotificationRepository) FinishFallback(ctx context.Context, parentID string, from int, result *notification.Notification, events []*notification.HistoryEntry) (bool, error) {
+	finished := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		cur, ok, err := lockFallbackStep(ctx, tx, parentID, from)
+		if err != nil || !ok {
+			return err
+		}
+		if err := cancelFallbackChild(tx, cur); err != nil {
+			return err
+		}
+		err = tx.Model(&NotificationModel{}).Where("id = ?", parentID).Updates(map[string]interface{}{
+			"status":            result.Status.String(),
+			"channel":           result.Channel.String(),
+			"recipient":         result.Recipient,
+			"sent_at":           result.SentAt,
+			"failure_reason":    result.FailureReason,
+			"fallback_deadline": nil,
+			"updated_at":        time.Now(),
+		}).Error
+		if err != nil {
+			return err
+		}
+		if err := createHistory(tx, parentID, events); err != nil {
+			return err
+		}
+		finished = true
+		return nil
+	})
+	return finished, err
+}
+
+func (r *NotificationRepository) Buffer(ctx context.Context, n *notification.Notification) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var open struct{ Due *time.Time }
+		err := tx.Model(&NotificationModel{}).Select("MIN(digest_due) AS due").
+			Where("tenant_id = ? AND channel = ? AND recipient = ? AND status = ?",
+				n.TenantID, n.Channel.String(), n.Recipient, notification.StatusBuffered.String()).
+			Scan(&open).Error
+		if err != nil {
+			return err
+		}
+		if open.Due != nil {
+			n.DigestDue = open.Due
+		}
+		if err := tx.Create(toNotificationModel(n)).Error; err != nil {
+			return err
+		}
+		detail := "digest due at " + n.DigestDue.UTC().Format(time.RFC3339)
+		return tx.Create(newHistoryModel(n.ID, notification.HistoryBuffered, detail, n.CreatedAt)).Error
+	})
+}
+
+func (r *NotificationRepository) DueDigests(ctx context.Context, now time.Time, limit int) ([]*port.DigestBuffer, error) {
+	db := r.db.WithContext(ctx)
+	var keys []struct {
+		TenantID  string
+		Channel   string
+		Recipient string
+	}
+	err := db.Model(&NotificationModel{}).Select("tenant_id, channel, recipient").
+		Where("status = ?", notification.StatusBuffered.String()).
+		Group("tenant_id, channel, recipient").
+		Having("MIN(digest_due) <= ?", now).
+		Order("MIN(digest_due)").Limit(limit).Scan(&keys).Error
+	if err != nil || len(keys) == 0 {
+		return nil, err
+	}
+
+	due := make([]*port.DigestBuffer, 0, len(keys))
+	for _, k := range keys {
+		var rows []NotificationModel
+		err := db.Where("tenant_id = ? AND channel = ? AND recipient = ? AND status = ?",
+			k.TenantID, k.Channel, k.Recipient, notification.StatusBuffered.String()).
+			Order("created_at, id").Find(&rows).Error
+		if err != nil {
+			return nil, err
+		}
+		if len(rows) == 0 {
+			continue
+		}
+		buf := &port.DigestBuffer{
+			TenantID:      k.TenantID,
+			Channel:       notification.Channel(k.Channel),
+			Recipient:     k.Recipient,
+			Notifications: make([]*notification.Notification, len(rows)),
+		}
+		for i := range rows {
+			buf.Notifications[i] = toNotificationDomain(&rows[i])
+		}
+		due = append(due, buf)
+	}
+	return due, nil
+}
+
+func (r *NotificationRepository) CreateDigest(ctx context.Context, digest *notification.Notification, bufferedIDs []string) (bool, error) {
+	created := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var rows []NotificationModel
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id", "status").
+			Where("id IN ?", bufferedIDs).Find(&rows).Error
+		if err != nil {
+			return err
+		}
+		if len(rows) != len(bufferedIDs) {
+			return nil
+		}
+		for _, row := range rows {
+			if row.Status != notification.StatusBuffered.String() {
+				return nil
+			}
+		}
+
+		if err := tx.Create(toNotificationModel(digest)).Error; err != nil {
+			return err
+		}
+		err = tx.Model(&NotificationModel{}).Where("id IN ?", bufferedIDs).Updates(map[string]interface{}{
+			"status":     notification.StatusDigested.String(),
+			"digest_id":  digest.ID,
+			"digest_due": nil,
+			"updated_at": digest.CreatedAt,
+		}).Error
+		if err != nil {
+			return err
+		}
+		history := make([]*NotificationHistoryModel, len(bufferedIDs))
+		for i, id := range bufferedIDs {
+			history[i] = newHistoryModel(id, notification.HistoryDigested, digest.ID, digest.CreatedAt)
+		}
+		if err := tx.Create(&history).Error; err != nil {
+			return err
+		}
+		created = true
+		return nil
+	})
+	return created, err
+}
+
+func (r *NotificationRepository) ApplyReceipt(ctx context.Context, rc *notification.DeliveryReceipt) (*notification.Notification, bool, error) {
+	var (
+		n       *notification.Notification
+		applied bool
+	)
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var cur NotificationModel
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = (?)", tx.Model(&DeliveryAttemptModel{}).Select("notification_id").
+				Where("response_body = ? AND success", rc.MessageID).Order("created_at DESC").Limit(1)).
+			First(&cur).Error
+		if err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return notification.ErrNotFound
+			}
+			return err
+		}
+		n = toNotificationDomain(&cur)
+		if cur.Status != notification.StatusSent.String() {
+			return nil
+		}
+
+		updates := map[string]interface{}{
+			"status":     rc.Status.String(),
+			"updated_at": time.Now(),
+		}
+		if rc.Status == notification.StatusUndelivered && rc.Reason != "" {
+			updates["failure_reason"] = rc.Reason
+		}
+		if err := tx.Model(&NotificationModel{}).Where("id = ?", cur.ID).Updates(updates).Error; err != nil {
+			return err
+		}
+		if err := tx.Create(newHistoryModel(cur.ID, notification.HistoryReceipt, rc.Detail(), rc.OccurredAt)).Error; err != nil {
+			return err
+		}
+		if cur.BatchID != nil {
+			err := adjustBatchCounts(tx, *cur.BatchID, map[notification.Status]int{
+				notification.StatusSent: -1,
+				rc.Status:               1,
+			})
+			if err != nil {
+				return err
+			}
+		}
+		applied = true
+		return nil
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	return n, applied, nil
+}
+
+func (r *NotificationRepository) RecordEngagement(ctx context.Context, id string, e *notification.Engagement) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var cur NotificationModel
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id", "opened_at", "clicked_at").Where("id = ?", id).First(&cur).Error
+		if err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return notification.ErrNotFound
+			}
+			return err
+		}
+
+		updates := map[string]interface{}{}
+		if cur.OpenedAt == nil {
+			updates["opened_at"] = e.OccurredAt
+		}
+		if e.Event == notification.HistoryClicked && cur.ClickedAt == nil {
+			updates["clicked_at"] = e.OccurredAt
+		}
+		if len(updates) > 0 {
+			if err := tx.Model(&NotificationModel{}).Where("id = ?", id).Updates(updates).Error; err != nil {
+				return err
+			}
+		}
+		if e.Event == notification.HistoryOpened && cur.OpenedAt != nil {
+			return nil
+		}
+		return tx.Create(newHistoryModel(id, e.Event, e.URL, e.OccurredAt)).Error
+	})
+}
+
+func (r *NotificationRepository) BatchEngagement(ctx context.Context, batchID string) (*notification.EngagementStats, error) {
+	return r.engagement(ctx, "batch_id = ?", batchID)
+}
+
+func (r *NotificationRepository) DigestEngagement(ctx context.Context, templateID string) (*notification.EngagementStats, error) {
+	return r.engagement(ctx, "template_id = ?", templateID)
+}
+
+// engagement counts the sent tracked emails matching the condition and their opens
+// and clicks.
+func (r *NotificationRepository) engagement(ctx context.Context, cond string, arg string) (*notification.EngagementStats, error) {
+	var stats notification.EngagementStats
+	err := r.db.WithContext(ctx).Model(&NotificationModel{}).Scopes(tenantScope(ctx)).
+		Select("COUNT(*) AS tracked, COUNT(opened_at) AS opened, COUNT(clicked_at) AS clicked").
+		Where(cond, arg).
+		Where("tracking AND channel = ? AND status IN ?", notification.ChannelEmail.String(),
+			[]string{notification.StatusSent.String(), notification.StatusDelivered.String()}).
+		Scan(&stats).Error
+	if err != nil {
+		return nil, err
+	}
+	return &stats, nil
+}
+
+// lockFallbackStep locks a fallback parent and reports whether it is still running
+// step from.
+func lockFallbackStep(ctx context.Context, tx *gorm.DB, parentID string, from int) (*NotificationModel, bool, error) {
+	var cur NotificationModel
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Scopes(tenantScope(ctx)).
+		Select("id", "status", "fallback_step", "fallback_child_id").Where("id = ?", parentID).First(&cur).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, false, notification.ErrNotFound
+		}
+		return nil, false, err
+	}
+	running := cur.Status == notification.StatusQueued.String() && cur.FallbackStep != nil && *cur.FallbackStep == from
+	return &cur, running, nil
+}
+
+// cancelFallbackChild cancels the parent's current child unless it has finished.
+// Children never belong to a batch, so there are no counters to adjust.
+func cancelFallbackChild(tx *gorm.DB, parent *NotificationModel) error {
+	if parent.FallbackChildID == nil {
+		return nil
+	}
+	return tx.Model(&NotificationModel{}).
+		Where("id = ? AND status IN ?", *parent.FallbackChildID, cancellableStatuses()).
+		Updates(map[string]interface{}{"status": "cancelled", "deferred_until": nil, "digest_due": nil, "updated_at": time.Now()}).Error
+}
+
+// cancellableStatuses returns the statuses of notifications not yet terminal.
+func cancellableStatuses() []string {
+	var out []string
+	for _, s := range notification.Statuses() {
+		if !s.Terminal() {
+			out = append(out, s.String())
+		}
+	}
+	return out
+}
+
+func createHistory(tx *gorm.DB, notificationID string, events []*notification.HistoryEntry) error {
+	if len(events) == 0 {
+		return nil
+	}
+	models := make([]*NotificationHistoryModel, len(events))
+	for i, e := range events {
+		models[i] = newHistoryModel(notificationID, e.Event, e.Detail, e.OccurredAt)
+	}
+	return tx.Create(&models).Error
+}
+
+// loadFallbackSteps fills in the steps of the fallback parents among list.
+func loadFallbackSteps(db *gorm.DB, list ...*notification.Notification) error {
+	parents := make(map[string]*notification.Notification)
+	for _, n := range list {
+		if n.Fallback != nil {
+			parents[n.ID] = n
+		}
+	}
+	if len(parents) == 0 {
+		return nil
+	}
+	ids := make([]string, 0, len(parents))
+	for id := range parents {
+		ids = append(ids, id)
+	}
+	var steps []FallbackStepModel
+	if err := db.Where("notification_id IN ?", ids).Order("notification_id, position").Find(&steps).Error; err != nil {
+		return err
+	}
+	for _, s := range steps {
+		f := parents[s.NotificationID].Fallback
+		f.Steps = append(f.Steps, notification.FallbackStep{
+			Channel:   notification.Channel(s.Channel),
+			Recipient: s.Recipient,
+			Timeout:   time.Duration(s.TimeoutSeconds) * time.Second,
+		})
+	}
+	return nil
+}
+
+func (r *NotificationRepository) ListHistory(ctx context.Context, notificationID string) ([]*notification.HistoryEntry, error) {
+	var list []NotificationHistoryModel
+	err := r.db.WithContext(ctx).Where("notification_id = ?", notificationID).Order("occurred_at, id").Find(&list).Error
+	if err != nil {
+		return nil, err
+	}
+	out := make([]*notification.HistoryEntry, len(list))
+	for i, m := range list {
+		out[i] = &notification.HistoryEntry{
+			ID:             m.ID,
+			NotificationID: m.NotificationID,
+			Event:          notification.HistoryEvent(m.Event),
+			Detail:         m.Detail,
+			OccurredAt:     m.OccurredAt,
+		}
+	}
+	return out, nil
+}
+
+func newHistoryModel(notificationID string, event notification.HistoryEvent, detail string, at time.Time) *NotificationHistoryModel {
+	return &NotificationHistoryModel{
+		ID:             uuid.New().String(),
+		NotificationID: notificationID,
+		Event:          event.String(),
+		Detail:         detail,
+		OccurredAt:     at,
+	}
 }
 
 func (r *NotificationRepository) ExistsByIdempotencyKey(ctx context.Context, key string) (bool, error) {
 	var n int64
-	err := r.db.WithContext(ctx).Model(&NotificationModel{}).Where("idempotency_key = ?", key).Limit(1).Count(&n).Error
+	err := r.db.WithContext(ctx).Model(&NotificationModel{}).Scopes(tenantScope(ctx)).Where("idempotency_key = ?", key).Limit(1).Count(&n).Error
 	return n > 0, err
 }
 
+func (r *NotificationRepository) GetByIdempotencyKey(ctx context.Context, key string) (*notification.Notification, error) {
+	var m NotificationModel
+	err := r.db.WithContext(ctx).Scopes(tenantScope(ctx)).Where("idempotency_key = ?", key).First(&m).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, notification.ErrNotFound
+		}
+		return nil, err
+	}
+	n := toNotificationDomain(&m)
+	if err := loadFallbackSteps(r.db.WithContext(ctx), n); err != nil {
+		return nil, err
+	}
+	return n, nil
+}
+
+func (r *NotificationRepository) IDsByIdempotencyKey(ctx context.Context, keys []string) (map[string]string, error) {
+	ids := make(map[string]string)
+	if len(keys) == 0 {
+		return ids, nil
+	}
+	var rows []struct {
+		ID             string
+		IdempotencyKey string
+	}
+	err := r.db.WithContext(ctx).Model(&NotificationModel{}).Scopes(tenantScope(ctx)).
+		Select("id", "idempotency_key").Where("idempotency_key IN ?", keys).Find(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		ids[row.IdempotencyKey] = row.ID
+	}
+	return ids, nil
+}
+
 func toNotificationModel(n *notification.Notification) *NotificationModel {
 	m := &NotificationModel{
 		ID:        n.ID,
+		TenantID:  n.TenantID,
 		Recipient: n.Recipient,
 		Channel:   n.Channel.String(),
 		Content:   n.Content,
@@ -163,14 +953,43 @@ func toNotificationModel(n *notification.Notification) *NotificationModel {
 	}
 	m.BatchID = n.BatchID
 	m.IdempotencyKey = n.IdempotencyKey
+	m.ClientID = n.ClientID
 	m.SentAt = n.SentAt
 	m.FailureReason = n.FailureReason
+	if r := n.Resolution; r != nil {
+		m.UserID = &r.UserID
+		m.Category = &r.Category
+		source := r.Source.String()
+		m.ResolvedBy = &source
+	}
+	if w := n.DeliveryWindow; w != nil {
+		start, end, zone := w.Start, w.End, w.TimeZone
+		m.WindowStart, m.WindowEnd, m.WindowTimeZone = &start, &end, &zone
+	}
+	m.DeferredUntil = n.DeferredUntil
+	if f := n.Fallback; f != nil {
+		step := f.Current
+		m.FallbackStep = &step
+		if f.ChildID != "" {
+			m.FallbackChildID = &f.ChildID
+		}
+		m.FallbackDeadline = f.Deadline
+	}
+	m.ParentID = n.ParentID
+	m.DigestDue = n.DigestDue
+	m.DigestID = n.DigestID
+	m.TemplateID = n.TemplateID
+	m.Locale = n.Locale
+	m.Tracking = n.Tracking
+	m.OpenedAt = n.OpenedAt
+	m.ClickedAt = n.ClickedAt
 	return m
 }
 
 func toNotificationDomain(m *NotificationModel) *notification.Notification {
 	n := &notification.Notification{
 		ID:        m.ID,
+		TenantID:  m.TenantID,
 		Recipient: m.Recipient,
 		Channel:   notification.Channel(m.Channel),
 		Content:   m.Content,
@@ -181,7 +1000,38 @@ func toNotificationDomain(m *NotificationModel) *notification.Notification {
 	}
 	n.BatchID = m.BatchID
 	n.IdempotencyKey = m.IdempotencyKey
+	n.ClientID = m.ClientID
 	n.SentAt = m.SentAt
 	n.FailureReason = m.FailureReason
+	if m.UserID != nil {
+		n.Resolution = &notification.Resolution{UserID: *m.UserID}
+		if m.Category != nil {
+			n.Resolution.Category = *m.Category
+		}
+		if m.ResolvedBy != nil {
+			n.Resolution.Source = notification.ResolutionSource(*m.ResolvedBy)
+		}
+	}
+	if m.WindowStart != nil && m.WindowEnd != nil {
+		n.DeliveryWindow = &notification.DeliveryWindow{Start: *m.WindowStart, End: *m.WindowEnd}
+		if m.WindowTimeZone != nil {
+			n.DeliveryWindow.TimeZone = *m.WindowTimeZone
+		}
+	}
+	n.DeferredUntil = m.DeferredUntil
+	if m.FallbackStep != nil {
+		n.Fallback = &notification.Fallback{Current: *m.FallbackStep, Deadline: m.FallbackDeadline}
+		if m.FallbackChildID != nil {
+			n.Fallback.ChildID = *m.FallbackChildID
+		}
+	}
+	n.ParentID = m.ParentID
+	n.DigestDue = m.DigestDue
+	n.DigestID = m.DigestID
+	n.TemplateID = m.TemplateID
+	n.Locale = m.Locale
+	n.Tracking = m.Tracking
+	n.OpenedAt = m.OpenedAt
+	n.ClickedAt = m.ClickedAt
 	return n
 }
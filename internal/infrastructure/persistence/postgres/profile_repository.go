@@ -0,0 +1,197 @@
+package postgres
+
+import (
+	"context"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/semih-yildiz/notification-service/internal/application/profile/port"
+	"github.com/semih-yildiz/notification-service/internal/domain/notification"
+	"github.com/semih-yildiz/notification-service/internal/domain/profile"
+)
+
+var _ port.ProfileRepository = (*ProfileRepository)(nil)
+
+// ProfileRepository stores user profiles. User IDs are only unique within a tenant,
+// so it is meant for tenant-scoped contexts.
+type ProfileRepository struct {
+	db *gorm.DB
+}
+
+func NewProfileRepository(db *gorm.DB) *ProfileRepository {
+	return &ProfileRepository{db: db}
+}
+
+func (r *ProfileRepository) Save(ctx context.Context, p *profile.Profile) error {
+	m := &UserProfileModel{
+		TenantID:       p.TenantID,
+		UserID:         p.UserID,
+		Phone:          p.Phone,
+		Email:          p.Email,
+		PushTokens:     strings.Join(p.PushTokens, " "),
+		Locale:         p.Locale,
+		TimeZone:       p.TimeZone,
+		OptOuts:        joinChannels(p.OptOuts),
+		TrackingOptOut: p.TrackingOptOut,
+		CreatedAt:      p.CreatedAt,
+		UpdatedAt:      p.UpdatedAt,
+	}
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "tenant_id"}, {Name: "user_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"phone", "email", "push_tokens", "locale", "time_zone", "opt_outs", "tracking_opt_out", "updated_at"}),
+		}).Create(m).Error
+		if err != nil {
+			return err
+		}
+		if err := tx.Where("tenant_id = ? AND user_id = ?", p.TenantID, p.UserID).Delete(&UserPreferenceModel{}).Error; err != nil {
+			return err
+		}
+		if len(p.Preferences) == 0 {
+			return nil
+		}
+		rows := make([]UserPreferenceModel, 0, len(p.Preferences))
+		for category, channels := range p.Preferences {
+			rows = append(rows, UserPreferenceModel{TenantID: p.TenantID, UserID: p.UserID, Category: category, Channels: joinChannels(channels)})
+		}
+		return tx.Create(&rows).Error
+	})
+}
+
+func (r *ProfileRepository) GetByID(ctx context.Context, userID string) (*profile.Profile, error) {
+	var m UserProfileModel
+	err := r.db.WithContext(ctx).Scopes(tenantScope(ctx)).Where("user_id = ?", userID).First(&m).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, profile.ErrNotFound
+		}
+		return nil, err
+	}
+	var prefs []UserPreferenceModel
+	if err := r.db.WithContext(ctx).Where("tenant_id = ? AND user_id = ?", m.TenantID, m.UserID).Find(&prefs).Error; err != nil {
+		return nil, err
+	}
+	return toProfileDomain(&m, prefs), nil
+}
+
+func (r *ProfileRepository) Delete(ctx context.Context, userID string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var m UserProfileModel
+		if err := tx.Scopes(tenantScope(ctx)).Where("user_id = ?", userID).First(&m).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return profile.ErrNotFound
+			}
+			return err
+		}
+		if err := tx.Where("tenant_id = ? AND user_id = ?", m.TenantID, m.UserID).Delete(&UserPreferenceModel{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("tenant_id = ? AND user_id = ?", m.TenantID, m.UserID).Delete(&UserProfileModel{}).Error
+	})
+}
+
+func (r *ProfileRepository) TrackingOptOuts(ctx context.Context, emails []string) (map[string]bool, error) {
+	out := make(map[string]bool)
+	if len(emails) == 0 {
+		return out, nil
+	}
+	lower := make([]string, len(emails))
+	for i, e := range emails {
+		lower[i] = strings.ToLower(e)
+	}
+	var found []string
+	err := r.db.WithContext(ctx).Model(&UserProfileModel{}).Scopes(tenantScope(ctx)).
+		Where("tracking_opt_out AND LOWER(email) IN ?", lower).
+		Distinct().Pluck("LOWER(email)", &found).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range found {
+		out[e] = true
+	}
+	return out, nil
+}
+
+func (r *ProfileRepository) Locales(ctx context.Context, ch notification.Channel, addresses []string) (map[string]string, error) {
+	out := make(map[string]string)
+	if len(addresses) == 0 {
+		return out, nil
+	}
+	q := r.db.WithContext(ctx).Scopes(tenantScope(ctx)).Where("locale <> ''")
+	switch ch {
+	case notification.ChannelEmail:
+		lower := make([]string, len(addresses))
+		for i, a := range addresses {
+			lower[i] = strings.ToLower(a)
+		}
+		q = q.Where("LOWER(email) IN ?", lower)
+	case notification.ChannelSMS:
+		q = q.Where("phone IN ?", addresses)
+	case notification.ChannelPush:
+		q = q.Where("EXISTS (SELECT 1 FROM unnest(string_to_array(push_tokens, ' ')) AS token WHERE token IN ?)", addresses)
+	case notification.ChannelInApp:
+		q = q.Where("user_id IN ?", addresses)
+	default:
+		return out, nil
+	}
+	var found []UserProfileModel
+	if err := q.Find(&found).Error; err != nil {
+		return nil, err
+	}
+	for _, m := range found {
+		switch ch {
+		case notification.ChannelEmail:
+			out[strings.ToLower(m.Email)] = m.Locale
+		case notification.ChannelSMS:
+			out[m.Phone] = m.Locale
+		case notification.ChannelPush:
+			for _, token := range strings.Fields(m.PushTokens) {
+				out[token] = m.Locale
+			}
+		case notification.ChannelInApp:
+			out[m.UserID] = m.Locale
+		}
+	}
+	return out, nil
+}
+
+func toProfileDomain(m *UserProfileModel, prefs []UserPreferenceModel) *profile.Profile {
+	p := &profile.Profile{
+		UserID:         m.UserID,
+		TenantID:       m.TenantID,
+		Phone:          m.Phone,
+		Email:          m.Email,
+		PushTokens:     strings.Fields(m.PushTokens),
+		Locale:         m.Locale,
+		TimeZone:       m.TimeZone,
+		Preferences:    make(map[string][]notification.Channel, len(prefs)),
+		OptOuts:        splitChannels(m.OptOuts),
+		TrackingOptOut: m.TrackingOptOut,
+		CreatedAt:      m.CreatedAt,
+		UpdatedAt:      m.UpdatedAt,
+	}
+	for _, pref := range prefs {
+		p.Preferences[pref.Category] = splitChannels(pref.Channels)
+	}
+	return p
+}
+
+func joinChannels(channels []notification.Channel) string {
+	names := make([]string, len(channels))
+	for i, ch := range channels {
+		names[i] = ch.String()
+	}
+	return strings.Join(names, " ")
+}
+
+// splitChannels never returns nil, so an empty preference stays an opt-out.
+func splitChannels(s string) []notification.Channel {
+	fields := strings.Fields(s)
+	channels := make([]notification.Channel, len(fields))
+	for i, f := range fields {
+		channels[i] = notification.Channel(f)
+	}
+	return channels
+}
@@ -0,0 +1,180 @@
+package postgres
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"github.com/semih-yildiz/notification-service/internal/application/template/port"
+	"github.com/semih-yildiz/notification-service/internal/domain/notification"
+	"github.com/semih-yildiz/notification-service/internal/domain/template"
+)
+
+var _ port.TemplateRepository = (*TemplateRepository)(nil)
+
+type TemplateRepository struct {
+	db *gorm.DB
+}
+
+func NewTemplateRepository(db *gorm.DB) *TemplateRepository {
+	return &TemplateRepository{db: db}
+}
+
+func (r *TemplateRepository) Create(ctx context.Context, t *template.Template) error {
+	m := toTemplateModel(t)
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := checkTemplateName(tx, t); err != nil {
+			return err
+		}
+		if err := tx.Create(m).Error; err != nil {
+			return err
+		}
+		return saveTemplateVariants(tx, t)
+	})
+}
+
+func (r *TemplateRepository) GetByID(ctx context.Context, id string) (*template.Template, error) {
+	var m TemplateModel
+	err := r.db.WithContext(ctx).Scopes(tenantScope(ctx)).Where("id = ?", id).First(&m).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, template.ErrNotFound
+		}
+		return nil, err
+	}
+	variants, err := r.variants(ctx, m.ID)
+	if err != nil {
+		return nil, err
+	}
+	t := toTemplateDomain(&m)
+	t.Variants = variants[m.ID]
+	return t, nil
+}
+
+func (r *TemplateRepository) List(ctx context.Context) ([]*template.Template, error) {
+	var list []TemplateModel
+	if err := r.db.WithContext(ctx).Scopes(tenantScope(ctx)).Order("name").Find(&list).Error; err != nil {
+		return nil, err
+	}
+	ids := make([]string, len(list))
+	for i := range list {
+		ids[i] = list[i].ID
+	}
+	variants, err := r.variants(ctx, ids...)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]*template.Template, len(list))
+	for i := range list {
+		out[i] = toTemplateDomain(&list[i])
+		out[i].Variants = variants[list[i].ID]
+	}
+	return out, nil
+}
+
+func (r *TemplateRepository) Update(ctx context.Context, t *template.Template) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := checkTemplateName(tx, t); err != nil {
+			return err
+		}
+		res := tx.Model(&TemplateModel{}).Scopes(tenantScope(ctx)).Where("id = ?", t.ID).Updates(map[string]any{
+			"name":       t.Name,
+			"body":       t.Body,
+			"updated_at": t.UpdatedAt,
+		})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return template.ErrNotFound
+		}
+		return saveTemplateVariants(tx, t)
+	})
+}
+
+func (r *TemplateRepository) Delete(ctx context.Context, id string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Scopes(tenantScope(ctx)).Where("id = ?", id).Delete(&TemplateModel{})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return template.ErrNotFound
+		}
+		return tx.Where("template_id = ?", id).Delete(&TemplateVariantModel{}).Error
+	})
+}
+
+// variants returns the variants of the templates with ids, by template ID.
+func (r *TemplateRepository) variants(ctx context.Context, ids ...string) (map[string]notification.Variants, error) {
+	out := make(map[string]notification.Variants)
+	if len(ids) == 0 {
+		return out, nil
+	}
+	var rows []TemplateVariantModel
+	if err := r.db.WithContext(ctx).Where("template_id IN ?", ids).Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		if out[row.TemplateID] == nil {
+			out[row.TemplateID] = make(notification.Variants)
+		}
+		out[row.TemplateID][row.Locale] = row.Body
+	}
+	return out, nil
+}
+
+// saveTemplateVariants replaces the stored variants of t with t.Variants.
+func saveTemplateVariants(tx *gorm.DB, t *template.Template) error {
+	if err := tx.Where("template_id = ?", t.ID).Delete(&TemplateVariantModel{}).Error; err != nil {
+		return err
+	}
+	if len(t.Variants) == 0 {
+		return nil
+	}
+	rows := make([]TemplateVariantModel, 0, len(t.Variants))
+	for locale, body := range t.Variants {
+		rows = append(rows, TemplateVariantModel{TemplateID: t.ID, Locale: locale, Body: body})
+	}
+	return tx.Create(&rows).Error
+}
+
+// checkTemplateName fails with template.ErrExists if another template of the tenant
+// has t's name.
+func checkTemplateName(tx *gorm.DB, t *template.Template) error {
+	var taken int64
+	err := tx.Model(&TemplateModel{}).
+		Where("tenant_id = ? AND name = ? AND id <> ?", t.TenantID, t.Name, t.ID).
+		Count(&taken).Error
+	if err != nil {
+		return err
+	}
+	if taken > 0 {
+		return template.ErrExists
+	}
+	return nil
+}
+
+func toTemplateModel(t *template.Template) *TemplateModel {
+	return &TemplateModel{
+		ID:        t.ID,
+		TenantID:  t.TenantID,
+		Name:      t.Name,
+		Body:      t.Body,
+		ClientID:  t.ClientID,
+		CreatedAt: t.CreatedAt,
+		UpdatedAt: t.UpdatedAt,
+	}
+}
+
+func toTemplateDomain(m *TemplateModel) *template.Template {
+	return &template.Template{
+		ID:        m.ID,
+		TenantID:  m.TenantID,
+		Name:      m.Name,
+		Body:      m.Body,
+		ClientID:  m.ClientID,
+		CreatedAt: m.CreatedAt,
+		UpdatedAt: m.UpdatedAt,
+	}
+}
@@ -0,0 +1,191 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/semih-yildiz/notification-service/internal/application/tenant/port"
+	"github.com/semih-yildiz/notification-service/internal/domain/notification"
+	"github.com/semih-yildiz/notification-service/internal/domain/tenant"
+)
+
+var _ port.TenantRepository = (*TenantRepository)(nil)
+
+type TenantRepository struct {
+	db *gorm.DB
+}
+
+func NewTenantRepository(db *gorm.DB) *TenantRepository {
+	return &TenantRepository{db: db}
+}
+
+func (r *TenantRepository) Create(ctx context.Context, t *tenant.Tenant) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		m := &TenantModel{ID: t.ID, Name: t.Name, CreatedAt: t.CreatedAt}
+		if err := tx.Create(m).Error; err != nil {
+			return err
+		}
+		if err := insertQuotas(tx, t.ID, t.DailyQuotas); err != nil {
+			return err
+		}
+		if err := insertDeliveryWindows(tx, t.ID, t.DeliveryWindows); err != nil {
+			return err
+		}
+		return insertDigestPolicies(tx, t.ID, t.DigestPolicies)
+	})
+}
+
+func (r *TenantRepository) GetByID(ctx context.Context, id string) (*tenant.Tenant, error) {
+	var m TenantModel
+	err := r.db.WithContext(ctx).Where("id = ?", id).First(&m).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, tenant.ErrNotFound
+		}
+		return nil, err
+	}
+	var quotas []TenantQuotaModel
+	if err := r.db.WithContext(ctx).Where("tenant_id = ?", id).Find(&quotas).Error; err != nil {
+		return nil, err
+	}
+	var windows []TenantDeliveryWindowModel
+	if err := r.db.WithContext(ctx).Where("tenant_id = ?", id).Find(&windows).Error; err != nil {
+		return nil, err
+	}
+	var policies []TenantDigestPolicyModel
+	if err := r.db.WithContext(ctx).Where("tenant_id = ?", id).Find(&policies).Error; err != nil {
+		return nil, err
+	}
+	return toTenantDomain(&m, quotas, windows, policies), nil
+}
+
+func (r *TenantRepository) List(ctx context.Context) ([]*tenant.Tenant, error) {
+	var list []TenantModel
+	if err := r.db.WithContext(ctx).Order("created_at").Find(&list).Error; err != nil {
+		return nil, err
+	}
+	var quotas []TenantQuotaModel
+	if err := r.db.WithContext(ctx).Find(&quotas).Error; err != nil {
+		return nil, err
+	}
+	var windows []TenantDeliveryWindowModel
+	if err := r.db.WithContext(ctx).Find(&windows).Error; err != nil {
+		return nil, err
+	}
+	var policies []TenantDigestPolicyModel
+	if err := r.db.WithContext(ctx).Find(&policies).Error; err != nil {
+		return nil, err
+	}
+	byTenant := make(map[string][]TenantQuotaModel)
+	for _, q := range quotas {
+		byTenant[q.TenantID] = append(byTenant[q.TenantID], q)
+	}
+	windowsByTenant := make(map[string][]TenantDeliveryWindowModel)
+	for _, w := range windows {
+		windowsByTenant[w.TenantID] = append(windowsByTenant[w.TenantID], w)
+	}
+	policiesByTenant := make(map[string][]TenantDigestPolicyModel)
+	for _, p := range policies {
+		policiesByTenant[p.TenantID] = append(policiesByTenant[p.TenantID], p)
+	}
+	out := make([]*tenant.Tenant, len(list))
+	for i := range list {
+		id := list[i].ID
+		out[i] = toTenantDomain(&list[i], byTenant[id], windowsByTenant[id], policiesByTenant[id])
+	}
+	return out, nil
+}
+
+func (r *TenantRepository) SetQuotas(ctx context.Context, id string, quotas map[notification.Channel]int) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("tenant_id = ?", id).Delete(&TenantQuotaModel{}).Error; err != nil {
+			return err
+		}
+		return insertQuotas(tx, id, quotas)
+	})
+}
+
+func (r *TenantRepository) SetDeliveryWindows(ctx context.Context, id string, windows map[string]notification.DeliveryWindow) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("tenant_id = ?", id).Delete(&TenantDeliveryWindowModel{}).Error; err != nil {
+			return err
+		}
+		return insertDeliveryWindows(tx, id, windows)
+	})
+}
+
+func (r *TenantRepository) SetDigestPolicies(ctx context.Context, id string, policies map[string]notification.DigestPolicy) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("tenant_id = ?", id).Delete(&TenantDigestPolicyModel{}).Error; err != nil {
+			return err
+		}
+		return insertDigestPolicies(tx, id, policies)
+	})
+}
+
+func insertDigestPolicies(tx *gorm.DB, tenantID string, policies map[string]notification.DigestPolicy) error {
+	if len(policies) == 0 {
+		return nil
+	}
+	rows := make([]TenantDigestPolicyModel, 0, len(policies))
+	for category, p := range policies {
+		rows = append(rows, TenantDigestPolicyModel{
+			TenantID:      tenantID,
+			Category:      category,
+			WindowSeconds: int(p.Window / time.Second),
+			TemplateID:    p.TemplateID,
+		})
+	}
+	return tx.Create(&rows).Error
+}
+
+func insertDeliveryWindows(tx *gorm.DB, tenantID string, windows map[string]notification.DeliveryWindow) error {
+	if len(windows) == 0 {
+		return nil
+	}
+	rows := make([]TenantDeliveryWindowModel, 0, len(windows))
+	for category, w := range windows {
+		rows = append(rows, TenantDeliveryWindowModel{
+			TenantID:    tenantID,
+			Category:    category,
+			StartMinute: w.Start,
+			EndMinute:   w.End,
+			TimeZone:    w.TimeZone,
+		})
+	}
+	return tx.Create(&rows).Error
+}
+
+func insertQuotas(tx *gorm.DB, tenantID string, quotas map[notification.Channel]int) error {
+	if len(quotas) == 0 {
+		return nil
+	}
+	rows := make([]TenantQuotaModel, 0, len(quotas))
+	for ch, limit := range quotas {
+		rows = append(rows, TenantQuotaModel{TenantID: tenantID, Channel: ch.String(), DailyLimit: limit})
+	}
+	return tx.Create(&rows).Error
+}
+
+func toTenantDomain(m *TenantModel, quotas []TenantQuotaModel, windows []TenantDeliveryWindowModel, policies []TenantDigestPolicyModel) *tenant.Tenant {
+	t := &tenant.Tenant{
+		ID:              m.ID,
+		Name:            m.Name,
+		DailyQuotas:     make(map[notification.Channel]int, len(quotas)),
+		DeliveryWindows: make(map[string]notification.DeliveryWindow, len(windows)),
+		DigestPolicies:  make(map[string]notification.DigestPolicy, len(policies)),
+		CreatedAt:       m.CreatedAt,
+	}
+	for _, q := range quotas {
+		t.DailyQuotas[notification.Channel(q.Channel)] = q.DailyLimit
+	}
+	for _, w := range windows {
+		t.DeliveryWindows[w.Category] = notification.DeliveryWindow{Start: w.StartMinute, End: w.EndMinute, TimeZone: w.TimeZone}
+	}
+	for _, p := range policies {
+		t.DigestPolicies[p.Category] = notification.DigestPolicy{Window: time.Duration(p.WindowSeconds) * time.Second, TemplateID: p.TemplateID}
+	}
+	return t
+}
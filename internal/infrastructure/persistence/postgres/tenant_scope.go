@@ -0,0 +1,30 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+
+	sharedctx "github.com/semih-yildiz/notification-service/internal/shared/context"
+)
+
+// errNoTenant fails queries whose context is neither scoped to a tenant nor
+// explicitly unscoped; a missing scope is a bug, not a reason to see every tenant.
+var errNoTenant = errors.New("postgres: tenant-scoped query without a tenant; use sharedctx.WithTenantID or sharedctx.Unscoped")
+
+// tenantScope restricts a query to the tenant ctx is scoped to. Contexts marked
+// with sharedctx.Unscoped, such as the worker's, see every tenant; any other
+// context fails the query.
+func tenantScope(ctx context.Context) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if id, ok := sharedctx.TenantID(ctx); ok {
+			return db.Where("tenant_id = ?", id)
+		}
+		if sharedctx.IsUnscoped(ctx) {
+			return db
+		}
+		_ = db.AddError(errNoTenant)
+		return db
+	}
+}
@@ -0,0 +1,127 @@
+package chat
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/semih-yildiz/notification-service/internal/application/notification/port"
+	"github.com/semih-yildiz/notification-service/internal/domain/notification"
+)
+
+// Client posts chat notifications to Slack-compatible incoming webhooks (Slack,
+// Mattermost, Rocket.Chat). A recipient is either a webhook URL on an allowed host,
+// or workspace#channel, posted to the workspace's configured webhook.
+type Client struct {
+	workspaces   map[string]string // workspace name -> incoming webhook URL
+	allowedHosts []string          // hosts recipients may name directly; "*.example.com" matches subdomains
+	httpClient   *http.Client
+}
+
+var _ port.DeliveryClient = (*Client)(nil)
+
+func NewClient(workspaces map[string]string, allowedHosts []string) *Client {
+	return &Client{
+		workspaces:   workspaces,
+		allowedHosts: allowedHosts,
+		httpClient: &http.Client{
+			Timeout: 15 * time.Second,
+		},
+	}
+}
+
+// WithHTTPClient replaces the HTTP client, e.g. to change the timeout.
+func (c *Client) WithHTTPClient(httpClient *http.Client) *Client {
+	c.httpClient = httpClient
+	return c
+}
+
+func (c *Client) Deliver(ctx context.Context, req *port.DeliveryRequest) (*port.DeliveryResponse, int, error) {
+	target, channel, err := c.resolve(req.To)
+	if err != nil {
+		return nil, 0, fmt.Errorf("%w: %v", port.ErrDeliveryRejected, err)
+	}
+	msg, err := buildMessage(req.Content)
+	if err != nil {
+		return nil, 0, fmt.Errorf("%w: %v", port.ErrDeliveryRejected, err)
+	}
+	msg.Channel = channel
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return nil, 0, err
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, target, bytes.NewReader(body))
+	if err != nil {
+		return nil, 0, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		// The webhook URL is a credential; keep it out of the stored failure reason.
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return nil, 0, fmt.Errorf("chat delivery failed: %w", err)
+	}
+	defer resp.Body.Close()
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return &port.DeliveryResponse{Status: "accepted", Timestamp: time.Now().Format(time.RFC3339)}, resp.StatusCode, nil
+	}
+	err = fmt.Errorf("chat delivery failed: status %d body %s", resp.StatusCode, string(data))
+	if permanentStatus(resp.StatusCode) {
+		err = fmt.Errorf("%w: %v", port.ErrDeliveryRejected, err)
+	}
+	return nil, resp.StatusCode, err
+}
+
+// permanentStatus reports whether a webhook answer means the post will never be
+// accepted, e.g. a revoked webhook or an archived channel. Timeouts and rate
+// limits are worth retrying.
+func permanentStatus(code int) bool {
+	return code >= 400 && code < 500 && code != http.StatusRequestTimeout && code != http.StatusTooManyRequests
+}
+
+// resolve returns the webhook to post to and, for a channel reference, the
+// channel to post in.
+func (c *Client) resolve(recipient string) (string, string, error) {
+	r, err := notification.ParseChatRecipient(recipient)
+	if err != nil {
+		return "", "", err
+	}
+	if r.WebhookURL != "" {
+		u, _ := url.Parse(r.WebhookURL)
+		if !c.hostAllowed(u.Hostname()) {
+			return "", "", fmt.Errorf("chat webhook host %s is not allowed", u.Hostname())
+		}
+		return r.WebhookURL, "", nil
+	}
+	target, ok := c.workspaces[r.Workspace]
+	if !ok {
+		return "", "", fmt.Errorf("chat workspace %s has no webhook configured", r.Workspace)
+	}
+	return target, "#" + r.Channel, nil
+}
+
+func (c *Client) hostAllowed(host string) bool {
+	host = strings.ToLower(host)
+	for _, allowed := range c.allowedHosts {
+		allowed = strings.ToLower(allowed)
+		if suffix, ok := strings.CutPrefix(allowed, "*"); ok {
+			if strings.HasSuffix(host, suffix) {
+				return true
+			}
+		} else if host == allowed {
+			return true
+		}
+	}
+	return false
+}
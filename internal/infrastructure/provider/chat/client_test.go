@@ -0,0 +1,132 @@
+package chat
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/semih-yildiz/notification-service/internal/application/notification/port"
+)
+
+// fakeWebhook records the payloads posted to it and answers with status.
+type fakeWebhook struct {
+	server   *httptest.Server
+	status   int
+	payloads []map[string]any
+}
+
+func newFakeWebhook(t *testing.T, status int) *fakeWebhook {
+	f := &fakeWebhook{status: status}
+	f.server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload map[string]any
+		body, _ := io.ReadAll(r.Body)
+		if err := json.Unmarshal(body, &payload); err != nil {
+			t.Errorf("expected a JSON payload, got %s", body)
+		}
+		f.payloads = append(f.payloads, payload)
+		w.WriteHeader(f.status)
+		_, _ = w.Write([]byte("ok"))
+	}))
+	t.Cleanup(f.server.Close)
+	return f
+}
+
+func (f *fakeWebhook) client(workspaces map[string]string) *Client {
+	u, _ := url.Parse(f.server.URL)
+	return NewClient(workspaces, []string{u.Hostname()}).WithHTTPClient(f.server.Client())
+}
+
+func TestDeliver_ChannelReference(t *testing.T) {
+	hook := newFakeWebhook(t, http.StatusOK)
+	client := hook.client(map[string]string{"ops": hook.server.URL + "/services/ops"})
+
+	resp, code, err := client.Deliver(context.Background(), &port.DeliveryRequest{
+		To: "ops#alerts", Channel: "chat", Content: "# Deploy finished\n\n**api** is on [v2](https://example.com/v2)",
+	})
+	if err != nil || code != http.StatusOK || resp.Status != "accepted" {
+		t.Fatalf("expected accepted delivery, got %+v, %d, %v", resp, code, err)
+	}
+	if len(hook.payloads) != 1 {
+		t.Fatalf("expected one post, got %d", len(hook.payloads))
+	}
+	payload := hook.payloads[0]
+	if payload["channel"] != "#alerts" {
+		t.Errorf("expected channel #alerts, got %v", payload["channel"])
+	}
+	if text, _ := payload["text"].(string); !strings.Contains(text, "*api* is on <https://example.com/v2|v2>") {
+		t.Errorf("expected mrkdwn text, got %q", text)
+	}
+	if blocks, _ := payload["blocks"].([]any); len(blocks) != 2 {
+		t.Errorf("expected a header and a section, got %v", payload["blocks"])
+	}
+}
+
+func TestDeliver_WebhookURL(t *testing.T) {
+	hook := newFakeWebhook(t, http.StatusOK)
+	client := hook.client(nil)
+
+	content := `{"text":"Disk almost full","blocks":[{"type":"section","text":{"type":"mrkdwn","text":"*db-1* at 91%"}}]}`
+	if _, _, err := client.Deliver(context.Background(), &port.DeliveryRequest{To: hook.server.URL + "/hook", Content: content}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	payload := hook.payloads[0]
+	if payload["text"] != "Disk almost full" || payload["channel"] != nil {
+		t.Errorf("expected blocks passed through without a channel, got %v", payload)
+	}
+}
+
+func TestDeliver_Rejected(t *testing.T) {
+	hook := newFakeWebhook(t, http.StatusNotFound)
+	client := hook.client(map[string]string{"default": hook.server.URL})
+
+	_, code, err := client.Deliver(context.Background(), &port.DeliveryRequest{To: "#gone", Content: "hi"})
+	if !errors.Is(err, port.ErrDeliveryRejected) || code != http.StatusNotFound {
+		t.Errorf("expected a rejected delivery with status 404, got %d, %v", code, err)
+	}
+
+	hook.status = http.StatusTooManyRequests
+	_, code, err = client.Deliver(context.Background(), &port.DeliveryRequest{To: "#busy", Content: "hi"})
+	if err == nil || errors.Is(err, port.ErrDeliveryRejected) || code != http.StatusTooManyRequests {
+		t.Errorf("expected a retryable failure with status 429, got %d, %v", code, err)
+	}
+}
+
+func TestDeliver_Unresolvable(t *testing.T) {
+	hook := newFakeWebhook(t, http.StatusOK)
+	client := hook.client(map[string]string{"ops": hook.server.URL})
+
+	for _, to := range []string{"billing#alerts", "https://attacker.example.com/hook", "alerts"} {
+		if _, _, err := client.Deliver(context.Background(), &port.DeliveryRequest{To: to, Content: "hi"}); !errors.Is(err, port.ErrDeliveryRejected) {
+			t.Errorf("expected %q to be rejected, got %v", to, err)
+		}
+	}
+	if len(hook.payloads) != 0 {
+		t.Errorf("expected nothing posted, got %d posts", len(hook.payloads))
+	}
+}
+
+func TestHostAllowed(t *testing.T) {
+	client := NewClient(nil, []string{"hooks.slack.com", "*.webhook.office.com"})
+
+	tests := []struct {
+		host string
+		want bool
+	}{
+		{"hooks.slack.com", true},
+		{"HOOKS.SLACK.COM", true},
+		{"acme.webhook.office.com", true},
+		{"webhook.office.com.evil.com", false},
+		{"slack.com", false},
+	}
+	for _, tt := range tests {
+		if got := client.hostAllowed(tt.host); got != tt.want {
+			t.Errorf("hostAllowed(%q) = %v, want %v", tt.host, got, tt.want)
+		}
+	}
+}
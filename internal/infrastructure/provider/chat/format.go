@@ -0,0 +1,138 @@
+package chat
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/semih-yildiz/notification-service/internal/domain/notification"
+)
+
+const (
+	maxSectionText = 3000 // Block Kit limit of a section's text
+	maxHeaderText  = 150  // Block Kit limit of a header's text
+)
+
+var (
+	boldPattern   = regexp.MustCompile(`\*\*(.+?)\*\*|__(.+?)__`)
+	strikePattern = regexp.MustCompile(`~~(.+?)~~`)
+	mdLinkPattern = regexp.MustCompile(`\[([^\]]+)\]\((https?://[^)\s]+)\)`)
+	rulePattern   = regexp.MustCompile(`^(-{3,}|\*{3,}|_{3,})$`)
+	mrkdwnEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+)
+
+// message is the payload of an incoming webhook.
+type message struct {
+	Text    string            `json:"text"`
+	Blocks  []json.RawMessage `json:"blocks,omitempty"`
+	Channel string            `json:"channel,omitempty"`
+}
+
+// buildMessage turns notification content into a webhook payload. A Block Kit
+// document is passed through; Markdown is converted to mrkdwn sections.
+func buildMessage(content string) (*message, error) {
+	if notification.IsChatBlocks(content) {
+		doc, err := notification.ParseChatBlocks(content)
+		if err != nil {
+			return nil, err
+		}
+		return &message{Text: doc.Text, Blocks: doc.Blocks}, nil
+	}
+	text, blocks := formatMarkdown(content)
+	msg := &message{Text: text}
+	// Too many blocks for one message: the plain text alone still carries it all.
+	if len(blocks) <= notification.MaxChatBlocks {
+		msg.Blocks = blocks
+	}
+	return msg, nil
+}
+
+// formatMarkdown converts Markdown to mrkdwn: the whole text for notifications,
+// and blocks where "# " headings become headers, rules dividers and paragraphs
+// sections. Fenced code is kept as is.
+func formatMarkdown(content string) (string, []json.RawMessage) {
+	var (
+		blocks    []json.RawMessage
+		text      []string
+		paragraph []string
+		inFence   bool
+	)
+	flush := func() {
+		if len(paragraph) > 0 {
+			for _, chunk := range splitText(strings.Join(paragraph, "\n"), maxSectionText) {
+				blocks = append(blocks, block(map[string]any{"type": "section", "text": map[string]string{"type": "mrkdwn", "text": chunk}}))
+			}
+			paragraph = nil
+		}
+	}
+
+	for _, line := range strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") {
+			inFence = !inFence
+		}
+		if inFence || strings.HasPrefix(trimmed, "```") {
+			escaped := mrkdwnEscaper.Replace(line)
+			paragraph = append(paragraph, escaped)
+			text = append(text, escaped)
+			continue
+		}
+		switch {
+		case trimmed == "":
+			flush()
+			if len(text) > 0 && text[len(text)-1] != "" {
+				text = append(text, "")
+			}
+		case rulePattern.MatchString(trimmed):
+			flush()
+			blocks = append(blocks, block(map[string]string{"type": "divider"}))
+		case strings.HasPrefix(trimmed, "#"):
+			heading := strings.TrimSpace(strings.TrimLeft(trimmed, "#"))
+			flush()
+			blocks = append(blocks, block(map[string]any{"type": "header", "text": map[string]any{"type": "plain_text", "text": truncate(heading, maxHeaderText)}}))
+			text = append(text, "*"+mrkdwnEscaper.Replace(heading)+"*")
+		default:
+			converted := inlineMrkdwn(line)
+			paragraph = append(paragraph, converted)
+			text = append(text, converted)
+		}
+	}
+	flush()
+	return strings.TrimSpace(strings.Join(text, "\n")), blocks
+}
+
+// inlineMrkdwn escapes a line and converts Markdown bold, strikethrough and links.
+func inlineMrkdwn(line string) string {
+	line = mrkdwnEscaper.Replace(line)
+	line = boldPattern.ReplaceAllString(line, "*$1$2*")
+	line = strikePattern.ReplaceAllString(line, "~$1~")
+	return mdLinkPattern.ReplaceAllString(line, "<$2|$1>")
+}
+
+// splitText cuts s into chunks of at most n bytes, preferring line breaks.
+func splitText(s string, n int) []string {
+	var chunks []string
+	for len(s) > n {
+		cut := strings.LastIndex(s[:n], "\n")
+		if cut <= 0 {
+			for cut = n; cut > 0 && !utf8.RuneStart(s[cut]); cut-- {
+			}
+		}
+		chunks = append(chunks, s[:cut])
+		s = strings.TrimPrefix(s[cut:], "\n")
+	}
+	return append(chunks, s)
+}
+
+func truncate(s string, n int) string {
+	if r := []rune(s); len(r) > n {
+		return string(r[:n-1]) + "…"
+	}
+	return s
+}
+
+func block(v any) json.RawMessage {
+	data, _ := json.Marshal(v)
+	return data
+}
@@ -0,0 +1,49 @@
+package chat
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestFormatMarkdown(t *testing.T) {
+	text, blocks := formatMarkdown("# Incident\n\nError rate ~~1%~~ **5%** & rising\n\n---\n\n```\nx < y\n```")
+
+	want := "*Incident*\n\nError rate ~1%~ *5%* &amp; rising\n\n```\nx &lt; y\n```"
+	if text != want {
+		t.Errorf("text = %q, want %q", text, want)
+	}
+	var types []string
+	for _, b := range blocks {
+		var v struct {
+			Type string `json:"type"`
+		}
+		_ = json.Unmarshal(b, &v)
+		types = append(types, v.Type)
+	}
+	if got := strings.Join(types, ","); got != "header,section,divider,section" {
+		t.Errorf("block types = %s", got)
+	}
+}
+
+func TestBuildMessage_LongContent(t *testing.T) {
+	paragraph := strings.Repeat("word ", 700) // over one section's limit
+	msg, err := buildMessage(paragraph)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(msg.Blocks) != 2 {
+		t.Errorf("expected the paragraph split into 2 sections, got %d", len(msg.Blocks))
+	}
+
+	many := strings.Repeat("line\n\n", 60)
+	if msg, _ := buildMessage(many); msg.Blocks != nil || msg.Text == "" {
+		t.Errorf("expected text only above the block limit, got %d blocks", len(msg.Blocks))
+	}
+}
+
+func TestBuildMessage_InvalidBlocks(t *testing.T) {
+	if _, err := buildMessage(`{"blocks":[]}`); err == nil {
+		t.Error("expected an invalid Block Kit document to be rejected")
+	}
+}
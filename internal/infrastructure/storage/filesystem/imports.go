@@ -0,0 +1,64 @@
+package filesystem
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/semih-yildiz/notification-service/internal/application/notification/port"
+	"github.com/semih-yildiz/notification-service/internal/domain/notification"
+)
+
+var _ port.ImportStorage = (*ImportStorage)(nil)
+
+// ImportStorage keeps import uploads as files in one directory. Uploads are only
+// read by the process that received them, so the directory need not be shared.
+type ImportStorage struct {
+	dir string
+}
+
+func NewImportStorage(dir string) (*ImportStorage, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("filesystem: import dir: %w", err)
+	}
+	return &ImportStorage{dir: dir}, nil
+}
+
+func (s *ImportStorage) Save(ctx context.Context, jobID string, r io.Reader, maxBytes int64) error {
+	f, err := os.OpenFile(s.path(jobID), os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	// Read one byte past the limit to tell a file of exactly maxBytes from a larger one.
+	n, err := io.Copy(f, io.LimitReader(r, maxBytes+1))
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	if n > maxBytes {
+		return notification.ErrImportTooLarge
+	}
+	return ctx.Err()
+}
+
+func (s *ImportStorage) Open(_ context.Context, jobID string) (io.ReadCloser, error) {
+	return os.Open(s.path(jobID))
+}
+
+func (s *ImportStorage) Delete(_ context.Context, jobID string) error {
+	err := os.Remove(s.path(jobID))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// path confines job IDs to the storage directory.
+func (s *ImportStorage) path(jobID string) string {
+	return filepath.Join(s.dir, filepath.Base(jobID)+".upload")
+}
@@ -0,0 +1,52 @@
+package tracking
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"net/url"
+	"strings"
+
+	"github.com/semih-yildiz/notification-service/internal/application/notification/port"
+)
+
+var _ port.TrackingLinks = (*Links)(nil)
+
+// sigBytes is how much of the HMAC-SHA256 goes into a link.
+const sigBytes = 16
+
+// Links signs tracking URLs served by the API at baseURL with an HMAC of the
+// notification ID and, for clicks, the target.
+type Links struct {
+	baseURL string
+	secret  []byte
+}
+
+// NewLinks returns links under baseURL, the API's public address, signed with secret.
+func NewLinks(baseURL, secret string) *Links {
+	return &Links{baseURL: strings.TrimRight(baseURL, "/"), secret: []byte(secret)}
+}
+
+func (l *Links) OpenURL(notificationID string) string {
+	q := url.Values{"sig": {l.sign("open", notificationID, "")}}
+	return l.baseURL + "/track/open/" + url.PathEscape(notificationID) + "?" + q.Encode()
+}
+
+func (l *Links) ClickURL(notificationID, target string) string {
+	q := url.Values{"url": {target}, "sig": {l.sign("click", notificationID, target)}}
+	return l.baseURL + "/track/click/" + url.PathEscape(notificationID) + "?" + q.Encode()
+}
+
+func (l *Links) VerifyOpen(notificationID, sig string) bool {
+	return hmac.Equal([]byte(sig), []byte(l.sign("open", notificationID, "")))
+}
+
+func (l *Links) VerifyClick(notificationID, target, sig string) bool {
+	return hmac.Equal([]byte(sig), []byte(l.sign("click", notificationID, target)))
+}
+
+func (l *Links) sign(kind, notificationID, target string) string {
+	mac := hmac.New(sha256.New, l.secret)
+	mac.Write([]byte(kind + "\x00" + notificationID + "\x00" + target))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil)[:sigBytes])
+}
@@ -0,0 +1,61 @@
+package tracking
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestLinks_RoundTrip(t *testing.T) {
+	l := NewLinks("https://api.example.com/", "secret")
+
+	open, err := url.Parse(l.OpenURL("n-1"))
+	if err != nil {
+		t.Fatalf("invalid open URL: %v", err)
+	}
+	if open.Path != "/track/open/n-1" || !strings.HasPrefix(open.String(), "https://api.example.com/") {
+		t.Errorf("unexpected open URL %s", open)
+	}
+	if !l.VerifyOpen("n-1", open.Query().Get("sig")) {
+		t.Error("expected open signature to verify")
+	}
+
+	target := "https://shop.example/p?a=1&b=2"
+	click, err := url.Parse(l.ClickURL("n-1", target))
+	if err != nil {
+		t.Fatalf("invalid click URL: %v", err)
+	}
+	if click.Path != "/track/click/n-1" || click.Query().Get("url") != target {
+		t.Errorf("unexpected click URL %s", click)
+	}
+	if !l.VerifyClick("n-1", target, click.Query().Get("sig")) {
+		t.Error("expected click signature to verify")
+	}
+}
+
+func TestLinks_RejectsTampering(t *testing.T) {
+	l := NewLinks("https://api.example.com", "secret")
+	click, _ := url.Parse(l.ClickURL("n-1", "https://shop.example"))
+	sig := click.Query().Get("sig")
+	open, _ := url.Parse(l.OpenURL("n-1"))
+
+	tests := []struct {
+		name string
+		ok   bool
+	}{
+		{"Other target", l.VerifyClick("n-1", "https://evil.example", sig)},
+		{"Other notification", l.VerifyClick("n-2", "https://shop.example", sig)},
+		{"Open signature used for a click", l.VerifyClick("n-1", "", open.Query().Get("sig"))},
+		{"Click signature used for an open", l.VerifyOpen("n-1", sig)},
+		{"Other secret", NewLinks("https://api.example.com", "other").VerifyClick("n-1", "https://shop.example", sig)},
+		{"Empty signature", l.VerifyOpen("n-1", "")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.ok {
+				t.Error("expected verification to fail")
+			}
+		})
+	}
+}
@@ -0,0 +1,8 @@
+package context
+
+type apiClientKey struct{}
+
+// APIClientKey is the context key of the authenticated API client.
+func APIClientKey() interface{} {
+	return apiClientKey{}
+}
@@ -0,0 +1,33 @@
+package context
+
+import "context"
+
+type (
+	tenantIDKey struct{}
+	unscopedKey struct{}
+)
+
+// WithTenantID scopes ctx to a tenant. Repositories only see that tenant's data.
+func WithTenantID(ctx context.Context, tenantID string) context.Context {
+	return context.WithValue(ctx, tenantIDKey{}, tenantID)
+}
+
+// TenantID returns the tenant ctx is scoped to.
+func TenantID(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(tenantIDKey{}).(string)
+	return id, ok && id != ""
+}
+
+// Unscoped marks ctx as acting for no tenant in particular, such as the worker's,
+// so repositories see every tenant. Contexts that are neither scoped nor marked
+// are refused by tenant-scoped repositories. It drops a tenant set before;
+// WithTenantID scopes the result again.
+func Unscoped(ctx context.Context) context.Context {
+	return context.WithValue(WithTenantID(ctx, ""), unscopedKey{}, true)
+}
+
+// IsUnscoped reports whether ctx was marked with Unscoped.
+func IsUnscoped(ctx context.Context) bool {
+	unscoped, _ := ctx.Value(unscopedKey{}).(bool)
+	return unscoped
+}